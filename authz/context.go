@@ -0,0 +1,23 @@
+package authz
+
+import (
+	"context"
+
+	"github.com/amehrotra/car-dealership/models"
+)
+
+type contextKey int
+
+const principalKey contextKey = iota
+
+// WithPrincipal returns a copy of ctx carrying the authenticated principal
+func WithPrincipal(ctx context.Context, principal *models.Principal) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+// PrincipalFromContext returns the authenticated principal of the request, if any
+func PrincipalFromContext(ctx context.Context) (*models.Principal, bool) {
+	principal, ok := ctx.Value(principalKey).(*models.Principal)
+
+	return principal, ok && principal != nil
+}
@@ -0,0 +1,89 @@
+package authz
+
+import (
+	"context"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/models"
+)
+
+type Role string
+
+const (
+	Viewer           Role = "viewer"
+	Salesperson      Role = "salesperson"
+	InventoryManager Role = "inventory_manager"
+	Admin            Role = "admin"
+)
+
+type Permission string
+
+const (
+	ReadCars         Permission = "car:read"
+	CreateCars       Permission = "car:create"
+	UpdateCars       Permission = "car:update"
+	ChangePrices     Permission = "car:price"
+	DeleteCars       Permission = "car:delete"
+	ReserveCars      Permission = "car:reserve"
+	ReadCustomers    Permission = "customer:read"
+	ManageCustomers  Permission = "customer:manage"
+	DeleteCustomers  Permission = "customer:delete"
+	ReadOrders       Permission = "order:read"
+	ManageOrders     Permission = "order:manage"
+	ReadTestDrives   Permission = "testdrive:read"
+	ManageTestDrives Permission = "testdrive:manage"
+	ReadTradeIns     Permission = "tradein:read"
+	ManageTradeIns   Permission = "tradein:manage"
+	ApproveTradeIns  Permission = "tradein:approve"
+	ManageAPIKeys    Permission = "apikey:manage"
+	ManageWebhooks   Permission = "webhook:manage"
+)
+
+// permissions returns the permission matrix, the permissions granted to each role
+func permissions() map[Role][]Permission {
+	return map[Role][]Permission{
+		Viewer: {ReadCars},
+		Salesperson: {ReadCars, UpdateCars, ReserveCars, ReadCustomers, ManageCustomers, ReadOrders, ManageOrders, ReadTestDrives,
+			ManageTestDrives, ReadTradeIns, ManageTradeIns},
+		InventoryManager: {ReadCars, CreateCars, UpdateCars, ChangePrices, DeleteCars, ReadTradeIns, ManageTradeIns, ApproveTradeIns},
+		Admin: {ReadCars, CreateCars, UpdateCars, ChangePrices, DeleteCars, ReserveCars, ReadCustomers, ManageCustomers,
+			DeleteCustomers, ReadOrders, ManageOrders, ReadTestDrives, ManageTestDrives, ReadTradeIns, ManageTradeIns, ApproveTradeIns,
+			ManageAPIKeys, ManageWebhooks},
+	}
+}
+
+// IsRole reports whether name is one of the roles
+func IsRole(name string) bool {
+	_, ok := permissions()[Role(name)]
+
+	return ok
+}
+
+// Allowed reports whether any role of the principal grants the permission
+func Allowed(principal *models.Principal, permission Permission) bool {
+	if principal == nil {
+		return false
+	}
+
+	matrix := permissions()
+
+	for _, role := range principal.Roles {
+		for _, p := range matrix[Role(role)] {
+			if p == permission {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Check returns errors.Forbidden unless the principal of ctx is granted the permission
+func Check(ctx context.Context, permission Permission) error {
+	principal, _ := PrincipalFromContext(ctx)
+	if !Allowed(principal, permission) {
+		return errors.Forbidden{Permission: string(permission)}
+	}
+
+	return nil
+}
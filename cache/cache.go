@@ -0,0 +1,28 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache keeps encoded values for a while, implementations backed by a shared store such as redis let several
+// instances share the cached values
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Observer is told about every lookup made in a cache, whether it hit or missed
+type Observer interface {
+	ObserveCacheLookup(component string, hit bool)
+}
+
+type discard struct{}
+
+func (discard) ObserveCacheLookup(string, bool) {}
+
+// Discard returns an Observer which ignores the lookups
+func Discard() Observer {
+	return discard{}
+}
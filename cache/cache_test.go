@@ -0,0 +1,319 @@
+package cache
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+	"github.com/amehrotra/car-dealership/types"
+)
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	now = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	car = models.Car{ID: uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4"), Model: "X1", ManufactureYear: 2020,
+		Brand: "BMW", FuelType: types.Petrol, Price: 4000000}
+)
+
+// lookups counts the lookups told to an Observer
+type lookups struct {
+	mu           sync.Mutex
+	hits, misses int
+}
+
+func (l *lookups) ObserveCacheLookup(_ string, hit bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if hit {
+		l.hits++
+	} else {
+		l.misses++
+	}
+}
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	clock := now
+	l := NewLRU(2).(*lru)
+	l.now = func() time.Time { return clock }
+
+	_ = l.Set(ctx, "a", []byte("1"), time.Minute)
+	_ = l.Set(ctx, "b", []byte("2"), time.Hour)
+	_, _, _ = l.Get(ctx, "a")
+	// c evicts b, the least recently used value
+	_ = l.Set(ctx, "c", []byte("3"), time.Hour)
+
+	clock = clock.Add(2 * time.Minute)
+
+	_ = l.Set(ctx, "d", []byte("4"), time.Hour)
+	_ = l.Delete(ctx, "d")
+
+	cases := []struct {
+		desc  string
+		key   string
+		value []byte
+		found bool
+	}{
+		{"expired", "a", nil, false},
+		{"evicted", "b", nil, false},
+		{"cached", "c", []byte("3"), true},
+		{"deleted", "d", nil, false},
+	}
+
+	for i, tc := range cases {
+		value, found, err := l.Get(ctx, tc.key)
+
+		if err != nil || found != tc.found || !reflect.DeepEqual(value, tc.value) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %s, %v, %v\nExpected %s, %v", i, tc.desc, value, found, err, tc.value, tc.found)
+		}
+	}
+}
+
+func TestCarStore_GetByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockCar := stores.NewMockCar(ctrl)
+	observed := &lookups{}
+	s := CarStore(mockCar, NewLRU(10), time.Minute, observed)
+
+	missing := uuid.New()
+	notFound := errors.EntityNotFound{Entity: "car", ID: missing.String()}
+
+	mockCar.EXPECT().GetByID(gomock.Any(), car.ID).Return(car, nil)
+	mockCar.EXPECT().GetByID(gomock.Any(), missing).Return(models.Car{}, notFound).Times(2)
+
+	cases := []struct {
+		desc   string
+		id     uuid.UUID
+		output models.Car
+		err    error
+	}{
+		{"miss", car.ID, car, nil},
+		{"hit", car.ID, car, nil},
+		{"errors are not cached", missing, models.Car{}, notFound},
+		{"errors are fetched again", missing, models.Car{}, notFound},
+	}
+
+	for i, tc := range cases {
+		output, err := s.GetByID(context.Background(), tc.id)
+
+		if !reflect.DeepEqual(output, tc.output) || !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v, %v", i, tc.desc, output, err, tc.output, tc.err)
+		}
+	}
+
+	if observed.hits != 1 || observed.misses != 3 {
+		t.Errorf("\n[TEST] Failed. Desc : lookups\nGot %v hits %v misses\nExpected 1 hit 3 misses", observed.hits, observed.misses)
+	}
+}
+
+func TestEngineStore_GetByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockEngine := stores.NewMockEngine(ctrl)
+	s := EngineStore(mockEngine, NewLRU(10), time.Minute, Discard())
+
+	engine := models.Engine{ID: car.ID, Displacement: 2000, NCylinder: 4}
+
+	mockEngine.EXPECT().GetByID(gomock.Any(), car.ID).Return(engine, nil)
+
+	for i := 0; i < 2; i++ {
+		// the id, left out of the json of an engine, survives the cache
+		if output, err := s.GetByID(context.Background(), car.ID); err != nil || output != engine {
+			t.Errorf("\n[TEST %d] Failed. Desc : cached engine\nGot %v, %v\nExpected %v", i, output, err, engine)
+		}
+	}
+}
+
+func TestCarStore_Stampede(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockCar := stores.NewMockCar(ctrl)
+	s := CarStore(mockCar, NewLRU(10), time.Minute, Discard())
+
+	release := make(chan struct{})
+
+	// the first miss holds the store until every reader is waiting on it
+	mockCar.EXPECT().GetByID(gomock.Any(), car.ID).DoAndReturn(func(context.Context, uuid.UUID) (models.Car, error) {
+		<-release
+
+		return car, nil
+	})
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if output, err := s.GetByID(context.Background(), car.ID); err != nil || output != car {
+				t.Errorf("\n[TEST] Failed. Desc : concurrent misses\nGot %v, %v\nExpected %v", output, err, car)
+			}
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+}
+
+func TestCarStore_StampedeCancel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockCar := stores.NewMockCar(ctrl)
+	s := CarStore(mockCar, NewLRU(10), time.Minute, Discard())
+
+	release := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mockCar.EXPECT().GetByID(gomock.Any(), car.ID).DoAndReturn(func(ctx context.Context, _ uuid.UUID) (models.Car, error) {
+		<-release
+
+		return car, ctx.Err()
+	})
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		_, _ = s.GetByID(ctx, car.ID)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		if output, err := s.GetByID(context.Background(), car.ID); err != nil || output != car {
+			t.Errorf("\n[TEST] Failed. Desc : first reader gone\nGot %v, %v\nExpected %v", output, err, car)
+		}
+	}()
+
+	// the reader whose context fetches the car goes away while the other one waits on it
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	close(release)
+	wg.Wait()
+}
+
+func TestCarStore_InvalidateDuringFill(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockCar := stores.NewMockCar(ctrl)
+	c := NewLRU(10)
+	s := CarStore(mockCar, c, time.Minute, Discard())
+
+	updated := car
+	updated.Price = 3500000
+
+	// the car is read before the update and cached after it was invalidated
+	mockCar.EXPECT().GetByID(gomock.Any(), car.ID).DoAndReturn(func(ctx context.Context, _ uuid.UUID) (models.Car, error) {
+		if err := s.Update(ctx, &updated); err != nil {
+			return models.Car{}, err
+		}
+
+		return car, nil
+	})
+	mockCar.EXPECT().Update(gomock.Any(), &updated).Return(nil)
+
+	if output, err := s.GetByID(context.Background(), car.ID); err != nil || output != car {
+		t.Errorf("\n[TEST] Failed. Desc : fill\nGot %v, %v\nExpected %v", output, err, car)
+	}
+
+	if _, found, _ := c.Get(context.Background(), "car:"+car.ID.String()); found {
+		t.Errorf("\n[TEST] Failed. Desc : invalidated during the fill\nGot cached car\nExpected nothing cached")
+	}
+}
+
+func TestCarStore_Invalidate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockCar := stores.NewMockCar(ctrl)
+	s := CarStore(mockCar, NewLRU(10), time.Minute, Discard())
+
+	updated := car
+	updated.Price = 3500000
+
+	gomock.InOrder(
+		mockCar.EXPECT().GetByID(gomock.Any(), car.ID).Return(car, nil),
+		mockCar.EXPECT().Update(gomock.Any(), &updated).Return(nil),
+		mockCar.EXPECT().GetByID(gomock.Any(), car.ID).Return(updated, nil),
+		mockCar.EXPECT().Delete(gomock.Any(), car.ID).Return(nil),
+		mockCar.EXPECT().GetByID(gomock.Any(), car.ID).Return(models.Car{}, errors.EntityNotFound{Entity: "car", ID: car.ID.String()}),
+	)
+
+	ctx := context.Background()
+
+	_, _ = s.GetByID(ctx, car.ID)
+	_ = s.Update(ctx, &updated)
+
+	if output, _ := s.GetByID(ctx, car.ID); output.Price != updated.Price {
+		t.Errorf("\n[TEST] Failed. Desc : update\nGot %v\nExpected %v", output.Price, updated.Price)
+	}
+
+	_ = s.Delete(ctx, car.ID)
+
+	if _, err := s.GetByID(ctx, car.ID); err == nil {
+		t.Errorf("\n[TEST] Failed. Desc : delete\nGot nil\nExpected not found")
+	}
+}
+
+func TestCarStore_Transaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	mockCar := stores.NewMockCar(ctrl)
+	c := NewLRU(10)
+	s := CarStore(mockCar, c, time.Minute, Discard())
+
+	updated := car
+	updated.Price = 3500000
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	mockCar.EXPECT().GetByID(gomock.Any(), car.ID).Return(car, nil).Times(2)
+	mockCar.EXPECT().Update(gomock.Any(), &updated).Return(nil)
+
+	err = stores.NewTransactor(db).InTx(context.Background(), func(ctx context.Context) error {
+		// reads in the transaction go to the store without filling the cache
+		if _, err := s.GetByID(ctx, car.ID); err != nil {
+			return err
+		}
+
+		if _, found, _ := c.Get(ctx, "car:"+car.ID.String()); found {
+			t.Errorf("\n[TEST] Failed. Desc : read in transaction\nGot cached car\nExpected nothing cached")
+		}
+
+		if err := s.Update(ctx, &updated); err != nil {
+			return err
+		}
+
+		// a value read outside of the transaction before the commit is dropped once it is committed
+		_, err := s.GetByID(context.Background(), car.ID)
+
+		return err
+	})
+	if err != nil {
+		t.Fatalf("\n[TEST] Failed. Desc : transaction\nGot %v\nExpected nil", err)
+	}
+
+	if _, found, _ := c.Get(context.Background(), "car:"+car.ID.String()); found {
+		t.Errorf("\n[TEST] Failed. Desc : after commit\nGot cached car\nExpected it invalidated")
+	}
+}
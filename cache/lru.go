@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+type lru struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+	now     func() time.Time
+}
+
+// NewLRU returns a Cache keeping at most size values in process, evicting the least recently used one when full
+func NewLRU(size int) Cache {
+	return &lru{size: size, order: list.New(), entries: make(map[string]*list.Element), now: time.Now}
+}
+
+func (l *lru) Get(_ context.Context, key string) ([]byte, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	e := elem.Value.(*entry)
+
+	if !l.now().Before(e.expiresAt) {
+		l.remove(elem)
+
+		return nil, false, nil
+	}
+
+	l.order.MoveToFront(elem)
+
+	return e.value, true, nil
+}
+
+func (l *lru) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	expiresAt := l.now().Add(ttl)
+
+	if elem, ok := l.entries[key]; ok {
+		e := elem.Value.(*entry)
+		e.value = value
+		e.expiresAt = expiresAt
+		l.order.MoveToFront(elem)
+
+		return nil
+	}
+
+	l.entries[key] = l.order.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+
+	if l.order.Len() > l.size {
+		l.remove(l.order.Back())
+	}
+
+	return nil
+}
+
+func (l *lru) Delete(_ context.Context, keys ...string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		if elem, ok := l.entries[key]; ok {
+			l.remove(elem)
+		}
+	}
+
+	return nil
+}
+
+func (l *lru) remove(elem *list.Element) {
+	l.order.Remove(elem)
+	delete(l.entries, elem.Value.(*entry).key)
+}
@@ -0,0 +1,235 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"hash/fnv"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+// generationStripes is the number of invalidation counters the keys are spread over
+const generationStripes = 256
+
+// readThrough fills a cache from a store, concurrent misses of a key share a single call to the store.
+// Invalidations bump the generation of their key, so that a fill that started before one does not keep its value cached.
+// Keys share the generations of their stripe, which at worst drops the fill of a key invalidated alongside another.
+type readThrough struct {
+	cache       Cache
+	ttl         time.Duration
+	observer    Observer
+	component   string
+	group       *singleflight.Group
+	generations *[generationStripes]atomic.Uint64
+}
+
+func newReadThrough(c Cache, ttl time.Duration, observer Observer, component string) readThrough {
+	return readThrough{cache: c, ttl: ttl, observer: observer, component: component, group: &singleflight.Group{},
+		generations: &[generationStripes]atomic.Uint64{}}
+}
+
+// generation returns the invalidation counter of the key
+func (r readThrough) generation(key string) *atomic.Uint64 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	return &r.generations[h.Sum32()%generationStripes]
+}
+
+func (r readThrough) key(id uuid.UUID) string {
+	return r.component + ":" + id.String()
+}
+
+// get decodes the cached value of id into out, fetching it on a miss.
+// Errors of the cache are treated as misses so that an unavailable cache only costs the lookups.
+func (r readThrough) get(ctx context.Context, id uuid.UUID, fetch func(ctx context.Context) (interface{}, error), out interface{}) error {
+	key := r.key(id)
+
+	if b, ok, err := r.cache.Get(ctx, key); err == nil && ok && decode(b, out) == nil {
+		r.observer.ObserveCacheLookup(r.component, true)
+
+		return nil
+	}
+
+	r.observer.ObserveCacheLookup(r.component, false)
+
+	v, err, _ := r.group.Do(key, func() (interface{}, error) {
+		// the fetch is shared by every waiting reader, the first one going away does not cancel it for the others
+		ctx := context.WithoutCancel(ctx)
+		generation := r.generation(key)
+		started := generation.Load()
+
+		value, err := fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := encode(value)
+		if err != nil {
+			return nil, err
+		}
+
+		_ = r.cache.Set(ctx, key, b, r.ttl)
+
+		// an invalidation since the fetch may have deleted the key before the set, the value read is then dropped,
+		// while an invalidation after this check deletes the key after the set
+		if generation.Load() != started {
+			_ = r.cache.Delete(ctx, key)
+		}
+
+		return b, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return decode(v.([]byte), out)
+}
+
+// invalidate drops the cached value of id, once the transaction of ctx is committed when there is one so that
+// values read from the database before the commit do not linger
+func (r readThrough) invalidate(ctx context.Context, id uuid.UUID) {
+	key := r.key(id)
+	ctx = context.WithoutCancel(ctx)
+
+	stores.AfterCommit(ctx, func() {
+		r.generation(key).Add(1)
+		r.group.Forget(key)
+
+		_ = r.cache.Delete(ctx, key)
+	})
+}
+
+func encode(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func decode(b []byte, out interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(b)).Decode(out)
+}
+
+type carStore struct {
+	next stores.Car
+	read readThrough
+}
+
+// CarStore caches the cars fetched by id for ttl, reads made in a transaction go to the store
+func CarStore(next stores.Car, c Cache, ttl time.Duration, observer Observer) stores.Car {
+	return carStore{next: next, read: newReadThrough(c, ttl, observer, "car")}
+}
+
+func (s carStore) Create(ctx context.Context, car *models.Car) error {
+	return s.next.Create(ctx, car)
+}
+
+func (s carStore) GetAll(ctx context.Context, filter filters.Car) ([]models.Car, error) {
+	return s.next.GetAll(ctx, filter)
+}
+
+func (s carStore) GetByID(ctx context.Context, id uuid.UUID) (models.Car, error) {
+	if stores.InTransaction(ctx) {
+		return s.next.GetByID(ctx, id)
+	}
+
+	var car models.Car
+
+	err := s.read.get(ctx, id, func(ctx context.Context) (interface{}, error) {
+		return s.next.GetByID(ctx, id)
+	}, &car)
+
+	return car, err
+}
+
+func (s carStore) Update(ctx context.Context, car *models.Car) error {
+	if err := s.next.Update(ctx, car); err != nil {
+		return err
+	}
+
+	s.read.invalidate(ctx, car.ID)
+
+	return nil
+}
+
+func (s carStore) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.next.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.read.invalidate(ctx, id)
+
+	return nil
+}
+
+func (s carStore) Iterate(ctx context.Context, filter filters.Car) (stores.CarIterator, error) {
+	return s.next.Iterate(ctx, filter)
+}
+
+func (s carStore) Lock(ctx context.Context, id uuid.UUID) error {
+	return s.next.Lock(ctx, id)
+}
+
+func (s carStore) Stock(ctx context.Context) ([]models.Stock, error) {
+	return s.next.Stock(ctx)
+}
+
+type engineStore struct {
+	next stores.Engine
+	read readThrough
+}
+
+// EngineStore caches the engines fetched by id for ttl, reads made in a transaction go to the store
+func EngineStore(next stores.Engine, c Cache, ttl time.Duration, observer Observer) stores.Engine {
+	return engineStore{next: next, read: newReadThrough(c, ttl, observer, "engine")}
+}
+
+func (s engineStore) Create(ctx context.Context, engine *models.Engine) error {
+	return s.next.Create(ctx, engine)
+}
+
+func (s engineStore) GetByID(ctx context.Context, id uuid.UUID) (models.Engine, error) {
+	if stores.InTransaction(ctx) {
+		return s.next.GetByID(ctx, id)
+	}
+
+	var engine models.Engine
+
+	err := s.read.get(ctx, id, func(ctx context.Context) (interface{}, error) {
+		return s.next.GetByID(ctx, id)
+	}, &engine)
+
+	return engine, err
+}
+
+func (s engineStore) Update(ctx context.Context, engine *models.Engine) error {
+	if err := s.next.Update(ctx, engine); err != nil {
+		return err
+	}
+
+	s.read.invalidate(ctx, engine.ID)
+
+	return nil
+}
+
+func (s engineStore) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.next.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.read.invalidate(ctx, id)
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// Batch sends the operations to POST /car/batch, the error of a failed operation is mapped like the error of a request
+func (c *Client) Batch(ctx context.Context, ops []models.BatchOperation, opts models.BatchOptions) ([]models.BatchResult, error) {
+	query := url.Values{}
+
+	if opts.BestEffort {
+		query.Set("mode", "bestEffort")
+	}
+
+	var resp models.BatchResponse
+
+	if err := c.do(ctx, http.MethodPost, "/car/batch", query, models.BatchRequest{Operations: ops}, &resp,
+		http.StatusMultiStatus); err != nil {
+		return nil, err
+	}
+
+	results := make([]models.BatchResult, len(ops))
+
+	for _, item := range resp.Results {
+		if item.Index < 0 || item.Index >= len(results) {
+			continue
+		}
+
+		result := models.BatchResult{Op: item.Op, Car: item.Car}
+
+		if item.Error != nil {
+			result.Err = typedError(item.Status, *item.Error)
+		}
+
+		results[item.Index] = result
+	}
+
+	return results, nil
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+const entity = "car"
+
+// the client can stand in for the car service of the server
+var _ services.Car = (*Client)(nil)
+
+// Create adds the car along with its engine and returns it with the id given by the server
+func (c *Client) Create(ctx context.Context, car *models.Car) (*models.Car, error) {
+	var resp models.Car
+
+	if err := c.do(ctx, http.MethodPost, "/car", nil, car, &resp, http.StatusCreated); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// GetAll lists the cars matching the filter
+func (c *Client) GetAll(ctx context.Context, filter filters.Car) ([]models.Car, error) {
+	query := url.Values{}
+
+	if filter.Brand != "" {
+		query.Set("brand", filter.Brand)
+	}
+
+	if filter.Engine {
+		query.Set("engine", strconv.FormatBool(filter.Engine))
+	}
+
+	var resp []models.Car
+
+	if err := c.do(ctx, http.MethodGet, "/car", query, nil, &resp, http.StatusOK); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// GetByID fetches the car with its engine
+func (c *Client) GetByID(ctx context.Context, id uuid.UUID) (*models.Car, error) {
+	var resp models.Car
+
+	if err := c.do(ctx, http.MethodGet, "/car/"+id.String(), nil, nil, &resp, http.StatusOK); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Update replaces the car with the id of car
+func (c *Client) Update(ctx context.Context, car *models.Car) (*models.Car, error) {
+	var resp models.Car
+
+	if err := c.do(ctx, http.MethodPut, "/car/"+car.ID.String(), nil, car, &resp, http.StatusOK); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Delete removes the car and its engine
+func (c *Client) Delete(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, "/car/"+id.String(), nil, nil, nil, http.StatusNoContent)
+}
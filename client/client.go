@@ -0,0 +1,252 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRetries = 3
+	defaultBackoff = 200 * time.Millisecond
+	maxBackoff     = 10 * time.Second
+	defaultTimeout = 30 * time.Second
+)
+
+// Client calls the car dealership API
+type Client struct {
+	baseURL    *url.URL
+	apiKey     string
+	token      string
+	httpClient *http.Client
+	retries    int
+	backoff    time.Duration
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithAPIKey authenticates the requests with the Api-Key header
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithToken authenticates the requests with an OIDC bearer token
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithHTTPClient replaces the http client, by default requests time out after 30 seconds
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetries sets how many times a failed request is retried and the first wait between attempts,
+// the wait doubles on every attempt
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.backoff = backoff
+	}
+}
+
+// New returns a client of the API served at baseURL, e.g. http://127.0.0.1:8000
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("base url %q must be http or https", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		retries:    defaultRetries,
+		backoff:    defaultBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// request is a call to the api, body is sent with contentType
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	body        []byte
+	contentType string
+}
+
+// do sends in as json and decodes the json response into out when the server answers with an expected status
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out interface{}, expected ...int) error {
+	req := request{method: method, path: path, query: query}
+
+	if in != nil {
+		var err error
+
+		if req.body, err = json.Marshal(in); err != nil {
+			return err
+		}
+
+		req.contentType = "application/json"
+	}
+
+	return c.roundTrip(ctx, req, out, expected...)
+}
+
+// roundTrip sends the request and decodes the response into out when the server answers with an expected status.
+// Requests rejected by the rate limiter are retried as they were not processed, other failures only when the
+// method is idempotent.
+func (c *Client) roundTrip(ctx context.Context, req request, out interface{}, expected ...int) error {
+	u := *c.baseURL
+	u.Path += req.path
+	u.RawQuery = req.query.Encode()
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, req.method, u.String(), req.body, req.contentType)
+
+		var wait time.Duration
+
+		switch {
+		case err != nil:
+			if ctx.Err() != nil || !idempotent(req.method) || attempt >= c.retries {
+				return err
+			}
+		case isExpected(resp.StatusCode, expected):
+			return decode(resp, out)
+		case retryable(req.method, resp.StatusCode) && attempt < c.retries:
+			wait = retryAfter(resp)
+			drain(resp)
+		default:
+			return decodeError(resp)
+		}
+
+		if err := c.sleep(ctx, attempt, wait); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, u string, body []byte, contentType string) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	if c.apiKey != "" {
+		req.Header.Set("Api-Key", c.apiKey)
+	}
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	return c.httpClient.Do(req)
+}
+
+// sleep waits for the backoff of the attempt, or longer when the server asked for it
+func (c *Client) sleep(ctx context.Context, attempt int, atLeast time.Duration) error {
+	wait := c.backoff << attempt
+	if wait > maxBackoff || wait <= 0 {
+		wait = maxBackoff
+	}
+
+	if atLeast > wait {
+		wait = atLeast
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func isExpected(statusCode int, expected []int) bool {
+	for _, e := range expected {
+		if statusCode == e {
+			return true
+		}
+	}
+
+	return false
+}
+
+func idempotent(method string) bool {
+	return method != http.MethodPost
+}
+
+func retryable(method string, statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent(method)
+	default:
+		return false
+	}
+}
+
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+func decode(resp *http.Response, out interface{}) error {
+	defer drain(resp)
+
+	switch out := out.(type) {
+	case nil:
+		return nil
+	case func(io.Reader) error:
+		// streamed responses are read by the caller
+		return out(resp.Body)
+	default:
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+}
+
+// drain reads the rest of the body so that the connection is reused
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
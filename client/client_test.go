@@ -0,0 +1,334 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/types"
+)
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	id  = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+	car = models.Car{ID: id, Model: "X", ManufactureYear: 2020, Brand: "Tesla", FuelType: types.Electric, Price: 100,
+		Engine: models.Engine{Range: 500}}
+)
+
+func newClient(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c, err := New(server.URL, WithAPIKey("key"), WithRetries(2, time.Millisecond))
+	if err != nil {
+		t.Fatalf("error %s was not expected when creating the client", err)
+	}
+
+	return c
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func TestClient_Requests(t *testing.T) {
+	var got []string
+
+	c := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("Api-Key"))
+
+		switch r.Method {
+		case http.MethodPost:
+			var body models.Car
+
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.FuelType != types.Electric {
+				w.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			writeJSON(w, http.StatusCreated, car)
+		case http.MethodGet:
+			if r.URL.Path == "/car" {
+				writeJSON(w, http.StatusOK, []models.Car{car})
+
+				return
+			}
+
+			writeJSON(w, http.StatusOK, car)
+		case http.MethodPut:
+			writeJSON(w, http.StatusOK, car)
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	ctx := context.Background()
+
+	created, err := c.Create(ctx, &car)
+	if err != nil || !reflect.DeepEqual(*created, car) {
+		t.Errorf("\n[TEST] Failed. Desc : create\nGot %v %v\nExpected %v", created, err, car)
+	}
+
+	cars, err := c.GetAll(ctx, filters.Car{Brand: "Tesla", Engine: true})
+	if err != nil || !reflect.DeepEqual(cars, []models.Car{car}) {
+		t.Errorf("\n[TEST] Failed. Desc : get all\nGot %v %v\nExpected %v", cars, err, []models.Car{car})
+	}
+
+	if _, err := c.GetByID(ctx, id); err != nil {
+		t.Errorf("\n[TEST] Failed. Desc : get by id\nGot %v\nExpected nil", err)
+	}
+
+	if _, err := c.Update(ctx, &car); err != nil {
+		t.Errorf("\n[TEST] Failed. Desc : update\nGot %v\nExpected nil", err)
+	}
+
+	if err := c.Delete(ctx, id); err != nil {
+		t.Errorf("\n[TEST] Failed. Desc : delete\nGot %v\nExpected nil", err)
+	}
+
+	expected := []string{
+		"POST /car key",
+		"GET /car?brand=Tesla&engine=true key",
+		"GET /car/" + id.String() + " key",
+		"PUT /car/" + id.String() + " key",
+		"DELETE /car/" + id.String() + " key",
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("\n[TEST] Failed. Desc : requests\nGot %v\nExpected %v", got, expected)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	cases := []struct {
+		desc       string
+		statusCode int
+		body       models.Error
+		err        error
+	}{
+		{"invalid parameters", http.StatusBadRequest, models.Error{Code: "invalid_parameter", Message: "parameters model, brand are invalid"},
+			errors.InvalidParam{Param: []string{"model", "brand"}}},
+		{"invalid parameter", http.StatusBadRequest, models.Error{Code: "invalid_parameter", Message: "parameter id is invalid"},
+			errors.InvalidParam{Param: []string{"id"}}},
+		{"missing parameter", http.StatusBadRequest, models.Error{Code: "invalid_parameter", Message: "parameter body is missing"},
+			errors.MissingParam{Param: "body"}},
+		{"not found", http.StatusNotFound, models.Error{Code: "not_found", Message: "entity car with id " + id.String() + " not found"},
+			errors.EntityNotFound{Entity: "car", ID: id.String()}},
+		{"unauthenticated", http.StatusUnauthorized,
+			models.Error{Code: "unauthenticated", Message: "request is not authenticated: credentials are missing"},
+			errors.Unauthenticated{Reason: "credentials are missing"}},
+		{"conflict", http.StatusConflict, models.Error{Code: "conflict", Message: "entity car with id " + id.String() + " is already on order"},
+			errors.Conflict{Entity: "car", ID: id.String(), Reason: "is already on order"}},
+		{"conflict of an entity with spaces", http.StatusConflict,
+			models.Error{Code: "conflict", Message: "entity test drive with id " + id.String() + " is completed"},
+			errors.Conflict{Entity: "test drive", ID: id.String(), Reason: "is completed"}},
+		{"forbidden", http.StatusForbidden, models.Error{Code: "forbidden", Message: "permission cars:delete is required"},
+			errors.Forbidden{Permission: "cars:delete"}},
+		{"internal error", http.StatusInternalServerError,
+			models.Error{Code: "internal_error", Message: "internal server error", RequestID: "r1"},
+			&Error{StatusCode: http.StatusInternalServerError, Code: "internal_error", Message: "internal server error", RequestID: "r1"}},
+	}
+
+	for i, tc := range cases {
+		c := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, tc.statusCode, tc.body)
+		})
+
+		err := c.Delete(context.Background(), id)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	cases := []struct {
+		desc       string
+		method     string
+		statusCode int
+		attempts   int32
+		fail       bool
+	}{
+		{"rate limited get is retried", http.MethodGet, http.StatusTooManyRequests, 3, true},
+		{"rate limited post is retried", http.MethodPost, http.StatusTooManyRequests, 3, true},
+		{"unavailable get is retried", http.MethodGet, http.StatusServiceUnavailable, 3, true},
+		{"unavailable post is not retried", http.MethodPost, http.StatusServiceUnavailable, 1, true},
+		{"internal error is not retried", http.MethodGet, http.StatusInternalServerError, 1, true},
+		{"recovers after a failure", http.MethodGet, http.StatusBadGateway, 2, false},
+	}
+
+	for i, tc := range cases {
+		var attempts int32
+
+		c := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&attempts, 1) == 2 && !tc.fail {
+				writeJSON(w, http.StatusOK, car)
+
+				return
+			}
+
+			writeJSON(w, tc.statusCode, models.Error{Code: "unavailable"})
+		})
+
+		var err error
+
+		if tc.method == http.MethodPost {
+			_, err = c.Create(context.Background(), &car)
+		} else {
+			_, err = c.GetByID(context.Background(), id)
+		}
+
+		if attempts != tc.attempts || (err != nil) != tc.fail {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v attempts, %v\nExpected %v attempts", i, tc.desc, attempts, err, tc.attempts)
+		}
+	}
+}
+
+func TestClient_RetryCancelled(t *testing.T) {
+	c := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.GetAll(ctx, filters.Car{}); err != context.DeadlineExceeded {
+		t.Errorf("\n[TEST] Failed. Desc : cancelled while waiting\nGot %v\nExpected %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestClient_Import(t *testing.T) {
+	var query string
+
+	c := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+
+		var lines int
+
+		for decoder := json.NewDecoder(r.Body); decoder.More(); lines++ {
+			_ = decoder.Decode(&models.Car{})
+		}
+
+		if r.Header.Get("Content-Type") != "application/x-ndjson" || lines != 2 {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		// the server rejects the second car it received
+		writeJSON(w, http.StatusUnprocessableEntity, models.ImportResult{Total: 2, Failed: 1,
+			Errors: []models.RowError{{Row: 2, Code: "already_exists", Message: "car already exists"}}})
+	})
+
+	rows := []models.ImportRow{{Row: 1, Car: car}, {Row: 2, Err: errors.InvalidParam{Param: []string{"price"}}}, {Row: 3, Car: car}}
+
+	result, err := c.Import(context.Background(), rows, models.ImportOptions{})
+
+	expected := &models.ImportResult{Total: 3, Failed: 2, Errors: []models.RowError{
+		{Row: 2, Code: "invalid_parameter", Message: "parameter price is invalid"},
+		{Row: 3, Code: "already_exists", Message: "car already exists"},
+	}}
+
+	if err != nil || !reflect.DeepEqual(result, expected) {
+		t.Errorf("\n[TEST] Failed. Desc : import\nGot %+v %v\nExpected %+v", result, err, expected)
+	}
+
+	// the server only validates since the unreadable row keeps every row from being imported
+	if query != "dryRun=true" {
+		t.Errorf("\n[TEST] Failed. Desc : import query\nGot %v\nExpected %v", query, "dryRun=true")
+	}
+}
+
+func TestClient_Export(t *testing.T) {
+	c := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/car/export" || r.URL.RawQuery != "brand=tesla&format=ndjson" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+
+		_ = json.NewEncoder(w).Encode(car)
+		_ = json.NewEncoder(w).Encode(car)
+	})
+
+	cars := make([]models.Car, 0)
+
+	err := c.Export(context.Background(), filters.Car{Brand: "tesla"}, func(c *models.Car) error {
+		cars = append(cars, *c)
+
+		return nil
+	})
+
+	if err != nil || !reflect.DeepEqual(cars, []models.Car{car, car}) {
+		t.Errorf("\n[TEST] Failed. Desc : export\nGot %v %v\nExpected %v", cars, err, []models.Car{car, car})
+	}
+}
+
+func TestClient_Batch(t *testing.T) {
+	c := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body models.BatchRequest
+
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Operations) != 2 || r.URL.RawQuery != "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		writeJSON(w, http.StatusMultiStatus, models.BatchResponse{Atomic: true, Failed: 2, Results: []models.BatchItem{
+			{Index: 0, Op: "create", Status: http.StatusFailedDependency,
+				Error: &models.Error{Code: "aborted", Message: "operation was not applied since operation 1 failed"}},
+			{Index: 1, Op: "delete", Status: http.StatusNotFound,
+				Error: &models.Error{Code: "not_found", Message: "entity car with id 1 not found"}},
+		}})
+	})
+
+	ops := []models.BatchOperation{{Op: "create", Car: &car}, {Op: "delete", ID: id}}
+
+	results, err := c.Batch(context.Background(), ops, models.BatchOptions{})
+
+	expected := []models.BatchResult{
+		{Op: "create", Err: errors.Aborted{Index: 1}},
+		{Op: "delete", Err: errors.EntityNotFound{Entity: "car", ID: "1"}},
+	}
+
+	if err != nil || !reflect.DeepEqual(results, expected) {
+		t.Errorf("\n[TEST] Failed. Desc : batch\nGot %+v %v\nExpected %+v", results, err, expected)
+	}
+}
+
+func TestNew(t *testing.T) {
+	cases := []struct {
+		desc    string
+		baseURL string
+		err     bool
+	}{
+		{"http", "http://127.0.0.1:8000/", false},
+		{"https", "https://cars.example.com", false},
+		{"missing scheme", "127.0.0.1:8000", true},
+	}
+
+	for i, tc := range cases {
+		_, err := New(tc.baseURL)
+
+		if (err != nil) != tc.err {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected error %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
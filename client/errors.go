@@ -0,0 +1,116 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// nolint:gochecknoglobals // compiled once
+var (
+	notFoundMessage = regexp.MustCompile(`^entity (.+) with id (\S+) not found$`)
+	conflictMessage = regexp.MustCompile(`^entity (.+?) with id (\S+) (.+)$`)
+)
+
+// Error is returned for error responses that have no counterpart in the errors package
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+	RequestID  string
+}
+
+func (e *Error) Error() string {
+	if e.RequestID == "" {
+		return fmt.Sprintf("%d %s: %s", e.StatusCode, e.Code, e.Message)
+	}
+
+	return fmt.Sprintf("%d %s: %s (request %s)", e.StatusCode, e.Code, e.Message, e.RequestID)
+}
+
+// decodeError maps the error body of the response back to the error the server started from
+func decodeError(resp *http.Response) error {
+	defer drain(resp)
+
+	var body models.Error
+
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Code == "" {
+		return &Error{StatusCode: resp.StatusCode, Code: "unknown", Message: http.StatusText(resp.StatusCode)}
+	}
+
+	return typedError(resp.StatusCode, body)
+}
+
+// typedError maps an error body back to the error the server started from
+func typedError(statusCode int, body models.Error) error {
+	switch body.Code {
+	case "invalid_parameter":
+		return invalidParam(body.Message)
+	case "not_found":
+		return notFound(body.Message)
+	case "already_exists":
+		return errors.EntityAlreadyExists{Entity: entity}
+	case "conflict":
+		return conflict(body.Message)
+	case "unauthenticated":
+		return errors.Unauthenticated{Reason: strings.TrimPrefix(body.Message, "request is not authenticated: ")}
+	case "forbidden":
+		var permission string
+
+		_, _ = fmt.Sscanf(body.Message, "permission %s is required", &permission)
+
+		return errors.Forbidden{Permission: permission}
+	case "aborted":
+		var index int
+
+		_, _ = fmt.Sscanf(body.Message, "operation was not applied since operation %d failed", &index)
+
+		return errors.Aborted{Index: index}
+	default:
+		return &Error{StatusCode: statusCode, Code: body.Code, Message: body.Message, RequestID: body.RequestID}
+	}
+}
+
+// invalidParam parses the messages of errors.MissingParam and errors.InvalidParam
+func invalidParam(message string) error {
+	var param string
+
+	if _, err := fmt.Sscanf(message, "parameter %s is missing", &param); err == nil {
+		return errors.MissingParam{Param: param}
+	}
+
+	if _, err := fmt.Sscanf(message, "parameter %s is invalid", &param); err == nil {
+		return errors.InvalidParam{Param: []string{param}}
+	}
+
+	if params := strings.TrimPrefix(message, "parameters "); params != message && strings.HasSuffix(params, " are invalid") {
+		return errors.InvalidParam{Param: strings.Split(strings.TrimSuffix(params, " are invalid"), ", ")}
+	}
+
+	return errors.InvalidParam{}
+}
+
+// notFound parses the message of errors.EntityNotFound
+func notFound(message string) error {
+	match := notFoundMessage.FindStringSubmatch(message)
+	if match == nil {
+		return errors.EntityNotFound{Entity: entity}
+	}
+
+	return errors.EntityNotFound{Entity: match[1], ID: match[2]}
+}
+
+// conflict parses the message of errors.Conflict
+func conflict(message string) error {
+	match := conflictMessage.FindStringSubmatch(message)
+	if match == nil {
+		return errors.Conflict{Entity: entity, Reason: message}
+	}
+
+	return errors.Conflict{Entity: match[1], ID: match[2], Reason: match[3]}
+}
@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+	goError "errors"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/amehrotra/car-dealership/codec"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// Export streams GET /car/export as ndjson and calls fn with every car as it arrives
+func (c *Client) Export(ctx context.Context, filter filters.Car, fn func(car *models.Car) error) error {
+	query := url.Values{"format": {"ndjson"}}
+
+	if filter.Brand != "" {
+		query.Set("brand", filter.Brand)
+	}
+
+	read := func(body io.Reader) error {
+		decoder := codec.NewNDJSONDecoder(body)
+
+		for {
+			var car models.Car
+
+			err := decoder.Decode(&car)
+			if goError.Is(err, io.EOF) {
+				return nil
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if err := fn(&car); err != nil {
+				return err
+			}
+		}
+	}
+
+	return c.roundTrip(ctx, request{method: http.MethodGet, path: "/car/export", query: query}, read, http.StatusOK)
+}
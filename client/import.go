@@ -0,0 +1,72 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/amehrotra/car-dealership/codec"
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// Import sends the readable rows to POST /car/import as ndjson and reports the outcome against the numbers of the given rows.
+// Rows that could not be read locally fail as invalid, without best effort they keep the server from importing the others.
+func (c *Client) Import(ctx context.Context, rows []models.ImportRow, opts models.ImportOptions) (*models.ImportResult, error) {
+	var (
+		body    bytes.Buffer
+		sent    = make([]int, 0, len(rows))
+		invalid = make([]models.RowError, 0)
+		encoder = codec.NewNDJSONEncoder(&body)
+	)
+
+	for i := range rows {
+		if rows[i].Err != nil {
+			invalid = append(invalid, models.RowError{Row: rows[i].Row, Code: "invalid_parameter", Message: rows[i].Err.Error()})
+
+			continue
+		}
+
+		if err := encoder.Encode(&rows[i].Car); err != nil {
+			return nil, err
+		}
+
+		sent = append(sent, rows[i].Row)
+	}
+
+	query := url.Values{}
+
+	// the server only validates when rows already failed and nothing may be imported
+	if opts.DryRun || (len(invalid) > 0 && !opts.BestEffort) {
+		query.Set("dryRun", "true")
+	}
+
+	if opts.BestEffort {
+		query.Set("mode", "bestEffort")
+	}
+
+	var result models.ImportResult
+
+	req := request{method: http.MethodPost, path: "/car/import", query: query, body: body.Bytes(), contentType: codec.NDJSON}
+
+	if err := c.roundTrip(ctx, req, &result, http.StatusOK, http.StatusUnprocessableEntity); err != nil {
+		return nil, err
+	}
+
+	for i := range result.Errors {
+		if n := result.Errors[i].Row; n >= 1 && n <= len(sent) {
+			result.Errors[i].Row = sent[n-1]
+		}
+	}
+
+	if len(invalid) > 0 && !opts.BestEffort && !opts.DryRun {
+		result.Imported = 0
+	}
+
+	result.Total = len(rows)
+	result.Failed += len(invalid)
+	result.Errors = append(invalid, result.Errors...)
+	result.DryRun = opts.DryRun
+
+	return &result, nil
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/types"
+)
+
+// fuels are the names of types.Fuel in the order of the constants
+func fuels() []string {
+	names := make([]string, 0)
+	for f := types.Fuel(0); f.String() != ""; f++ {
+		names = append(names, f.String())
+	}
+
+	return names
+}
+
+func parseFuel(s string) (types.Fuel, error) {
+	var f types.Fuel
+
+	if err := f.UnmarshalJSON([]byte(strconv.Quote(s))); err != nil {
+		return f, fmt.Errorf("fuel %q is not one of %v", s, fuels())
+	}
+
+	return f, nil
+}
+
+func parseID(s string) (uuid.UUID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("id %q is not a uuid", s)
+	}
+
+	return id, nil
+}
+
+func (a *app) listCommand() *cobra.Command {
+	var filter filters.Car
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the cars in stock",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+
+			cars, err := c.GetAll(cmd.Context(), filter)
+			if err != nil {
+				return err
+			}
+
+			return a.printCars(cars, filter.Engine)
+		},
+	}
+
+	cmd.Flags().StringVar(&filter.Brand, "brand", "", "only cars of the brand")
+	cmd.Flags().BoolVar(&filter.Engine, "engine", false, "include the engines")
+
+	return cmd
+}
+
+func (a *app) getCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get ID",
+		Short: "Show a car with its engine",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+
+			car, err := c.GetByID(cmd.Context(), id)
+			if err != nil {
+				return err
+			}
+
+			return a.printCar(car)
+		},
+	}
+}
+
+// carFlags are the fields of a car that can be given on the command line
+type carFlags struct {
+	file string
+	car  models.Car
+	fuel string
+	cmd  *cobra.Command
+}
+
+func addCarFlags(cmd *cobra.Command) *carFlags {
+	f := &carFlags{cmd: cmd}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&f.file, "file", "f", "", "read the car as json from the file, - for stdin, flags override its fields")
+	flags.StringVar(&f.car.Model, "model", "", "model")
+	flags.IntVar(&f.car.ManufactureYear, "year", 0, "year of manufacture")
+	flags.StringVar(&f.car.Brand, "brand", "", "brand")
+	flags.StringVar(&f.fuel, "fuel", "", "fuel type: "+fmt.Sprint(fuels()))
+	flags.Int64Var(&f.car.Price, "price", 0, "price in the smallest unit of the currency")
+	flags.IntVar(&f.car.Engine.Displacement, "displacement", 0, "engine displacement")
+	flags.IntVar(&f.car.Engine.NCylinder, "cylinders", 0, "number of cylinders")
+	flags.IntVar(&f.car.Engine.Range, "range", 0, "range of electric engines")
+
+	_ = cmd.RegisterFlagCompletionFunc("fuel", fixedCompletion(fuels()...))
+
+	return f
+}
+
+// apply reads the file when one is given and overrides the fields of car with the flags set
+func (f *carFlags) apply(car *models.Car, in io.Reader) error {
+	if f.file != "" {
+		r := in
+
+		if f.file != "-" {
+			file, err := os.Open(f.file)
+			if err != nil {
+				return err
+			}
+
+			defer file.Close()
+
+			r = file
+		}
+
+		if err := json.NewDecoder(r).Decode(car); err != nil {
+			return fmt.Errorf("reading car: %w", err)
+		}
+	}
+
+	changed := f.cmd.Flags().Changed
+
+	if changed("model") {
+		car.Model = f.car.Model
+	}
+
+	if changed("year") {
+		car.ManufactureYear = f.car.ManufactureYear
+	}
+
+	if changed("brand") {
+		car.Brand = f.car.Brand
+	}
+
+	if changed("fuel") {
+		fuel, err := parseFuel(f.fuel)
+		if err != nil {
+			return err
+		}
+
+		car.FuelType = fuel
+	}
+
+	if changed("price") {
+		car.Price = f.car.Price
+	}
+
+	if changed("displacement") {
+		car.Engine.Displacement = f.car.Engine.Displacement
+	}
+
+	if changed("cylinders") {
+		car.Engine.NCylinder = f.car.Engine.NCylinder
+	}
+
+	if changed("range") {
+		car.Engine.Range = f.car.Engine.Range
+	}
+
+	return nil
+}
+
+func (a *app) createCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Add a car from a json file or flags",
+		Args:  cobra.NoArgs,
+	}
+
+	flags := addCarFlags(cmd)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var car models.Car
+
+		if err := flags.apply(&car, cmd.InOrStdin()); err != nil {
+			return err
+		}
+
+		c, err := a.client()
+		if err != nil {
+			return err
+		}
+
+		created, err := c.Create(cmd.Context(), &car)
+		if err != nil {
+			return err
+		}
+
+		return a.printCar(created)
+	}
+
+	return cmd
+}
+
+func (a *app) updateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update ID",
+		Short: "Change the fields of a car given by a json file or flags, the others are kept",
+		Args:  cobra.ExactArgs(1),
+	}
+
+	flags := addCarFlags(cmd)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		id, err := parseID(args[0])
+		if err != nil {
+			return err
+		}
+
+		c, err := a.client()
+		if err != nil {
+			return err
+		}
+
+		car, err := c.GetByID(cmd.Context(), id)
+		if err != nil {
+			return err
+		}
+
+		if err := flags.apply(car, cmd.InOrStdin()); err != nil {
+			return err
+		}
+
+		car.ID = id
+
+		updated, err := c.Update(cmd.Context(), car)
+		if err != nil {
+			return err
+		}
+
+		return a.printCar(updated)
+	}
+
+	return cmd
+}
+
+func (a *app) deleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:     "delete ID...",
+		Aliases: []string{"rm"},
+		Short:   "Remove cars and their engines",
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+
+			for _, arg := range args {
+				id, err := parseID(arg)
+				if err != nil {
+					return err
+				}
+
+				if err := c.Delete(cmd.Context(), id); err != nil {
+					return fmt.Errorf("deleting %s: %w", id, err)
+				}
+
+				fmt.Fprintln(cmd.ErrOrStderr(), "deleted", id)
+			}
+
+			return nil
+		},
+	}
+}
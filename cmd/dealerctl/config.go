@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const defaultProfile = "default"
+
+// config is the file holding the profiles, api keys are stored in it so it is only readable by its owner
+type config struct {
+	Current  string             `yaml:"current,omitempty"`
+	Profiles map[string]profile `yaml:"profiles,omitempty"`
+}
+
+type profile struct {
+	Server string `yaml:"server,omitempty"`
+	APIKey string `yaml:"apiKey,omitempty"`
+}
+
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ".dealerctl.yaml"
+	}
+
+	return filepath.Join(dir, "dealerctl", "config.yaml")
+}
+
+// loadConfig reads the config file, a missing file is an empty config
+func loadConfig(path string) (*config, error) {
+	cfg := &config{Profiles: make(map[string]profile)}
+
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(b, cfg); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	if cfg.Profiles == nil {
+		cfg.Profiles = make(map[string]profile)
+	}
+
+	return cfg, nil
+}
+
+// save replaces the file with one only readable by its owner, whatever the mode of the file it replaces
+func (c *config) save(path string) error {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	// the temporary file is created with 0600 and renamed over the config so that it is never left half written
+	f, err := os.CreateTemp(filepath.Dir(path), ".dealerctl-*.yaml")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// selected returns the named profile, or the current one when name is empty.
+// Without any profile an empty one is used so that flags and the environment suffice.
+func (c *config) selected(name string) (profile, error) {
+	if name == "" {
+		name = firstOf(c.Current, defaultProfile)
+	}
+
+	p, ok := c.Profiles[name]
+	if !ok && (name != defaultProfile || len(c.Profiles) > 0) {
+		return profile{}, fmt.Errorf("profile %q does not exist", name)
+	}
+
+	return p, nil
+}
+
+func (c *config) names() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func (a *app) configCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the profiles holding the server url and api key",
+	}
+
+	var p profile
+
+	set := &cobra.Command{
+		Use:   "set NAME",
+		Short: "Create or change a profile, the first profile becomes the current one",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(a.configPath)
+			if err != nil {
+				return err
+			}
+
+			existing := cfg.Profiles[args[0]]
+
+			if cmd.Flags().Changed("server") {
+				existing.Server = p.Server
+			}
+
+			if cmd.Flags().Changed("api-key") {
+				existing.APIKey = p.APIKey
+			}
+
+			cfg.Profiles[args[0]] = existing
+
+			if cfg.Current == "" {
+				cfg.Current = args[0]
+			}
+
+			return cfg.save(a.configPath)
+		},
+	}
+
+	// the profile values shadow the global overrides of the same name on this command
+	set.Flags().StringVar(&p.Server, "server", "", "url of the server")
+	set.Flags().StringVar(&p.APIKey, "api-key", "", "api key")
+
+	use := &cobra.Command{
+		Use:               "use NAME",
+		Short:             "Make a profile the current one",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: a.completeProfiles,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(a.configPath)
+			if err != nil {
+				return err
+			}
+
+			if _, ok := cfg.Profiles[args[0]]; !ok {
+				return fmt.Errorf("profile %q does not exist", args[0])
+			}
+
+			cfg.Current = args[0]
+
+			return cfg.save(a.configPath)
+		},
+	}
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List the profiles, api keys are not shown",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(a.configPath)
+			if err != nil {
+				return err
+			}
+
+			rows := make([]profileRow, 0, len(cfg.Profiles))
+			for _, name := range cfg.names() {
+				rows = append(rows, profileRow{Name: name, Server: cfg.Profiles[name].Server, Current: name == cfg.Current,
+					HasAPIKey: cfg.Profiles[name].APIKey != ""})
+			}
+
+			return a.printProfiles(rows)
+		},
+	}
+
+	cmd.AddCommand(set, use, list)
+
+	return cmd
+}
+
+func (a *app) completeProfiles(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := loadConfig(a.configPath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	return cfg.names(), cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,12 @@
+// Command dealerctl manages the inventory of the car dealership through its HTTP API.
+package main
+
+import (
+	"os"
+)
+
+func main() {
+	if err := newRootCommand(os.Stdout, os.Stderr).Execute(); err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/types"
+)
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var id = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+
+// fakeServer serves the car routes from memory and records the requests
+type fakeServer struct {
+	cars     map[uuid.UUID]models.Car
+	requests []string
+}
+
+func (s *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests = append(s.requests, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("Api-Key"))
+
+	write := func(statusCode int, data interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		_ = json.NewEncoder(w).Encode(data)
+	}
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/car/import":
+		s.importCars(w, r, write)
+	case r.Method == http.MethodGet && r.URL.Path == "/car/export":
+		w.Header().Set("Content-Type", "application/x-ndjson")
+
+		for _, car := range s.cars {
+			_ = json.NewEncoder(w).Encode(car)
+		}
+	case r.Method == http.MethodGet && r.URL.Path == "/car":
+		cars := make([]models.Car, 0)
+		for _, car := range s.cars {
+			cars = append(cars, car)
+		}
+
+		write(http.StatusOK, cars)
+	case r.Method == http.MethodPost:
+		var car models.Car
+
+		_ = json.NewDecoder(r.Body).Decode(&car)
+
+		if car.Model == "" {
+			write(http.StatusBadRequest, models.Error{Code: "invalid_parameter", Message: "parameter model is invalid"})
+
+			return
+		}
+
+		car.ID = uuid.New()
+		s.cars[car.ID] = car
+		write(http.StatusCreated, car)
+	case r.Method == http.MethodGet:
+		write(http.StatusOK, s.cars[id])
+	case r.Method == http.MethodPut:
+		var car models.Car
+
+		_ = json.NewDecoder(r.Body).Decode(&car)
+		s.cars[id] = car
+		write(http.StatusOK, car)
+	case r.Method == http.MethodDelete:
+		delete(s.cars, id)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// importCars adds the ndjson cars having a model unless the import is a dry run
+func (s *fakeServer) importCars(w http.ResponseWriter, r *http.Request, write func(int, interface{})) {
+	result := models.ImportResult{DryRun: r.URL.Query().Get("dryRun") == "true", Errors: make([]models.RowError, 0)}
+	decoder := json.NewDecoder(r.Body)
+
+	for row := 1; decoder.More(); row++ {
+		var car models.Car
+
+		_ = decoder.Decode(&car)
+		result.Total++
+
+		if car.Model == "" {
+			result.Failed++
+			result.Errors = append(result.Errors, models.RowError{Row: row, Code: "invalid_parameter", Message: "parameter model is invalid"})
+
+			continue
+		}
+
+		result.Imported++
+
+		if !result.DryRun {
+			car.ID = uuid.New()
+			s.cars[car.ID] = car
+		}
+	}
+
+	write(http.StatusOK, result)
+}
+
+func run(t *testing.T, args ...string) (string, string, error) {
+	var out, errOut bytes.Buffer
+
+	cmd := newRootCommand(&out, &errOut)
+	cmd.SetArgs(args)
+
+	err := cmd.Execute()
+
+	return out.String(), errOut.String(), err
+}
+
+func setup(t *testing.T) (*fakeServer, []string) {
+	fake := &fakeServer{cars: map[uuid.UUID]models.Car{id: {ID: id, Model: "Roadster", ManufactureYear: 2020, Brand: "Tesla",
+		FuelType: types.Electric, Price: 100, Engine: models.Engine{Range: 600}}}}
+
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	config := filepath.Join(t.TempDir(), "config.yaml")
+
+	if _, _, err := run(t, "--config", config, "config", "set", "local", "--server", server.URL, "--api-key", "key"); err != nil {
+		t.Fatalf("error %s was not expected when setting the profile", err)
+	}
+
+	return fake, []string{"--config", config}
+}
+
+func TestList(t *testing.T) {
+	cases := []struct {
+		desc     string
+		args     []string
+		contains string
+	}{
+		{"table", []string{"list", "--engine"}, "Roadster  2020  electric  100    0             0          600"},
+		{"json", []string{"list", "-o", "json"}, `"fuelType": "electric"`},
+		{"yaml", []string{"list", "-o", "yaml"}, "yearOfManufacture: 2020"},
+		{"get", []string{"get", id.String(), "-o", "yaml"}, "range: 600"},
+	}
+
+	for i, tc := range cases {
+		_, global := setup(t)
+
+		out, _, err := run(t, append(global, tc.args...)...)
+
+		if err != nil || !strings.Contains(out, tc.contains) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\n%s\nExpected output containing %q", i, tc.desc, err, out, tc.contains)
+		}
+	}
+}
+
+func TestCreateUpdateDelete(t *testing.T) {
+	fake, global := setup(t)
+
+	_, _, err := run(t, append(global, "create", "--model", "911", "--year", "2021", "--brand", "Porsche", "--fuel", "Petrol",
+		"--displacement", "3000", "--cylinders", "6")...)
+	if err != nil || len(fake.cars) != 2 {
+		t.Errorf("\n[TEST] Failed. Desc : create\nGot %v %v cars\nExpected 2 cars", err, len(fake.cars))
+	}
+
+	if _, _, err := run(t, append(global, "update", id.String(), "--price", "250")...); err != nil ||
+		fake.cars[id].Price != 250 || fake.cars[id].Model != "Roadster" {
+		t.Errorf("\n[TEST] Failed. Desc : update keeps other fields\nGot %v %+v\nExpected price 250", err, fake.cars[id])
+	}
+
+	if _, _, err := run(t, append(global, "create", "--fuel", "hydrogen")...); err == nil {
+		t.Errorf("\n[TEST] Failed. Desc : invalid fuel\nGot %v\nExpected error", err)
+	}
+
+	if _, _, err := run(t, append(global, "delete", id.String())...); err != nil || len(fake.cars) != 1 {
+		t.Errorf("\n[TEST] Failed. Desc : delete\nGot %v %v cars\nExpected 1 car", err, len(fake.cars))
+	}
+
+	expected := "GET /car/" + id.String() + " key"
+	if fake.requests[1] != expected {
+		t.Errorf("\n[TEST] Failed. Desc : update reads the car\nGot %v\nExpected %v", fake.requests[1], expected)
+	}
+}
+
+func TestImportExport(t *testing.T) {
+	fake, global := setup(t)
+
+	file := filepath.Join(t.TempDir(), "cars.csv")
+	csv := "id,brand,model,yearOfManufacture,fuelType,price,displacement,noOfCylinder,range\n" +
+		",BMW,M3,2019,petrol,90,3000,6,0\n" +
+		",BMW,M4,2019,coal,90,3000,6,0\n" +
+		",BMW,,2019,petrol,90,3000,6,0\n"
+
+	if err := os.WriteFile(file, []byte(csv), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, errOut, err := run(t, append(global, "import", "--atomic", file)...)
+	if err == nil || len(fake.cars) != 1 || !strings.Contains(errOut, "imported 0 of 3 cars") {
+		t.Errorf("\n[TEST] Failed. Desc : atomic import\nGot %v %v cars %s\nExpected no car to be imported", err, len(fake.cars), errOut)
+	}
+
+	_, errOut, err = run(t, append(global, "import", file)...)
+	if err == nil || len(fake.cars) != 2 || !strings.Contains(errOut, "row 2: parameter fuelType is invalid") ||
+		!strings.Contains(errOut, "row 3: parameter model is invalid") {
+		t.Errorf("\n[TEST] Failed. Desc : import\nGot %v %v cars %s\nExpected the last rows to fail", err, len(fake.cars), errOut)
+	}
+
+	out, _, err := run(t, append(global, "export", "--format", "ndjson")...)
+	if err != nil || strings.Count(out, "\n") != 2 {
+		t.Errorf("\n[TEST] Failed. Desc : export\nGot %v %s\nExpected 2 lines", err, out)
+	}
+
+	out, _, err = run(t, append(global, "export")...)
+	if err != nil || !strings.HasPrefix(out, "id,brand,model") || strings.Count(out, "\n") != 3 {
+		t.Errorf("\n[TEST] Failed. Desc : export csv\nGot %v %s\nExpected csv", err, out)
+	}
+
+	xlsx := filepath.Join(t.TempDir(), "cars.xlsx")
+
+	if _, _, err = run(t, append(global, "export", "--file", xlsx)...); err != nil {
+		t.Errorf("\n[TEST] Failed. Desc : export xlsx\nGot %v\nExpected nil", err)
+	}
+
+	if _, err := zip.OpenReader(xlsx); err != nil {
+		t.Errorf("\n[TEST] Failed. Desc : export xlsx\nGot %v\nExpected a workbook", err)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	_, global := setup(t)
+
+	cases := []struct {
+		desc     string
+		args     []string
+		err      bool
+		contains string
+	}{
+		{"second profile", []string{"config", "set", "prod", "--server", "https://cars.example.com"}, false, ""},
+		{"first profile stays current", []string{"config", "list"}, false, "*        local"},
+		{"switch profile", []string{"config", "use", "prod"}, false, ""},
+		{"keys are not listed", []string{"config", "list", "-o", "json"}, false, `"hasApiKey": false`},
+		{"unknown profile", []string{"config", "use", "staging"}, true, ""},
+		{"unknown output", []string{"config", "list", "-o", "xml"}, true, ""},
+		{"completion", []string{"completion", "bash"}, false, "__start_dealerctl"},
+	}
+
+	for i, tc := range cases {
+		out, _, err := run(t, append(global, tc.args...)...)
+
+		if (err != nil) != tc.err || !strings.Contains(out, tc.contains) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v %s\nExpected error %v and output containing %q", i, tc.desc, err, out,
+				tc.err, tc.contains)
+		}
+	}
+}
+
+func TestConfig_Mode(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config.yaml")
+
+	// a config created readable by others is tightened once a key is saved in it
+	if err := os.WriteFile(config, []byte("current: local\n"), 0o644); err != nil {
+		t.Fatalf("error in writing config : %v", err)
+	}
+
+	if _, _, err := run(t, "--config", config, "config", "set", "local", "--server", "http://127.0.0.1", "--api-key", "key"); err != nil {
+		t.Fatalf("\n[TEST] Failed. Desc : save profile\nGot %v\nExpected nil", err)
+	}
+
+	info, err := os.Stat(config)
+	if err != nil {
+		t.Fatalf("error in reading config : %v", err)
+	}
+
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("\n[TEST] Failed. Desc : config mode\nGot %v\nExpected %v", info.Mode().Perm(), os.FileMode(0o600))
+	}
+
+	if entries, _ := os.ReadDir(filepath.Dir(config)); len(entries) != 1 {
+		t.Errorf("\n[TEST] Failed. Desc : temporary file\nGot %v entries\nExpected only the config", len(entries))
+	}
+}
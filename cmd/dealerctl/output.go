@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/amehrotra/car-dealership/models"
+)
+
+const (
+	table      = "table"
+	jsonFormat = "json"
+	yamlFormat = "yaml"
+)
+
+func checkOutput(output string) error {
+	switch output {
+	case table, jsonFormat, yamlFormat:
+		return nil
+	default:
+		return fmt.Errorf("output %q is not one of table, json or yaml", output)
+	}
+}
+
+type profileRow struct {
+	Name      string `json:"name"`
+	Server    string `json:"server,omitempty"`
+	Current   bool   `json:"current"`
+	HasAPIKey bool   `json:"hasApiKey"`
+}
+
+func (a *app) printCars(cars []models.Car, engine bool) error {
+	if a.output != table {
+		return a.encode(cars)
+	}
+
+	w := tabwriter.NewWriter(a.out, 0, 0, 2, ' ', 0)
+
+	header := "ID\tBRAND\tMODEL\tYEAR\tFUEL\tPRICE"
+	if engine {
+		header += "\tDISPLACEMENT\tCYLINDERS\tRANGE"
+	}
+
+	fmt.Fprintln(w, header)
+
+	for i := range cars {
+		car := &cars[i]
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%d", car.ID, car.Brand, car.Model, car.ManufactureYear, car.FuelType, car.Price)
+
+		if engine {
+			fmt.Fprintf(w, "\t%d\t%d\t%d", car.Engine.Displacement, car.Engine.NCylinder, car.Engine.Range)
+		}
+
+		fmt.Fprintln(w)
+	}
+
+	return w.Flush()
+}
+
+func (a *app) printCar(car *models.Car) error {
+	if a.output != table {
+		return a.encode(car)
+	}
+
+	return a.printCars([]models.Car{*car}, true)
+}
+
+func (a *app) printProfiles(rows []profileRow) error {
+	if a.output != table {
+		return a.encode(rows)
+	}
+
+	w := tabwriter.NewWriter(a.out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "CURRENT\tNAME\tSERVER\tAPI KEY")
+
+	for _, row := range rows {
+		current, key := "", "no"
+		if row.Current {
+			current = "*"
+		}
+
+		if row.HasAPIKey {
+			key = "yes"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", current, row.Name, row.Server, key)
+	}
+
+	return w.Flush()
+}
+
+// encode writes v as json or yaml, yaml goes through json so that both use the json names of the api
+func (a *app) encode(v interface{}) error {
+	if a.output == jsonFormat {
+		return writeJSON(a.out, v)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var generic interface{}
+
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return err
+	}
+
+	encoder := yaml.NewEncoder(a.out)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(generic); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(v)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/amehrotra/car-dealership/client"
+)
+
+const defaultServer = "http://127.0.0.1:8000"
+
+// app holds the global flags shared by the commands
+type app struct {
+	out        io.Writer
+	configPath string
+	profile    string
+	server     string
+	apiKey     string
+	output     string
+}
+
+func newRootCommand(out, errOut io.Writer) *cobra.Command {
+	a := &app{out: out}
+
+	cmd := &cobra.Command{
+		Use:           "dealerctl",
+		Short:         "Manage the inventory of the car dealership",
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return checkOutput(a.output)
+		},
+	}
+
+	cmd.SetOut(out)
+	cmd.SetErr(errOut)
+
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&a.configPath, "config", defaultConfigPath(), "path of the config file holding the profiles")
+	flags.StringVarP(&a.profile, "profile", "p", "", "profile to use instead of the current one")
+	flags.StringVar(&a.server, "server", "", "url of the server, overrides the profile and DEALERCTL_SERVER")
+	flags.StringVar(&a.apiKey, "api-key", "", "api key, overrides the profile and DEALERCTL_API_KEY")
+	flags.StringVarP(&a.output, "output", "o", table, "output format: table, json or yaml")
+
+	_ = cmd.RegisterFlagCompletionFunc("output", fixedCompletion(table, jsonFormat, yamlFormat))
+	_ = cmd.RegisterFlagCompletionFunc("profile", a.completeProfiles)
+
+	cmd.AddCommand(
+		a.listCommand(), a.getCommand(), a.createCommand(), a.updateCommand(), a.deleteCommand(),
+		a.importCommand(), a.exportCommand(), a.configCommand(),
+	)
+
+	return cmd
+}
+
+// client builds the api client of the selected profile, flags win over the environment which wins over the profile
+func (a *app) client() (*client.Client, error) {
+	cfg, err := loadConfig(a.configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := cfg.selected(a.profile)
+	if err != nil {
+		return nil, err
+	}
+
+	server := firstOf(a.server, os.Getenv("DEALERCTL_SERVER"), p.Server, defaultServer)
+	key := firstOf(a.apiKey, os.Getenv("DEALERCTL_API_KEY"), p.APIKey)
+
+	if key == "" {
+		return nil, fmt.Errorf("no api key, set one with --api-key, DEALERCTL_API_KEY or dealerctl config set")
+	}
+
+	return client.New(server, client.WithAPIKey(key))
+}
+
+func firstOf(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+func fixedCompletion(values ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
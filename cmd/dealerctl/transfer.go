@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/json"
+	goError "errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/amehrotra/car-dealership/codec"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+)
+
+const (
+	csvFormat    = "csv"
+	ndjsonFormat = "ndjson"
+	xlsxFormat   = "xlsx"
+)
+
+// fileFormat is the format given by the flag or else by the extension of the file
+func fileFormat(format, path string) (string, error) {
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+
+	switch format {
+	case csvFormat, ndjsonFormat, jsonFormat, xlsxFormat:
+		return format, nil
+	case "jsonl":
+		return ndjsonFormat, nil
+	default:
+		return "", fmt.Errorf("format %q is not one of csv, ndjson, json or xlsx", format)
+	}
+}
+
+func (a *app) importCommand() *cobra.Command {
+	var (
+		format string
+		atomic bool
+		opts   models.ImportOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Add the cars of a csv, ndjson or json file, - reads stdin",
+		Long: "Add the cars of a file in one request. Rows that fail are reported, the command fails when any did.\n" +
+			"By default the other rows are still added, with --atomic nothing is added when a row fails.\n" +
+			"CSV files have the header " + strings.Join(codec.CSVHeader, ",") + ", the id column is ignored.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if args[0] == "-" && format == "" {
+				return fmt.Errorf("--format is required when reading stdin")
+			}
+
+			format, err := fileFormat(format, args[0])
+			if err != nil {
+				return err
+			}
+
+			if format == xlsxFormat {
+				return fmt.Errorf("xlsx files can only be exported")
+			}
+
+			in := cmd.InOrStdin()
+
+			if args[0] != "-" {
+				file, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+
+				defer file.Close()
+
+				in = file
+			}
+
+			rows, err := readRows(in, format)
+			if err != nil {
+				return err
+			}
+
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+
+			opts.BestEffort = !atomic
+
+			result, err := c.Import(cmd.Context(), rows, opts)
+			if err != nil {
+				return err
+			}
+
+			for _, e := range result.Errors {
+				fmt.Fprintf(cmd.ErrOrStderr(), "row %d: %s\n", e.Row, e.Message)
+			}
+
+			verb := "imported"
+			if result.DryRun {
+				verb = "would import"
+			}
+
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s %d of %d cars\n", verb, result.Imported, result.Total)
+
+			if result.Failed > 0 {
+				return fmt.Errorf("%d cars were not imported", result.Failed)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "csv, ndjson or json, taken from the extension by default")
+	cmd.Flags().BoolVar(&atomic, "atomic", false, "add no car when any row fails")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "only validate the rows")
+	_ = cmd.RegisterFlagCompletionFunc("format", fixedCompletion(csvFormat, ndjsonFormat, jsonFormat))
+
+	return cmd
+}
+
+func (a *app) exportCommand() *cobra.Command {
+	var (
+		format string
+		file   string
+		filter = filters.Car{Engine: true}
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write the cars in stock with their engines as csv, ndjson, json or xlsx",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format == "" && file == "" {
+				format = csvFormat
+			}
+
+			format, err := fileFormat(format, file)
+			if err != nil {
+				return err
+			}
+
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+
+			out := a.out
+
+			if file != "" {
+				f, err := os.Create(file)
+				if err != nil {
+					return err
+				}
+
+				defer f.Close()
+
+				out = f
+			}
+
+			if format == jsonFormat {
+				cars := make([]models.Car, 0)
+
+				err := c.Export(cmd.Context(), filter, func(car *models.Car) error {
+					cars = append(cars, *car)
+
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+
+				return writeJSON(out, cars)
+			}
+
+			encoder := newEncoder(out, format)
+
+			if err := c.Export(cmd.Context(), filter, encoder.Encode); err != nil {
+				return err
+			}
+
+			return encoder.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "csv, ndjson, json or xlsx, taken from the extension of --file by default and csv on stdout")
+	cmd.Flags().StringVar(&file, "file", "", "write to the file instead of stdout")
+	cmd.Flags().StringVar(&filter.Brand, "brand", "", "only cars of the brand")
+	_ = cmd.RegisterFlagCompletionFunc("format", fixedCompletion(csvFormat, ndjsonFormat, jsonFormat, xlsxFormat))
+
+	return cmd
+}
+
+// readRows reads the rows of the file, rows that can not be read are kept with their error
+func readRows(r io.Reader, format string) ([]models.ImportRow, error) {
+	var decoder codec.Decoder
+
+	switch format {
+	case jsonFormat:
+		var cars []models.Car
+
+		if err := json.NewDecoder(r).Decode(&cars); err != nil {
+			return nil, err
+		}
+
+		rows := make([]models.ImportRow, len(cars))
+		for i := range cars {
+			rows[i] = models.ImportRow{Row: i + 1, Car: cars[i]}
+		}
+
+		return rows, nil
+	case ndjsonFormat:
+		decoder = codec.NewNDJSONDecoder(r)
+	default:
+		var err error
+
+		if decoder, err = codec.NewCSVDecoder(r); err != nil {
+			return nil, fmt.Errorf("csv header: %w", err)
+		}
+	}
+
+	rows := make([]models.ImportRow, 0)
+
+	for {
+		var car models.Car
+
+		err := decoder.Decode(&car)
+
+		var rowErr codec.RowError
+
+		switch {
+		case err == io.EOF:
+			return rows, nil
+		case goError.As(err, &rowErr):
+			rows = append(rows, models.ImportRow{Row: rowErr.Row, Err: rowErr.Err})
+		case err != nil:
+			return nil, err
+		default:
+			rows = append(rows, models.ImportRow{Row: len(rows) + 1, Car: car})
+		}
+	}
+}
+
+func newEncoder(w io.Writer, format string) codec.Encoder {
+	switch format {
+	case ndjsonFormat:
+		return codec.NewNDJSONEncoder(w)
+	case xlsxFormat:
+		return codec.NewXLSXEncoder(w)
+	default:
+		return codec.NewCSVEncoder(w)
+	}
+}
@@ -0,0 +1,43 @@
+// Package codec reads and writes cars as the rows of import and export files.
+package codec
+
+import (
+	"fmt"
+
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// Media types of the supported files
+const (
+	CSV    = "text/csv"
+	NDJSON = "application/x-ndjson"
+	XLSX   = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// Decoder reads the cars of a file one row at a time
+type Decoder interface {
+	// Decode reads the next row into car. It returns a RowError for a malformed row, after which decoding goes on
+	// with the next one, and io.EOF after the last row.
+	Decode(car *models.Car) error
+}
+
+// Encoder writes cars one row at a time
+type Encoder interface {
+	Encode(car *models.Car) error
+	// Flush writes buffered rows, a file without rows still gets its header
+	Flush() error
+}
+
+// RowError is a row that could not be read, rows are numbered from 1 without the header
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e RowError) Unwrap() error {
+	return e.Err
+}
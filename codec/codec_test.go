@@ -0,0 +1,197 @@
+package codec
+
+import (
+	"archive/zip"
+	"bytes"
+	goError "errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/types"
+)
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var car = models.Car{
+	ID:              uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4"),
+	Model:           "M3",
+	ManufactureYear: 2019,
+	Brand:           "BMW",
+	FuelType:        types.Petrol,
+	Price:           90,
+	Engine:          models.Engine{Displacement: 3000, NCylinder: 6},
+}
+
+// decodeAll reads every row, unreadable rows are returned as their errors
+func decodeAll(d Decoder) ([]models.Car, []error, error) {
+	cars := make([]models.Car, 0)
+	rowErrs := make([]error, 0)
+
+	for {
+		var c models.Car
+
+		err := d.Decode(&c)
+
+		var rowErr RowError
+
+		switch {
+		case err == io.EOF:
+			return cars, rowErrs, nil
+		case goError.As(err, &rowErr):
+			rowErrs = append(rowErrs, rowErr)
+		case err != nil:
+			return nil, nil, err
+		default:
+			cars = append(cars, c)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	cases := []struct {
+		desc    string
+		encoder func(io.Writer) Encoder
+		decoder func(io.Reader) (Decoder, error)
+	}{
+		{"csv", NewCSVEncoder, NewCSVDecoder},
+		{"ndjson", NewNDJSONEncoder, func(r io.Reader) (Decoder, error) { return NewNDJSONDecoder(r), nil }},
+	}
+
+	for i, tc := range cases {
+		var buf bytes.Buffer
+
+		encoder := tc.encoder(&buf)
+
+		if err := encoder.Encode(&car); err != nil {
+			t.Fatalf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected nil", i, tc.desc, err)
+		}
+
+		if err := encoder.Flush(); err != nil {
+			t.Fatalf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected nil", i, tc.desc, err)
+		}
+
+		decoder, err := tc.decoder(&buf)
+		if err != nil {
+			t.Fatalf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected nil", i, tc.desc, err)
+		}
+
+		cars, _, err := decodeAll(decoder)
+
+		// only ndjson keeps the id, csv ignores it when reading
+		expected := car
+		if tc.desc == "csv" {
+			expected.ID = uuid.Nil
+		}
+
+		if err != nil || len(cars) != 1 || !reflect.DeepEqual(cars[0], expected) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v %v\nExpected %v", i, tc.desc, cars, err, expected)
+		}
+	}
+}
+
+func TestCSVDecoder(t *testing.T) {
+	cases := []struct {
+		desc    string
+		csv     string
+		cars    int
+		rowErrs []error
+		err     error
+	}{
+		{"columns in any order with a bom", "\ufeffmodel,brand,yearOfManufacture,fuelType,price,displacement,noOfCylinder,range\n" +
+			"M3,BMW,2019,PETROL,90,3000,6,\n", 1, []error{}, nil},
+		{"invalid rows", "brand,model,yearOfManufacture,fuelType,price,displacement,noOfCylinder,range\n" +
+			"BMW,M3,new,petrol,90,3000,6,0\nBMW,M3,2019,coal,90,3000,6,0\nBMW,M3,2019,petrol,90,3000,6,0\n", 1,
+			[]error{RowError{Row: 1, Err: errors.InvalidParam{Param: []string{"yearOfManufacture"}}},
+				RowError{Row: 2, Err: errors.InvalidParam{Param: []string{"fuelType"}}}}, nil},
+		{"missing columns", "brand,model,price\n", 0, nil, errors.InvalidParam{Param: []string{"yearOfManufacture", "fuelType",
+			"displacement", "noOfCylinder", "range"}}},
+		{"empty file", "", 0, nil, errors.MissingParam{Param: "header"}},
+	}
+
+	for i, tc := range cases {
+		decoder, err := NewCSVDecoder(strings.NewReader(tc.csv))
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		cars, rowErrs, err := decodeAll(decoder)
+		if err != nil || len(cars) != tc.cars || !reflect.DeepEqual(rowErrs, tc.rowErrs) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v %v %v\nExpected %v cars and %v", i, tc.desc, len(cars), rowErrs, err,
+				tc.cars, tc.rowErrs)
+		}
+	}
+}
+
+func TestNDJSONDecoder(t *testing.T) {
+	decoder := NewNDJSONDecoder(strings.NewReader("{\"model\":\"M3\"}\n\n{\"model\":\n{\"model\":\"M4\"}\n"))
+
+	cars, rowErrs, err := decodeAll(decoder)
+	if err != nil || len(cars) != 2 || len(rowErrs) != 1 || rowErrs[0].(RowError).Row != 2 {
+		t.Errorf("\n[TEST] Failed. Desc : blank lines are skipped\nGot %v %v %v\nExpected the second row to fail", cars, rowErrs, err)
+	}
+}
+
+func TestCSVEncoder_Empty(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := NewCSVEncoder(&buf).Flush(); err != nil || buf.String() != strings.Join(CSVHeader, ",")+"\n" {
+		t.Errorf("\n[TEST] Failed. Desc : header without rows\nGot %q %v\nExpected the header", buf.String(), err)
+	}
+}
+
+func TestXLSXEncoder(t *testing.T) {
+	var buf bytes.Buffer
+
+	encoder := NewXLSXEncoder(&buf)
+
+	escaped := car
+	escaped.Model = "<M3 & co>"
+
+	if err := encoder.Encode(&escaped); err != nil {
+		t.Fatalf("\n[TEST] Failed. Desc : encode\nGot %v\nExpected nil", err)
+	}
+
+	if err := encoder.Flush(); err != nil {
+		t.Fatalf("\n[TEST] Failed. Desc : flush\nGot %v\nExpected nil", err)
+	}
+
+	workbook, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("\n[TEST] Failed. Desc : workbook\nGot %v\nExpected a zip", err)
+	}
+
+	var sheet string
+
+	for _, f := range workbook.File {
+		if f.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+
+		rc, _ := f.Open()
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+
+		sheet = string(b)
+	}
+
+	expected := []string{"<t>yearOfManufacture</t>", "<t>&lt;M3 &amp; co&gt;</t>", "<c><v>3000</v></c>", "</sheetData></worksheet>"}
+
+	for _, expected := range expected {
+		if !strings.Contains(sheet, expected) {
+			t.Errorf("\n[TEST] Failed. Desc : sheet\nGot %v\nExpected it to contain %v", sheet, expected)
+		}
+	}
+
+	if len(workbook.File) != len(xlsxParts)+1 {
+		t.Errorf("\n[TEST] Failed. Desc : parts\nGot %v\nExpected %v", len(workbook.File), len(xlsxParts)+1)
+	}
+}
@@ -0,0 +1,173 @@
+package codec
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/types"
+)
+
+// CSVHeader are the columns of csv files, the id column is ignored when reading
+// nolint:gochecknoglobals // read only column list
+var CSVHeader = []string{"id", "brand", "model", "yearOfManufacture", "fuelType", "price", "displacement", "noOfCylinder", "range"}
+
+type csvDecoder struct {
+	reader  *csv.Reader
+	columns map[string]int
+	row     int
+}
+
+// NewCSVDecoder reads the header, columns may come in any order and only id may be left out
+func NewCSVDecoder(r io.Reader) (Decoder, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.MissingParam{Param: "header"}
+	}
+
+	if err != nil {
+		return nil, errors.InvalidParam{Param: []string{"header"}}
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+
+	missing := make([]string, 0)
+
+	for _, name := range CSVHeader[1:] {
+		if _, ok := columns[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, errors.InvalidParam{Param: missing}
+	}
+
+	return &csvDecoder{reader: reader, columns: columns}, nil
+}
+
+func (d *csvDecoder) Decode(car *models.Car) error {
+	record, err := d.reader.Read()
+	if err == io.EOF {
+		return io.EOF
+	}
+
+	d.row++
+
+	if err != nil {
+		return RowError{Row: d.row, Err: err}
+	}
+
+	if err := d.parse(record, car); err != nil {
+		return RowError{Row: d.row, Err: err}
+	}
+
+	return nil
+}
+
+func (d *csvDecoder) parse(record []string, car *models.Car) error {
+	field := func(name string) string {
+		if i := d.columns[name]; i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+
+		return ""
+	}
+
+	invalid := make([]string, 0)
+
+	number := func(name string) int64 {
+		if field(name) == "" {
+			return 0
+		}
+
+		n, err := strconv.ParseInt(field(name), 10, 64)
+		if err != nil {
+			invalid = append(invalid, name)
+		}
+
+		return n
+	}
+
+	*car = models.Car{
+		Brand:           field("brand"),
+		Model:           field("model"),
+		ManufactureYear: int(number("yearOfManufacture")),
+		Price:           number("price"),
+		Engine: models.Engine{
+			Displacement: int(number("displacement")),
+			NCylinder:    int(number("noOfCylinder")),
+			Range:        int(number("range")),
+		},
+	}
+
+	if err := car.FuelType.UnmarshalJSON([]byte(strconv.Quote(field("fuelType")))); err != nil {
+		invalid = append(invalid, "fuelType")
+	}
+
+	if len(invalid) > 0 {
+		return errors.InvalidParam{Param: invalid}
+	}
+
+	return nil
+}
+
+type csvEncoder struct {
+	writer *csv.Writer
+	header bool
+}
+
+// NewCSVEncoder writes the rows with the columns of CSVHeader
+func NewCSVEncoder(w io.Writer) Encoder {
+	return &csvEncoder{writer: csv.NewWriter(w)}
+}
+
+func (e *csvEncoder) writeHeader() error {
+	if e.header {
+		return nil
+	}
+
+	e.header = true
+
+	return e.writer.Write(CSVHeader)
+}
+
+func (e *csvEncoder) Encode(car *models.Car) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	return e.writer.Write([]string{
+		car.ID.String(), car.Brand, car.Model, strconv.Itoa(car.ManufactureYear), fuel(car.FuelType), strconv.FormatInt(car.Price, 10),
+		strconv.Itoa(car.Engine.Displacement), strconv.Itoa(car.Engine.NCylinder), strconv.Itoa(car.Engine.Range),
+	})
+}
+
+func (e *csvEncoder) Flush() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	e.writer.Flush()
+
+	return e.writer.Error()
+}
+
+func fuel(f types.Fuel) string {
+	if name := f.String(); name != "" {
+		return name
+	}
+
+	return fmt.Sprint(int(f))
+}
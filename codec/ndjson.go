@@ -0,0 +1,68 @@
+package codec
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// maxLineBytes bounds a line of ndjson, a car is far below it
+const maxLineBytes = 64 << 10
+
+type ndjsonDecoder struct {
+	scanner *bufio.Scanner
+	row     int
+}
+
+// NewNDJSONDecoder reads a car per line, blank lines are skipped
+func NewNDJSONDecoder(r io.Reader) Decoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxLineBytes)
+
+	return &ndjsonDecoder{scanner: scanner}
+}
+
+func (d *ndjsonDecoder) Decode(car *models.Car) error {
+	for d.scanner.Scan() {
+		line := bytes.TrimSpace(d.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		d.row++
+
+		*car = models.Car{}
+
+		if err := json.Unmarshal(line, car); err != nil {
+			return RowError{Row: d.row, Err: err}
+		}
+
+		return nil
+	}
+
+	if err := d.scanner.Err(); err != nil {
+		return err
+	}
+
+	return io.EOF
+}
+
+type ndjsonEncoder struct {
+	encoder *json.Encoder
+}
+
+// NewNDJSONEncoder writes a car per line
+func NewNDJSONEncoder(w io.Writer) Encoder {
+	return ndjsonEncoder{encoder: json.NewEncoder(w)}
+}
+
+func (e ndjsonEncoder) Encode(car *models.Car) error {
+	return e.encoder.Encode(car)
+}
+
+func (e ndjsonEncoder) Flush() error {
+	return nil
+}
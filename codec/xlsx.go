@@ -0,0 +1,138 @@
+package codec
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"io"
+	"strconv"
+
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// parts of the workbook written ahead of the sheet, the sheet is streamed as the last part of the zip
+// nolint:gochecknoglobals // read only package parts
+var xlsxParts = []struct{ name, content string }{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ` +
+		`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ` +
+		`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" ` +
+		`Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Cars" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" ` +
+		`Target="worksheets/sheet1.xml"/></Relationships>`},
+}
+
+type xlsxEncoder struct {
+	zip   *zip.Writer
+	sheet io.Writer
+	done  bool
+}
+
+// NewXLSXEncoder writes a workbook with a single sheet having the columns of CSVHeader.
+// Rows are compressed into w as they are encoded, Flush completes the workbook and no rows may follow it.
+func NewXLSXEncoder(w io.Writer) Encoder {
+	return &xlsxEncoder{zip: zip.NewWriter(w)}
+}
+
+// start writes the parts preceding the sheet and the header row
+func (e *xlsxEncoder) start() error {
+	if e.sheet != nil {
+		return nil
+	}
+
+	for _, part := range xlsxParts {
+		f, err := e.zip.Create(part.name)
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+
+	sheet, err := e.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+
+	e.sheet = sheet
+
+	_, err = io.WriteString(sheet, xml.Header+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	if err != nil {
+		return err
+	}
+
+	header := make([]interface{}, len(CSVHeader))
+	for i := range CSVHeader {
+		header[i] = CSVHeader[i]
+	}
+
+	return e.writeRow(header...)
+}
+
+// writeRow writes strings as inline strings and integers as numbers
+func (e *xlsxEncoder) writeRow(cells ...interface{}) error {
+	if _, err := io.WriteString(e.sheet, "<row>"); err != nil {
+		return err
+	}
+
+	for _, cell := range cells {
+		var err error
+
+		switch v := cell.(type) {
+		case string:
+			if _, err = io.WriteString(e.sheet, `<c t="inlineStr"><is><t>`); err == nil {
+				if err = xml.EscapeText(e.sheet, []byte(v)); err == nil {
+					_, err = io.WriteString(e.sheet, "</t></is></c>")
+				}
+			}
+		case int64:
+			_, err = io.WriteString(e.sheet, "<c><v>"+strconv.FormatInt(v, 10)+"</v></c>")
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(e.sheet, "</row>")
+
+	return err
+}
+
+func (e *xlsxEncoder) Encode(car *models.Car) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	return e.writeRow(car.ID.String(), car.Brand, car.Model, int64(car.ManufactureYear), fuel(car.FuelType), car.Price,
+		int64(car.Engine.Displacement), int64(car.Engine.NCylinder), int64(car.Engine.Range))
+}
+
+func (e *xlsxEncoder) Flush() error {
+	if e.done {
+		return nil
+	}
+
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	e.done = true
+
+	if _, err := io.WriteString(e.sheet, "</sheetData></worksheet>"); err != nil {
+		return err
+	}
+
+	return e.zip.Close()
+}
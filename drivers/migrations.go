@@ -0,0 +1,30 @@
+package drivers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// SchemaVersion is the migration the code expects the database to be at,
+// every change to the schema in the readme adds a row to schema_migrations and bumps it
+const SchemaVersion = 12
+
+const getSchemaVersion = "SELECT COALESCE(MAX(version), 0) FROM schema_migrations"
+
+// CheckMigrations reports an error unless the database is migrated to at least SchemaVersion
+func CheckMigrations(db *sql.DB) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		var version int
+
+		if err := db.QueryRowContext(ctx, getSchemaVersion).Scan(&version); err != nil {
+			return err
+		}
+
+		if version < SchemaVersion {
+			return fmt.Errorf("schema is at version %d, expected %d", version, SchemaVersion)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,45 @@
+package drivers
+
+import (
+	"context"
+	goError "errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestCheckMigrations(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error %s was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	mock.ExpectQuery(getSchemaVersion).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(SchemaVersion))
+	mock.ExpectQuery(getSchemaVersion).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(SchemaVersion + 1))
+	mock.ExpectQuery(getSchemaVersion).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(SchemaVersion - 1))
+	mock.ExpectQuery(getSchemaVersion).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc string
+		err  bool
+	}{
+		{"expected version", false},
+		{"newer version", false},
+		{"pending migrations", true},
+		{"query error", true},
+	}
+
+	check := CheckMigrations(db)
+
+	for i, tc := range cases {
+		err := check(context.Background())
+
+		if (err != nil) != tc.err {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected error %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
@@ -1,35 +1,71 @@
 package drivers
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	"log/slog"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
 )
 
-func ConnectToSQL() (*sql.DB, error) {
+const (
+	pingTimeout    = 5 * time.Second
+	initialBackoff = 500 * time.Millisecond
+	maxBackoff     = 30 * time.Second
+)
+
+// ConnectToSQL opens the database and pings it until it answers, backing off exponentially between attempts.
+// It gives up with the last ping error once ctx is done.
+func ConnectToSQL(ctx context.Context, logger *slog.Logger) (*sql.DB, error) {
 	cfg := mysql.Config{
 		User:   "root",
 		Passwd: "password",
 		Net:    "tcp",
 		Addr:   "127.0.0.1:3306",
 		DBName: "car_dealership",
+
+		ParseTime: true,
 	}
 
 	db, err := sql.Open("mysql", cfg.FormatDSN())
 	if err != nil {
-		log.Println(err)
+		logger.ErrorContext(ctx, "error in opening the database", "error", err)
 
 		return nil, err
 	}
 
-	if err := db.Ping(); err != nil {
-		log.Println(err)
+	backoff := initialBackoff
 
-		return nil, err
+	for attempt := 1; ; attempt++ {
+		err = ping(ctx, db)
+		if err == nil {
+			break
+		}
+
+		logger.WarnContext(ctx, "database not reachable, retrying", "attempt", attempt, "backoff", backoff, "error", err)
+
+		select {
+		case <-ctx.Done():
+			_ = db.Close()
+
+			return nil, err
+		case <-time.After(backoff):
+		}
+
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
 	}
 
-	log.Println("Connected")
+	logger.InfoContext(ctx, "connected to the database")
 
 	return db, nil
 }
+
+func ping(ctx context.Context, db *sql.DB) error {
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	return db.PingContext(ctx)
+}
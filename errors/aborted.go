@@ -0,0 +1,12 @@
+package errors
+
+import "fmt"
+
+// Aborted is the outcome of an operation of an all or nothing batch that was not applied because another one failed
+type Aborted struct {
+	Index int
+}
+
+func (e Aborted) Error() string {
+	return fmt.Sprintf("operation was not applied since operation %d failed", e.Index)
+}
@@ -0,0 +1,14 @@
+package errors
+
+import "fmt"
+
+// Conflict is the outcome of a change the current state of the entity does not allow, such as selling a sold car
+type Conflict struct {
+	Entity string
+	ID     string
+	Reason string
+}
+
+func (e Conflict) Error() string {
+	return fmt.Sprintf("entity %s with id %s %s", e.Entity, e.ID, e.Reason)
+}
@@ -0,0 +1,11 @@
+package errors
+
+import "fmt"
+
+type Forbidden struct {
+	Permission string
+}
+
+func (e Forbidden) Error() string {
+	return fmt.Sprintf("permission %s is required", e.Permission)
+}
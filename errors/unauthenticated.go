@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type Unauthenticated struct {
+	Reason string
+}
+
+func (e Unauthenticated) Error() string {
+	if e.Reason == "" {
+		return "request is not authenticated"
+	}
+
+	return fmt.Sprintf("request is not authenticated: %s", e.Reason)
+}
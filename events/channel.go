@@ -0,0 +1,32 @@
+package events
+
+import (
+	"context"
+
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// Channel publishes to consumers in the same process
+type Channel struct {
+	messages chan models.Message
+}
+
+// NewChannel returns a Channel buffering up to size messages, publishing blocks while the buffer is full
+func NewChannel(size int) *Channel {
+	return &Channel{messages: make(chan models.Message, size)}
+}
+
+// Publish waits for room in the buffer until ctx is done
+func (c *Channel) Publish(ctx context.Context, msg models.Message) error {
+	select {
+	case c.messages <- msg:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Messages returns the channel the messages are received from
+func (c *Channel) Messages() <-chan models.Message {
+	return c.messages
+}
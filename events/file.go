@@ -0,0 +1,34 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/amehrotra/car-dealership/models"
+)
+
+type file struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// File appends every message to w as a line of JSON
+func File(w io.Writer) Publisher {
+	return &file{w: w}
+}
+
+func (f *file) Publish(_ context.Context, msg models.Message) error {
+	line, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, err = f.w.Write(append(line, '\n'))
+
+	return err
+}
@@ -0,0 +1,90 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// Hub publishes to the subscribers in the same process and keeps the latest messages so that subscribers
+// may resume after a disconnect. Publishing never waits for a subscriber, one whose buffer is full is closed instead.
+type Hub struct {
+	mu          sync.Mutex
+	history     []models.Message
+	size        int
+	subscribers map[*Subscription]struct{}
+}
+
+// Subscription receives the messages published after it was made, C is closed when the subscriber falls behind
+type Subscription struct {
+	C <-chan models.Message
+
+	c   chan models.Message
+	hub *Hub
+}
+
+// NewHub returns a Hub keeping the last size messages
+func NewHub(size int) *Hub {
+	return &Hub{size: size, subscribers: make(map[*Subscription]struct{})}
+}
+
+// Publish hands the message to every subscriber
+func (h *Hub) Publish(_ context.Context, msg models.Message) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.history = append(h.history, msg)
+	if len(h.history) > h.size {
+		h.history = h.history[len(h.history)-h.size:]
+	}
+
+	for sub := range h.subscribers {
+		select {
+		case sub.c <- msg:
+		default:
+			delete(h.subscribers, sub)
+			close(sub.c)
+		}
+	}
+
+	return nil
+}
+
+// Subscribe returns a subscription buffering up to buffer messages along with the kept messages published after
+// the one of lastID. Resumed is false when lastID is set but no longer kept, the subscriber may have missed messages.
+func (h *Hub) Subscribe(lastID string, buffer int) (sub *Subscription, missed []models.Message, resumed bool) {
+	c := make(chan models.Message, buffer)
+	sub = &Subscription{C: c, c: c, hub: h}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.subscribers[sub] = struct{}{}
+
+	if lastID == "" {
+		return sub, nil, true
+	}
+
+	for i := range h.history {
+		if h.history[i].ID.String() == lastID {
+			missed = make([]models.Message, len(h.history)-i-1)
+			copy(missed, h.history[i+1:])
+
+			return sub, missed, true
+		}
+	}
+
+	return sub, nil, false
+}
+
+// Close stops the subscription and closes C
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	if _, ok := s.hub.subscribers[s]; ok {
+		delete(s.hub.subscribers, s)
+		close(s.c)
+	}
+}
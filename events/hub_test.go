@@ -0,0 +1,69 @@
+package events
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/models"
+)
+
+func TestHub_Resume(t *testing.T) {
+	h := NewHub(2)
+	published := []models.Message{{ID: uuid.New()}, {ID: uuid.New()}, {ID: uuid.New()}}
+
+	for _, msg := range published {
+		_ = h.Publish(context.Background(), msg)
+	}
+
+	cases := []struct {
+		desc    string
+		lastID  string
+		missed  []models.Message
+		resumed bool
+	}{
+		{"live only", "", nil, true},
+		{"kept event", published[1].ID.String(), []models.Message{published[2]}, true},
+		{"latest event", published[2].ID.String(), []models.Message{}, true},
+		{"event no longer kept", published[0].ID.String(), nil, false},
+	}
+
+	for i, tc := range cases {
+		sub, missed, resumed := h.Subscribe(tc.lastID, 1)
+		sub.Close()
+
+		if !reflect.DeepEqual(missed, tc.missed) || resumed != tc.resumed {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v %v\nExpected %v %v", i, tc.desc, missed, resumed, tc.missed, tc.resumed)
+		}
+	}
+}
+
+func TestHub_SlowSubscriber(t *testing.T) {
+	h := NewHub(10)
+
+	slow, _, _ := h.Subscribe("", 1)
+	fast, _, _ := h.Subscribe("", 2)
+
+	first, second := models.Message{ID: uuid.New()}, models.Message{ID: uuid.New()}
+
+	// publishing does not wait for the slow subscriber, it is closed once its buffer is full
+	_ = h.Publish(context.Background(), first)
+	_ = h.Publish(context.Background(), second)
+
+	if msg, ok := <-slow.C; !ok || msg.ID != first.ID {
+		t.Errorf("\n[TEST] Failed. Desc : buffered message\nGot %v %v\nExpected %v", msg, ok, first)
+	}
+
+	if _, ok := <-slow.C; ok {
+		t.Errorf("\n[TEST] Failed. Desc : slow subscriber\nGot open subscription\nExpected closed")
+	}
+
+	if len(fast.C) != 2 {
+		t.Errorf("\n[TEST] Failed. Desc : fast subscriber\nGot %v messages\nExpected 2", len(fast.C))
+	}
+
+	fast.Close()
+	slow.Close()
+}
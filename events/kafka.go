@@ -0,0 +1,33 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// KafkaProducer writes a record to a topic, wrapping the producer of the Kafka client in use
+type KafkaProducer interface {
+	Produce(ctx context.Context, topic string, key, value []byte) error
+}
+
+type kafkaPublisher struct {
+	producer KafkaProducer
+	topic    string
+}
+
+// Kafka publishes every message as JSON to topic, keyed by the key of the message so that the messages
+// of a car land on the same partition and keep their order
+func Kafka(producer KafkaProducer, topic string) Publisher {
+	return kafkaPublisher{producer: producer, topic: topic}
+}
+
+func (k kafkaPublisher) Publish(ctx context.Context, msg models.Message) error {
+	value, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return k.producer.Produce(ctx, k.topic, []byte(msg.Key), value)
+}
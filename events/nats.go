@@ -0,0 +1,33 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// NATSConn is the part of a NATS connection used to publish, *nats.Conn of github.com/nats-io/nats.go satisfies it
+type NATSConn interface {
+	Publish(subject string, data []byte) error
+}
+
+type natsPublisher struct {
+	conn   NATSConn
+	prefix string
+}
+
+// NATS publishes every message as JSON on the subject <prefix>.<type>, like dealership.car.created.
+// Consumers drop duplicates by the id of the message.
+func NATS(conn NATSConn, prefix string) Publisher {
+	return natsPublisher{conn: conn, prefix: prefix}
+}
+
+func (n natsPublisher) Publish(_ context.Context, msg models.Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return n.conn.Publish(n.prefix+"."+msg.Type, data)
+}
@@ -0,0 +1,29 @@
+package events
+
+import (
+	"context"
+
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// Publisher hands messages to a broker, a nil error means the broker accepted the message and it is not published again
+type Publisher interface {
+	Publish(ctx context.Context, msg models.Message) error
+}
+
+type fanout []Publisher
+
+// Fanout publishes every message to each of the publishers, a message is published again to all of them when any fails
+func Fanout(publishers ...Publisher) Publisher {
+	return fanout(publishers)
+}
+
+func (f fanout) Publish(ctx context.Context, msg models.Message) error {
+	for _, p := range f {
+		if err := p.Publish(ctx, msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	goError "errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var msg = models.Message{ID: uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4"), Key: "car-1", Type: models.CarCreated,
+	Payload: json.RawMessage(`{"type":"car.created"}`), OccurredAt: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+type natsConn struct {
+	subject string
+	data    []byte
+}
+
+func (c *natsConn) Publish(subject string, data []byte) error {
+	c.subject, c.data = subject, data
+
+	return nil
+}
+
+type producer struct {
+	topic      string
+	key, value []byte
+}
+
+func (p *producer) Produce(_ context.Context, topic string, key, value []byte) error {
+	p.topic, p.key, p.value = topic, key, value
+
+	return nil
+}
+
+type failing struct{}
+
+func (failing) Publish(context.Context, models.Message) error {
+	return goError.New("broker unavailable")
+}
+
+func TestFile(t *testing.T) {
+	var buf bytes.Buffer
+
+	p := File(&buf)
+
+	if err := p.Publish(context.Background(), msg); err != nil {
+		t.Fatalf("\n[TEST] Failed. Desc : file\nGot %v\nExpected nil", err)
+	}
+
+	expected := `{"id":"8f443772-132b-4ae5-9f8f-9960649b3fb4","key":"car-1","type":"car.created","payload":{"type":"car.created"},` +
+		`"occurredAt":"2022-01-01T00:00:00Z"}` + "\n"
+
+	if buf.String() != expected {
+		t.Errorf("\n[TEST] Failed. Desc : file\nGot %v\nExpected %v", buf.String(), expected)
+	}
+}
+
+func TestChannel(t *testing.T) {
+	c := NewChannel(1)
+
+	if err := c.Publish(context.Background(), msg); err != nil {
+		t.Fatalf("\n[TEST] Failed. Desc : buffered\nGot %v\nExpected nil", err)
+	}
+
+	// the buffer is full, publishing waits until ctx is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := c.Publish(ctx, msg); !goError.Is(err, context.DeadlineExceeded) {
+		t.Errorf("\n[TEST] Failed. Desc : full buffer\nGot %v\nExpected %v", err, context.DeadlineExceeded)
+	}
+
+	if received := <-c.Messages(); !reflect.DeepEqual(received, msg) {
+		t.Errorf("\n[TEST] Failed. Desc : received\nGot %v\nExpected %v", received, msg)
+	}
+}
+
+func TestBrokers(t *testing.T) {
+	conn := &natsConn{}
+	prod := &producer{}
+
+	if err := Fanout(NATS(conn, "dealership"), Kafka(prod, "inventory")).Publish(context.Background(), msg); err != nil {
+		t.Fatalf("\n[TEST] Failed. Desc : brokers\nGot %v\nExpected nil", err)
+	}
+
+	if conn.subject != "dealership.car.created" {
+		t.Errorf("\n[TEST] Failed. Desc : nats subject\nGot %v\nExpected %v", conn.subject, "dealership.car.created")
+	}
+
+	if prod.topic != "inventory" || string(prod.key) != "car-1" || !bytes.Equal(prod.value, conn.data) {
+		t.Errorf("\n[TEST] Failed. Desc : kafka record\nGot %v %s %s\nExpected record keyed by car-1", prod.topic, prod.key, prod.value)
+	}
+
+	if err := Fanout(failing{}, NATS(conn, "dealership")).Publish(context.Background(), msg); err == nil {
+		t.Errorf("\n[TEST] Failed. Desc : fanout error\nGot %v\nExpected error", err)
+	}
+}
@@ -0,0 +1,6 @@
+package filters
+
+type Customer struct {
+	Email string
+	Phone string
+}
@@ -0,0 +1,9 @@
+package filters
+
+import "github.com/google/uuid"
+
+type Order struct {
+	CarID      uuid.UUID
+	CustomerID uuid.UUID
+	Status     string
+}
@@ -0,0 +1,17 @@
+package filters
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TestDrive matches the test drives overlapping From to To, zero times leave that end open
+type TestDrive struct {
+	CarID       uuid.UUID
+	CustomerID  uuid.UUID
+	Salesperson string
+	Status      string
+	From        time.Time
+	To          time.Time
+}
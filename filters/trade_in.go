@@ -0,0 +1,8 @@
+package filters
+
+import "github.com/google/uuid"
+
+type TradeIn struct {
+	CustomerID uuid.UUID
+	Status     string
+}
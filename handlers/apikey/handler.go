@@ -0,0 +1,106 @@
+package apikey
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/handlers/response"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+type handler struct {
+	service services.APIKey
+	logger  *slog.Logger
+}
+
+// nolint:revive // handler should not be exported
+func New(service services.APIKey, logger *slog.Logger) handler {
+	return handler{service: service, logger: logger}
+}
+
+// Create issues a new api key and writes it with its plain text secret
+func (h handler) Create(w http.ResponseWriter, r *http.Request) {
+	key, err := getKey(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	key, err = h.service.Create(r.Context(), key)
+	response.Write(w, r, h.logger, key, err)
+}
+
+// GetAll writes all the api keys without their secrets
+func (h handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.service.GetAll(r.Context())
+	response.Write(w, r, h.logger, keys, err)
+}
+
+// Rotate replaces the secret of the api key and writes the new secret
+func (h handler) Rotate(w http.ResponseWriter, r *http.Request) {
+	id, err := getID(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	key, err := h.service.Rotate(r.Context(), id)
+	response.Write(w, r, h.logger, key, err)
+}
+
+// Revoke disables the api key based on ID
+func (h handler) Revoke(w http.ResponseWriter, r *http.Request) {
+	id, err := getID(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	err = h.service.Revoke(r.Context(), id)
+	response.Write(w, r, h.logger, nil, err)
+}
+
+// getID reads the id from path parameter of url
+func getID(r *http.Request) (uuid.UUID, error) {
+	param := mux.Vars(r)
+	idParam := strings.TrimSpace(param["id"])
+
+	if idParam == "" {
+		return uuid.Nil, errors.MissingParam{Param: "id"}
+	}
+
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		return uuid.Nil, errors.InvalidParam{Param: []string{"id"}}
+	}
+
+	return id, nil
+}
+
+// getKey reads request body and returns the api key to be issued
+func getKey(r *http.Request) (*models.APIKey, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	var key models.APIKey
+
+	err = json.Unmarshal(body, &key)
+	if err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	return &key, nil
+}
@@ -0,0 +1,131 @@
+package apikey
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+func initializeTest(t *testing.T, method string, body io.Reader, pParam map[string]string) (handler, *services.MockAPIKey,
+	*http.Request, *httptest.ResponseRecorder) {
+	ctrl := gomock.NewController(t)
+
+	mockService := services.NewMockAPIKey(ctrl)
+	h := New(mockService, logging.Discard())
+
+	req := httptest.NewRequest(method, "http://apikey", body)
+	r := mux.SetURLVars(req, pParam)
+
+	w := httptest.NewRecorder()
+
+	return h, mockService, r, w
+}
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var id = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+
+func TestHandler_Create(t *testing.T) {
+	cases := []struct {
+		desc       string
+		body       string
+		mockCall   bool
+		mockErr    error
+		statusCode int
+	}{
+		{"success case", `{"owner":"crm","scopes":["admin"]}`, true, nil, http.StatusCreated},
+		{"invalid owner", `{"owner":""}`, true, errors.InvalidParam{Param: []string{"owner"}}, http.StatusBadRequest},
+		{"invalid body", `{"owner":`, false, nil, http.StatusBadRequest},
+	}
+
+	for i, tc := range cases {
+		h, mockService, r, w := initializeTest(t, http.MethodPost, bytes.NewReader([]byte(tc.body)), nil)
+
+		if tc.mockCall {
+			mockService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&models.APIKey{ID: id, Key: "key"}, tc.mockErr)
+		}
+
+		h.Create(w, r)
+
+		if w.Code != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, w.Code, tc.statusCode)
+		}
+	}
+}
+
+func TestHandler_GetAll(t *testing.T) {
+	h, mockService, r, w := initializeTest(t, http.MethodGet, nil, nil)
+
+	mockService.EXPECT().GetAll(gomock.Any()).Return([]models.APIKey{{ID: id, Hash: "hash"}}, nil)
+
+	h.GetAll(w, r)
+
+	if w.Code != http.StatusOK || bytes.Contains(w.Body.Bytes(), []byte("hash")) {
+		t.Errorf("\n[TEST] Failed. Desc : get all\nGot %v %s\nExpected %v without hashes", w.Code, w.Body.String(), http.StatusOK)
+	}
+}
+
+func TestHandler_Rotate(t *testing.T) {
+	cases := []struct {
+		desc       string
+		id         string
+		mockErr    error
+		statusCode int
+	}{
+		{"success case", id.String(), nil, http.StatusCreated},
+		{"key not found", id.String(), errors.EntityNotFound{}, http.StatusNotFound},
+		{"invalid id", "123", nil, http.StatusBadRequest},
+		{"missing id", "", nil, http.StatusBadRequest},
+	}
+
+	for i, tc := range cases {
+		h, mockService, r, w := initializeTest(t, http.MethodPost, nil, map[string]string{"id": tc.id})
+
+		if tc.id == id.String() {
+			mockService.EXPECT().Rotate(gomock.Any(), id).Return(&models.APIKey{ID: id}, tc.mockErr)
+		}
+
+		h.Rotate(w, r)
+
+		if w.Code != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, w.Code, tc.statusCode)
+		}
+	}
+}
+
+func TestHandler_Revoke(t *testing.T) {
+	cases := []struct {
+		desc       string
+		id         string
+		mockErr    error
+		statusCode int
+	}{
+		{"success case", id.String(), nil, http.StatusNoContent},
+		{"db error", id.String(), errors.DB{}, http.StatusInternalServerError},
+		{"invalid id", "123", nil, http.StatusBadRequest},
+	}
+
+	for i, tc := range cases {
+		h, mockService, r, w := initializeTest(t, http.MethodDelete, nil, map[string]string{"id": tc.id})
+
+		if tc.id == id.String() {
+			mockService.EXPECT().Revoke(gomock.Any(), id).Return(tc.mockErr)
+		}
+
+		h.Revoke(w, r)
+
+		if w.Code != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, w.Code, tc.statusCode)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package car
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/handlers/response"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// Batch applies a list of create, update and delete operations and answers 207 with the outcome of each.
+// The operations are applied all or nothing unless ?mode=bestEffort is given.
+func (h handler) Batch(w http.ResponseWriter, r *http.Request) {
+	bestEffort, err := getMode(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	var body models.BatchRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		response.Write(w, r, h.logger, nil, errors.InvalidParam{Param: []string{"body"}})
+
+		return
+	}
+
+	results, err := h.service.Batch(r.Context(), body.Operations, models.BatchOptions{BestEffort: bestEffort})
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	resp := models.BatchResponse{Atomic: !bestEffort, Results: make([]models.BatchItem, len(results))}
+
+	for i, result := range results {
+		item := models.BatchItem{Index: i, Op: result.Op, Car: result.Car}
+
+		switch {
+		case result.Err != nil:
+			statusCode, code, message := response.Error(r.Context(), h.logger, result.Err)
+
+			// a duplicate fails its item, it must not read as a success among the others
+			if _, ok := result.Err.(errors.EntityAlreadyExists); ok {
+				statusCode = http.StatusConflict
+			}
+
+			item.Status = statusCode
+			item.Error = &models.Error{Code: code, Message: message, RequestID: logging.RequestID(r.Context())}
+			resp.Failed++
+		case result.Op == models.BatchCreate:
+			item.Status = http.StatusCreated
+			resp.Succeeded++
+		case result.Op == models.BatchDelete:
+			item.Status = http.StatusNoContent
+			resp.Succeeded++
+		default:
+			item.Status = http.StatusOK
+			resp.Succeeded++
+		}
+
+		resp.Results[i] = item
+	}
+
+	response.WriteBody(w, r, h.logger, http.StatusMultiStatus, resp)
+}
@@ -0,0 +1,80 @@
+package car
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/models"
+)
+
+func TestHandler_Batch(t *testing.T) {
+	body := `{"operations":[{"op":"create","car":{"model":"X"}},{"op":"update","id":"8f443772-132b-4ae5-9f8f-9960649b3fb4",
+		"car":{"model":"X"}},{"op":"delete","id":"8f443772-132b-4ae5-9f8f-9960649b3fb4"},
+		{"op":"create","car":{"model":"X"}}]}`
+
+	results := []models.BatchResult{
+		{Op: models.BatchCreate, Car: &car},
+		{Op: models.BatchUpdate, Err: errors.Forbidden{Permission: "car:price"}},
+		{Op: models.BatchDelete},
+		{Op: models.BatchCreate, Err: errors.EntityAlreadyExists{Entity: "car"}},
+	}
+
+	expected := models.BatchResponse{Succeeded: 2, Failed: 2, Results: []models.BatchItem{
+		{Index: 0, Op: models.BatchCreate, Status: http.StatusCreated, Car: &car},
+		{Index: 1, Op: models.BatchUpdate, Status: http.StatusForbidden,
+			Error: &models.Error{Code: "forbidden", Message: "permission car:price is required"}},
+		{Index: 2, Op: models.BatchDelete, Status: http.StatusNoContent},
+		{Index: 3, Op: models.BatchCreate, Status: http.StatusConflict,
+			Error: &models.Error{Code: "already_exists", Message: "entity  car already exists"}},
+	}}
+
+	cases := []struct {
+		desc       string
+		body       string
+		query      url.Values
+		call       bool
+		opts       models.BatchOptions
+		mockErr    error
+		statusCode int
+	}{
+		{"best effort", body, url.Values{"mode": {"bestEffort"}}, true, models.BatchOptions{BestEffort: true}, nil, http.StatusMultiStatus},
+		{"invalid batch", body, nil, true, models.BatchOptions{}, errors.InvalidParam{Param: []string{"operations"}}, http.StatusBadRequest},
+		{"invalid body", "[", nil, false, models.BatchOptions{}, nil, http.StatusBadRequest},
+		{"invalid mode", body, url.Values{"mode": {"all"}}, false, models.BatchOptions{}, nil, http.StatusBadRequest},
+	}
+
+	for i, tc := range cases {
+		h, mockService, r, w := initializeTest(t, http.MethodPost, strings.NewReader(tc.body), nil, tc.query)
+
+		if tc.call {
+			mockService.EXPECT().Batch(gomock.Any(), gomock.Len(4), tc.opts).Return(results, tc.mockErr)
+		}
+
+		h.Batch(w, r)
+
+		resp := w.Result()
+
+		if resp.StatusCode != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, resp.StatusCode, tc.statusCode)
+		}
+
+		if tc.statusCode == http.StatusMultiStatus {
+			var got models.BatchResponse
+
+			_ = json.NewDecoder(resp.Body).Decode(&got)
+
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %+v\nExpected %+v", i, tc.desc, got, expected)
+			}
+		}
+
+		resp.Body.Close()
+	}
+}
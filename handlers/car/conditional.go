@@ -0,0 +1,110 @@
+package car
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/amehrotra/car-dealership/handlers/response"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+// DefaultCacheControl lets clients keep responses to themselves as long as they revalidate them before use
+const DefaultCacheControl = "private, no-cache"
+
+// Option configures the car handler
+type Option func(*handler)
+
+// WithCacheControl sets the Cache-Control header of the car reads
+func WithCacheControl(value string) Option {
+	return func(h *handler) {
+		h.cacheControl = value
+	}
+}
+
+// WithReservations includes the reservation holding a car in its read by id
+func WithReservations(reservations services.Reservation) Option {
+	return func(h *handler) {
+		h.reservations = reservations
+	}
+}
+
+// writeConditional writes the data in the representation the client accepts with its validators, or 304 when the
+// client already has it. The ETag is a digest of the body so that it changes with any field and differs between
+// representations, Last-Modified is only compared when modifiedSince is set as deleting one of several cars does not
+// make them more recent.
+func (h handler) writeConditional(w http.ResponseWriter, r *http.Request, rep representation, data interface{}, lastModified time.Time,
+	modifiedSince bool) {
+	body, err := rep.encode(data)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", h.cacheControl)
+	w.Header().Add("Vary", "Accept")
+
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(r, etag, lastModified, modifiedSince) {
+		w.WriteHeader(http.StatusNotModified)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", rep.contentType)
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := w.Write(body); err != nil {
+		h.logger.ErrorContext(r.Context(), "error in writing response", "error", err)
+	}
+}
+
+// notModified evaluates If-None-Match, falling back to If-Modified-Since when it is absent
+func notModified(r *http.Request, etag string, lastModified time.Time, modifiedSince bool) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+
+			if tag == "*" || tag == etag {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if !modifiedSince || lastModified.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// lastModified is the time the most recently updated of the cars changed
+func lastModified(cars []models.Car) time.Time {
+	var last time.Time
+
+	for i := range cars {
+		if cars[i].UpdatedAt.After(last) {
+			last = cars[i].UpdatedAt
+		}
+	}
+
+	return last
+}
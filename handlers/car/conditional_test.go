@@ -0,0 +1,169 @@
+package car
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/amehrotra/car-dealership/authz"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+func TestHandler_GetByIDConditional(t *testing.T) {
+	updated := car
+	updated.UpdatedAt = time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	mockService := services.NewMockCar(ctrl)
+	h := New(mockService, logging.Discard(), WithCacheControl("private, max-age=60"))
+
+	mockService.EXPECT().GetByID(gomock.Any(), updated.ID).Return(&updated, nil).AnyTimes()
+
+	get := func(header, value string) *httptest.ResponseRecorder {
+		r := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "http://cars", http.NoBody), map[string]string{"id": updated.ID.String()})
+		if header != "" {
+			r.Header.Set(header, value)
+		}
+
+		w := httptest.NewRecorder()
+		h.GetByID(w, r)
+
+		return w
+	}
+
+	first := get("", "")
+	etag := first.Header().Get("ETag")
+
+	if first.Code != http.StatusOK || etag == "" || first.Header().Get("Last-Modified") != "Sat, 01 Jan 2022 10:00:00 GMT" ||
+		first.Header().Get("Cache-Control") != "private, max-age=60" {
+		t.Fatalf("\n[TEST] Failed. Desc : validators\nGot %v %v\nExpected 200 with ETag, Last-Modified and Cache-Control",
+			first.Code, first.Header())
+	}
+
+	cases := []struct {
+		desc       string
+		header     string
+		value      string
+		statusCode int
+	}{
+		{"same etag", "If-None-Match", etag, http.StatusNotModified},
+		{"one of several etags", "If-None-Match", `"other", W/` + etag, http.StatusNotModified},
+		{"any etag", "If-None-Match", "*", http.StatusNotModified},
+		{"changed etag", "If-None-Match", `"other"`, http.StatusOK},
+		{"not modified since", "If-Modified-Since", "Sat, 01 Jan 2022 10:00:00 GMT", http.StatusNotModified},
+		{"modified since", "If-Modified-Since", "Sat, 01 Jan 2022 09:59:59 GMT", http.StatusOK},
+		{"invalid date", "If-Modified-Since", "yesterday", http.StatusOK},
+	}
+
+	for i, tc := range cases {
+		w := get(tc.header, tc.value)
+
+		if w.Code != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, w.Code, tc.statusCode)
+		}
+
+		if tc.statusCode == http.StatusNotModified && (w.Body.Len() != 0 || w.Header().Get("ETag") != etag) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %q %v\nExpected empty body with the ETag", i, tc.desc, w.Body.String(), w.Header())
+		}
+	}
+}
+
+func TestHandler_GetAllConditional(t *testing.T) {
+	first := car
+	first.UpdatedAt = time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC)
+	second := car
+	second.UpdatedAt = time.Date(2022, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	mockService := services.NewMockCar(ctrl)
+	h := New(mockService, logging.Discard())
+
+	mockService.EXPECT().GetAll(gomock.Any(), filters.Car{}).Return([]models.Car{first, second}, nil).Times(2)
+
+	w := httptest.NewRecorder()
+	h.GetAll(w, httptest.NewRequest(http.MethodGet, "http://cars", http.NoBody))
+
+	if w.Header().Get("Last-Modified") != "Sun, 02 Jan 2022 10:00:00 GMT" || w.Header().Get("Cache-Control") != DefaultCacheControl {
+		t.Errorf("\n[TEST] Failed. Desc : list validators\nGot %v\nExpected Last-Modified of the latest car", w.Header())
+	}
+
+	// a deletion leaves the latest update untouched, so lists are only revalidated by their ETag
+	r := httptest.NewRequest(http.MethodGet, "http://cars", http.NoBody)
+	r.Header.Set("If-Modified-Since", "Sun, 02 Jan 2022 10:00:00 GMT")
+
+	w = httptest.NewRecorder()
+	h.GetAll(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("\n[TEST] Failed. Desc : list if modified since\nGot %v\nExpected %v", w.Code, http.StatusOK)
+	}
+}
+
+func TestHandler_GetByIDReservation(t *testing.T) {
+	updated := car
+	updated.UpdatedAt = time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC)
+	held := models.Reservation{ID: uuid.New(), CarID: updated.ID, CustomerID: uuid.New(), Deposit: 50000,
+		ExpiresAt: time.Date(2022, 1, 4, 11, 0, 0, 0, time.UTC), Status: models.ReservationActive,
+		CreatedAt: time.Date(2022, 1, 1, 11, 0, 0, 0, time.UTC)}
+	shown := models.Hold{Status: held.Status, ExpiresAt: held.ExpiresAt}
+	detailed := models.Hold{Status: held.Status, ExpiresAt: held.ExpiresAt, CustomerID: &held.CustomerID, Deposit: &held.Deposit}
+
+	ctrl := gomock.NewController(t)
+	mockService := services.NewMockCar(ctrl)
+	mockReservations := services.NewMockReservation(ctrl)
+	h := New(mockService, logging.Discard(), WithReservations(mockReservations))
+
+	mockService.EXPECT().GetByID(gomock.Any(), updated.ID).DoAndReturn(func(_ context.Context, _ uuid.UUID) (*models.Car, error) {
+		c := updated
+
+		return &c, nil
+	}).Times(3)
+	mockReservations.EXPECT().GetActive(gomock.Any(), updated.ID).Return(&held, nil).Times(2)
+	mockReservations.EXPECT().GetActive(gomock.Any(), updated.ID).Return(nil, nil)
+
+	// the hold expires without the car changing, If-Modified-Since would keep it in the cache of the client
+	get := func(role authz.Role) *httptest.ResponseRecorder {
+		r := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "http://cars", http.NoBody), map[string]string{"id": updated.ID.String()})
+		r = r.WithContext(authz.WithPrincipal(r.Context(), &models.Principal{Roles: []string{string(role)}}))
+		r.Header.Set("If-Modified-Since", "Sat, 01 Jan 2022 11:00:00 GMT")
+
+		w := httptest.NewRecorder()
+		h.GetByID(w, r)
+
+		return w
+	}
+
+	var got models.Car
+
+	w := get(authz.Salesperson)
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &got) != nil || !reflect.DeepEqual(got.Reservation, &detailed) ||
+		w.Header().Get("Last-Modified") != "Sat, 01 Jan 2022 11:00:00 GMT" {
+		t.Errorf("\n[TEST] Failed. Desc : reserved car\nGot %v %v %s\nExpected 200 with the reservation", w.Code, w.Header(), w.Body)
+	}
+
+	got = models.Car{}
+
+	// viewers, who may not read customers, only see that the car is held and until when
+	w = get(authz.Viewer)
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &got) != nil || !reflect.DeepEqual(got.Reservation, &shown) {
+		t.Errorf("\n[TEST] Failed. Desc : reserved car for a viewer\nGot %v %s\nExpected 200 with the hold", w.Code, w.Body)
+	}
+
+	got = models.Car{}
+
+	w = get(authz.Viewer)
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &got) != nil || got.Reservation != nil {
+		t.Errorf("\n[TEST] Failed. Desc : released car\nGot %v %s\nExpected 200 without a reservation", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,116 @@
+package car
+
+import (
+	goError "errors"
+	"net/http"
+	"strings"
+
+	"github.com/amehrotra/car-dealership/codec"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/handlers/response"
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// exportFormat is a file format cars can be exported as
+type exportFormat struct {
+	name        string
+	contentType string
+	encoder     func(w http.ResponseWriter) codec.Encoder
+}
+
+// nolint:gochecknoglobals // read only list of formats, the first is the default
+var exportFormats = []exportFormat{
+	{"csv", codec.CSV + "; charset=utf-8", func(w http.ResponseWriter) codec.Encoder { return codec.NewCSVEncoder(w) }},
+	{"ndjson", codec.NDJSON, func(w http.ResponseWriter) codec.Encoder { return codec.NewNDJSONEncoder(w) }},
+	{"xlsx", codec.XLSX, func(w http.ResponseWriter) codec.Encoder { return codec.NewXLSXEncoder(w) }},
+}
+
+// nolint:gochecknoglobals // sentinel error
+var errNotAcceptable = goError.New("accept must allow " + codec.CSV + ", " + codec.NDJSON + " or " + codec.XLSX)
+
+// Export streams the cars in stock with their engines as csv, ndjson or xlsx.
+// The format is taken from the format query parameter or else the Accept header, brand filters like in GetAll.
+// The status is sent with the first row, a failure after it aborts the response so that clients notice the truncation.
+func (h handler) Export(w http.ResponseWriter, r *http.Request) {
+	format, err := getExportFormat(r)
+	if err == errNotAcceptable {
+		response.WriteError(w, r, h.logger, http.StatusNotAcceptable, "not_acceptable", err.Error())
+
+		return
+	}
+
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	var (
+		encoder = format.encoder(w)
+		started bool
+	)
+
+	start := func() {
+		if started {
+			return
+		}
+
+		started = true
+
+		w.Header().Set("Content-Type", format.contentType)
+		w.Header().Set("Content-Disposition", `attachment; filename="cars.`+format.name+`"`)
+		w.WriteHeader(http.StatusOK)
+	}
+
+	filter := filters.Car{Brand: strings.TrimSpace(r.URL.Query().Get("brand")), Engine: true}
+
+	err = h.service.Export(r.Context(), filter, func(car *models.Car) error {
+		start()
+
+		return encoder.Encode(car)
+	})
+	if err == nil {
+		start()
+
+		err = encoder.Flush()
+	}
+
+	switch {
+	case err == nil:
+	case !started:
+		response.Write(w, r, h.logger, nil, err)
+	default:
+		h.logger.ErrorContext(r.Context(), "error in exporting cars", "error", err)
+
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// getExportFormat picks the format named by the format query parameter, else the most preferred one of the Accept header
+func getExportFormat(r *http.Request) (exportFormat, error) {
+	if name := r.URL.Query().Get("format"); name != "" {
+		for _, format := range exportFormats {
+			if strings.EqualFold(name, format.name) {
+				return format, nil
+			}
+		}
+
+		return exportFormat{}, errors.InvalidParam{Param: []string{"format"}}
+	}
+
+	accept := r.Header.Get("Accept")
+	if strings.TrimSpace(accept) == "" {
+		return exportFormats[0], nil
+	}
+
+	for _, rng := range acceptedRanges(accept) {
+		for _, format := range exportFormats {
+			if rng.allows(format.contentType) {
+				return format, nil
+			}
+		}
+	}
+
+	return exportFormat{}, errNotAcceptable
+}
@@ -0,0 +1,114 @@
+package car
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/amehrotra/car-dealership/codec"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// exportCars calls fn with the car twice
+func exportCars(_ interface{}, _ filters.Car, fn func(car *models.Car) error) error {
+	for i := 0; i < 2; i++ {
+		c := car
+		if err := fn(&c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestHandler_Export(t *testing.T) {
+	cases := []struct {
+		desc        string
+		query       url.Values
+		accept      string
+		call        bool
+		mockErr     error
+		statusCode  int
+		contentType string
+	}{
+		{"default csv", nil, "", true, nil, http.StatusOK, codec.CSV + "; charset=utf-8"},
+		{"format parameter", url.Values{"format": {"NDJSON"}, "brand": {"bmw"}}, codec.CSV, true, nil, http.StatusOK, codec.NDJSON},
+		{"accept header", nil, "text/csv;q=0.5, " + codec.XLSX, true, nil, http.StatusOK, codec.XLSX},
+		{"accept wildcard", nil, "application/*", true, nil, http.StatusOK, codec.NDJSON},
+		{"not acceptable", nil, "application/json", false, nil, http.StatusNotAcceptable, "application/json"},
+		{"invalid format", url.Values{"format": {"pdf"}}, "", false, nil, http.StatusBadRequest, "application/json"},
+		{"service error", nil, "", true, errors.InvalidParam{Param: []string{"brand"}}, http.StatusBadRequest, "application/json"},
+	}
+
+	for i, tc := range cases {
+		h, mockService, r, w := initializeTest(t, http.MethodGet, nil, nil, tc.query)
+		r.Header.Set("Accept", tc.accept)
+
+		if tc.call {
+			filter := filters.Car{Brand: tc.query.Get("brand"), Engine: true}
+
+			if tc.mockErr != nil {
+				mockService.EXPECT().Export(gomock.Any(), filter, gomock.Any()).Return(tc.mockErr)
+			} else {
+				mockService.EXPECT().Export(gomock.Any(), filter, gomock.Any()).DoAndReturn(exportCars)
+			}
+		}
+
+		h.Export(w, r)
+
+		resp := w.Result()
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != tc.statusCode || resp.Header.Get("Content-Type") != tc.contentType {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v %v\nExpected %v %v", i, tc.desc, resp.StatusCode,
+				resp.Header.Get("Content-Type"), tc.statusCode, tc.contentType)
+		}
+
+		if tc.statusCode != http.StatusOK {
+			continue
+		}
+
+		switch tc.contentType {
+		case codec.XLSX:
+			if _, err := zip.NewReader(bytes.NewReader(body), int64(len(body))); err != nil {
+				t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected a workbook", i, tc.desc, err)
+			}
+		case codec.NDJSON:
+			if strings.Count(string(body), "\n") != 2 {
+				t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %s\nExpected 2 lines", i, tc.desc, body)
+			}
+		default:
+			if !strings.HasPrefix(string(body), "id,brand,model") || strings.Count(string(body), "\n") != 3 {
+				t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %s\nExpected a header and 2 rows", i, tc.desc, body)
+			}
+		}
+	}
+}
+
+func TestHandler_ExportAbort(t *testing.T) {
+	h, mockService, r, w := initializeTest(t, http.MethodGet, nil, nil, nil)
+
+	mockService.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx interface{}, filter filters.Car, fn func(car *models.Car) error) error {
+			_ = fn(&car)
+
+			return errors.DB{}
+		})
+
+	defer func() {
+		if rec := recover(); rec != http.ErrAbortHandler {
+			t.Errorf("\n[TEST] Failed. Desc : failure after the first row\nGot %v\nExpected %v", rec, http.ErrAbortHandler)
+		}
+	}()
+
+	h.Export(w, r)
+}
@@ -1,28 +1,40 @@
 package car
 
 import (
+	"context"
 	"encoding/json"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"strings"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 
+	"github.com/amehrotra/car-dealership/authz"
 	"github.com/amehrotra/car-dealership/errors"
 	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/handlers/response"
 	"github.com/amehrotra/car-dealership/models"
 	"github.com/amehrotra/car-dealership/services"
 )
 
 type handler struct {
-	service services.Car
+	service      services.Car
+	logger       *slog.Logger
+	cacheControl string
+	reservations services.Reservation
 }
 
 // nolint:revive // handler should not be exported
-func New(service services.Car) handler {
-	return handler{service: service}
+func New(service services.Car, logger *slog.Logger, opts ...Option) handler {
+	h := handler{service: service, logger: logger, cacheControl: DefaultCacheControl}
+
+	for _, opt := range opts {
+		opt(&h)
+	}
+
+	return h
 }
 
 // Create takes the clients request to create entity in database
@@ -31,17 +43,24 @@ func (h handler) Create(w http.ResponseWriter, r *http.Request) {
 
 	car, err := getCar(r)
 	if err != nil {
-		setStatusCode(w, r.Method, car, err)
+		response.Write(w, r, h.logger, car, err)
 
 		return
 	}
 
-	car, err = h.service.Create(car)
-	setStatusCode(w, r.Method, car, err)
+	car, err = h.service.Create(r.Context(), car)
+	response.Write(w, r, h.logger, car, err)
 }
 
-// GetAll writes all the cars from the database based on the query parameter
+// GetAll writes all the cars from the database based on the query parameter, as json, csv or MessagePack
 func (h handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	rep, err := negotiate(r.Header.Get("Accept"))
+	if err != nil {
+		response.WriteError(w, r, h.logger, http.StatusNotAcceptable, "not_acceptable", err.Error())
+
+		return
+	}
+
 	query := r.URL.Query()
 
 	var hasEngine bool
@@ -55,57 +74,110 @@ func (h handler) GetAll(w http.ResponseWriter, r *http.Request) {
 
 	filter := filters.Car{Brand: brand, Engine: hasEngine}
 
-	resp, err := h.service.GetAll(filter)
-	setStatusCode(w, r.Method, resp, err)
+	resp, err := h.service.GetAll(r.Context(), filter)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	h.writeConditional(w, r, rep, resp, lastModified(resp), false)
 }
 
-// GetByID writes the response based on ID of the resp
+// GetByID writes the response based on ID of the resp, as json, csv or MessagePack
 func (h handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	rep, err := negotiate(r.Header.Get("Accept"))
+	if err != nil {
+		response.WriteError(w, r, h.logger, http.StatusNotAcceptable, "not_acceptable", err.Error())
+
+		return
+	}
+
 	id, err := getID(r)
 	if err != nil {
-		setStatusCode(w, r.Method, nil, err)
+		response.Write(w, r, h.logger, nil, err)
 
 		return
 	}
 
-	car, err := h.service.GetByID(id)
-	log.Println(err)
-	setStatusCode(w, r.Method, car, err)
+	car, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	if h.reservations == nil {
+		h.writeConditional(w, r, rep, car, car.UpdatedAt, true)
+
+		return
+	}
+
+	// reservations expire without the car or them changing, so only the ETag tells whether the read is still current
+	held, err := h.reservations.GetActive(r.Context(), id)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	modified := car.UpdatedAt
+
+	if held != nil {
+		car.Reservation = hold(r.Context(), held)
+
+		if held.CreatedAt.After(modified) {
+			modified = held.CreatedAt
+		}
+	}
+
+	h.writeConditional(w, r, rep, car, modified, false)
 }
 
 // Update writes the updated resp entity in the database
 func (h handler) Update(w http.ResponseWriter, r *http.Request) {
 	id, err := getID(r)
 	if err != nil {
-		setStatusCode(w, r.Method, nil, err)
+		response.Write(w, r, h.logger, nil, err)
 
 		return
 	}
 
 	car, err := getCar(r)
 	if err != nil {
-		setStatusCode(w, r.Method, car, err)
+		response.Write(w, r, h.logger, car, err)
 
 		return
 	}
 
 	car.ID = id
 
-	car, err = h.service.Update(car)
-	setStatusCode(w, r.Method, car, err)
+	car, err = h.service.Update(r.Context(), car)
+	response.Write(w, r, h.logger, car, err)
 }
 
 // Delete removes the resp from database based on ID
 func (h handler) Delete(w http.ResponseWriter, r *http.Request) {
 	id, err := getID(r)
 	if err != nil {
-		setStatusCode(w, r.Method, nil, err)
+		response.Write(w, r, h.logger, nil, err)
 
 		return
 	}
 
-	err = h.service.Delete(id)
-	setStatusCode(w, r.Method, nil, err)
+	err = h.service.Delete(r.Context(), id)
+	response.Write(w, r, h.logger, nil, err)
+}
+
+// hold shows the reservation with its car, with the customer and the deposit only for principals who may read customers
+func hold(ctx context.Context, reservation *models.Reservation) *models.Hold {
+	h := &models.Hold{Status: reservation.Status, ExpiresAt: reservation.ExpiresAt}
+
+	if authz.Check(ctx, authz.ReadCustomers) == nil {
+		h.CustomerID, h.Deposit = &reservation.CustomerID, &reservation.Deposit
+	}
+
+	return h
 }
 
 // getID reads the id from path parameter of url
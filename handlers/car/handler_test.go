@@ -10,7 +10,6 @@ import (
 	"net/url"
 	"reflect"
 	"strconv"
-	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -19,6 +18,7 @@ import (
 
 	"github.com/amehrotra/car-dealership/errors"
 	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/logging"
 	"github.com/amehrotra/car-dealership/models"
 	"github.com/amehrotra/car-dealership/services"
 	"github.com/amehrotra/car-dealership/types"
@@ -29,7 +29,7 @@ func initializeTest(t *testing.T, method string, body io.Reader, pParam map[stri
 	ctrl := gomock.NewController(t)
 
 	mockService := services.NewMockCar(ctrl)
-	h := New(mockService)
+	h := New(mockService, logging.Discard())
 
 	req := httptest.NewRequest(method, "http://cars", body)
 	r := mux.SetURLVars(req, pParam)
@@ -71,7 +71,7 @@ func TestHandler_Create(t *testing.T) {
 	for i, tc := range cases {
 		h, mockService, r, w := initializeTest(t, http.MethodPost, bytes.NewReader(body), nil, nil)
 
-		mockService.EXPECT().Create(&car).Return(tc.mockOutput, tc.mockErr)
+		mockService.EXPECT().Create(gomock.Any(), &car).Return(tc.mockOutput, tc.mockErr)
 
 		h.Create(w, r)
 
@@ -165,7 +165,7 @@ func TestHandler_GetAll(t *testing.T) {
 
 		h, mockService, r, w := initializeTest(t, http.MethodGet, http.NoBody, nil, params)
 
-		mockService.EXPECT().GetAll(tc.filter).Return(tc.mockOutput, tc.mockErr)
+		mockService.EXPECT().GetAll(gomock.Any(), tc.filter).Return(tc.mockOutput, tc.mockErr)
 
 		h.GetAll(w, r)
 
@@ -208,7 +208,7 @@ func TestHandler_GetByID(t *testing.T) {
 	for i, tc := range cases {
 		h, mockService, r, w := initializeTest(t, http.MethodGet, http.NoBody, map[string]string{"id": id.URN()}, nil)
 
-		mockService.EXPECT().GetByID(id).Return(tc.mockOutput, tc.mockErr)
+		mockService.EXPECT().GetByID(gomock.Any(), id).Return(tc.mockOutput, tc.mockErr)
 
 		h.GetByID(w, r)
 
@@ -283,7 +283,7 @@ func TestHandler_Update(t *testing.T) {
 
 		h, mockService, r, w := initializeTest(t, http.MethodPut, bytes.NewReader(body), param, nil)
 
-		mockService.EXPECT().Update(&car).Return(tc.resp, tc.mockErr)
+		mockService.EXPECT().Update(gomock.Any(), &car).Return(tc.resp, tc.mockErr)
 
 		h.Update(w, r)
 
@@ -364,13 +364,14 @@ func TestHandler_Delete(t *testing.T) {
 	}{
 		{"delete successful", nil, http.StatusNoContent},
 		{"entity does not exist", errors.EntityNotFound{}, http.StatusNotFound},
+		{"permission denied", errors.Forbidden{Permission: "car:delete"}, http.StatusForbidden},
 		{"internal server error", errors.DB{}, http.StatusInternalServerError},
 	}
 
 	for i, tc := range cases {
 		h, mockService, r, w := initializeTest(t, http.MethodDelete, http.NoBody, map[string]string{"id": id.URN()}, nil)
 
-		mockService.EXPECT().Delete(id).Return(tc.mockErr)
+		mockService.EXPECT().Delete(gomock.Any(), id).Return(tc.mockErr)
 
 		h.Delete(w, r)
 
@@ -469,61 +470,12 @@ func Test_getID(t *testing.T) {
 	}
 }
 
-func Test_writeResponseBodyMarshalError(t *testing.T) {
-	data := complex(1, 1)
-
-	w := httptest.NewRecorder()
-	expectedStatusCode := http.StatusInternalServerError
-
-	writeResponseBody(w, http.StatusOK, data)
-
-	resp := w.Result()
-	defer resp.Body.Close()
-
-	if resp.StatusCode != expectedStatusCode {
-		t.Errorf("\n[TEST] Failed. Desc : Marshal Error \nGot %v\nExpected %v", resp.StatusCode, http.StatusInternalServerError)
-	}
-}
-
-func Test_writeResponseBodyWriteError(t *testing.T) {
-	data := []byte(`{"id":"8f443772-132b-4ae5-9f8f-9960649b3fb4","model":"x","yearOfManufacture":2020,"brand":"BMW","fuelType":"petrol",
-		"engine":{"displacement":200,"noOfCylinder":2,"range":0}}`)
-
-	w := mockResponseWriter{}
-
-	var b bytes.Buffer
-
-	log.SetOutput(&b)
-
-	writeResponseBody(w, http.StatusOK, data)
-
-	if !strings.Contains(b.String(), "error in writing response") {
-		t.Errorf("\n[TEST] Failed. Desc : Write Error \nGot %v\nExpected 'error in writing response' in logs", b.String())
-	}
-}
-
 type mockReader struct{}
 
 func (m mockReader) Read(p []byte) (n int, err error) {
 	return 0, errors.InvalidParam{}
 }
 
-type mockResponseWriter struct{}
-
-func (m mockResponseWriter) Header() http.Header {
-	header := make(map[string][]string)
-
-	return header
-}
-
-func (m mockResponseWriter) Write([]byte) (int, error) {
-	return 0, errors.InvalidParam{}
-}
-
-func (m mockResponseWriter) WriteHeader(statusCode int) {
-
-}
-
 func getResponseBody(resp *http.Response) ([]byte, error) {
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -538,7 +490,15 @@ func getResponseBody(resp *http.Response) ([]byte, error) {
 }
 
 func getOutput(t *testing.T, respBody []byte) *models.Car {
-	var output *models.Car
+	var (
+		output  *models.Car
+		errBody models.Error
+	)
+
+	// error bodies carry a code and are not cars
+	if json.Unmarshal(respBody, &errBody) == nil && errBody.Code != "" {
+		return nil
+	}
 
 	if len(respBody) != 0 {
 		output = &models.Car{}
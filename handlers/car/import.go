@@ -0,0 +1,138 @@
+package car
+
+import (
+	goError "errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/amehrotra/car-dealership/codec"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/handlers/response"
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// maxImportBytes bounds the size of an import file
+const maxImportBytes = 32 << 20
+
+// Import reads a csv or ndjson file of cars and imports its rows.
+// ?dryRun=true only validates the rows and ?mode=bestEffort imports the valid rows even when others fail.
+func (h handler) Import(w http.ResponseWriter, r *http.Request) {
+	opts, err := getImportOptions(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	decoder, err := getDecoder(r, http.MaxBytesReader(w, r.Body, maxImportBytes))
+	if err == errUnsupportedMediaType {
+		response.WriteError(w, r, h.logger, http.StatusUnsupportedMediaType, "unsupported_media_type", err.Error())
+
+		return
+	}
+
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	rows := make([]models.ImportRow, 0)
+
+	for {
+		var car models.Car
+
+		err := decoder.Decode(&car)
+		if err == io.EOF {
+			break
+		}
+
+		var rowErr codec.RowError
+
+		switch {
+		case goError.As(err, &rowErr):
+			rows = append(rows, models.ImportRow{Row: rowErr.Row, Err: rowErr.Err})
+		case err != nil:
+			response.Write(w, r, h.logger, nil, errors.InvalidParam{Param: []string{"body"}})
+
+			return
+		default:
+			rows = append(rows, models.ImportRow{Row: len(rows) + 1, Car: car})
+		}
+	}
+
+	result, err := h.service.Import(r.Context(), rows, opts)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	// nothing was, or on a dry run would be, imported because of the failed rows
+	if result.Failed > 0 && result.Imported == 0 {
+		response.WriteBody(w, r, h.logger, http.StatusUnprocessableEntity, result)
+
+		return
+	}
+
+	response.WriteBody(w, r, h.logger, http.StatusOK, result)
+}
+
+// nolint:gochecknoglobals // sentinel error
+var errUnsupportedMediaType = goError.New("content type must be " + codec.CSV + " or " + codec.NDJSON)
+
+// getDecoder returns the decoder of the content type of the request
+func getDecoder(r *http.Request, body io.Reader) (codec.Decoder, error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, errUnsupportedMediaType
+	}
+
+	switch mediaType {
+	case codec.CSV:
+		return codec.NewCSVDecoder(body)
+	case codec.NDJSON, "application/ndjson", "application/jsonl":
+		return codec.NewNDJSONDecoder(body), nil
+	default:
+		return nil, errUnsupportedMediaType
+	}
+}
+
+// getImportOptions reads the dryRun and mode query parameters
+func getImportOptions(r *http.Request) (models.ImportOptions, error) {
+	var opts models.ImportOptions
+
+	query := r.URL.Query()
+
+	if v := query.Get("dryRun"); v != "" {
+		dryRun, err := strconv.ParseBool(v)
+		if err != nil {
+			return opts, errors.InvalidParam{Param: []string{"dryRun"}}
+		}
+
+		opts.DryRun = dryRun
+	}
+
+	bestEffort, err := getMode(r)
+	if err != nil {
+		return opts, err
+	}
+
+	opts.BestEffort = bestEffort
+
+	return opts, nil
+}
+
+// getMode reads the mode query parameter, atomic by default or bestEffort
+func getMode(r *http.Request) (bool, error) {
+	switch r.URL.Query().Get("mode") {
+	case "", "atomic":
+		return false, nil
+	case "bestEffort":
+		return true, nil
+	default:
+		return false, errors.InvalidParam{Param: []string{"mode"}}
+	}
+}
@@ -0,0 +1,95 @@
+package car
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/amehrotra/car-dealership/codec"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/models"
+)
+
+func TestHandler_Import(t *testing.T) {
+	csv := "brand,model,yearOfManufacture,fuelType,price,displacement,noOfCylinder,range\n" +
+		"BMW,X,2020,petrol,0,200,2,0\n" +
+		"BMW,X,2020,coal,0,200,2,0\n"
+
+	imported := &models.ImportResult{Total: 2, Imported: 1, Failed: 1}
+	rejected := &models.ImportResult{Total: 2, Failed: 1}
+
+	cases := []struct {
+		desc        string
+		contentType string
+		body        string
+		query       url.Values
+		rows        int
+		opts        models.ImportOptions
+		mockOutput  *models.ImportResult
+		mockErr     error
+		statusCode  int
+	}{
+		{"csv", codec.CSV, csv, nil, 2, models.ImportOptions{}, imported, nil, http.StatusOK},
+		{"ndjson best effort dry run", codec.NDJSON, `{"model":"X"}` + "\n\n" + `{"model":"Y"}`,
+			url.Values{"mode": {"bestEffort"}, "dryRun": {"true"}}, 2, models.ImportOptions{DryRun: true, BestEffort: true}, imported, nil,
+			http.StatusOK},
+		{"nothing imported", codec.CSV, csv, nil, 2, models.ImportOptions{}, rejected, nil, http.StatusUnprocessableEntity},
+		{"service error", codec.CSV, csv, nil, 2, models.ImportOptions{}, nil, errors.DB{}, http.StatusInternalServerError},
+		{"unsupported content type", "application/json", "[]", nil, 0, models.ImportOptions{}, nil, nil, http.StatusUnsupportedMediaType},
+		{"missing columns", codec.CSV, "brand,model\nBMW,X\n", nil, 0, models.ImportOptions{}, nil, nil, http.StatusBadRequest},
+		{"invalid mode", codec.CSV, csv, url.Values{"mode": {"fast"}}, 0, models.ImportOptions{}, nil, nil, http.StatusBadRequest},
+	}
+
+	for i, tc := range cases {
+		h, mockService, r, w := initializeTest(t, http.MethodPost, strings.NewReader(tc.body), nil, tc.query)
+		r.Header.Set("Content-Type", tc.contentType)
+
+		if tc.rows > 0 {
+			mockService.EXPECT().Import(gomock.Any(), gomock.Len(tc.rows), tc.opts).Return(tc.mockOutput, tc.mockErr)
+		}
+
+		h.Import(w, r)
+
+		resp := w.Result()
+
+		if resp.StatusCode != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, resp.StatusCode, tc.statusCode)
+		}
+
+		if tc.mockOutput != nil {
+			var result models.ImportResult
+
+			_ = json.NewDecoder(resp.Body).Decode(&result)
+
+			if !reflect.DeepEqual(&result, tc.mockOutput) {
+				t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, result, tc.mockOutput)
+			}
+		}
+
+		resp.Body.Close()
+	}
+}
+
+func TestHandler_ImportRowErrors(t *testing.T) {
+	csv := "brand,model,yearOfManufacture,fuelType,price,displacement,noOfCylinder,range\n" +
+		"BMW,X,2020,coal,0,200,2,0\n"
+
+	h, mockService, r, w := initializeTest(t, http.MethodPost, strings.NewReader(csv), nil, nil)
+	r.Header.Set("Content-Type", "text/csv; charset=utf-8")
+
+	mockService.EXPECT().Import(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ interface{}, rows []models.ImportRow, _ models.ImportOptions) (*models.ImportResult, error) {
+			if len(rows) != 1 || rows[0].Row != 1 || !reflect.DeepEqual(rows[0].Err, errors.InvalidParam{Param: []string{"fuelType"}}) {
+				t.Errorf("\n[TEST] Failed. Desc : unreadable row\nGot %+v\nExpected row 1 to fail on fuelType", rows)
+			}
+
+			return &models.ImportResult{Total: 1, Failed: 1}, nil
+		})
+
+	h.Import(w, r)
+}
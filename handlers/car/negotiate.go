@@ -0,0 +1,168 @@
+package car
+
+import (
+	"bytes"
+	"encoding/json"
+	goError "errors"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/amehrotra/car-dealership/codec"
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// MessagePack is the media type of MessagePack bodies
+const MessagePack = "application/msgpack"
+
+// representation is a media type the cars can be read as
+type representation struct {
+	contentType string
+	encode      func(data interface{}) ([]byte, error)
+}
+
+// nolint:gochecknoglobals // read only list of representations, the first is the default
+var representations = []representation{
+	{"application/json", json.Marshal},
+	{codec.CSV + "; charset=utf-8", encodeCSV},
+	{MessagePack, encodeMessagePack},
+	{"application/x-msgpack", encodeMessagePack},
+}
+
+// nolint:gochecknoglobals // sentinel error
+var errNotAcceptableRepresentation = goError.New("accept must allow application/json, " + codec.CSV + " or " + MessagePack)
+
+// mediaRange is a media range of the Accept header along with its weight
+type mediaRange struct {
+	mediaType string
+	q         float64
+}
+
+// allows reports whether the range matches the content type, parameters of the content type are ignored
+func (m mediaRange) allows(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	typ, _, _ := strings.Cut(mediaType, "/")
+
+	return m.mediaType == mediaType || m.mediaType == "*/*" || m.mediaType == typ+"/*"
+}
+
+// acceptedRanges parses the media ranges of an Accept header, most preferred first, leaving out refused and malformed ones
+func acceptedRanges(accept string) []mediaRange {
+	ranges := make([]mediaRange, 0)
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+
+		if q > 0 {
+			ranges = append(ranges, mediaRange{mediaType: mediaType, q: q})
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	return ranges
+}
+
+// negotiate picks the most preferred representation of the Accept header, json when there is none
+func negotiate(accept string) (representation, error) {
+	if strings.TrimSpace(accept) == "" {
+		return representations[0], nil
+	}
+
+	for _, rng := range acceptedRanges(accept) {
+		for _, rep := range representations {
+			if rng.allows(rep.contentType) {
+				return rep, nil
+			}
+		}
+	}
+
+	return representation{}, errNotAcceptableRepresentation
+}
+
+// encodeCSV writes a car or a list of cars as the rows of a csv file with the columns of the exports
+func encodeCSV(data interface{}) ([]byte, error) {
+	var cars []models.Car
+
+	switch v := data.(type) {
+	case *models.Car:
+		cars = []models.Car{*v}
+	case []models.Car:
+		cars = v
+	default:
+		return nil, goError.New("only cars are written as csv")
+	}
+
+	var buf bytes.Buffer
+
+	encoder := codec.NewCSVEncoder(&buf)
+
+	for i := range cars {
+		if err := encoder.Encode(&cars[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// encodeMessagePack writes the data with the same fields and values as its json, through its json
+func encodeMessagePack(data interface{}) ([]byte, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+
+	var v interface{}
+
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return msgpack.Marshal(numbers(v))
+}
+
+// numbers turns the json numbers of a decoded value into integers where they are whole and floats otherwise
+func numbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+
+		f, _ := v.Float64()
+
+		return f
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = numbers(v[k])
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = numbers(v[i])
+		}
+	}
+
+	return v
+}
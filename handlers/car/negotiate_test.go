@@ -0,0 +1,88 @@
+package car
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+func TestHandler_GetByIDNegotiation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := services.NewMockCar(ctrl)
+	h := New(mockService, logging.Discard())
+
+	mockService.EXPECT().GetByID(gomock.Any(), car.ID).Return(&car, nil).AnyTimes()
+
+	cases := []struct {
+		desc        string
+		accept      string
+		statusCode  int
+		contentType string
+	}{
+		{"default", "", http.StatusOK, "application/json"},
+		{"any", "*/*", http.StatusOK, "application/json"},
+		{"csv", "text/csv", http.StatusOK, "text/csv; charset=utf-8"},
+		{"preferred csv", "application/json;q=0.5, text/*", http.StatusOK, "text/csv; charset=utf-8"},
+		{"msgpack", "application/msgpack", http.StatusOK, MessagePack},
+		{"unsupported", "application/xml", http.StatusNotAcceptable, "application/json"},
+	}
+
+	for i, tc := range cases {
+		r := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "http://cars", http.NoBody), map[string]string{"id": car.ID.String()})
+		r.Header.Set("Accept", tc.accept)
+
+		w := httptest.NewRecorder()
+		h.GetByID(w, r)
+
+		if w.Code != tc.statusCode || w.Header().Get("Content-Type") != tc.contentType {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v %v\nExpected %v %v", i, tc.desc, w.Code, w.Header().Get("Content-Type"),
+				tc.statusCode, tc.contentType)
+		}
+	}
+}
+
+func TestHandler_GetAllRepresentations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := services.NewMockCar(ctrl)
+	h := New(mockService, logging.Discard())
+
+	mockService.EXPECT().GetAll(gomock.Any(), filters.Car{}).Return([]models.Car{car}, nil).Times(2)
+
+	get := func(accept string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "http://cars", http.NoBody)
+		r.Header.Set("Accept", accept)
+
+		w := httptest.NewRecorder()
+		h.GetAll(w, r)
+
+		return w
+	}
+
+	w := get("text/csv")
+	if lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n"); len(lines) != 2 ||
+		!strings.HasPrefix(lines[1], car.ID.String()+",BMW,X,2020,petrol") {
+		t.Errorf("\n[TEST] Failed. Desc : csv\nGot %q\nExpected the header and a row per car", w.Body.String())
+	}
+
+	var cars []map[string]interface{}
+
+	w = get(MessagePack)
+
+	decoder := msgpack.NewDecoder(w.Body)
+	decoder.UseLooseInterfaceDecoding(true)
+
+	if err := decoder.Decode(&cars); err != nil || len(cars) != 1 || cars[0]["id"] != car.ID.String() ||
+		cars[0]["fuelType"] != "petrol" || cars[0]["yearOfManufacture"] != int64(2020) {
+		t.Errorf("\n[TEST] Failed. Desc : msgpack\nGot %v %v\nExpected the fields of the json", cars, err)
+	}
+}
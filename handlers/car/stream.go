@@ -0,0 +1,96 @@
+package car
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/handlers/response"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+// keepAlive is how often an idle stream sends a comment so that proxies do not time it out
+const keepAlive = 15 * time.Second
+
+type streamHandler struct {
+	handler
+	stream services.Stream
+}
+
+// nolint:revive // handler should not be exported
+func NewStream(stream services.Stream, logger *slog.Logger) streamHandler {
+	return streamHandler{handler: handler{logger: logger}, stream: stream}
+}
+
+// Stream pushes the changes to the cars as Server-Sent Events named after the type of the event, with the event as data.
+// Brand filters like in GetAll and the Last-Event-ID header resumes after the given event.
+func (h streamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	filter := filters.Car{Brand: strings.TrimSpace(r.URL.Query().Get("brand"))}
+
+	messages, err := h.stream.Subscribe(r.Context(), filter, r.Header.Get("Last-Event-ID"))
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+
+	if _, err := fmt.Fprint(w, "retry: 3000\n\n"); err != nil {
+		return
+	}
+
+	if err := rc.Flush(); err != nil {
+		h.logger.ErrorContext(r.Context(), "error in flushing event stream", "error", err)
+
+		return
+	}
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+
+	for {
+		var err error
+
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			err = writeEvent(w, msg)
+		case <-ticker.C:
+			_, err = fmt.Fprint(w, ": keep-alive\n\n")
+		}
+
+		if err == nil {
+			err = rc.Flush()
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// writeEvent writes the message as an event, a reset clears the last event id of the client
+func writeEvent(w http.ResponseWriter, msg models.Message) error {
+	if msg.Type == models.StreamReset {
+		_, err := fmt.Fprint(w, "id:\nevent: reset\ndata: {}\n\n")
+
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", msg.ID, msg.Type, msg.Payload)
+
+	return err
+}
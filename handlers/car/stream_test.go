@@ -0,0 +1,60 @@
+package car
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+func TestHandler_Stream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStream := services.NewMockStream(ctrl)
+	h := NewStream(mockStream, logging.Discard())
+
+	id := uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+	messages := make(chan models.Message, 2)
+	messages <- models.Message{Type: models.StreamReset}
+	messages <- models.Message{ID: id, Type: models.CarCreated, Payload: []byte(`{"type":"car.created"}`)}
+	close(messages)
+
+	mockStream.EXPECT().Subscribe(gomock.Any(), filters.Car{Brand: "tesla"}, "last").Return((<-chan models.Message)(messages), nil)
+
+	r := httptest.NewRequest(http.MethodGet, "http://car/stream?brand=tesla", nil)
+	r.Header.Set("Last-Event-ID", "last")
+	w := httptest.NewRecorder()
+
+	h.Stream(w, r)
+
+	expected := "retry: 3000\n\nid:\nevent: reset\ndata: {}\n\n" +
+		"id: 8f443772-132b-4ae5-9f8f-9960649b3fb4\nevent: car.created\ndata: {\"type\":\"car.created\"}\n\n"
+
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "text/event-stream" || w.Body.String() != expected {
+		t.Errorf("\n[TEST] Failed. Desc : stream\nGot %v %v\nExpected %v", w.Code, w.Body.String(), expected)
+	}
+}
+
+func TestHandler_StreamError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStream := services.NewMockStream(ctrl)
+	h := NewStream(mockStream, logging.Discard())
+
+	mockStream.EXPECT().Subscribe(gomock.Any(), filters.Car{Brand: "fiat"}, "").Return(nil, errors.InvalidParam{Param: []string{"brand"}})
+
+	r := httptest.NewRequest(http.MethodGet, "http://car/stream?brand=fiat", nil)
+	w := httptest.NewRecorder()
+
+	h.Stream(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("\n[TEST] Failed. Desc : invalid brand\nGot %v\nExpected %v", w.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,131 @@
+package customer
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/handlers/response"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+type handler struct {
+	service services.Customer
+	logger  *slog.Logger
+}
+
+// nolint:revive // handler should not be exported
+func New(service services.Customer, logger *slog.Logger) handler {
+	return handler{service: service, logger: logger}
+}
+
+// Create records a customer and writes it back
+func (h handler) Create(w http.ResponseWriter, r *http.Request) {
+	customer, err := getCustomer(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	customer, err = h.service.Create(r.Context(), customer)
+	response.Write(w, r, h.logger, customer, err)
+}
+
+// GetAll writes the customers with their personal details masked, searched by the email and phone query parameters
+func (h handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	customers, err := h.service.GetAll(r.Context(), filters.Customer{Email: query.Get("email"), Phone: query.Get("phone")})
+	response.Write(w, r, h.logger, customers, err)
+}
+
+// GetByID writes the customer based on ID with all their details
+func (h handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getID(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	customer, err := h.service.GetByID(r.Context(), id)
+	response.Write(w, r, h.logger, customer, err)
+}
+
+// Update replaces the details of the customer based on ID
+func (h handler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := getID(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	customer, err := getCustomer(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	customer.ID = id
+
+	customer, err = h.service.Update(r.Context(), customer)
+	response.Write(w, r, h.logger, customer, err)
+}
+
+// Delete removes the customer based on ID
+func (h handler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := getID(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	err = h.service.Delete(r.Context(), id)
+	response.Write(w, r, h.logger, nil, err)
+}
+
+// getID reads the id from the path parameter of url
+func getID(r *http.Request) (uuid.UUID, error) {
+	param := mux.Vars(r)
+	idParam := strings.TrimSpace(param["id"])
+
+	if idParam == "" {
+		return uuid.Nil, errors.MissingParam{Param: "id"}
+	}
+
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		return uuid.Nil, errors.InvalidParam{Param: []string{"id"}}
+	}
+
+	return id, nil
+}
+
+// getCustomer reads request body and returns the customer
+func getCustomer(r *http.Request) (*models.Customer, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	var customer models.Customer
+
+	err = json.Unmarshal(body, &customer)
+	if err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	return &customer, nil
+}
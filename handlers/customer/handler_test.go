@@ -0,0 +1,126 @@
+package customer
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+func initializeTest(t *testing.T, method string, body io.Reader, pParam map[string]string) (handler, *services.MockCustomer,
+	*http.Request, *httptest.ResponseRecorder) {
+	ctrl := gomock.NewController(t)
+
+	mockService := services.NewMockCustomer(ctrl)
+	h := New(mockService, logging.Discard())
+
+	req := httptest.NewRequest(method, "http://customer", body)
+	r := mux.SetURLVars(req, pParam)
+
+	w := httptest.NewRecorder()
+
+	return h, mockService, r, w
+}
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var id = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+
+func TestHandler_Create(t *testing.T) {
+	cases := []struct {
+		desc       string
+		body       string
+		mockCall   bool
+		mockErr    error
+		statusCode int
+	}{
+		{"success case", `{"name":"Jane Doe","email":"jane@example.com","consent":{"marketing":true}}`, true, nil, http.StatusCreated},
+		{"invalid email", `{"name":"Jane Doe","email":"jane"}`, true, errors.InvalidParam{Param: []string{"email"}}, http.StatusBadRequest},
+		{"invalid body", `{"name":`, false, nil, http.StatusBadRequest},
+	}
+
+	for i, tc := range cases {
+		h, mockService, r, w := initializeTest(t, http.MethodPost, bytes.NewReader([]byte(tc.body)), nil)
+
+		if tc.mockCall {
+			mockService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&models.Customer{ID: id}, tc.mockErr)
+		}
+
+		h.Create(w, r)
+
+		if w.Code != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, w.Code, tc.statusCode)
+		}
+	}
+}
+
+func TestHandler_Update(t *testing.T) {
+	cases := []struct {
+		desc       string
+		id         string
+		mockCall   bool
+		mockErr    error
+		statusCode int
+	}{
+		{"success case", id.String(), true, nil, http.StatusOK},
+		{"missing customer", id.String(), true, errors.EntityNotFound{Entity: "customer", ID: id.String()}, http.StatusNotFound},
+		{"invalid id", "1", false, nil, http.StatusBadRequest},
+	}
+
+	for i, tc := range cases {
+		h, mockService, r, w := initializeTest(t, http.MethodPut, bytes.NewReader([]byte(`{"name":"Jane Doe","phone":"+14155550123"}`)),
+			map[string]string{"id": tc.id})
+
+		if tc.mockCall {
+			mockService.EXPECT().Update(gomock.Any(), &models.Customer{ID: id, Name: "Jane Doe", Phone: "+14155550123"}).
+				Return(&models.Customer{ID: id}, tc.mockErr)
+		}
+
+		h.Update(w, r)
+
+		if w.Code != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, w.Code, tc.statusCode)
+		}
+	}
+}
+
+func TestHandler_GetAndDelete(t *testing.T) {
+	h, mockService, r, w := initializeTest(t, http.MethodGet, nil, nil)
+	r.URL.RawQuery = "email=jane%40example.com&phone=%2B14155550123"
+
+	mockService.EXPECT().GetAll(gomock.Any(), filters.Customer{Email: "jane@example.com", Phone: "+14155550123"}).
+		Return([]models.Customer{{ID: id}}, nil)
+	h.GetAll(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("\n[TEST] Failed. Desc : search\nGot %v\nExpected %v", w.Code, http.StatusOK)
+	}
+
+	h, mockService, r, w = initializeTest(t, http.MethodGet, nil, map[string]string{"id": id.String()})
+
+	mockService.EXPECT().GetByID(gomock.Any(), id).Return(&models.Customer{ID: id}, nil)
+	h.GetByID(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("\n[TEST] Failed. Desc : get by id\nGot %v\nExpected %v", w.Code, http.StatusOK)
+	}
+
+	h, mockService, r, w = initializeTest(t, http.MethodDelete, nil, map[string]string{"id": id.String()})
+
+	mockService.EXPECT().Delete(gomock.Any(), id).Return(errors.EntityNotFound{Entity: "customer", ID: id.String()})
+	h.Delete(w, r)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("\n[TEST] Failed. Desc : delete missing customer\nGot %v\nExpected %v", w.Code, http.StatusNotFound)
+	}
+}
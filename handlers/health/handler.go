@@ -0,0 +1,92 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/amehrotra/car-dealership/handlers/response"
+)
+
+// Check reports whether a dependency is usable
+type Check func(ctx context.Context) error
+
+type handler struct {
+	checks  map[string]Check
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+// report lists the outcome of every check by name
+type report struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// New returns the probe handlers, each readiness check is given timeout to answer
+func New(checks map[string]Check, timeout time.Duration, logger *slog.Logger) handler {
+	return handler{checks: checks, timeout: timeout, logger: logger}
+}
+
+// Live reports that the process is up and serving, it does not depend on anything else
+func (h handler) Live(w http.ResponseWriter, r *http.Request) {
+	h.writeReport(w, r, http.StatusOK, report{Status: "ok"})
+}
+
+// Ready runs the checks concurrently and fails with 503 when any of them fails.
+// The probes are unauthenticated, so failures are only detailed in the logs.
+func (h handler) Ready(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	errs := make([]chan error, len(names))
+
+	for i, name := range names {
+		errs[i] = make(chan error, 1)
+
+		go func(check Check, errc chan<- error) {
+			errc <- check(ctx)
+		}(h.checks[name], errs[i])
+	}
+
+	resp := report{Status: "ok", Checks: make(map[string]string, len(names))}
+	statusCode := http.StatusOK
+
+	for i, name := range names {
+		var err error
+
+		select {
+		case err = <-errs[i]:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+
+		if err != nil {
+			h.logger.WarnContext(r.Context(), "readiness check failed", "check", name, "error", err)
+
+			resp.Status, resp.Checks[name] = "unavailable", "unavailable"
+			statusCode = http.StatusServiceUnavailable
+
+			continue
+		}
+
+		resp.Checks[name] = "ok"
+	}
+
+	h.writeReport(w, r, statusCode, resp)
+}
+
+// writeReport writes the report, probes are never cached
+func (h handler) writeReport(w http.ResponseWriter, r *http.Request, statusCode int, rep report) {
+	w.Header().Set("Cache-Control", "no-store")
+	response.WriteBody(w, r, h.logger, statusCode, rep)
+}
@@ -0,0 +1,58 @@
+package health
+
+import (
+	"context"
+	goError "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amehrotra/car-dealership/logging"
+)
+
+func TestHandler_Live(t *testing.T) {
+	h := New(nil, time.Second, logging.Discard())
+	w := httptest.NewRecorder()
+
+	h.Live(w, httptest.NewRequest(http.MethodGet, "http://cars/healthz", nil))
+
+	if w.Code != http.StatusOK || w.Body.String() != `{"status":"ok"}` {
+		t.Errorf("\n[TEST] Failed. Desc : live\nGot %v %s\nExpected %v", w.Code, w.Body.String(), http.StatusOK)
+	}
+}
+
+func TestHandler_Ready(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	failing := func(ctx context.Context) error { return goError.New("connection refused") }
+	hanging := func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	}
+
+	cases := []struct {
+		desc       string
+		checks     map[string]Check
+		statusCode int
+		body       string
+	}{
+		{"all checks pass", map[string]Check{"database": ok, "migrations": ok}, http.StatusOK,
+			`{"status":"ok","checks":{"database":"ok","migrations":"ok"}}`},
+		{"failing check", map[string]Check{"database": failing, "migrations": ok}, http.StatusServiceUnavailable,
+			`{"status":"unavailable","checks":{"database":"unavailable","migrations":"ok"}}`},
+		{"check timed out", map[string]Check{"database": hanging}, http.StatusServiceUnavailable,
+			`{"status":"unavailable","checks":{"database":"unavailable"}}`},
+	}
+
+	for i, tc := range cases {
+		h := New(tc.checks, 10*time.Millisecond, logging.Discard())
+		w := httptest.NewRecorder()
+
+		h.Ready(w, httptest.NewRequest(http.MethodGet, "http://cars/readyz", nil))
+
+		if w.Code != tc.statusCode || w.Body.String() != tc.body {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v %s\nExpected %v %s", i, tc.desc, w.Code, w.Body.String(), tc.statusCode, tc.body)
+		}
+	}
+}
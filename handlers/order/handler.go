@@ -0,0 +1,150 @@
+package order
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/handlers/response"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+type handler struct {
+	service services.Order
+	logger  *slog.Logger
+}
+
+// nolint:revive // handler should not be exported
+func New(service services.Order, logger *slog.Logger) handler {
+	return handler{service: service, logger: logger}
+}
+
+// Create drafts an order and writes it back, 409 when the car is already on another order or reserved for someone else
+func (h handler) Create(w http.ResponseWriter, r *http.Request) {
+	order, err := getOrder(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	order, err = h.service.Create(r.Context(), order)
+	response.Write(w, r, h.logger, order, err)
+}
+
+// GetAll writes the orders of the carId, customerId and status query parameters
+func (h handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	filter, err := getFilter(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	orders, err := h.service.GetAll(r.Context(), filter)
+	response.Write(w, r, h.logger, orders, err)
+}
+
+// GetByID writes the order based on ID
+func (h handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getID(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	order, err := h.service.GetByID(r.Context(), id)
+	response.Write(w, r, h.logger, order, err)
+}
+
+// Update changes the amounts or the status of the order based on ID
+func (h handler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := getID(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	order, err := getOrder(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	order.ID = id
+
+	order, err = h.service.Update(r.Context(), order)
+	response.Write(w, r, h.logger, order, err)
+}
+
+// getFilter reads the filter of the orders from the query parameters
+func getFilter(r *http.Request) (filters.Order, error) {
+	query := r.URL.Query()
+	filter := filters.Order{Status: query.Get("status")}
+
+	ids := []struct {
+		param string
+		id    *uuid.UUID
+	}{{"carId", &filter.CarID}, {"customerId", &filter.CustomerID}}
+
+	for _, p := range ids {
+		value := query.Get(p.param)
+		if value == "" {
+			continue
+		}
+
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return filters.Order{}, errors.InvalidParam{Param: []string{p.param}}
+		}
+
+		*p.id = id
+	}
+
+	return filter, nil
+}
+
+// getID reads the id from the path parameter of url
+func getID(r *http.Request) (uuid.UUID, error) {
+	param := mux.Vars(r)
+	idParam := strings.TrimSpace(param["id"])
+
+	if idParam == "" {
+		return uuid.Nil, errors.MissingParam{Param: "id"}
+	}
+
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		return uuid.Nil, errors.InvalidParam{Param: []string{"id"}}
+	}
+
+	return id, nil
+}
+
+// getOrder reads request body and returns the order
+func getOrder(r *http.Request) (*models.Order, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	var order models.Order
+
+	err = json.Unmarshal(body, &order)
+	if err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	return &order, nil
+}
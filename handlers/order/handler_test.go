@@ -0,0 +1,123 @@
+package order
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+func initializeTest(t *testing.T, method string, body io.Reader, pParam map[string]string) (handler, *services.MockOrder,
+	*http.Request, *httptest.ResponseRecorder) {
+	ctrl := gomock.NewController(t)
+
+	mockService := services.NewMockOrder(ctrl)
+	h := New(mockService, logging.Discard())
+
+	req := httptest.NewRequest(method, "http://order", body)
+	r := mux.SetURLVars(req, pParam)
+
+	w := httptest.NewRecorder()
+
+	return h, mockService, r, w
+}
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	id    = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+	carID = uuid.MustParse("3b7e4a1c-2b0e-4d55-a3a4-6f1f1f0c9a10")
+)
+
+func TestHandler_Create(t *testing.T) {
+	body := `{"carId":"` + carID.String() + `","customerId":"` + id.String() + `","taxes":100}`
+
+	cases := []struct {
+		desc       string
+		body       string
+		mockCall   bool
+		mockErr    error
+		statusCode int
+	}{
+		{"success case", body, true, nil, http.StatusCreated},
+		{"car on another order", body, true, errors.Conflict{Entity: "car", ID: carID.String(), Reason: "is already on order"},
+			http.StatusConflict},
+		{"missing car", body, true, errors.EntityNotFound{Entity: "car", ID: carID.String()}, http.StatusNotFound},
+		{"invalid body", `{"carId":`, false, nil, http.StatusBadRequest},
+	}
+
+	for i, tc := range cases {
+		h, mockService, r, w := initializeTest(t, http.MethodPost, bytes.NewReader([]byte(tc.body)), nil)
+
+		if tc.mockCall {
+			mockService.EXPECT().Create(gomock.Any(), &models.Order{CarID: carID, CustomerID: id, Taxes: 100}).
+				Return(&models.Order{ID: id}, tc.mockErr)
+		}
+
+		h.Create(w, r)
+
+		if w.Code != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, w.Code, tc.statusCode)
+		}
+	}
+}
+
+func TestHandler_GetAll(t *testing.T) {
+	cases := []struct {
+		desc       string
+		query      string
+		filter     *filters.Order
+		statusCode int
+	}{
+		{"every order", "", &filters.Order{}, http.StatusOK},
+		{"orders of the car", "carId=" + carID.String() + "&status=paid", &filters.Order{CarID: carID, Status: "paid"}, http.StatusOK},
+		{"invalid customer id", "customerId=1", nil, http.StatusBadRequest},
+	}
+
+	for i, tc := range cases {
+		h, mockService, r, w := initializeTest(t, http.MethodGet, nil, nil)
+		r.URL.RawQuery = tc.query
+
+		if tc.filter != nil {
+			mockService.EXPECT().GetAll(gomock.Any(), *tc.filter).Return([]models.Order{}, nil)
+		}
+
+		h.GetAll(w, r)
+
+		if w.Code != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, w.Code, tc.statusCode)
+		}
+	}
+}
+
+func TestHandler_GetByIDAndUpdate(t *testing.T) {
+	h, mockService, r, w := initializeTest(t, http.MethodGet, nil, map[string]string{"id": id.String()})
+
+	mockService.EXPECT().GetByID(gomock.Any(), id).Return(&models.Order{ID: id}, nil)
+	h.GetByID(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("\n[TEST] Failed. Desc : get by id\nGot %v\nExpected %v", w.Code, http.StatusOK)
+	}
+
+	h, mockService, r, w = initializeTest(t, http.MethodPut, bytes.NewReader([]byte(`{"price":1000,"status":"confirmed"}`)),
+		map[string]string{"id": id.String()})
+
+	mockService.EXPECT().Update(gomock.Any(), &models.Order{ID: id, Price: 1000, Status: models.OrderConfirmed}).
+		Return(nil, errors.Conflict{Entity: "order", ID: id.String(), Reason: "is delivered"})
+	h.Update(w, r)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("\n[TEST] Failed. Desc : update delivered order\nGot %v\nExpected %v", w.Code, http.StatusConflict)
+	}
+}
@@ -0,0 +1,97 @@
+package reservation
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/handlers/response"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+type handler struct {
+	service services.Reservation
+	logger  *slog.Logger
+}
+
+// nolint:revive // handler should not be exported
+func New(service services.Reservation, logger *slog.Logger) handler {
+	return handler{service: service, logger: logger}
+}
+
+// Create reserves the car of the path for a customer and writes the reservation back, 409 when the car is already
+// reserved or on an order
+func (h handler) Create(w http.ResponseWriter, r *http.Request) {
+	carID, err := getID(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	reservation, err := getReservation(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	reservation.CarID = carID
+
+	reservation, err = h.service.Create(r.Context(), reservation)
+	response.Write(w, r, h.logger, reservation, err)
+}
+
+// Cancel releases the car of the path from its reservation
+func (h handler) Cancel(w http.ResponseWriter, r *http.Request) {
+	carID, err := getID(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	err = h.service.Cancel(r.Context(), carID)
+	response.Write(w, r, h.logger, nil, err)
+}
+
+// getID reads the id of the car from the path parameter of url
+func getID(r *http.Request) (uuid.UUID, error) {
+	param := mux.Vars(r)
+	idParam := strings.TrimSpace(param["id"])
+
+	if idParam == "" {
+		return uuid.Nil, errors.MissingParam{Param: "id"}
+	}
+
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		return uuid.Nil, errors.InvalidParam{Param: []string{"id"}}
+	}
+
+	return id, nil
+}
+
+// getReservation reads request body and returns the reservation
+func getReservation(r *http.Request) (*models.Reservation, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	var reservation models.Reservation
+
+	err = json.Unmarshal(body, &reservation)
+	if err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	return &reservation, nil
+}
@@ -0,0 +1,98 @@
+package reservation
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+func initializeTest(t *testing.T, method string, body io.Reader, pParam map[string]string) (handler, *services.MockReservation,
+	*http.Request, *httptest.ResponseRecorder) {
+	ctrl := gomock.NewController(t)
+
+	mockService := services.NewMockReservation(ctrl)
+	h := New(mockService, logging.Discard())
+
+	req := httptest.NewRequest(method, "http://car/reservation", body)
+	r := mux.SetURLVars(req, pParam)
+
+	w := httptest.NewRecorder()
+
+	return h, mockService, r, w
+}
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	carID      = uuid.MustParse("3b7e4a1c-2b0e-4d55-a3a4-6f1f1f0c9a10")
+	customerID = uuid.MustParse("c4d0f7a2-5e1b-4f0e-9d57-0a2b8e6d3c21")
+	expiresAt  = time.Date(2022, 1, 4, 0, 0, 0, 0, time.UTC)
+)
+
+func TestHandler_Create(t *testing.T) {
+	body := `{"customerId":"` + customerID.String() + `","deposit":50000,"expiresAt":"2022-01-04T00:00:00Z"}`
+
+	cases := []struct {
+		desc       string
+		carID      string
+		body       string
+		mockCall   bool
+		mockErr    error
+		statusCode int
+	}{
+		{"success case", carID.String(), body, true, nil, http.StatusCreated},
+		{"car already reserved", carID.String(), body, true,
+			errors.Conflict{Entity: "car", ID: carID.String(), Reason: "is reserved until 2022-01-03T00:00:00Z"}, http.StatusConflict},
+		{"expiry too far", carID.String(), body, true, errors.InvalidParam{Param: []string{"expiresAt"}}, http.StatusBadRequest},
+		{"invalid car id", "abc", body, false, nil, http.StatusBadRequest},
+		{"invalid body", carID.String(), `{"deposit":`, false, nil, http.StatusBadRequest},
+	}
+
+	for i, tc := range cases {
+		h, mockService, r, w := initializeTest(t, http.MethodPost, bytes.NewReader([]byte(tc.body)), map[string]string{"id": tc.carID})
+
+		if tc.mockCall {
+			mockService.EXPECT().Create(gomock.Any(), &models.Reservation{CarID: carID, CustomerID: customerID, Deposit: 50000,
+				ExpiresAt: expiresAt}).Return(&models.Reservation{CarID: carID}, tc.mockErr)
+		}
+
+		h.Create(w, r)
+
+		if w.Code != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, w.Code, tc.statusCode)
+		}
+	}
+}
+
+func TestHandler_Cancel(t *testing.T) {
+	cases := []struct {
+		desc       string
+		mockErr    error
+		statusCode int
+	}{
+		{"success case", nil, http.StatusNoContent},
+		{"not reserved", errors.EntityNotFound{Entity: "reservation", ID: carID.String()}, http.StatusNotFound},
+	}
+
+	for i, tc := range cases {
+		h, mockService, r, w := initializeTest(t, http.MethodDelete, nil, map[string]string{"id": carID.String()})
+
+		mockService.EXPECT().Cancel(gomock.Any(), carID).Return(tc.mockErr)
+		h.Cancel(w, r)
+
+		if w.Code != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, w.Code, tc.statusCode)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Package response writes the bodies and errors of the API handlers
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// Write answers with the data and the status code of the method, or with the error when there is one
+func Write(w http.ResponseWriter, r *http.Request, logger *slog.Logger, data interface{}, err error) {
+	if err != nil {
+		statusCode, code, message := Error(r.Context(), logger, err)
+		WriteError(w, r, logger, statusCode, code, message)
+
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		WriteBody(w, r, logger, http.StatusCreated, data)
+	case http.MethodDelete:
+		WriteBody(w, r, logger, http.StatusNoContent, nil)
+	default:
+		WriteBody(w, r, logger, http.StatusOK, data)
+	}
+}
+
+// Error maps the error to its status code and error body, unexpected errors are only detailed in the logs
+func Error(ctx context.Context, logger *slog.Logger, err error) (statusCode int, code, message string) {
+	switch err.(type) {
+	case errors.EntityAlreadyExists:
+		return http.StatusOK, "already_exists", err.Error()
+	case errors.MissingParam, errors.InvalidParam:
+		return http.StatusBadRequest, "invalid_parameter", err.Error()
+	case errors.EntityNotFound:
+		return http.StatusNotFound, "not_found", err.Error()
+	case errors.Forbidden:
+		return http.StatusForbidden, "forbidden", err.Error()
+	case errors.Conflict:
+		return http.StatusConflict, "conflict", err.Error()
+	case errors.Aborted:
+		return http.StatusFailedDependency, "aborted", err.Error()
+	default:
+		logger.ErrorContext(ctx, "error in serving request", "error", err)
+
+		return http.StatusInternalServerError, "internal_error", "internal server error"
+	}
+}
+
+// WriteError writes the error body carrying the id of the request
+func WriteError(w http.ResponseWriter, r *http.Request, logger *slog.Logger, statusCode int, code, message string) {
+	WriteBody(w, r, logger, statusCode, models.Error{Code: code, Message: message, RequestID: logging.RequestID(r.Context())})
+}
+
+// WriteBody marshals the data and writes it with the status code, a 204 is written without a body
+func WriteBody(w http.ResponseWriter, r *http.Request, logger *slog.Logger, statusCode int, data interface{}) {
+	if statusCode == http.StatusNoContent {
+		w.WriteHeader(statusCode)
+
+		return
+	}
+
+	resp, err := json.Marshal(data)
+	if err != nil {
+		logger.ErrorContext(r.Context(), "error in marshalling response", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if _, err := w.Write(resp); err != nil {
+		logger.ErrorContext(r.Context(), "error in writing response", "error", err)
+	}
+}
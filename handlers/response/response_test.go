@@ -0,0 +1,106 @@
+package response
+
+import (
+	"bytes"
+	goError "errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/logging"
+)
+
+func TestWrite(t *testing.T) {
+	cases := []struct {
+		desc       string
+		method     string
+		data       interface{}
+		err        error
+		statusCode int
+		body       string
+	}{
+		{"created", http.MethodPost, map[string]string{"id": "1"}, nil, http.StatusCreated, `{"id":"1"}`},
+		{"read", http.MethodGet, map[string]string{"id": "1"}, nil, http.StatusOK, `{"id":"1"}`},
+		{"updated", http.MethodPut, map[string]string{"id": "1"}, nil, http.StatusOK, `{"id":"1"}`},
+		{"deleted", http.MethodDelete, nil, nil, http.StatusNoContent, ""},
+		{"already exists", http.MethodPost, nil, errors.EntityAlreadyExists{Entity: "car"}, http.StatusOK,
+			`{"code":"already_exists","message":"entity  car already exists"}`},
+		{"invalid", http.MethodPost, nil, errors.InvalidParam{Param: []string{"brand"}}, http.StatusBadRequest,
+			`{"code":"invalid_parameter","message":"parameter brand is invalid"}`},
+		{"not found", http.MethodGet, nil, errors.EntityNotFound{Entity: "car", ID: "1"}, http.StatusNotFound,
+			`{"code":"not_found","message":"entity car with id 1 not found"}`},
+		{"forbidden", http.MethodDelete, nil, errors.Forbidden{Permission: "car:delete"}, http.StatusForbidden,
+			`{"code":"forbidden","message":"permission car:delete is required"}`},
+		{"conflict", http.MethodDelete, nil, errors.Conflict{Entity: "car", ID: "1", Reason: "is sold"}, http.StatusConflict,
+			`{"code":"conflict","message":"entity car with id 1 is sold"}`},
+		{"unexpected", http.MethodGet, nil, goError.New("connection refused"), http.StatusInternalServerError,
+			`{"code":"internal_error","message":"internal server error"}`},
+	}
+
+	for i, tc := range cases {
+		r := httptest.NewRequest(tc.method, "http://cars/car", nil)
+		w := httptest.NewRecorder()
+
+		Write(w, r, logging.Discard(), tc.data, tc.err)
+
+		if w.Code != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, w.Code, tc.statusCode)
+		}
+
+		if tc.body != "" && w.Body.String() != tc.body {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, w.Body.String(), tc.body)
+		}
+
+		if tc.statusCode == http.StatusNoContent && (w.Body.Len() != 0 || w.Header().Get("Content-Type") != "") {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %q\nExpected no body", i, tc.desc, w.Body.String())
+		}
+	}
+}
+
+func TestWrite_MarshalError(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "http://cars/car", nil)
+	w := httptest.NewRecorder()
+
+	Write(w, r, logging.Discard(), complex(1, 1), nil)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("\n[TEST] Failed. Desc : Marshal Error \nGot %v\nExpected %v", resp.StatusCode, http.StatusInternalServerError)
+	}
+}
+
+func TestWrite_WriteError(t *testing.T) {
+	data := []byte(`{"id":"8f443772-132b-4ae5-9f8f-9960649b3fb4","model":"x","yearOfManufacture":2020,"brand":"BMW","fuelType":"petrol",
+		"engine":{"displacement":200,"noOfCylinder":2,"range":0}}`)
+
+	var b bytes.Buffer
+
+	r := httptest.NewRequest(http.MethodGet, "http://cars/car", nil)
+
+	Write(mockResponseWriter{}, r, slog.New(slog.NewTextHandler(&b, nil)), data, nil)
+
+	if !strings.Contains(b.String(), "error in writing response") {
+		t.Errorf("\n[TEST] Failed. Desc : Write Error \nGot %v\nExpected 'error in writing response' in logs", b.String())
+	}
+}
+
+type mockResponseWriter struct{}
+
+func (m mockResponseWriter) Header() http.Header {
+	header := make(map[string][]string)
+
+	return header
+}
+
+func (m mockResponseWriter) Write([]byte) (int, error) {
+	return 0, errors.InvalidParam{}
+}
+
+func (m mockResponseWriter) WriteHeader(statusCode int) {
+
+}
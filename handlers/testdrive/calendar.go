@@ -0,0 +1,85 @@
+package testdrive
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/amehrotra/car-dealership/models"
+)
+
+const (
+	// icsTime is the UTC date-time format of iCalendar
+	icsTime = "20060102T150405Z"
+	// lineLimit is the length in octets iCalendar lines are folded at
+	lineLimit = 75
+)
+
+// nolint:gochecknoglobals // read only replacer of the characters iCalendar text escapes
+var icsText = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// encodeCalendar writes the test drives as the events of an iCalendar (RFC 5545) feed of the salesperson.
+// Their ids keep the events of a test drive the same across fetches, cancelled ones are sent as cancelled events so
+// that subscribed calendars drop them.
+func encodeCalendar(salesperson string, drives []models.TestDrive) []byte {
+	var buf bytes.Buffer
+
+	writeLine(&buf, "BEGIN:VCALENDAR")
+	writeLine(&buf, "VERSION:2.0")
+	writeLine(&buf, "PRODID:-//car-dealership//test drives//EN")
+	writeLine(&buf, "CALSCALE:GREGORIAN")
+	writeLine(&buf, "METHOD:PUBLISH")
+	writeLine(&buf, "X-WR-CALNAME:"+icsText.Replace("Test drives of "+salesperson))
+
+	for i := range drives {
+		d := &drives[i]
+
+		status := "CONFIRMED"
+		if d.Status == models.TestDriveCancelled {
+			status = "CANCELLED"
+		}
+
+		description := "Car " + d.CarID.String() + "\nCustomer " + d.CustomerID.String() + "\nStatus " + d.Status
+		if d.Notes != "" {
+			description += "\n" + d.Notes
+		}
+
+		writeLine(&buf, "BEGIN:VEVENT")
+		writeLine(&buf, "UID:"+d.ID.String()+"@car-dealership")
+		writeLine(&buf, "DTSTAMP:"+d.UpdatedAt.UTC().Format(icsTime))
+		writeLine(&buf, "LAST-MODIFIED:"+d.UpdatedAt.UTC().Format(icsTime))
+		writeLine(&buf, "DTSTART:"+d.StartsAt.UTC().Format(icsTime))
+		writeLine(&buf, "DTEND:"+d.EndsAt.UTC().Format(icsTime))
+		writeLine(&buf, "SUMMARY:Test drive")
+		writeLine(&buf, "DESCRIPTION:"+icsText.Replace(description))
+		writeLine(&buf, "STATUS:"+status)
+		writeLine(&buf, "END:VEVENT")
+	}
+
+	writeLine(&buf, "END:VCALENDAR")
+
+	return buf.Bytes()
+}
+
+// writeLine writes a content line ending in CRLF, folding it into lines of at most lineLimit octets that continue with
+// a space without splitting a character
+func writeLine(buf *bytes.Buffer, line string) {
+	limit := lineLimit
+
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+
+		line = line[cut:]
+		// the space of a continuation line counts towards its length
+		limit = lineLimit - 1
+	}
+
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
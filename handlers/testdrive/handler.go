@@ -0,0 +1,189 @@
+package testdrive
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/handlers/response"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+type handler struct {
+	service services.TestDrive
+	logger  *slog.Logger
+}
+
+// nolint:revive // handler should not be exported
+func New(service services.TestDrive, logger *slog.Logger) handler {
+	return handler{service: service, logger: logger}
+}
+
+// Create books a test drive and writes it back, 409 when the car or the salesperson is booked for an overlapping one
+func (h handler) Create(w http.ResponseWriter, r *http.Request) {
+	drive, err := getTestDrive(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	drive, err = h.service.Create(r.Context(), drive)
+	response.Write(w, r, h.logger, drive, err)
+}
+
+// GetAll writes the test drives of the carId, customerId, salesperson, status, from and to query parameters
+func (h handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	filter, err := getFilter(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	drives, err := h.service.GetAll(r.Context(), filter)
+	response.Write(w, r, h.logger, drives, err)
+}
+
+// GetByID writes the test drive based on ID
+func (h handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getID(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	drive, err := h.service.GetByID(r.Context(), id)
+	response.Write(w, r, h.logger, drive, err)
+}
+
+// Update reschedules the test drive based on ID or changes its status
+func (h handler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := getID(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	drive, err := getTestDrive(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	drive.ID = id
+
+	drive, err = h.service.Update(r.Context(), drive)
+	response.Write(w, r, h.logger, drive, err)
+}
+
+// Calendar writes the test drives of the salesperson of the path as an iCalendar feed
+func (h handler) Calendar(w http.ResponseWriter, r *http.Request) {
+	salesperson := strings.TrimSpace(mux.Vars(r)["salesperson"])
+
+	drives, err := h.service.Calendar(r.Context(), salesperson)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := w.Write(encodeCalendar(salesperson, drives)); err != nil {
+		h.logger.ErrorContext(r.Context(), "error in writing response", "error", err)
+	}
+}
+
+// getFilter reads the filter of the test drives from the query parameters
+func getFilter(r *http.Request) (filters.TestDrive, error) {
+	query := r.URL.Query()
+	filter := filters.TestDrive{Salesperson: query.Get("salesperson"), Status: query.Get("status")}
+
+	ids := []struct {
+		param string
+		id    *uuid.UUID
+	}{{"carId", &filter.CarID}, {"customerId", &filter.CustomerID}}
+
+	for _, p := range ids {
+		value := query.Get(p.param)
+		if value == "" {
+			continue
+		}
+
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return filters.TestDrive{}, errors.InvalidParam{Param: []string{p.param}}
+		}
+
+		*p.id = id
+	}
+
+	times := []struct {
+		param string
+		t     *time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}}
+
+	for _, p := range times {
+		value := query.Get(p.param)
+		if value == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filters.TestDrive{}, errors.InvalidParam{Param: []string{p.param}}
+		}
+
+		*p.t = t.UTC()
+	}
+
+	return filter, nil
+}
+
+// getID reads the id from the path parameter of url
+func getID(r *http.Request) (uuid.UUID, error) {
+	param := mux.Vars(r)
+	idParam := strings.TrimSpace(param["id"])
+
+	if idParam == "" {
+		return uuid.Nil, errors.MissingParam{Param: "id"}
+	}
+
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		return uuid.Nil, errors.InvalidParam{Param: []string{"id"}}
+	}
+
+	return id, nil
+}
+
+// getTestDrive reads request body and returns the test drive
+func getTestDrive(r *http.Request) (*models.TestDrive, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	var drive models.TestDrive
+
+	err = json.Unmarshal(body, &drive)
+	if err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	return &drive, nil
+}
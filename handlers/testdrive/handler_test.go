@@ -0,0 +1,185 @@
+package testdrive
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+func initializeTest(t *testing.T, method string, body io.Reader, pParam map[string]string) (handler, *services.MockTestDrive,
+	*http.Request, *httptest.ResponseRecorder) {
+	ctrl := gomock.NewController(t)
+
+	mockService := services.NewMockTestDrive(ctrl)
+	h := New(mockService, logging.Discard())
+
+	req := httptest.NewRequest(method, "http://testdrive", body)
+	r := mux.SetURLVars(req, pParam)
+
+	w := httptest.NewRecorder()
+
+	return h, mockService, r, w
+}
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	id       = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+	carID    = uuid.MustParse("3b7e4a1c-2b0e-4d55-a3a4-6f1f1f0c9a10")
+	startsAt = time.Date(2022, 1, 3, 10, 0, 0, 0, time.UTC)
+	endsAt   = startsAt.Add(30 * time.Minute)
+)
+
+func TestHandler_Create(t *testing.T) {
+	body := `{"carId":"` + carID.String() + `","customerId":"` + id.String() + `","startsAt":"2022-01-03T10:00:00Z",` +
+		`"endsAt":"2022-01-03T10:30:00Z"}`
+
+	cases := []struct {
+		desc       string
+		body       string
+		mockCall   bool
+		mockErr    error
+		statusCode int
+	}{
+		{"success case", body, true, nil, http.StatusCreated},
+		{"salesperson booked", body, true, errors.Conflict{Entity: "salesperson", ID: "jane", Reason: "is booked"}, http.StatusConflict},
+		{"outside business hours", body, true, errors.InvalidParam{Param: []string{"startsAt", "endsAt"}}, http.StatusBadRequest},
+		{"invalid body", `{"carId":`, false, nil, http.StatusBadRequest},
+	}
+
+	for i, tc := range cases {
+		h, mockService, r, w := initializeTest(t, http.MethodPost, bytes.NewReader([]byte(tc.body)), nil)
+
+		if tc.mockCall {
+			mockService.EXPECT().Create(gomock.Any(), &models.TestDrive{CarID: carID, CustomerID: id, StartsAt: startsAt, EndsAt: endsAt}).
+				Return(&models.TestDrive{ID: id}, tc.mockErr)
+		}
+
+		h.Create(w, r)
+
+		if w.Code != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, w.Code, tc.statusCode)
+		}
+	}
+}
+
+func TestHandler_GetAll(t *testing.T) {
+	cases := []struct {
+		desc       string
+		query      string
+		filter     *filters.TestDrive
+		statusCode int
+	}{
+		{"no filter", "", &filters.TestDrive{}, http.StatusOK},
+		{"salesperson in a period", "salesperson=jane&from=2022-01-03T00:00:00Z&to=2022-01-04T00:00:00%2B01:00",
+			&filters.TestDrive{Salesperson: "jane", From: time.Date(2022, 1, 3, 0, 0, 0, 0, time.UTC),
+				To: time.Date(2022, 1, 3, 23, 0, 0, 0, time.UTC)}, http.StatusOK},
+		{"invalid car", "carId=abc", nil, http.StatusBadRequest},
+		{"invalid from", "from=monday", nil, http.StatusBadRequest},
+	}
+
+	for i, tc := range cases {
+		h, mockService, r, w := initializeTest(t, http.MethodGet, nil, nil)
+		r.URL.RawQuery = tc.query
+
+		if tc.filter != nil {
+			mockService.EXPECT().GetAll(gomock.Any(), *tc.filter).Return([]models.TestDrive{}, nil)
+		}
+
+		h.GetAll(w, r)
+
+		if w.Code != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, w.Code, tc.statusCode)
+		}
+	}
+}
+
+func TestHandler_Update(t *testing.T) {
+	h, mockService, r, w := initializeTest(t, http.MethodPut, bytes.NewReader([]byte(`{"status":"no_show"}`)),
+		map[string]string{"id": id.String()})
+
+	mockService.EXPECT().Update(gomock.Any(), &models.TestDrive{ID: id, Status: models.TestDriveNoShow}).
+		Return(nil, errors.Conflict{Entity: "test drive", ID: id.String(), Reason: "has not started"})
+	h.Update(w, r)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("\n[TEST] Failed. Desc : no show before the start\nGot %v\nExpected %v", w.Code, http.StatusConflict)
+	}
+}
+
+func TestHandler_Calendar(t *testing.T) {
+	h, mockService, r, w := initializeTest(t, http.MethodGet, nil, map[string]string{"salesperson": "jane"})
+
+	drives := []models.TestDrive{
+		{ID: id, CarID: carID, CustomerID: id, Salesperson: "jane", StartsAt: startsAt, EndsAt: endsAt, Status: models.TestDriveScheduled,
+			Notes: "wants the motorway; bring plates, and a charger", UpdatedAt: startsAt.Add(-time.Hour)},
+		{ID: carID, CarID: carID, CustomerID: id, Salesperson: "jane", StartsAt: startsAt, EndsAt: endsAt,
+			Status: models.TestDriveCancelled, UpdatedAt: startsAt.Add(-time.Hour)},
+	}
+
+	mockService.EXPECT().Calendar(gomock.Any(), "jane").Return(drives, nil)
+	h.Calendar(w, r)
+
+	expected := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//car-dealership//test drives//EN\r\nCALSCALE:GREGORIAN\r\n" +
+		"METHOD:PUBLISH\r\nX-WR-CALNAME:Test drives of jane\r\n" +
+		"BEGIN:VEVENT\r\nUID:" + id.String() + "@car-dealership\r\nDTSTAMP:20220103T090000Z\r\nLAST-MODIFIED:20220103T090000Z\r\n" +
+		"DTSTART:20220103T100000Z\r\nDTEND:20220103T103000Z\r\nSUMMARY:Test drive\r\n" +
+		"DESCRIPTION:Car " + carID.String() + "\\nCustomer " + id.String() +
+		"\\nStatus scheduled\\nwants the motorway\\; bring plates\\, and a charger\r\n" +
+		"STATUS:CONFIRMED\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:" + carID.String() + "@car-dealership\r\nDTSTAMP:20220103T090000Z\r\nLAST-MODIFIED:20220103T090000Z\r\n" +
+		"DTSTART:20220103T100000Z\r\nDTEND:20220103T103000Z\r\nSUMMARY:Test drive\r\n" +
+		"DESCRIPTION:Car " + carID.String() + "\\nCustomer " + id.String() + "\\nStatus cancelled\r\n" +
+		"STATUS:CANCELLED\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+	// long lines are folded, unfolding them gives back the content lines
+	body := w.Body.String()
+
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "text/calendar; charset=utf-8" ||
+		strings.ReplaceAll(body, "\r\n ", "") != expected {
+		t.Errorf("\n[TEST] Failed. Desc : calendar\nGot %v %v\n%q\nExpected %q", w.Code, w.Header(), body, expected)
+	}
+
+	for _, line := range strings.Split(body, "\r\n") {
+		if len(line) > lineLimit {
+			t.Errorf("\n[TEST] Failed. Desc : calendar line length\nGot %q\nExpected at most %d octets", line, lineLimit)
+		}
+	}
+}
+
+func TestWriteLine(t *testing.T) {
+	cases := []struct {
+		desc   string
+		line   string
+		output string
+	}{
+		{"short line", "SUMMARY:Test drive", "SUMMARY:Test drive\r\n"},
+		{"folded line", "DESCRIPTION:" + string(bytes.Repeat([]byte("a"), 100)),
+			"DESCRIPTION:" + string(bytes.Repeat([]byte("a"), 63)) + "\r\n " + string(bytes.Repeat([]byte("a"), 37)) + "\r\n"},
+		{"character at the fold", "DESCRIPTION:" + string(bytes.Repeat([]byte("a"), 62)) + "é",
+			"DESCRIPTION:" + string(bytes.Repeat([]byte("a"), 62)) + "\r\n é\r\n"},
+	}
+
+	for i, tc := range cases {
+		var buf bytes.Buffer
+
+		writeLine(&buf, tc.line)
+
+		if buf.String() != tc.output {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %q\nExpected %q", i, tc.desc, buf.String(), tc.output)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package tradein
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/handlers/response"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+type handler struct {
+	service services.TradeIn
+	logger  *slog.Logger
+}
+
+// nolint:revive // handler should not be exported
+func New(service services.TradeIn, logger *slog.Logger) handler {
+	return handler{service: service, logger: logger}
+}
+
+// review is the body of the approval of a trade-in, approved is required so that a missing value does not reject it
+type review struct {
+	Approved *bool `json:"approved"`
+}
+
+// Create records the trade-in and writes it back
+func (h handler) Create(w http.ResponseWriter, r *http.Request) {
+	var tradeIn models.TradeIn
+
+	if err := getBody(r, &tradeIn); err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	output, err := h.service.Create(r.Context(), &tradeIn)
+	response.Write(w, r, h.logger, output, err)
+}
+
+// GetAll writes the trade-ins of the customerId and status query parameters
+func (h handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	filter, err := getFilter(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	tradeIns, err := h.service.GetAll(r.Context(), filter)
+	response.Write(w, r, h.logger, tradeIns, err)
+}
+
+// GetByID writes the trade-in based on ID
+func (h handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getID(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	tradeIn, err := h.service.GetByID(r.Context(), id)
+	response.Write(w, r, h.logger, tradeIn, err)
+}
+
+// Update replaces the vehicle of the trade-in based on ID, 409 once it is approved
+func (h handler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := getID(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	var tradeIn models.TradeIn
+
+	if err := getBody(r, &tradeIn); err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	tradeIn.ID = id
+
+	output, err := h.service.Update(r.Context(), &tradeIn)
+	response.Write(w, r, h.logger, output, err)
+}
+
+// Appraise offers the value of the body for the trade-in based on ID
+func (h handler) Appraise(w http.ResponseWriter, r *http.Request) {
+	id, err := getID(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	var appraisal models.Appraisal
+
+	if err := getBody(r, &appraisal); err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	tradeIn, err := h.service.Appraise(r.Context(), id, &appraisal)
+	response.Write(w, r, h.logger, tradeIn, err)
+}
+
+// Review approves the appraisal of the trade-in based on ID or rejects the trade-in
+func (h handler) Review(w http.ResponseWriter, r *http.Request) {
+	id, err := getID(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	var body review
+
+	if err := getBody(r, &body); err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	if body.Approved == nil {
+		response.Write(w, r, h.logger, nil, errors.MissingParam{Param: "approved"})
+
+		return
+	}
+
+	tradeIn, err := h.service.Review(r.Context(), id, *body.Approved)
+	response.Write(w, r, h.logger, tradeIn, err)
+}
+
+// Convert adds the vehicle of the approved trade-in based on ID to the inventory with the price and engine of the body,
+// and writes the car created
+func (h handler) Convert(w http.ResponseWriter, r *http.Request) {
+	id, err := getID(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	var car models.Car
+
+	if err := getBody(r, &car); err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	output, err := h.service.Convert(r.Context(), id, &car)
+	response.Write(w, r, h.logger, output, err)
+}
+
+// getFilter reads the filter of the trade-ins from the query parameters
+func getFilter(r *http.Request) (filters.TradeIn, error) {
+	query := r.URL.Query()
+	filter := filters.TradeIn{Status: query.Get("status")}
+
+	if value := query.Get("customerId"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return filters.TradeIn{}, errors.InvalidParam{Param: []string{"customerId"}}
+		}
+
+		filter.CustomerID = id
+	}
+
+	return filter, nil
+}
+
+// getID reads the id from the path parameter of url
+func getID(r *http.Request) (uuid.UUID, error) {
+	param := mux.Vars(r)
+	idParam := strings.TrimSpace(param["id"])
+
+	if idParam == "" {
+		return uuid.Nil, errors.MissingParam{Param: "id"}
+	}
+
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		return uuid.Nil, errors.InvalidParam{Param: []string{"id"}}
+	}
+
+	return id, nil
+}
+
+// getBody reads request body into v
+func getBody(r *http.Request, v interface{}) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package tradein
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+	"github.com/amehrotra/car-dealership/types"
+)
+
+func initializeTest(t *testing.T, method string, body io.Reader, pParam map[string]string) (handler, *services.MockTradeIn,
+	*http.Request, *httptest.ResponseRecorder) {
+	ctrl := gomock.NewController(t)
+
+	mockService := services.NewMockTradeIn(ctrl)
+	h := New(mockService, logging.Discard())
+
+	req := httptest.NewRequest(method, "http://tradein", body)
+	r := mux.SetURLVars(req, pParam)
+
+	w := httptest.NewRecorder()
+
+	return h, mockService, r, w
+}
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	id         = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+	customerID = uuid.MustParse("c4d0f7a2-5e1b-4f0e-9d57-0a2b8e6d3c21")
+)
+
+func TestHandler_Create(t *testing.T) {
+	body := `{"customerId":"` + customerID.String() + `","brand":"BMW","model":"320d","yearOfManufacture":2015,` +
+		`"fuelType":"diesel","mileage":120000,"checklist":{"bodywork":true},"photos":["https://photos.example.com/1.jpg"]}`
+
+	cases := []struct {
+		desc       string
+		body       string
+		mockCall   bool
+		mockErr    error
+		statusCode int
+	}{
+		{"success case", body, true, nil, http.StatusCreated},
+		{"invalid vehicle", body, true, errors.InvalidParam{Param: []string{"yearOfManufacture"}}, http.StatusBadRequest},
+		{"customer not found", body, true, errors.EntityNotFound{Entity: "customer", ID: customerID.String()}, http.StatusNotFound},
+		{"invalid body", `{"brand":`, false, nil, http.StatusBadRequest},
+	}
+
+	for i, tc := range cases {
+		h, mockService, r, w := initializeTest(t, http.MethodPost, bytes.NewReader([]byte(tc.body)), nil)
+
+		if tc.mockCall {
+			mockService.EXPECT().Create(gomock.Any(), &models.TradeIn{CustomerID: customerID, Brand: "BMW", Model: "320d",
+				ManufactureYear: 2015, FuelType: types.Diesel, Mileage: 120000, Checklist: models.Checklist{Bodywork: true},
+				Photos: []string{"https://photos.example.com/1.jpg"}}).Return(&models.TradeIn{ID: id}, tc.mockErr)
+		}
+
+		h.Create(w, r)
+
+		if w.Code != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, w.Code, tc.statusCode)
+		}
+	}
+}
+
+func TestHandler_GetAll(t *testing.T) {
+	cases := []struct {
+		desc       string
+		query      string
+		filter     *filters.TradeIn
+		statusCode int
+	}{
+		{"no filter", "", &filters.TradeIn{}, http.StatusOK},
+		{"customer and status", "customerId=" + customerID.String() + "&status=approved",
+			&filters.TradeIn{CustomerID: customerID, Status: models.TradeInApproved}, http.StatusOK},
+		{"invalid customer", "customerId=abc", nil, http.StatusBadRequest},
+	}
+
+	for i, tc := range cases {
+		h, mockService, r, w := initializeTest(t, http.MethodGet, nil, nil)
+		r.URL.RawQuery = tc.query
+
+		if tc.filter != nil {
+			mockService.EXPECT().GetAll(gomock.Any(), *tc.filter).Return([]models.TradeIn{}, nil)
+		}
+
+		h.GetAll(w, r)
+
+		if w.Code != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, w.Code, tc.statusCode)
+		}
+	}
+}
+
+func TestHandler_GetByID(t *testing.T) {
+	h, mockService, r, w := initializeTest(t, http.MethodGet, nil, map[string]string{"id": id.String()})
+
+	mockService.EXPECT().GetByID(gomock.Any(), id).Return(nil, errors.EntityNotFound{Entity: "trade-in", ID: id.String()})
+	h.GetByID(w, r)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("\n[TEST] Failed. Desc : trade-in not found\nGot %v\nExpected %v", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandler_Appraise(t *testing.T) {
+	expiresAt := time.Date(2022, 1, 17, 0, 0, 0, 0, time.UTC)
+
+	h, mockService, r, w := initializeTest(t, http.MethodPut,
+		bytes.NewReader([]byte(`{"value":900000,"expiresAt":"2022-01-17T00:00:00Z"}`)), map[string]string{"id": id.String()})
+
+	mockService.EXPECT().Appraise(gomock.Any(), id, &models.Appraisal{Value: 900000, ExpiresAt: expiresAt}).
+		Return(&models.TradeIn{ID: id, Status: models.TradeInAppraised}, nil)
+	h.Appraise(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("\n[TEST] Failed. Desc : appraise\nGot %v\nExpected %v", w.Code, http.StatusOK)
+	}
+}
+
+func TestHandler_Review(t *testing.T) {
+	cases := []struct {
+		desc       string
+		body       string
+		approve    *bool
+		mockErr    error
+		statusCode int
+	}{
+		{"approve", `{"approved":true}`, boolPtr(true), nil, http.StatusOK},
+		{"reject", `{"approved":false}`, boolPtr(false), nil, http.StatusOK},
+		{"expired appraisal", `{"approved":true}`, boolPtr(true),
+			errors.Conflict{Entity: "trade-in", ID: id.String(), Reason: "has an appraisal that expired"}, http.StatusConflict},
+		{"missing approval", `{}`, nil, nil, http.StatusBadRequest},
+		{"invalid body", `{"approved":"yes"}`, nil, nil, http.StatusBadRequest},
+	}
+
+	for i, tc := range cases {
+		h, mockService, r, w := initializeTest(t, http.MethodPut, bytes.NewReader([]byte(tc.body)), map[string]string{"id": id.String()})
+
+		if tc.approve != nil {
+			mockService.EXPECT().Review(gomock.Any(), id, *tc.approve).Return(&models.TradeIn{ID: id}, tc.mockErr)
+		}
+
+		h.Review(w, r)
+
+		if w.Code != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, w.Code, tc.statusCode)
+		}
+	}
+}
+
+func TestHandler_Convert(t *testing.T) {
+	cases := []struct {
+		desc       string
+		mockErr    error
+		statusCode int
+	}{
+		{"success case", nil, http.StatusCreated},
+		{"not approved", errors.Conflict{Entity: "trade-in", ID: id.String(), Reason: "is appraised"}, http.StatusConflict},
+	}
+
+	for i, tc := range cases {
+		h, mockService, r, w := initializeTest(t, http.MethodPost,
+			bytes.NewReader([]byte(`{"price":1500000,"engine":{"displacement":2000,"noOfCylinder":4}}`)), map[string]string{"id": id.String()})
+
+		mockService.EXPECT().Convert(gomock.Any(), id, &models.Car{Price: 1500000, Engine: models.Engine{Displacement: 2000, NCylinder: 4}}).
+			Return(&models.Car{ID: id}, tc.mockErr)
+
+		h.Convert(w, r)
+
+		if w.Code != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, w.Code, tc.statusCode)
+		}
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,167 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/handlers/response"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+type handler struct {
+	service services.Webhook
+	logger  *slog.Logger
+}
+
+// nolint:revive // handler should not be exported
+func New(service services.Webhook, logger *slog.Logger) handler {
+	return handler{service: service, logger: logger}
+}
+
+// Create subscribes a webhook and writes it with the secret its payloads are signed with
+func (h handler) Create(w http.ResponseWriter, r *http.Request) {
+	webhook, err := getWebhook(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	webhook, err = h.service.Create(r.Context(), webhook)
+	response.Write(w, r, h.logger, webhook, err)
+}
+
+// GetAll writes all the webhooks without their secrets
+func (h handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.service.GetAll(r.Context())
+	response.Write(w, r, h.logger, webhooks, err)
+}
+
+// GetByID writes the webhook based on ID
+func (h handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getID(r, "id")
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	webhook, err := h.service.GetByID(r.Context(), id)
+	response.Write(w, r, h.logger, webhook, err)
+}
+
+// Update changes the url, event types and state of the webhook based on ID
+func (h handler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := getID(r, "id")
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	webhook, err := getWebhook(r)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	webhook.ID = id
+
+	webhook, err = h.service.Update(r.Context(), webhook)
+	response.Write(w, r, h.logger, webhook, err)
+}
+
+// Delete removes the webhook based on ID
+func (h handler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := getID(r, "id")
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	err = h.service.Delete(r.Context(), id)
+	response.Write(w, r, h.logger, nil, err)
+}
+
+// GetDeliveries writes the deliveries of the webhook, optionally only those of the status query parameter
+func (h handler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := getID(r, "id")
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	deliveries, err := h.service.GetDeliveries(r.Context(), id, r.URL.Query().Get("status"))
+	response.Write(w, r, h.logger, deliveries, err)
+}
+
+// Redeliver queues the delivery again and answers 202 since it is sent in the background
+func (h handler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	id, err := getID(r, "id")
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	deliveryID, err := getID(r, "deliveryId")
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	delivery, err := h.service.Redeliver(r.Context(), id, deliveryID)
+	if err != nil {
+		response.Write(w, r, h.logger, nil, err)
+
+		return
+	}
+
+	response.WriteBody(w, r, h.logger, http.StatusAccepted, delivery)
+}
+
+// getID reads the id from the given path parameter of url
+func getID(r *http.Request, name string) (uuid.UUID, error) {
+	param := mux.Vars(r)
+	idParam := strings.TrimSpace(param[name])
+
+	if idParam == "" {
+		return uuid.Nil, errors.MissingParam{Param: name}
+	}
+
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		return uuid.Nil, errors.InvalidParam{Param: []string{name}}
+	}
+
+	return id, nil
+}
+
+// getWebhook reads request body and returns the webhook
+func getWebhook(r *http.Request) (*models.Webhook, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	var webhook models.Webhook
+
+	err = json.Unmarshal(body, &webhook)
+	if err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	return &webhook, nil
+}
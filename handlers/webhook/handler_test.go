@@ -0,0 +1,163 @@
+package webhook
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+func initializeTest(t *testing.T, method string, body io.Reader, pParam map[string]string) (handler, *services.MockWebhook,
+	*http.Request, *httptest.ResponseRecorder) {
+	ctrl := gomock.NewController(t)
+
+	mockService := services.NewMockWebhook(ctrl)
+	h := New(mockService, logging.Discard())
+
+	req := httptest.NewRequest(method, "http://webhook", body)
+	r := mux.SetURLVars(req, pParam)
+
+	w := httptest.NewRecorder()
+
+	return h, mockService, r, w
+}
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var id = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+
+func TestHandler_Create(t *testing.T) {
+	cases := []struct {
+		desc       string
+		body       string
+		mockCall   bool
+		mockErr    error
+		statusCode int
+	}{
+		{"success case", `{"url":"https://crm.example.com/hook","eventTypes":["car.created"]}`, true, nil, http.StatusCreated},
+		{"invalid url", `{"url":"crm"}`, true, errors.InvalidParam{Param: []string{"url"}}, http.StatusBadRequest},
+		{"invalid body", `{"url":`, false, nil, http.StatusBadRequest},
+	}
+
+	for i, tc := range cases {
+		h, mockService, r, w := initializeTest(t, http.MethodPost, bytes.NewReader([]byte(tc.body)), nil)
+
+		if tc.mockCall {
+			mockService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&models.Webhook{ID: id, Secret: "secret"}, tc.mockErr)
+		}
+
+		h.Create(w, r)
+
+		if w.Code != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, w.Code, tc.statusCode)
+		}
+	}
+}
+
+func TestHandler_Update(t *testing.T) {
+	cases := []struct {
+		desc       string
+		id         string
+		mockCall   bool
+		mockErr    error
+		statusCode int
+	}{
+		{"success case", id.String(), true, nil, http.StatusOK},
+		{"missing webhook", id.String(), true, errors.EntityNotFound{Entity: "webhook", ID: id.String()}, http.StatusNotFound},
+		{"invalid id", "1", false, nil, http.StatusBadRequest},
+	}
+
+	for i, tc := range cases {
+		h, mockService, r, w := initializeTest(t, http.MethodPut, bytes.NewReader([]byte(`{"url":"https://crm.example.com/hook"}`)),
+			map[string]string{"id": tc.id})
+
+		if tc.mockCall {
+			mockService.EXPECT().Update(gomock.Any(), &models.Webhook{ID: id, URL: "https://crm.example.com/hook"}).
+				Return(&models.Webhook{ID: id}, tc.mockErr)
+		}
+
+		h.Update(w, r)
+
+		if w.Code != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, w.Code, tc.statusCode)
+		}
+	}
+}
+
+func TestHandler_GetAndDelete(t *testing.T) {
+	h, mockService, r, w := initializeTest(t, http.MethodGet, nil, nil)
+
+	mockService.EXPECT().GetAll(gomock.Any()).Return([]models.Webhook{{ID: id}}, nil)
+	h.GetAll(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("\n[TEST] Failed. Desc : get all\nGot %v\nExpected %v", w.Code, http.StatusOK)
+	}
+
+	h, mockService, r, w = initializeTest(t, http.MethodGet, nil, map[string]string{"id": id.String()})
+
+	mockService.EXPECT().GetByID(gomock.Any(), id).Return(&models.Webhook{ID: id}, nil)
+	h.GetByID(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("\n[TEST] Failed. Desc : get by id\nGot %v\nExpected %v", w.Code, http.StatusOK)
+	}
+
+	h, mockService, r, w = initializeTest(t, http.MethodDelete, nil, map[string]string{"id": id.String()})
+
+	mockService.EXPECT().Delete(gomock.Any(), id).Return(nil)
+	h.Delete(w, r)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("\n[TEST] Failed. Desc : delete\nGot %v\nExpected %v", w.Code, http.StatusNoContent)
+	}
+}
+
+func TestHandler_Deliveries(t *testing.T) {
+	deliveryID := uuid.New()
+
+	h, mockService, r, w := initializeTest(t, http.MethodGet, nil, map[string]string{"id": id.String()})
+	r.URL.RawQuery = "status=dead"
+
+	mockService.EXPECT().GetDeliveries(gomock.Any(), id, models.DeliveryDead).Return([]models.Delivery{}, nil)
+	h.GetDeliveries(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("\n[TEST] Failed. Desc : dead deliveries\nGot %v\nExpected %v", w.Code, http.StatusOK)
+	}
+
+	cases := []struct {
+		desc       string
+		deliveryID string
+		mockCall   bool
+		mockErr    error
+		statusCode int
+	}{
+		{"queued again", deliveryID.String(), true, nil, http.StatusAccepted},
+		{"missing delivery", deliveryID.String(), true, errors.EntityNotFound{Entity: "delivery", ID: deliveryID.String()}, http.StatusNotFound},
+		{"invalid delivery id", "1", false, nil, http.StatusBadRequest},
+	}
+
+	for i, tc := range cases {
+		h, mockService, r, w := initializeTest(t, http.MethodPost, nil, map[string]string{"id": id.String(), "deliveryId": tc.deliveryID})
+
+		if tc.mockCall {
+			mockService.EXPECT().Redeliver(gomock.Any(), id, deliveryID).Return(&models.Delivery{ID: deliveryID}, tc.mockErr)
+		}
+
+		h.Redeliver(w, r)
+
+		if w.Code != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, w.Code, tc.statusCode)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package logging
+
+import "context"
+
+type contextKey int
+
+const requestKey contextKey = iota
+
+// Request holds the request scoped values stamped on every log line
+type Request struct {
+	ID        string
+	Principal string
+}
+
+// WithRequest returns a copy of ctx carrying the request values
+func WithRequest(ctx context.Context, req *Request) context.Context {
+	return context.WithValue(ctx, requestKey, req)
+}
+
+// FromContext returns the request values of ctx, if any
+func FromContext(ctx context.Context) (*Request, bool) {
+	req, ok := ctx.Value(requestKey).(*Request)
+
+	return req, ok && req != nil
+}
+
+// RequestID returns the id of the request carried by ctx
+func RequestID(ctx context.Context) string {
+	if req, ok := FromContext(ctx); ok {
+		return req.ID
+	}
+
+	return ""
+}
+
+// SetPrincipal records the authenticated principal of the request carried by ctx
+func SetPrincipal(ctx context.Context, principal string) {
+	if req, ok := FromContext(ctx); ok {
+		req.Principal = principal
+	}
+}
@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+type contextHandler struct {
+	slog.Handler
+}
+
+// New returns a JSON logger writing records of at least the given level, records logged with a request
+// context carry its request id and principal
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(contextHandler{Handler: slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})})
+}
+
+// Discard returns a logger dropping every record
+func Discard() *slog.Logger {
+	return slog.New(slog.NewJSONHandler(io.Discard, nil))
+}
+
+// ParseLevel reads the level name, unknown names fall back to info
+func ParseLevel(name string) slog.Level {
+	var level slog.Level
+
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+		return slog.LevelInfo
+	}
+
+	return level
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if req, ok := FromContext(ctx); ok {
+		record.AddAttrs(slog.String("request_id", req.ID))
+
+		if req.Principal != "" {
+			record.AddAttrs(slog.String("principal", req.Principal))
+		}
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithGroup(name)}
+}
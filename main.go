@@ -1,23 +1,91 @@
 package main
 
 import (
-	"log"
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 
+	"github.com/amehrotra/car-dealership/authz"
+	"github.com/amehrotra/car-dealership/cache"
 	"github.com/amehrotra/car-dealership/drivers"
+	"github.com/amehrotra/car-dealership/events"
+	apiKeyHandlers "github.com/amehrotra/car-dealership/handlers/apikey"
 	handlers "github.com/amehrotra/car-dealership/handlers/car"
+	customerHandlers "github.com/amehrotra/car-dealership/handlers/customer"
+	healthHandlers "github.com/amehrotra/car-dealership/handlers/health"
+	orderHandlers "github.com/amehrotra/car-dealership/handlers/order"
+	reservationHandlers "github.com/amehrotra/car-dealership/handlers/reservation"
+	testDriveHandlers "github.com/amehrotra/car-dealership/handlers/testdrive"
+	tradeInHandlers "github.com/amehrotra/car-dealership/handlers/tradein"
+	webhookHandlers "github.com/amehrotra/car-dealership/handlers/webhook"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/metrics"
 	"github.com/amehrotra/car-dealership/middlewares"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/openapi"
+	apiKeyServices "github.com/amehrotra/car-dealership/services/apikey"
 	services "github.com/amehrotra/car-dealership/services/car"
+	customerServices "github.com/amehrotra/car-dealership/services/customer"
+	orderServices "github.com/amehrotra/car-dealership/services/order"
+	outboxServices "github.com/amehrotra/car-dealership/services/outbox"
+	reservationServices "github.com/amehrotra/car-dealership/services/reservation"
+	testDriveServices "github.com/amehrotra/car-dealership/services/testdrive"
+	tradeInServices "github.com/amehrotra/car-dealership/services/tradein"
+	webhookServices "github.com/amehrotra/car-dealership/services/webhook"
+	"github.com/amehrotra/car-dealership/stores"
+	"github.com/amehrotra/car-dealership/stores/apikey"
+	"github.com/amehrotra/car-dealership/stores/broadcast"
 	"github.com/amehrotra/car-dealership/stores/car"
+	"github.com/amehrotra/car-dealership/stores/customer"
+	"github.com/amehrotra/car-dealership/stores/delivery"
 	"github.com/amehrotra/car-dealership/stores/engine"
+	"github.com/amehrotra/car-dealership/stores/order"
+	"github.com/amehrotra/car-dealership/stores/outbox"
+	"github.com/amehrotra/car-dealership/stores/reservation"
+	"github.com/amehrotra/car-dealership/stores/testdrive"
+	"github.com/amehrotra/car-dealership/stores/tradein"
+	"github.com/amehrotra/car-dealership/stores/webhook"
+	"github.com/amehrotra/car-dealership/tracing"
 )
 
 func main() {
-	// database connection
-	db, err := drivers.ConnectToSQL()
+	adminOwner := flag.String("issue-admin-key", "", "issue an admin api key for the given owner and exit")
+	flag.Parse()
+
+	logger := logging.New(os.Stdout, logging.ParseLevel(os.Getenv("LOG_LEVEL")))
+	slog.SetDefault(logger)
+
+	// database connection, retried until DB_CONNECT_TIMEOUT so that the database may start after the server
+	connectTimeout := time.Minute
+
+	if v := os.Getenv("DB_CONNECT_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			logger.Error("invalid DB_CONNECT_TIMEOUT", "error", err)
+
+			return
+		}
+
+		connectTimeout = d
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	db, err := drivers.ConnectToSQL(ctx, logger)
+
+	cancel()
+
 	if err != nil {
+		logger.Error("giving up connecting to the database", "error", err)
+
 		return
 	}
 
@@ -28,28 +96,290 @@ func main() {
 		}
 	}()
 
-	// dependency injection
-	carStore := car.New(db)
-	engineStore := engine.New(db)
-	service := services.New(engineStore, carStore)
-	handler := handlers.New(service)
+	// metrics, the inventory gauges read the uninstrumented store so that scrapes do not count as store calls
+	m := metrics.New()
+
+	if err := m.Register(collectors.NewDBStatsCollector(db, "car_dealership"), metrics.Inventory(car.New(db, logger), logger)); err != nil {
+		logger.Error("error in registering metrics", "error", err)
+
+		return
+	}
+
+	// tracing, traces are continued from and propagated with the W3C traceparent header
+	tp, err := tracing.NewProvider(context.Background(), os.Getenv("OTEL_TRACES_EXPORTER"), os.Stdout)
+	if err != nil {
+		logger.Error("error in setting up tracing", "error", err)
+
+		return
+	}
+
+	defer func() {
+		if err := tp.Shutdown(context.Background()); err != nil {
+			logger.Error("error in shutting down tracing", "error", err)
+		}
+	}()
+
+	propagator := propagation.TraceContext{}
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagator)
 
-	r := mux.NewRouter()
-	r.HandleFunc("/car", handler.Create).Methods(http.MethodPost)
-	r.HandleFunc("/car", handler.GetAll).Methods(http.MethodGet)
-	r.HandleFunc("/car/{id}", handler.GetByID).Methods(http.MethodGet)
-	r.HandleFunc("/car/{id}", handler.Update).Methods(http.MethodPut)
-	r.HandleFunc("/car/{id}", handler.Delete).Methods(http.MethodDelete)
+	// dependency injection, changes to cars are written to the outbox in their transaction
+	tx := stores.NewTransactor(db)
+	outboxStore := outbox.New(db, logger)
+	webhookStore := webhook.New(db, logger)
+	deliveryStore := delivery.New(db, logger)
+	webhookService := webhookServices.New(webhookStore, deliveryStore, logger)
+	webhookHandler := webhookHandlers.New(webhookService, logger)
+
+	// cars and engines fetched by id are cached in process for CACHE_TTL, a zero ttl turns the cache off
+	cacheTTL := 30 * time.Second
+
+	if v := os.Getenv("CACHE_TTL"); v != "" {
+		if cacheTTL, err = time.ParseDuration(v); err != nil {
+			logger.Error("invalid CACHE_TTL", "error", err)
+
+			return
+		}
+	}
+
+	carStore := metrics.CarStore(car.New(db, logger), m)
+	engineStore := metrics.EngineStore(engine.New(db), m)
+
+	if cacheTTL > 0 {
+		lru := cache.NewLRU(10000)
+		carStore = cache.CarStore(carStore, lru, cacheTTL, m)
+		engineStore = cache.EngineStore(engineStore, lru, cacheTTL, m)
+	}
+
+	carStore = tracing.CarStore(carStore, tp)
+	engineStore = tracing.EngineStore(engineStore, tp)
+	service := tracing.CarService(metrics.CarService(services.New(engineStore, carStore, tx, outboxServices.New(outboxStore), logger), m), tp)
+
+	// orders and reservations lock their car in the transaction placing them so that a car is sold or held once
+	customerStore := customer.New(db, logger)
+	reservationStore := reservation.New(db, logger)
+	orderStore := order.New(db, logger)
+	reservationService := reservationServices.New(reservationStore, carStore, customerStore, orderStore, tx, logger)
+	customerHandler := customerHandlers.New(customerServices.New(customerStore, logger), logger)
+	orderHandler := orderHandlers.New(orderServices.New(orderStore, carStore, customerStore, reservationStore, tx, logger), logger)
+	reservationHandler := reservationHandlers.New(reservationService, logger)
+
+	// test drives are booked within the business hours of the dealership, in the DEALERSHIP_TIMEZONE time zone
+	location := time.UTC
+
+	if v := os.Getenv("DEALERSHIP_TIMEZONE"); v != "" {
+		if location, err = time.LoadLocation(v); err != nil {
+			logger.Error("invalid DEALERSHIP_TIMEZONE", "error", err)
+
+			return
+		}
+	}
+
+	testDriveService := testDriveServices.New(testdrive.New(db, logger), carStore, customerStore, tx, testDriveServices.DefaultHours(location),
+		logger)
+	testDriveHandler := testDriveHandlers.New(testDriveService, logger)
+
+	// approved trade-ins become used cars through the car service, like the cars created through the api
+	tradeInHandler := tradeInHandlers.New(tradeInServices.New(tradein.New(db, logger), customerStore, service, tx, logger), logger)
+
+	// reads of cars carry ETag and Last-Modified, CACHE_CONTROL overrides how long clients may reuse them
+	handlerOpts := []handlers.Option{handlers.WithReservations(reservationService)}
+	if v := os.Getenv("CACHE_CONTROL"); v != "" {
+		handlerOpts = append(handlerOpts, handlers.WithCacheControl(v))
+	}
+
+	handler := handlers.New(service, logger, handlerOpts...)
+
+	// the outbox is relayed to the webhooks, the event stream and, when EVENTS_FILE is set, appended to that file.
+	// The stream is broadcast through the database so that every instance streams what any of them relays.
+	hub := events.NewHub(1000)
+	streamHandler := handlers.NewStream(services.NewStream(hub), logger)
+	broadcaster := outboxServices.NewBroadcast(broadcast.New(db, logger), hub, logger)
+	publishers := []events.Publisher{webhookService, broadcaster}
+
+	if path := os.Getenv("EVENTS_FILE"); path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			logger.Error("error in opening EVENTS_FILE", "error", err)
+
+			return
+		}
+
+		defer f.Close()
+
+		publishers = append(publishers, events.File(f))
+	}
+
+	apiKeyService := apiKeyServices.New(apikey.New(db, logger), logger)
+	apiKeyHandler := apiKeyHandlers.New(apiKeyService, logger)
+
+	// bootstrap the first admin key, further keys are issued through the admin endpoints
+	if *adminOwner != "" {
+		key, err := apiKeyService.Create(context.Background(), &models.APIKey{Owner: *adminOwner, Scopes: []string{string(authz.Admin)}})
+		if err != nil {
+			logger.Error("error in issuing the admin key", "error", err)
+
+			return
+		}
+
+		fmt.Println(key.Key)
+
+		return
+	}
+
+	// the outbox is relayed and the queued deliveries are sent in the background
+	relay := outboxServices.NewRelay(outboxStore, tx, events.Fanout(publishers...), logger)
+
+	go relay.Run(context.Background(), time.Second)
+
+	go broadcaster.Run(context.Background(), time.Second)
+
+	dispatcher := webhookServices.NewDispatcher(webhookStore, deliveryStore, tx, webhookServices.NewClient(10*time.Second), logger)
+
+	go dispatcher.Run(context.Background(), 5*time.Second)
+
+	sweeper := reservationServices.NewSweeper(reservationStore, logger)
+
+	go sweeper.Run(context.Background(), time.Minute)
+
+	// every route is guarded by the permission it needs
+	allow := func(permission authz.Permission, h http.HandlerFunc) http.Handler {
+		return middlewares.RequirePermission(logger, permission)(h)
+	}
+
+	root := mux.NewRouter()
+	root.Use(middlewares.Tracing(tp, propagator), middlewares.RequestID, middlewares.AccessLog(logger), middlewares.Metrics(m),
+		middlewares.Compress(1024))
+
+	// the metrics endpoint is scraped without credentials and is kept off the public interface by the bind address
+	root.Handle("/metrics", m.Handler()).Methods(http.MethodGet)
+
+	// the api description and its Swagger UI are public
+	spec, err := openapi.Load()
+	if err != nil {
+		logger.Error("error in loading the api description", "error", err)
+
+		return
+	}
+
+	root.Handle("/openapi.json", spec.Handler(logger)).Methods(http.MethodGet)
+	root.PathPrefix("/docs/").Handler(http.StripPrefix("/docs/", openapi.UI(logger))).Methods(http.MethodGet)
+
+	// probes of the orchestrator are not authenticated either
+	healthHandler := healthHandlers.New(map[string]healthHandlers.Check{
+		"database":   db.PingContext,
+		"migrations": drivers.CheckMigrations(db),
+	}, 2*time.Second, logger)
+
+	root.HandleFunc("/healthz", healthHandler.Live).Methods(http.MethodGet)
+	root.HandleFunc("/readyz", healthHandler.Ready).Methods(http.MethodGet)
+
+	r := root.PathPrefix("/").Subrouter()
+	r.Handle("/car", allow(authz.CreateCars, handler.Create)).Methods(http.MethodPost)
+	r.Handle("/car", allow(authz.ReadCars, handler.GetAll)).Methods(http.MethodGet)
+	r.Handle("/car/import", allow(authz.CreateCars, handler.Import)).Methods(http.MethodPost)
+	r.Handle("/car/export", allow(authz.ReadCars, handler.Export)).Methods(http.MethodGet)
+	r.Handle("/car/batch", allow(authz.UpdateCars, handler.Batch)).Methods(http.MethodPost)
+	r.Handle("/car/stream", allow(authz.ReadCars, streamHandler.Stream)).Methods(http.MethodGet)
+	r.Handle("/car/{id}", allow(authz.ReadCars, handler.GetByID)).Methods(http.MethodGet)
+	r.Handle("/car/{id}", allow(authz.UpdateCars, handler.Update)).Methods(http.MethodPut)
+	r.Handle("/car/{id}", allow(authz.DeleteCars, handler.Delete)).Methods(http.MethodDelete)
+	r.Handle("/car/{id}/reservation", allow(authz.ReserveCars, reservationHandler.Create)).Methods(http.MethodPost)
+	r.Handle("/car/{id}/reservation", allow(authz.ReserveCars, reservationHandler.Cancel)).Methods(http.MethodDelete)
+
+	// customers, listed with their personal details masked
+	r.Handle("/customer", allow(authz.ManageCustomers, customerHandler.Create)).Methods(http.MethodPost)
+	r.Handle("/customer", allow(authz.ReadCustomers, customerHandler.GetAll)).Methods(http.MethodGet)
+	r.Handle("/customer/{id}", allow(authz.ReadCustomers, customerHandler.GetByID)).Methods(http.MethodGet)
+	r.Handle("/customer/{id}", allow(authz.ManageCustomers, customerHandler.Update)).Methods(http.MethodPut)
+	r.Handle("/customer/{id}", allow(authz.DeleteCustomers, customerHandler.Delete)).Methods(http.MethodDelete)
+
+	// sales orders of the cars
+	r.Handle("/order", allow(authz.ManageOrders, orderHandler.Create)).Methods(http.MethodPost)
+	r.Handle("/order", allow(authz.ReadOrders, orderHandler.GetAll)).Methods(http.MethodGet)
+	r.Handle("/order/{id}", allow(authz.ReadOrders, orderHandler.GetByID)).Methods(http.MethodGet)
+	r.Handle("/order/{id}", allow(authz.ManageOrders, orderHandler.Update)).Methods(http.MethodPut)
+
+	// test drives and the calendars of the salespeople
+	r.Handle("/testdrive", allow(authz.ManageTestDrives, testDriveHandler.Create)).Methods(http.MethodPost)
+	r.Handle("/testdrive", allow(authz.ReadTestDrives, testDriveHandler.GetAll)).Methods(http.MethodGet)
+	r.Handle("/testdrive/{id}", allow(authz.ReadTestDrives, testDriveHandler.GetByID)).Methods(http.MethodGet)
+	r.Handle("/testdrive/{id}", allow(authz.ManageTestDrives, testDriveHandler.Update)).Methods(http.MethodPut)
+	r.Handle("/salesperson/{salesperson}/testdrives.ics", allow(authz.ReadTestDrives, testDriveHandler.Calendar)).Methods(http.MethodGet)
+
+	// trade-ins, their appraisal and approval, and their conversion into used cars
+	r.Handle("/tradein", allow(authz.ManageTradeIns, tradeInHandler.Create)).Methods(http.MethodPost)
+	r.Handle("/tradein", allow(authz.ReadTradeIns, tradeInHandler.GetAll)).Methods(http.MethodGet)
+	r.Handle("/tradein/{id}", allow(authz.ReadTradeIns, tradeInHandler.GetByID)).Methods(http.MethodGet)
+	r.Handle("/tradein/{id}", allow(authz.ManageTradeIns, tradeInHandler.Update)).Methods(http.MethodPut)
+	r.Handle("/tradein/{id}/appraisal", allow(authz.ManageTradeIns, tradeInHandler.Appraise)).Methods(http.MethodPut)
+	r.Handle("/tradein/{id}/approval", allow(authz.ApproveTradeIns, tradeInHandler.Review)).Methods(http.MethodPut)
+	r.Handle("/tradein/{id}/car", allow(authz.CreateCars, tradeInHandler.Convert)).Methods(http.MethodPost)
+
+	// api key administration
+	r.Handle("/apikey", allow(authz.ManageAPIKeys, apiKeyHandler.Create)).Methods(http.MethodPost)
+	r.Handle("/apikey", allow(authz.ManageAPIKeys, apiKeyHandler.GetAll)).Methods(http.MethodGet)
+	r.Handle("/apikey/{id}/rotate", allow(authz.ManageAPIKeys, apiKeyHandler.Rotate)).Methods(http.MethodPost)
+	r.Handle("/apikey/{id}", allow(authz.ManageAPIKeys, apiKeyHandler.Revoke)).Methods(http.MethodDelete)
+
+	// webhook subscriptions and their deliveries
+	r.Handle("/webhook", allow(authz.ManageWebhooks, webhookHandler.Create)).Methods(http.MethodPost)
+	r.Handle("/webhook", allow(authz.ManageWebhooks, webhookHandler.GetAll)).Methods(http.MethodGet)
+	r.Handle("/webhook/{id}", allow(authz.ManageWebhooks, webhookHandler.GetByID)).Methods(http.MethodGet)
+	r.Handle("/webhook/{id}", allow(authz.ManageWebhooks, webhookHandler.Update)).Methods(http.MethodPut)
+	r.Handle("/webhook/{id}", allow(authz.ManageWebhooks, webhookHandler.Delete)).Methods(http.MethodDelete)
+	r.Handle("/webhook/{id}/deliveries", allow(authz.ManageWebhooks, webhookHandler.GetDeliveries)).Methods(http.MethodGet)
+	r.Handle("/webhook/{id}/deliveries/{deliveryId}/retry", allow(authz.ManageWebhooks, webhookHandler.Redeliver)).
+		Methods(http.MethodPost)
 
 	// authentication middleware
-	r.Use(middlewares.AuthMiddleware)
+	authenticators := []middlewares.Authenticator{middlewares.APIKeyAuthenticator(apiKeyService)}
+
+	if jwks := os.Getenv("OIDC_JWKS"); jwks != "" {
+		if os.Getenv("OIDC_ISSUER") == "" || os.Getenv("OIDC_AUDIENCE") == "" {
+			logger.Error("OIDC_ISSUER and OIDC_AUDIENCE are required along with OIDC_JWKS")
+
+			return
+		}
+
+		keys, err := middlewares.NewKeySet(jwks, logger)
+		if err != nil {
+			logger.Error("error in loading OIDC_JWKS", "error", err)
+
+			return
+		}
+
+		authenticators = append(authenticators, middlewares.JWTAuthenticator(middlewares.JWTConfig{
+			Issuer:   os.Getenv("OIDC_ISSUER"),
+			Audience: os.Getenv("OIDC_AUDIENCE"),
+			Keys:     keys,
+		}))
+	}
+
+	// rate limits, clients are limited by address before authentication and by principal per route after it
+	limits := middlewares.NewMemoryStore()
+	routeLimits := map[string]middlewares.Limit{
+		"GET /car":         {Rate: 2, Burst: 5},
+		"GET /car/export":  {Rate: 0.1, Burst: 2},
+		"GET /car/stream":  {Rate: 0.2, Burst: 5},
+		"POST /car":        {Rate: 5, Burst: 10},
+		"POST /car/import": {Rate: 0.1, Burst: 2},
+		"POST /car/batch":  {Rate: 1, Burst: 5},
+		"PUT /car/{id}":    {Rate: 5, Burst: 10},
+	}
+
+	r.Use(middlewares.RateLimit(logger, limits, "ip", middlewares.Limit{Rate: 50, Burst: 100}, middlewares.ByIP))
+	r.Use(middlewares.AuthMiddleware(logger, authenticators...))
+	r.Use(middlewares.RouteRateLimit(logger, limits, routeLimits, middlewares.Limit{Rate: 10, Burst: 20}))
+	r.Use(middlewares.ValidateBody(logger, spec))
 
 	// setup server variables
 	srv := &http.Server{
-		Handler: r,
+		Handler: root,
 		Addr:    "127.0.0.1:8000",
 	}
 
 	// start server
-	log.Println(srv.ListenAndServe())
+	logger.Error("server stopped", "error", srv.ListenAndServe())
 }
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+const inventoryTimeout = 5 * time.Second
+
+type inventory struct {
+	cars   stores.Car
+	logger *slog.Logger
+	stock  *prometheus.Desc
+}
+
+// Inventory collects the cars in stock by brand and fuel type from the car store on every scrape, counted by the database
+func Inventory(cars stores.Car, logger *slog.Logger) prometheus.Collector {
+	return inventory{
+		cars:   cars,
+		logger: logger,
+		stock: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "cars_in_stock"),
+			"Cars in stock, by brand and fuel type.", []string{"brand", "fuel_type"}, nil),
+	}
+}
+
+func (i inventory) Describe(ch chan<- *prometheus.Desc) {
+	ch <- i.stock
+}
+
+func (i inventory) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), inventoryTimeout)
+	defer cancel()
+
+	stock, err := i.cars.Stock(ctx)
+	if err != nil {
+		i.logger.ErrorContext(ctx, "error in collecting inventory metrics", "error", err)
+
+		return
+	}
+
+	type key struct{ brand, fuel string }
+
+	counts := make(map[key]int)
+
+	// brands differing only in case are counted together
+	for _, st := range stock {
+		counts[key{brand: strings.ToLower(st.Brand), fuel: st.FuelType.String()}] += st.Count
+	}
+
+	for k, n := range counts {
+		ch <- prometheus.MustNewConstMetric(i.stock, prometheus.GaugeValue, float64(n), k.brand, k.fuel)
+	}
+}
@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "car_dealership"
+
+// Metrics owns the registry exposed on /metrics and the collectors shared by the instrumented layers
+type Metrics struct {
+	registry     *prometheus.Registry
+	requests     *prometheus.CounterVec
+	requestTime  *prometheus.HistogramVec
+	calls        *prometheus.CounterVec
+	callDuration *prometheus.HistogramVec
+	cacheLookups *prometheus.CounterVec
+}
+
+// New registers the http and layer collectors along with the go runtime and process collectors
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests served, by route template, method and status.",
+		}, []string{"route", "method", "status"}),
+		requestTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of HTTP requests, by route template, method and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "calls_total",
+			Help:      "Calls to services and stores, by layer, component, method and outcome.",
+		}, []string{"layer", "component", "method", "outcome"}),
+		callDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "call_duration_seconds",
+			Help:      "Latency of calls to services and stores, by layer, component and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"layer", "component", "method"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_lookups_total",
+			Help:      "Lookups in the read-through caches, by component and outcome.",
+		}, []string{"component", "outcome"}),
+	}
+
+	m.registry.MustRegister(m.requests, m.requestTime, m.calls, m.callDuration, m.cacheLookups,
+		collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+	return m
+}
+
+// Register adds collectors to the registry
+func (m *Metrics) Register(cs ...prometheus.Collector) error {
+	for _, c := range cs {
+		if err := m.registry.Register(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Handler serves the registry in the prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveRequest records a served HTTP request
+func (m *Metrics) ObserveRequest(route, method string, status int, d time.Duration) {
+	code := strconv.Itoa(status)
+
+	m.requests.WithLabelValues(route, method, code).Inc()
+	m.requestTime.WithLabelValues(route, method, code).Observe(d.Seconds())
+}
+
+// ObserveCacheLookup records a lookup in a read-through cache
+func (m *Metrics) ObserveCacheLookup(component string, hit bool) {
+	outcome := "miss"
+	if hit {
+		outcome = "hit"
+	}
+
+	m.cacheLookups.WithLabelValues(component, outcome).Inc()
+}
+
+// observeCall records a call made to a service or store method
+func (m *Metrics) observeCall(layer, component, method string, start time.Time, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+
+	m.calls.WithLabelValues(layer, component, method, outcome).Inc()
+	m.callDuration.WithLabelValues(layer, component, method).Observe(time.Since(start).Seconds())
+}
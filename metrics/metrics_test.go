@@ -0,0 +1,142 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+	"github.com/amehrotra/car-dealership/types"
+)
+
+func TestMetrics_ObserveRequest(t *testing.T) {
+	m := New()
+
+	m.ObserveRequest("/car/{id}", http.MethodGet, http.StatusOK, time.Millisecond)
+	m.ObserveRequest("/car/{id}", http.MethodGet, http.StatusOK, time.Millisecond)
+	m.ObserveRequest("/car/{id}", http.MethodGet, http.StatusNotFound, time.Millisecond)
+
+	cases := []struct {
+		desc   string
+		status string
+		output float64
+	}{
+		{"ok responses", "200", 2},
+		{"not found responses", "404", 1},
+		{"no responses", "500", 0},
+	}
+
+	for i, tc := range cases {
+		output := testutil.ToFloat64(m.requests.WithLabelValues("/car/{id}", http.MethodGet, tc.status))
+
+		if output != tc.output {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, output, tc.output)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://cars/metrics", nil))
+
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "car_dealership_http_requests_total") {
+		t.Errorf("\n[TEST] Failed. Desc : exposition\nGot %v %s\nExpected %v with request counts", w.Code, w.Body.String(), http.StatusOK)
+	}
+}
+
+func TestCarStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := stores.NewMockCar(ctrl)
+	m := New()
+	s := CarStore(mockStore, m)
+	id := uuid.New()
+
+	mockStore.EXPECT().GetByID(gomock.Any(), id).Return(models.Car{ID: id}, nil)
+	mockStore.EXPECT().Delete(gomock.Any(), id).Return(errors.DB{Err: context.Canceled})
+
+	if _, err := s.GetByID(context.Background(), id); err != nil {
+		t.Errorf("\n[TEST] Failed. Desc : get by id\nGot %v\nExpected nil", err)
+	}
+
+	if err := s.Delete(context.Background(), id); err == nil {
+		t.Errorf("\n[TEST] Failed. Desc : delete\nGot %v\nExpected error", err)
+	}
+
+	cases := []struct {
+		desc    string
+		method  string
+		outcome string
+	}{
+		{"successful call", "GetByID", "success"},
+		{"failed call", "Delete", "error"},
+	}
+
+	for i, tc := range cases {
+		output := testutil.ToFloat64(m.calls.WithLabelValues(storeLayer, "car", tc.method, tc.outcome))
+
+		if output != 1 {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected 1", i, tc.desc, output)
+		}
+	}
+}
+
+func TestInventory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := stores.NewMockCar(ctrl)
+	collector := Inventory(mockStore, logging.Discard())
+
+	mockStore.EXPECT().Stock(gomock.Any()).Return([]models.Stock{
+		{Brand: "BMW", FuelType: types.Petrol, Count: 2},
+		{Brand: "bmw", FuelType: types.Petrol, Count: 1},
+		{Brand: "Tesla", FuelType: types.Electric, Count: 1},
+	}, nil)
+	mockStore.EXPECT().Stock(gomock.Any()).Return(nil, errors.DB{Err: context.Canceled})
+
+	expected := `
+# HELP car_dealership_cars_in_stock Cars in stock, by brand and fuel type.
+# TYPE car_dealership_cars_in_stock gauge
+car_dealership_cars_in_stock{brand="bmw",fuel_type="petrol"} 3
+car_dealership_cars_in_stock{brand="tesla",fuel_type="electric"} 1
+`
+
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected)); err != nil {
+		t.Errorf("\n[TEST] Failed. Desc : stock by brand and fuel\nGot %v\nExpected nil", err)
+	}
+
+	if n := testutil.CollectAndCount(collector); n != 0 {
+		t.Errorf("\n[TEST] Failed. Desc : store error\nGot %v metrics\nExpected 0", n)
+	}
+}
+
+func TestMetrics_ObserveCacheLookup(t *testing.T) {
+	m := New()
+
+	m.ObserveCacheLookup("car", true)
+	m.ObserveCacheLookup("car", true)
+	m.ObserveCacheLookup("car", false)
+
+	cases := []struct {
+		desc    string
+		outcome string
+		output  float64
+	}{
+		{"hits", "hit", 2},
+		{"misses", "miss", 1},
+	}
+
+	for i, tc := range cases {
+		output := testutil.ToFloat64(m.cacheLookups.WithLabelValues("car", tc.outcome))
+
+		if output != tc.output {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, output, tc.output)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+const serviceLayer = "service"
+
+type carService struct {
+	next    services.Car
+	metrics *Metrics
+}
+
+// CarService records the count, outcome and latency of every call to the car service
+func CarService(next services.Car, m *Metrics) services.Car {
+	return carService{next: next, metrics: m}
+}
+
+func (s carService) Create(ctx context.Context, car *models.Car) (*models.Car, error) {
+	start := time.Now()
+	resp, err := s.next.Create(ctx, car)
+	s.metrics.observeCall(serviceLayer, "car", "Create", start, err)
+
+	return resp, err
+}
+
+func (s carService) GetAll(ctx context.Context, filter filters.Car) ([]models.Car, error) {
+	start := time.Now()
+	resp, err := s.next.GetAll(ctx, filter)
+	s.metrics.observeCall(serviceLayer, "car", "GetAll", start, err)
+
+	return resp, err
+}
+
+func (s carService) GetByID(ctx context.Context, id uuid.UUID) (*models.Car, error) {
+	start := time.Now()
+	resp, err := s.next.GetByID(ctx, id)
+	s.metrics.observeCall(serviceLayer, "car", "GetByID", start, err)
+
+	return resp, err
+}
+
+func (s carService) Update(ctx context.Context, car *models.Car) (*models.Car, error) {
+	start := time.Now()
+	resp, err := s.next.Update(ctx, car)
+	s.metrics.observeCall(serviceLayer, "car", "Update", start, err)
+
+	return resp, err
+}
+
+func (s carService) Delete(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := s.next.Delete(ctx, id)
+	s.metrics.observeCall(serviceLayer, "car", "Delete", start, err)
+
+	return err
+}
+
+func (s carService) Import(ctx context.Context, rows []models.ImportRow, opts models.ImportOptions) (*models.ImportResult, error) {
+	start := time.Now()
+	resp, err := s.next.Import(ctx, rows, opts)
+	s.metrics.observeCall(serviceLayer, "car", "Import", start, err)
+
+	return resp, err
+}
+
+func (s carService) Export(ctx context.Context, filter filters.Car, fn func(car *models.Car) error) error {
+	start := time.Now()
+	err := s.next.Export(ctx, filter, fn)
+	s.metrics.observeCall(serviceLayer, "car", "Export", start, err)
+
+	return err
+}
+
+func (s carService) Batch(ctx context.Context, ops []models.BatchOperation, opts models.BatchOptions) ([]models.BatchResult, error) {
+	start := time.Now()
+	resp, err := s.next.Batch(ctx, ops, opts)
+	s.metrics.observeCall(serviceLayer, "car", "Batch", start, err)
+
+	return resp, err
+}
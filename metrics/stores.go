@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+const storeLayer = "store"
+
+type carStore struct {
+	next    stores.Car
+	metrics *Metrics
+}
+
+// CarStore records the count, outcome and latency of every call to the car store
+func CarStore(next stores.Car, m *Metrics) stores.Car {
+	return carStore{next: next, metrics: m}
+}
+
+func (s carStore) Create(ctx context.Context, car *models.Car) error {
+	start := time.Now()
+	err := s.next.Create(ctx, car)
+	s.metrics.observeCall(storeLayer, "car", "Create", start, err)
+
+	return err
+}
+
+func (s carStore) GetAll(ctx context.Context, filter filters.Car) ([]models.Car, error) {
+	start := time.Now()
+	resp, err := s.next.GetAll(ctx, filter)
+	s.metrics.observeCall(storeLayer, "car", "GetAll", start, err)
+
+	return resp, err
+}
+
+func (s carStore) GetByID(ctx context.Context, id uuid.UUID) (models.Car, error) {
+	start := time.Now()
+	resp, err := s.next.GetByID(ctx, id)
+	s.metrics.observeCall(storeLayer, "car", "GetByID", start, err)
+
+	return resp, err
+}
+
+func (s carStore) Update(ctx context.Context, car *models.Car) error {
+	start := time.Now()
+	err := s.next.Update(ctx, car)
+	s.metrics.observeCall(storeLayer, "car", "Update", start, err)
+
+	return err
+}
+
+func (s carStore) Delete(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := s.next.Delete(ctx, id)
+	s.metrics.observeCall(storeLayer, "car", "Delete", start, err)
+
+	return err
+}
+
+// Iterate only observes running the query, reading the rows is paced by the caller
+func (s carStore) Iterate(ctx context.Context, filter filters.Car) (stores.CarIterator, error) {
+	start := time.Now()
+	resp, err := s.next.Iterate(ctx, filter)
+	s.metrics.observeCall(storeLayer, "car", "Iterate", start, err)
+
+	return resp, err
+}
+
+func (s carStore) Lock(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := s.next.Lock(ctx, id)
+	s.metrics.observeCall(storeLayer, "car", "Lock", start, err)
+
+	return err
+}
+
+func (s carStore) Stock(ctx context.Context) ([]models.Stock, error) {
+	start := time.Now()
+	resp, err := s.next.Stock(ctx)
+	s.metrics.observeCall(storeLayer, "car", "Stock", start, err)
+
+	return resp, err
+}
+
+type engineStore struct {
+	next    stores.Engine
+	metrics *Metrics
+}
+
+// EngineStore records the count, outcome and latency of every call to the engine store
+func EngineStore(next stores.Engine, m *Metrics) stores.Engine {
+	return engineStore{next: next, metrics: m}
+}
+
+func (s engineStore) Create(ctx context.Context, engine *models.Engine) error {
+	start := time.Now()
+	err := s.next.Create(ctx, engine)
+	s.metrics.observeCall(storeLayer, "engine", "Create", start, err)
+
+	return err
+}
+
+func (s engineStore) GetByID(ctx context.Context, id uuid.UUID) (models.Engine, error) {
+	start := time.Now()
+	resp, err := s.next.GetByID(ctx, id)
+	s.metrics.observeCall(storeLayer, "engine", "GetByID", start, err)
+
+	return resp, err
+}
+
+func (s engineStore) Update(ctx context.Context, engine *models.Engine) error {
+	start := time.Now()
+	err := s.next.Update(ctx, engine)
+	s.metrics.observeCall(storeLayer, "engine", "Update", start, err)
+
+	return err
+}
+
+func (s engineStore) Delete(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := s.next.Delete(ctx, id)
+	s.metrics.observeCall(storeLayer, "engine", "Delete", start, err)
+
+	return err
+}
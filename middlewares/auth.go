@@ -1,18 +1,83 @@
 package middlewares
 
 import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
 	"net/http"
+
+	"github.com/amehrotra/car-dealership/authz"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
 )
 
-func AuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.Header.Get("Api-Key") != "aryan-zs" {
-			w.WriteHeader(http.StatusUnauthorized)
+// AuthMiddleware tries the authenticators in order and places the first resolved principal in the request context
+func AuthMiddleware(logger *slog.Logger, authenticators ...Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, a := range authenticators {
+				principal, err := a.Authenticate(r)
+
+				switch err.(type) {
+				case nil:
+				case errors.Unauthenticated:
+					logger.InfoContext(r.Context(), "request rejected", "reason", err.Error())
+					writeError(w, r, logger, http.StatusUnauthorized, "unauthenticated", err)
+
+					return
+				default:
+					logger.ErrorContext(r.Context(), "error in authenticating request", "error", err)
+					writeError(w, r, logger, http.StatusInternalServerError, "internal_error", fmt.Errorf("internal server error"))
+
+					return
+				}
+
+				if principal != nil {
+					logging.SetPrincipal(r.Context(), principal.ID)
+
+					// Call the next handler
+					next.ServeHTTP(w, r.WithContext(authz.WithPrincipal(r.Context(), principal)))
+
+					return
+				}
+			}
+
+			writeError(w, r, logger, http.StatusUnauthorized, "unauthenticated", errors.Unauthenticated{Reason: "credentials are missing"})
+		})
+	}
+}
+
+// RequirePermission rejects principals whose roles do not grant the permission
+func RequirePermission(logger *slog.Logger, permission authz.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := authz.PrincipalFromContext(r.Context())
+			if !ok {
+				writeError(w, r, logger, http.StatusUnauthorized, "unauthenticated", errors.Unauthenticated{})
+
+				return
+			}
+
+			if !authz.Allowed(principal, permission) {
+				writeError(w, r, logger, http.StatusForbidden, "forbidden", errors.Forbidden{Permission: string(permission)})
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writeError writes the structured error body carrying the id of the request
+func writeError(w http.ResponseWriter, r *http.Request, logger *slog.Logger, statusCode int, code string, err error) {
+	body, _ := json.Marshal(models.Error{Code: code, Message: err.Error(), RequestID: logging.RequestID(r.Context())})
 
-			return
-		}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
 
-		// Call the next handler
-		next.ServeHTTP(w, r)
-	})
+	if _, err := w.Write(body); err != nil {
+		logger.ErrorContext(r.Context(), "error in writing response", "error", err)
+	}
 }
@@ -0,0 +1,33 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+// Authenticator resolves the principal of a request from one kind of credential.
+// It returns a nil principal and a nil error when the request does not carry its kind of credential,
+// so that the next authenticator of the chain is tried.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*models.Principal, error)
+}
+
+type apiKeyAuthenticator struct {
+	keys services.APIKey
+}
+
+// APIKeyAuthenticator authenticates the Api-Key header against the issued api keys
+func APIKeyAuthenticator(keys services.APIKey) Authenticator {
+	return apiKeyAuthenticator{keys: keys}
+}
+
+func (a apiKeyAuthenticator) Authenticate(r *http.Request) (*models.Principal, error) {
+	key := r.Header.Get("Api-Key")
+	if key == "" {
+		return nil, nil
+	}
+
+	return a.keys.Authenticate(r.Context(), key)
+}
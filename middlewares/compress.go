@@ -0,0 +1,234 @@
+package middlewares
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// contentEncoding is a compression the responses can be sent with
+type contentEncoding struct {
+	name   string
+	writer func(w io.Writer) compressor
+}
+
+// compressor is a compressing writer whose buffered output can be flushed ahead of closing it
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// nolint:gochecknoglobals // read only list of encodings, preferred first among equally weighted ones
+var contentEncodings = []contentEncoding{
+	{"br", func(w io.Writer) compressor { return brotli.NewWriterLevel(w, brotli.DefaultCompression) }},
+	{"gzip", func(w io.Writer) compressor { return gzip.NewWriter(w) }},
+}
+
+// Compress compresses responses of at least minSize bytes with brotli or gzip, as preferred by the Accept-Encoding of the
+// request. Responses already encoded, without a body or streamed as Server-Sent Events are sent as they are.
+func Compress(minSize int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding, ok := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if !ok || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, encoding: encoding, minSize: minSize}
+
+			// a panicking handler aborts the response, it is not completed with the end of the compressed stream
+			next.ServeHTTP(cw, r)
+
+			_ = cw.Close()
+		})
+	}
+}
+
+// negotiateEncoding picks the most preferred encoding of an Accept-Encoding header, none when it is missing
+func negotiateEncoding(accept string) (contentEncoding, bool) {
+	var (
+		best  contentEncoding
+		bestQ float64
+	)
+
+	weights := make(map[string]float64)
+
+	for _, part := range strings.Split(accept, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+
+		q := 1.0
+
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+
+		weights[strings.ToLower(strings.TrimSpace(name))] = q
+	}
+
+	for _, encoding := range contentEncodings {
+		q, ok := weights[encoding.name]
+		if !ok {
+			q, ok = weights["*"]
+		}
+
+		if ok && q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+
+	return best, bestQ > 0
+}
+
+// compressWriter holds the start of the response back until it reaches minSize bytes, the response is then compressed
+// if it is eligible, and sent as it is otherwise
+type compressWriter struct {
+	http.ResponseWriter
+	encoding contentEncoding
+	minSize  int
+	status   int
+	buf      []byte
+	decided  bool
+	writer   io.Writer
+	closer   compressor
+}
+
+func (cw *compressWriter) WriteHeader(statusCode int) {
+	if cw.decided || cw.status != 0 {
+		return
+	}
+
+	// informational responses are sent right away, the final status follows them
+	if statusCode < http.StatusOK {
+		cw.ResponseWriter.WriteHeader(statusCode)
+
+		return
+	}
+
+	cw.status = statusCode
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if cw.decided {
+		return cw.writer.Write(b)
+	}
+
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+
+	cw.buf = append(cw.buf, b...)
+
+	if len(cw.buf) >= cw.minSize {
+		if err := cw.decide(); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(b), nil
+}
+
+// Flush sends what is held back or buffered by the compressor, so that streaming handlers can flush through the writer
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		if err := cw.decide(); err != nil {
+			return
+		}
+	}
+
+	if cw.closer != nil {
+		if err := cw.closer.Flush(); err != nil {
+			return
+		}
+	}
+
+	_ = http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Unwrap exposes the wrapped writer to http.ResponseController
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// Close sends the response held back when it stayed short of minSize and ends the compressed stream
+func (cw *compressWriter) Close() error {
+	if !cw.decided && cw.status != 0 {
+		if err := cw.decide(); err != nil {
+			return err
+		}
+	}
+
+	if cw.closer != nil {
+		return cw.closer.Close()
+	}
+
+	return nil
+}
+
+// decide sends the status line and headers along with the response held back, compressing it when it is eligible
+func (cw *compressWriter) decide() error {
+	cw.decided = true
+	cw.writer = cw.ResponseWriter
+
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+
+	if cw.compressible() {
+		h := cw.Header()
+
+		// the content type can no longer be sniffed from the compressed body
+		if h.Get("Content-Type") == "" {
+			h.Set("Content-Type", http.DetectContentType(cw.buf))
+		}
+
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", cw.encoding.name)
+
+		// a strong validator is of the exact bytes sent, the compressed body only matches the uncompressed one weakly
+		if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			h.Set("ETag", "W/"+etag)
+		}
+
+		cw.closer = cw.encoding.writer(cw.ResponseWriter)
+		cw.writer = cw.closer
+	}
+
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	if len(cw.buf) == 0 {
+		return nil
+	}
+
+	_, err := cw.writer.Write(cw.buf)
+	cw.buf = nil
+
+	return err
+}
+
+// compressible reports whether the response is long enough, has a body and is not already encoded or an event stream
+func (cw *compressWriter) compressible() bool {
+	if len(cw.buf) < cw.minSize || cw.status == http.StatusNoContent || cw.status == http.StatusNotModified {
+		return false
+	}
+
+	h := cw.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(h.Get("Content-Type"))
+
+	return mediaType != "text/event-stream"
+}
@@ -0,0 +1,158 @@
+package middlewares
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	cases := []struct {
+		desc     string
+		accept   string
+		encoding string
+	}{
+		{"missing header", "", ""},
+		{"gzip only", "gzip", "gzip"},
+		{"brotli preferred among equals", "gzip, deflate, br", "br"},
+		{"weights", "br;q=0.5, gzip;q=0.8", "gzip"},
+		{"refused", "gzip;q=0", ""},
+		{"wildcard", "*", "br"},
+		{"wildcard with refusal", "br;q=0, *", "gzip"},
+		{"unsupported", "deflate", ""},
+	}
+
+	for i, tc := range cases {
+		encoding, _ := negotiateEncoding(tc.accept)
+
+		if encoding.name != tc.encoding {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, encoding.name, tc.encoding)
+		}
+	}
+}
+
+func TestCompress(t *testing.T) {
+	long := strings.Repeat(`{"brand":"bmw"}`, 100)
+
+	handler := func(status int, contentType, body string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if contentType != "" {
+				w.Header().Set("Content-Type", contentType)
+			}
+
+			w.WriteHeader(status)
+			_, _ = io.WriteString(w, body)
+		})
+	}
+
+	cases := []struct {
+		desc     string
+		accept   string
+		handler  http.Handler
+		encoding string
+	}{
+		{"gzip", "gzip", handler(http.StatusOK, "application/json", long), "gzip"},
+		{"brotli", "br", handler(http.StatusOK, "application/json", long), "br"},
+		{"error bodies", "gzip", handler(http.StatusNotFound, "application/json", long), "gzip"},
+		{"below the threshold", "gzip", handler(http.StatusOK, "application/json", `{}`), ""},
+		{"not accepted", "", handler(http.StatusOK, "application/json", long), ""},
+		{"event stream", "gzip", handler(http.StatusOK, "text/event-stream", long), ""},
+		{"no content", "gzip", handler(http.StatusNoContent, "", ""), ""},
+	}
+
+	for i, tc := range cases {
+		r := httptest.NewRequest(http.MethodGet, "http://cars/car", nil)
+		r.Header.Set("Accept-Encoding", tc.accept)
+
+		w := httptest.NewRecorder()
+		Compress(1024)(tc.handler).ServeHTTP(w, r)
+
+		if encoding := w.Header().Get("Content-Encoding"); encoding != tc.encoding {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %q\nExpected %q", i, tc.desc, encoding, tc.encoding)
+
+			continue
+		}
+
+		var body io.Reader = w.Body
+
+		switch tc.encoding {
+		case "gzip":
+			gz, err := gzip.NewReader(w.Body)
+			if err != nil {
+				t.Fatalf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected a gzip body", i, tc.desc, err)
+			}
+
+			body = gz
+		case "br":
+			body = brotli.NewReader(w.Body)
+		}
+
+		b, err := io.ReadAll(body)
+		if err != nil || (tc.encoding != "" && string(b) != long) || w.Header().Get("Vary") != "Accept-Encoding" {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v %v\nExpected the original body varying by encoding", i, tc.desc, len(b), err)
+		}
+	}
+}
+
+func TestCompress_ETag(t *testing.T) {
+	cases := []struct {
+		desc   string
+		accept string
+		body   string
+		etag   string
+	}{
+		{"compressed", "gzip", strings.Repeat("a", 2048), `W/"abc"`},
+		{"sent as it is", "gzip", "a", `"abc"`},
+		{"not accepted", "", strings.Repeat("a", 2048), `"abc"`},
+	}
+
+	for i, tc := range cases {
+		body := tc.body
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("ETag", `"abc"`)
+			_, _ = io.WriteString(w, body)
+		})
+
+		r := httptest.NewRequest(http.MethodGet, "http://cars/car/1", nil)
+		r.Header.Set("Accept-Encoding", tc.accept)
+
+		w := httptest.NewRecorder()
+		Compress(1024)(handler).ServeHTTP(w, r)
+
+		if etag := w.Header().Get("ETag"); etag != tc.etag {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, etag, tc.etag)
+		}
+	}
+}
+
+func TestCompress_Flush(t *testing.T) {
+	flushed := make(chan struct{})
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "retry: 3000\n\n")
+
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("\n[TEST] Failed. Desc : flush\nGot %v\nExpected nil", err)
+		}
+
+		close(flushed)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "http://cars/car/stream", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+
+	w := httptest.NewRecorder()
+	Compress(1024)(handler).ServeHTTP(w, r)
+	<-flushed
+
+	if !w.Flushed || w.Body.String() != "retry: 3000\n\n" {
+		t.Errorf("\n[TEST] Failed. Desc : flush\nGot %v %q\nExpected the held back event flushed uncompressed", w.Flushed, w.Body.String())
+	}
+}
@@ -0,0 +1,223 @@
+package middlewares
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const jwksRefreshInterval = time.Minute
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// KeySet holds the public keys used to verify token signatures.
+// Keys are read from a local JWKS file or fetched from a JWKS URL, URLs are refetched when an unknown key id is seen.
+type KeySet struct {
+	source string
+	client *http.Client
+	group  singleflight.Group
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	keys    map[string]crypto.PublicKey
+	fetched time.Time
+}
+
+// NewKeySet loads the JWKS document from the given file path or http(s) URL
+func NewKeySet(source string, logger *slog.Logger) (*KeySet, error) {
+	ks := &KeySet{source: source, client: &http.Client{Timeout: 5 * time.Second}, logger: logger}
+
+	if err := ks.refresh(); err != nil {
+		return nil, err
+	}
+
+	return ks, nil
+}
+
+// Key returns the public key of the given key id, an empty id matches the only key of the set.
+// The set is refetched without holding the lock, concurrent lookups of unknown ids share one fetch.
+func (ks *KeySet) Key(kid string) (crypto.PublicKey, error) {
+	key, ok, due := ks.lookup(kid)
+	if ok {
+		return key, nil
+	}
+
+	if due {
+		if _, err, _ := ks.group.Do(ks.source, func() (interface{}, error) { return nil, ks.refresh() }); err != nil {
+			return nil, err
+		}
+
+		if key, ok, _ := ks.lookup(kid); ok {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookup finds the key of the id and tells whether the set may be refetched for an unknown one
+func (ks *KeySet) lookup(kid string) (key crypto.PublicKey, ok, due bool) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	due = ks.isURL() && time.Since(ks.fetched) > jwksRefreshInterval
+
+	if kid == "" && len(ks.keys) == 1 {
+		for _, key := range ks.keys {
+			return key, true, due
+		}
+	}
+
+	key, ok = ks.keys[kid]
+
+	return key, ok, due
+}
+
+// refresh fetches the JWKS document and swaps its keys in
+func (ks *KeySet) refresh() error {
+	keys, err := ks.load()
+	if err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	ks.keys = keys
+	ks.fetched = time.Now()
+
+	return nil
+}
+
+// load reads and parses the JWKS document. Keys that cannot verify signatures here, like encryption or EdDSA keys
+// published along with the signing keys, are skipped, the document is only refused when no signing key is left.
+func (ks *KeySet) load() (map[string]crypto.PublicKey, error) {
+	body, err := ks.read()
+	if err != nil {
+		return nil, err
+	}
+
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("invalid jwks document: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(doc.Keys))
+
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			ks.logger.Debug("skipping jwk not used for signatures", "kid", k.Kid, "use", k.Use)
+
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			ks.logger.Debug("skipping unusable jwk", "kid", k.Kid, "error", err)
+
+			continue
+		}
+
+		keys[k.Kid] = key
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("jwks document has none of %d keys usable for signatures", len(doc.Keys))
+	}
+
+	return keys, nil
+}
+
+func (ks *KeySet) read() ([]byte, error) {
+	if !ks.isURL() {
+		return os.ReadFile(ks.source)
+	}
+
+	resp, err := ks.client.Get(ks.source)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching jwks returned status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+func (ks *KeySet) isURL() bool {
+	return strings.HasPrefix(ks.source, "http://") || strings.HasPrefix(ks.source, "https://")
+}
+
+// publicKey decodes the RSA or P-256 EC public key described by the jwk
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,110 @@
+package middlewares
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/models"
+)
+
+const bearerPrefix = "Bearer "
+
+// JWTConfig describes the tokens accepted by the JWT authenticator
+type JWTConfig struct {
+	Issuer   string
+	Audience string
+	Keys     *KeySet
+}
+
+type claims struct {
+	jwt.RegisteredClaims
+	Name              string   `json:"name"`
+	PreferredUsername string   `json:"preferred_username"`
+	Email             string   `json:"email"`
+	Scope             string   `json:"scope"`
+	Scp               []string `json:"scp"`
+	Roles             []string `json:"roles"`
+}
+
+type jwtAuthenticator struct {
+	config JWTConfig
+	parser *jwt.Parser
+}
+
+// JWTAuthenticator authenticates RS256 and ES256 signed bearer tokens issued by the configured OIDC provider
+func JWTAuthenticator(config JWTConfig) Authenticator {
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg()}),
+		jwt.WithIssuer(config.Issuer),
+		jwt.WithAudience(config.Audience),
+		jwt.WithExpirationRequired(),
+	)
+
+	return jwtAuthenticator{config: config, parser: parser}
+}
+
+func (a jwtAuthenticator) Authenticate(r *http.Request) (*models.Principal, error) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, bearerPrefix) {
+		return nil, nil
+	}
+
+	var c claims
+
+	_, err := a.parser.ParseWithClaims(strings.TrimPrefix(header, bearerPrefix), &c, a.key)
+	if err != nil {
+		return nil, errors.Unauthenticated{Reason: err.Error()}
+	}
+
+	if c.Subject == "" {
+		return nil, errors.Unauthenticated{Reason: "token has no subject"}
+	}
+
+	return c.principal(), nil
+}
+
+// key returns the verification key of the token, the key type must match the signing algorithm
+func (a jwtAuthenticator) key(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, err := a.config.Keys.Key(kid)
+	if err != nil {
+		return nil, err
+	}
+
+	switch token.Method.(type) {
+	case *jwt.SigningMethodRSA:
+		if k, ok := key.(*rsa.PublicKey); ok {
+			return k, nil
+		}
+	case *jwt.SigningMethodECDSA:
+		if k, ok := key.(*ecdsa.PublicKey); ok {
+			return k, nil
+		}
+	}
+
+	return nil, fmt.Errorf("key %q cannot verify %s tokens", kid, token.Method.Alg())
+}
+
+// principal maps the token claims to the principal of the request
+func (c claims) principal() *models.Principal {
+	name := c.Name
+
+	for _, n := range []string{c.PreferredUsername, c.Email, c.Subject} {
+		if name != "" {
+			break
+		}
+
+		name = n
+	}
+
+	scopes := append(strings.Fields(c.Scope), c.Scp...)
+
+	return &models.Principal{ID: c.Subject, Name: name, Scopes: scopes, Roles: c.Roles}
+}
@@ -0,0 +1,292 @@
+package middlewares
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/amehrotra/car-dealership/authz"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+)
+
+const (
+	issuer   = "https://idp.local"
+	audience = "car-dealership"
+)
+
+func encode(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+// initializeTest generates an RSA and an EC key and publishes them in a local JWKS file
+func initializeTest(t *testing.T) (Authenticator, *rsa.PrivateKey, *ecdsa.PrivateKey) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error in generating rsa key : %v", err)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error in generating ec key : %v", err)
+	}
+
+	doc, err := json.Marshal(map[string][]jwk{"keys": {
+		{Kty: "RSA", Kid: "rsa", N: encode(rsaKey.N), E: encode(big.NewInt(int64(rsaKey.E)))},
+		{Kty: "EC", Kid: "ec", Crv: "P-256", X: encode(ecKey.X), Y: encode(ecKey.Y)},
+	}})
+	if err != nil {
+		t.Fatalf("error in marshaling jwks : %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, doc, 0o600); err != nil {
+		t.Fatalf("error in writing jwks : %v", err)
+	}
+
+	keys, err := NewKeySet(path, logging.Discard())
+	if err != nil {
+		t.Fatalf("error in loading jwks : %v", err)
+	}
+
+	return JWTAuthenticator(JWTConfig{Issuer: issuer, Audience: audience, Keys: keys}), rsaKey, ecKey
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key crypto.PrivateKey, c jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, c)
+	token.Header["kid"] = kid
+
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("error in signing token : %v", err)
+	}
+
+	return s
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	a, rsaKey, ecKey := initializeTest(t)
+
+	exp := time.Now().Add(time.Hour).Unix()
+	valid := jwt.MapClaims{"iss": issuer, "aud": audience, "sub": "42", "exp": exp, "email": "a@b.c", "scope": "admin read",
+		"roles": []string{"viewer"}}
+	expired := jwt.MapClaims{"iss": issuer, "aud": audience, "sub": "42", "exp": time.Now().Add(-time.Hour).Unix()}
+	wrongIssuer := jwt.MapClaims{"iss": "https://other", "aud": audience, "sub": "42", "exp": exp}
+	wrongAudience := jwt.MapClaims{"iss": issuer, "aud": "other", "sub": "42", "exp": exp}
+	principal := &models.Principal{ID: "42", Name: "a@b.c", Scopes: []string{"admin", "read"}, Roles: []string{"viewer"}}
+
+	cases := []struct {
+		desc      string
+		header    string
+		principal *models.Principal
+		fails     bool
+	}{
+		{"no bearer token", "", nil, false},
+		{"rs256 token", "Bearer " + sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, valid), principal, false},
+		{"es256 token", "Bearer " + sign(t, jwt.SigningMethodES256, "ec", ecKey, valid), principal, false},
+		{"expired token", "Bearer " + sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, expired), nil, true},
+		{"wrong issuer", "Bearer " + sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, wrongIssuer), nil, true},
+		{"wrong audience", "Bearer " + sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, wrongAudience), nil, true},
+		{"unknown key", "Bearer " + sign(t, jwt.SigningMethodRS256, "other", rsaKey, valid), nil, true},
+		{"key type mismatch", "Bearer " + sign(t, jwt.SigningMethodES256, "rsa", ecKey, valid), nil, true},
+		{"hmac token", "Bearer " + sign(t, jwt.SigningMethodHS256, "rsa", []byte("secret"), valid), nil, true},
+		{"malformed token", "Bearer abc", nil, true},
+	}
+
+	for i, tc := range cases {
+		r := httptest.NewRequest(http.MethodGet, "http://cars", nil)
+		r.Header.Set("Authorization", tc.header)
+
+		output, err := a.Authenticate(r)
+
+		if _, ok := err.(errors.Unauthenticated); ok != tc.fails {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected failure %v", i, tc.desc, err, tc.fails)
+		}
+
+		if !reflect.DeepEqual(output, tc.principal) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, output, tc.principal)
+		}
+	}
+}
+
+type authenticatorFunc func(r *http.Request) (*models.Principal, error)
+
+func (f authenticatorFunc) Authenticate(r *http.Request) (*models.Principal, error) {
+	return f(r)
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	skip := authenticatorFunc(func(*http.Request) (*models.Principal, error) { return nil, nil })
+	accept := authenticatorFunc(func(*http.Request) (*models.Principal, error) { return &models.Principal{ID: "1"}, nil })
+	reject := authenticatorFunc(func(*http.Request) (*models.Principal, error) { return nil, errors.Unauthenticated{} })
+	fail := authenticatorFunc(func(*http.Request) (*models.Principal, error) { return nil, errors.DB{} })
+
+	cases := []struct {
+		desc           string
+		authenticators []Authenticator
+		statusCode     int
+	}{
+		{"second authenticator accepts", []Authenticator{skip, accept}, http.StatusOK},
+		{"no credentials", []Authenticator{skip, skip}, http.StatusUnauthorized},
+		{"invalid credentials", []Authenticator{reject, accept}, http.StatusUnauthorized},
+		{"authenticator error", []Authenticator{fail}, http.StatusInternalServerError},
+	}
+
+	for i, tc := range cases {
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := authz.PrincipalFromContext(r.Context()); !ok {
+				t.Errorf("\n[TEST %d] Failed. Desc : %v\nprincipal missing from context", i, tc.desc)
+			}
+		})
+
+		w := httptest.NewRecorder()
+
+		AuthMiddleware(logging.Discard(), tc.authenticators...)(next).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://cars", nil))
+
+		if w.Code != tc.statusCode {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, w.Code, tc.statusCode)
+		}
+	}
+}
+
+func TestKeySet_Refetch(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error in generating rsa key : %v", err)
+	}
+
+	var (
+		mu      sync.Mutex
+		fetches int
+	)
+
+	release := make(chan struct{})
+	rotated := jwk{Kty: "RSA", Kid: "rotated", N: encode(rsaKey.N), E: encode(big.NewInt(int64(rsaKey.E)))}
+
+	// the first fetch only has the old key, the refetch has the rotated one too and is held until released
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fetches++
+		n := fetches
+		mu.Unlock()
+
+		keys := []jwk{{Kty: "RSA", Kid: "rsa", N: rotated.N, E: rotated.E}}
+		if n > 1 {
+			<-release
+
+			keys = append(keys, rotated)
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string][]jwk{"keys": keys})
+	}))
+	defer server.Close()
+
+	ks, err := NewKeySet(server.URL, logging.Discard())
+	if err != nil {
+		t.Fatalf("error in loading jwks : %v", err)
+	}
+
+	ks.fetched = ks.fetched.Add(-2 * jwksRefreshInterval)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if _, err := ks.Key("rotated"); err != nil {
+				t.Errorf("\n[TEST] Failed. Desc : rotated key\nGot %v\nExpected nil", err)
+			}
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	// known keys are served while the set is being refetched
+	if _, err := ks.Key("rsa"); err != nil {
+		t.Errorf("\n[TEST] Failed. Desc : known key during refetch\nGot %v\nExpected nil", err)
+	}
+
+	close(release)
+	wg.Wait()
+
+	if fetches != 2 {
+		t.Errorf("\n[TEST] Failed. Desc : shared refetch\nGot %v fetches\nExpected 2", fetches)
+	}
+}
+
+func TestKeySet_MixedKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error in generating rsa key : %v", err)
+	}
+
+	n, e := encode(rsaKey.N), encode(big.NewInt(int64(rsaKey.E)))
+	eddsa := jwk{Kty: "OKP", Kid: "eddsa", Crv: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}
+
+	cases := []struct {
+		desc   string
+		keys   []jwk
+		usable []string
+		fails  bool
+	}{
+		{"signing keys among others", []jwk{
+			{Kty: "RSA", Kid: "rsa", Use: "sig", N: n, E: e},
+			{Kty: "RSA", Kid: "enc", Use: "enc", N: n, E: e},
+			{Kty: "EC", Kid: "p384", Crv: "P-384", X: "AA", Y: "AA"},
+			eddsa,
+		}, []string{"rsa"}, false},
+		{"no signing key", []jwk{eddsa, {Kty: "RSA", Kid: "enc", Use: "enc", N: n, E: e}}, nil, true},
+	}
+
+	for i, tc := range cases {
+		doc, err := json.Marshal(map[string][]jwk{"keys": tc.keys})
+		if err != nil {
+			t.Fatalf("error in marshaling jwks : %v", err)
+		}
+
+		path := filepath.Join(t.TempDir(), "jwks.json")
+		if err := os.WriteFile(path, doc, 0o600); err != nil {
+			t.Fatalf("error in writing jwks : %v", err)
+		}
+
+		ks, err := NewKeySet(path, logging.Discard())
+		if (err != nil) != tc.fails {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected failure %v", i, tc.desc, err, tc.fails)
+
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if len(ks.keys) != len(tc.usable) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v keys\nExpected %v", i, tc.desc, len(ks.keys), tc.usable)
+		}
+
+		for _, kid := range tc.usable {
+			if _, err := ks.Key(kid); err != nil {
+				t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected key %v", i, tc.desc, err, kid)
+			}
+		}
+	}
+}
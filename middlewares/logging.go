@@ -0,0 +1,75 @@
+package middlewares
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/logging"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the client supplied ids copied into the logs
+const maxRequestIDLength = 128
+
+// RequestID reuses the X-Request-ID of the client or generates one, echoes it in the response
+// and places it in the request context so that every log line and error body carries it
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = uuid.NewString()
+		}
+
+		w.Header().Set(requestIDHeader, id)
+
+		next.ServeHTTP(w, r.WithContext(logging.WithRequest(r.Context(), &logging.Request{ID: id})))
+	})
+}
+
+// AccessLog logs a line for every request once it is served
+func AccessLog(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(rec, r)
+
+			logger.InfoContext(r.Context(), "request served",
+				"method", r.Method,
+				"route", routeTemplate(r),
+				"status", rec.status,
+				"latency_ms", time.Since(start).Milliseconds(),
+				"bytes", rec.bytes,
+			)
+		})
+	}
+}
+
+// recorder captures the status code and size of the response
+type recorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (rec *recorder) WriteHeader(statusCode int) {
+	rec.status = statusCode
+	rec.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rec *recorder) Write(b []byte) (int, error) {
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += n
+
+	return n, err
+}
+
+// Unwrap exposes the wrapped writer to http.ResponseController, so that streaming handlers can flush through the recorder
+func (rec *recorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
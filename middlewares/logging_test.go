@@ -0,0 +1,56 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+)
+
+func TestRequestIDAndAccessLog(t *testing.T) {
+	var b bytes.Buffer
+
+	logger := logging.New(&b, slog.LevelInfo)
+	reject := authenticatorFunc(func(*http.Request) (*models.Principal, error) { return nil, nil })
+	handler := RequestID(AccessLog(logger)(AuthMiddleware(logger, reject)(http.NotFoundHandler())))
+
+	cases := []struct {
+		desc      string
+		requestID string
+		generated bool
+	}{
+		{"client request id", "abc-123", false},
+		{"generated request id", "", true},
+	}
+
+	for i, tc := range cases {
+		b.Reset()
+
+		r := httptest.NewRequest(http.MethodGet, "http://cars/car", nil)
+		r.Header.Set(requestIDHeader, tc.requestID)
+
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, r)
+
+		id := w.Header().Get(requestIDHeader)
+		if id == "" || (!tc.generated && id != tc.requestID) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %q\nExpected %q", i, tc.desc, id, tc.requestID)
+		}
+
+		var body models.Error
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.RequestID != id || body.Code != "unauthenticated" {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot body %s\nExpected error body with request id %q", i, tc.desc, w.Body.String(), id)
+		}
+
+		var line map[string]interface{}
+		if err := json.Unmarshal(b.Bytes(), &line); err != nil || line["request_id"] != id || line["status"] != float64(http.StatusUnauthorized) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot log %s\nExpected access log with request id %q", i, tc.desc, b.String(), id)
+		}
+	}
+}
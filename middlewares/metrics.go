@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"net/http"
+	"time"
+)
+
+// RequestObserver records the outcome of served requests
+type RequestObserver interface {
+	ObserveRequest(route, method string, status int, d time.Duration)
+}
+
+// Metrics reports every request served to the observer, keyed by its route template to bound the label cardinality
+func Metrics(observer RequestObserver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(rec, r)
+
+			observer.ObserveRequest(routeTemplate(r), r.Method, rec.status, time.Since(start))
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type observation struct {
+	route  string
+	method string
+	status int
+}
+
+type observerFunc func(route, method string, status int, d time.Duration)
+
+func (f observerFunc) ObserveRequest(route, method string, status int, d time.Duration) {
+	f(route, method, status, d)
+}
+
+func TestMetrics(t *testing.T) {
+	var got []observation
+
+	observer := observerFunc(func(route, method string, status int, _ time.Duration) {
+		got = append(got, observation{route: route, method: method, status: status})
+	})
+
+	r := mux.NewRouter()
+	r.HandleFunc("/car/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}).Methods(http.MethodGet)
+	r.HandleFunc("/car", func(w http.ResponseWriter, r *http.Request) {}).Methods(http.MethodGet)
+	r.Use(Metrics(observer))
+
+	for _, target := range []string{"http://cars/car/1", "http://cars/car/2", "http://cars/car"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	expected := []observation{
+		{"/car/{id}", http.MethodGet, http.StatusNotFound},
+		{"/car/{id}", http.MethodGet, http.StatusNotFound},
+		{"/car", http.MethodGet, http.StatusOK},
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("\n[TEST] Failed. Desc : observations\nGot %v\nExpected %v", got, expected)
+	}
+}
@@ -0,0 +1,120 @@
+package middlewares
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/amehrotra/car-dealership/authz"
+)
+
+// Limit is a token bucket refilled with Rate tokens per second and holding at most Burst tokens
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitResult is the outcome of taking a token from a bucket
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// RateLimitStore keeps the token buckets, implementations backed by a shared store let several instances share limits
+type RateLimitStore interface {
+	Take(key string, limit Limit) (RateLimitResult, error)
+}
+
+// KeyFunc returns the bucket key of a request
+type KeyFunc func(r *http.Request) string
+
+// ByIP keys requests by the address of the client
+func ByIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return "ip:" + host
+}
+
+// ByPrincipal keys requests by the authenticated principal, falling back to the client address
+func ByPrincipal(r *http.Request) string {
+	if principal, ok := authz.PrincipalFromContext(r.Context()); ok {
+		return "principal:" + principal.ID
+	}
+
+	return ByIP(r)
+}
+
+// RateLimit rejects requests with 429 once the bucket of their key is empty.
+// The name separates the buckets of differently limited routes.
+func RateLimit(logger *slog.Logger, store RateLimitStore, name string, limit Limit, key KeyFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			res, err := store.Take(name+"|"+key(r), limit)
+			if err != nil {
+				// an unavailable limiter should not take the api down with it
+				logger.ErrorContext(r.Context(), "error in rate limiting request", "error", err)
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(seconds(res.Reset)))
+
+			if !res.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(seconds(res.RetryAfter)))
+				writeError(w, r, logger, http.StatusTooManyRequests, "rate_limited",
+					fmt.Errorf("rate limit exceeded, retry in %d seconds", seconds(res.RetryAfter)))
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RouteRateLimit limits every route per principal, with the limit configured for "METHOD /path/template"
+// or the default limit for routes without one
+func RouteRateLimit(logger *slog.Logger, store RateLimitStore, limits map[string]Limit, def Limit) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			name := r.Method + " " + routeTemplate(r)
+
+			limit, ok := limits[name]
+			if !ok {
+				limit = def
+			}
+
+			RateLimit(logger, store, name, limit, ByPrincipal)(next).ServeHTTP(w, r)
+		})
+	}
+}
+
+// routeTemplate returns the path template of the matched route, or the path when no route matched
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if tmpl, err := route.GetPathTemplate(); err == nil {
+			return tmpl
+		}
+	}
+
+	return r.URL.Path
+}
+
+// seconds rounds the duration up to whole seconds
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,78 @@
+package middlewares
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	limit  Limit
+}
+
+type memoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+	now     func() time.Time
+}
+
+// NewMemoryStore returns a RateLimitStore keeping the buckets in process
+func NewMemoryStore() RateLimitStore {
+	return &memoryStore{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+func (m *memoryStore) Take(key string, limit Limit) (RateLimitResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now, limit: limit}
+		m.buckets[key] = b
+	}
+
+	// refill the tokens earned since the last request
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+
+	res := RateLimitResult{}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = duration((1 - b.tokens) / limit.Rate)
+	}
+
+	res.Remaining = int(b.tokens)
+	res.Reset = duration((float64(limit.Burst) - b.tokens) / limit.Rate)
+
+	return res, nil
+}
+
+// sweep drops the buckets that have been idle long enough to be full again
+func (m *memoryStore) sweep(now time.Time) {
+	if now.Sub(m.swept) < sweepInterval {
+		return
+	}
+
+	m.swept = now
+
+	for key, b := range m.buckets {
+		if now.Sub(b.last) > duration(float64(b.limit.Burst)/b.limit.Rate) {
+			delete(m.buckets, key)
+		}
+	}
+}
+
+func duration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,97 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amehrotra/car-dealership/authz"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+)
+
+func TestMemoryStore_Take(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := &memoryStore{buckets: make(map[string]*bucket), now: func() time.Time { return now }}
+	limit := Limit{Rate: 1, Burst: 2}
+
+	cases := []struct {
+		desc    string
+		advance time.Duration
+		key     string
+		output  RateLimitResult
+	}{
+		{"first token", 0, "a", RateLimitResult{Allowed: true, Remaining: 1, Reset: time.Second}},
+		{"last token", 0, "a", RateLimitResult{Allowed: true, Remaining: 0, Reset: 2 * time.Second}},
+		{"bucket empty", 0, "a", RateLimitResult{Allowed: false, Remaining: 0, Reset: 2 * time.Second, RetryAfter: time.Second}},
+		{"other key", 0, "b", RateLimitResult{Allowed: true, Remaining: 1, Reset: time.Second}},
+		{"refilled", 1500 * time.Millisecond, "a", RateLimitResult{Allowed: true, Remaining: 0, Reset: 1500 * time.Millisecond}},
+		{"idle bucket swept", 2 * time.Minute, "a", RateLimitResult{Allowed: true, Remaining: 1, Reset: time.Second}},
+	}
+
+	for i, tc := range cases {
+		now = now.Add(tc.advance)
+
+		output, err := store.Take(tc.key, limit)
+
+		if err != nil || output != tc.output {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %+v %v\nExpected %+v", i, tc.desc, output, err, tc.output)
+		}
+	}
+
+	if len(store.buckets) != 1 {
+		t.Errorf("\n[TEST] Failed. Desc : sweep\nGot %v buckets\nExpected 1", len(store.buckets))
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(string, Limit) (RateLimitResult, error) {
+	return RateLimitResult{}, errors.DB{}
+}
+
+func TestRateLimit(t *testing.T) {
+	store := NewMemoryStore()
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	limited := RateLimit(logging.Discard(), store, "test", Limit{Rate: 0.5, Burst: 1}, ByPrincipal)(next)
+
+	principal := authz.WithPrincipal(context.Background(), &models.Principal{ID: "1"})
+
+	cases := []struct {
+		desc       string
+		ctx        context.Context
+		statusCode int
+		retryAfter string
+	}{
+		{"principal allowed", principal, http.StatusOK, ""},
+		{"principal limited", principal, http.StatusTooManyRequests, "2"},
+		{"anonymous keyed by address", context.Background(), http.StatusOK, ""},
+	}
+
+	for i, tc := range cases {
+		w := httptest.NewRecorder()
+
+		limited.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://cars", nil).WithContext(tc.ctx))
+
+		if w.Code != tc.statusCode || w.Header().Get("Retry-After") != tc.retryAfter {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v %q\nExpected %v %q", i, tc.desc, w.Code, w.Header().Get("Retry-After"),
+				tc.statusCode, tc.retryAfter)
+		}
+
+		if w.Header().Get("RateLimit-Limit") != "1" {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot RateLimit-Limit %q\nExpected 1", i, tc.desc, w.Header().Get("RateLimit-Limit"))
+		}
+	}
+
+	w := httptest.NewRecorder()
+
+	unlimited := RateLimit(logging.Discard(), failingStore{}, "test", Limit{Rate: 1, Burst: 1}, ByIP)(next)
+	unlimited.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://cars", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("\n[TEST] Failed. Desc : store error\nGot %v\nExpected %v", w.Code, http.StatusOK)
+	}
+}
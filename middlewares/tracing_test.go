@@ -0,0 +1,59 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	var inner trace.SpanContext
+
+	r := mux.NewRouter()
+	r.HandleFunc("/car/{id}", func(w http.ResponseWriter, r *http.Request) {
+		inner = trace.SpanContextFromContext(r.Context())
+
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	r.Use(Tracing(tp, propagation.TraceContext{}))
+
+	req := httptest.NewRequest(http.MethodGet, "http://cars/car/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("\n[TEST] Failed. Desc : spans\nGot %v\nExpected 1", len(spans))
+	}
+
+	span := spans[0]
+
+	cases := []struct {
+		desc     string
+		output   interface{}
+		expected interface{}
+	}{
+		{"span name", span.Name(), "GET /car/{id}"},
+		{"trace id propagated", span.SpanContext().TraceID().String(), "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"remote parent", span.Parent().SpanID().String(), "00f067aa0ba902b7"},
+		{"span in handler context", inner.SpanID(), span.SpanContext().SpanID()},
+		{"server error status", span.Status().Code, codes.Error},
+	}
+
+	for i, tc := range cases {
+		if tc.output != tc.expected {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, tc.output, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package middlewares
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/amehrotra/car-dealership/errors"
+)
+
+// maxBodyBytes bounds the request bodies read into memory for validation
+const maxBodyBytes = 1 << 20
+
+// RequestValidator checks request bodies against the schema of the operation of their route
+type RequestValidator interface {
+	Validates(route, method string) bool
+	ValidateRequest(route, method string, body []byte) error
+}
+
+// ValidateBody rejects requests whose json body does not match the schema of their route with 400,
+// the body is handed on to the handler untouched
+func ValidateBody(logger *slog.Logger, validator RequestValidator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := routeTemplate(r)
+			if !validator.Validates(route, r.Method) {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+			if err != nil {
+				writeError(w, r, logger, http.StatusBadRequest, "invalid_parameter", errors.InvalidParam{Param: []string{"body"}})
+
+				return
+			}
+
+			if err := validator.ValidateRequest(route, r.Method, body); err != nil {
+				writeError(w, r, logger, http.StatusBadRequest, "invalid_parameter", err)
+
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package middlewares
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/logging"
+)
+
+type validatorFunc func(route, method string, body []byte) error
+
+func (f validatorFunc) Validates(route, method string) bool {
+	return method == http.MethodPost
+}
+
+func (f validatorFunc) ValidateRequest(route, method string, body []byte) error {
+	return f(route, method, body)
+}
+
+func TestValidateBody(t *testing.T) {
+	validator := validatorFunc(func(route, method string, body []byte) error {
+		if route != "/car" || string(body) != `{"model":"X"}` {
+			return errors.InvalidParam{Param: []string{"model"}}
+		}
+
+		return nil
+	})
+
+	var received string
+
+	r := mux.NewRouter()
+	r.HandleFunc("/car", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+	})
+	r.Use(ValidateBody(logging.Discard(), validator))
+
+	cases := []struct {
+		desc       string
+		method     string
+		body       string
+		statusCode int
+		received   string
+	}{
+		{"valid body is handed on", http.MethodPost, `{"model":"X"}`, http.StatusOK, `{"model":"X"}`},
+		{"invalid body", http.MethodPost, `{"model":""}`, http.StatusBadRequest, ""},
+		{"route without schema", http.MethodGet, `ignored`, http.StatusOK, "ignored"},
+	}
+
+	for i, tc := range cases {
+		received = ""
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, httptest.NewRequest(tc.method, "http://cars/car", strings.NewReader(tc.body)))
+
+		if w.Code != tc.statusCode || received != tc.received {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v %q\nExpected %v %q", i, tc.desc, w.Code, received, tc.statusCode, tc.received)
+		}
+	}
+}
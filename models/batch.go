@@ -0,0 +1,52 @@
+package models
+
+import "github.com/google/uuid"
+
+// Operations of a batch
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// BatchOperation creates Car, replaces the car of ID with Car or deletes the car of ID
+type BatchOperation struct {
+	Op  string    `json:"op"`
+	ID  uuid.UUID `json:"id"`
+	Car *Car      `json:"car,omitempty"`
+}
+
+// BatchRequest is the body of a batch
+type BatchRequest struct {
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchOptions select how a batch is carried out
+type BatchOptions struct {
+	// BestEffort applies every operation on its own, by default the batch is applied in one transaction or not at all
+	BestEffort bool
+}
+
+// BatchResult is the outcome of the operation at the same index, Err is nil when it was applied
+type BatchResult struct {
+	Op  string
+	Car *Car
+	Err error
+}
+
+// BatchResponse is the multi-status body answering a batch
+type BatchResponse struct {
+	Atomic    bool        `json:"atomic"`
+	Succeeded int         `json:"succeeded"`
+	Failed    int         `json:"failed"`
+	Results   []BatchItem `json:"results"`
+}
+
+// BatchItem is the response the operation at Index would have got as a request of its own
+type BatchItem struct {
+	Index  int    `json:"index"`
+	Op     string `json:"op"`
+	Status int    `json:"status"`
+	Car    *Car   `json:"car,omitempty"`
+	Error  *Error `json:"error,omitempty"`
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 
 	"github.com/amehrotra/car-dealership/types"
@@ -12,5 +14,23 @@ type Car struct {
 	ManufactureYear int        `json:"yearOfManufacture"`
 	Brand           string     `json:"brand"`
 	FuelType        types.Fuel `json:"fuelType"`
+	Price           int64      `json:"price"`
+	Condition       string     `json:"condition"`
+	Mileage         int        `json:"mileage"`
 	Engine          Engine     `json:"engine"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+	Reservation     *Hold      `json:"reservation,omitempty"`
+}
+
+// conditions of a car, cars taken in on trade-in are used
+const (
+	CarNew  = "new"
+	CarUsed = "used"
+)
+
+// Stock is the number of cars of a brand and fuel type
+type Stock struct {
+	Brand    string
+	FuelType types.Fuel
+	Count    int
 }
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Customer is a buyer of the dealership with the contact details and consents they gave
+type Customer struct {
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email"`
+	Phone         string    `json:"phone"`
+	Address       Address   `json:"address"`
+	LicenceNumber string    `json:"drivingLicenceNumber"`
+	Consent       Consent   `json:"consent"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// Address is the postal address of a customer
+type Address struct {
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2"`
+	City       string `json:"city"`
+	PostalCode string `json:"postalCode"`
+	Country    string `json:"country"`
+}
+
+// Consent records what the customer agreed their details may be used for
+type Consent struct {
+	Marketing   bool `json:"marketing"`
+	DataSharing bool `json:"dataSharing"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// Error is the body written for failed requests
+type Error struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"requestId,omitempty"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Types of the events about the inventory
+const (
+	CarCreated = "car.created"
+	CarUpdated = "car.updated"
+	CarDeleted = "car.deleted"
+)
+
+// StreamReset tells a subscriber that it may have missed events and has to fetch the cars again
+const StreamReset = "reset"
+
+// EventTypes lists every event type
+func EventTypes() []string {
+	return []string{CarCreated, CarUpdated, CarDeleted}
+}
+
+// Event is a change to the inventory, Car is the car after the change and only carries the id of a deleted car
+type Event struct {
+	ID         uuid.UUID `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurredAt"`
+	Car        *Car      `json:"car"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "github.com/google/uuid"
+
+// ImportRow is a row of an import file, Err tells why the row could not be read into Car
+type ImportRow struct {
+	Row int
+	Car Car
+	Err error
+}
+
+// ImportOptions select how an import is carried out
+type ImportOptions struct {
+	// DryRun validates the rows without inserting any
+	DryRun bool
+	// BestEffort inserts the valid rows even when others fail, by default nothing is inserted unless every row is valid
+	BestEffort bool
+}
+
+// ImportResult reports the outcome of an import, rows are numbered from 1 without the header
+type ImportResult struct {
+	Total    int         `json:"total"`
+	Imported int         `json:"imported"`
+	Failed   int         `json:"failed"`
+	DryRun   bool        `json:"dryRun"`
+	IDs      []uuid.UUID `json:"ids,omitempty"`
+	Errors   []RowError  `json:"errors,omitempty"`
+}
+
+// RowError is why a row was not imported, Code is the code of the error response the row would have got on its own
+type RowError struct {
+	Row     int    `json:"row"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Message is an event as it is published, ID stays the same when the message is published again so that consumers
+// can drop duplicates and the messages of a Key are published in the order they were written
+type Message struct {
+	ID         uuid.UUID       `json:"id"`
+	Key        string          `json:"key"`
+	Type       string          `json:"type"`
+	Payload    json.RawMessage `json:"payload"`
+	OccurredAt time.Time       `json:"occurredAt"`
+}
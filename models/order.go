@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// statuses of an order, delivered and cancelled orders no longer change
+const (
+	OrderDraft     = "draft"
+	OrderConfirmed = "confirmed"
+	OrderPaid      = "paid"
+	OrderDelivered = "delivered"
+	OrderCancelled = "cancelled"
+)
+
+// Order is the sale of a car to a customer, amounts are in the smallest currency unit like the prices of the cars
+type Order struct {
+	ID            uuid.UUID `json:"id"`
+	CarID         uuid.UUID `json:"carId"`
+	CustomerID    uuid.UUID `json:"customerId"`
+	Price         int64     `json:"price"`
+	Taxes         int64     `json:"taxes"`
+	Fees          int64     `json:"fees"`
+	TradeInCredit int64     `json:"tradeInCredit"`
+	Deposit       int64     `json:"deposit"`
+	Total         int64     `json:"total"`
+	Status        string    `json:"status"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,8 @@
+package models
+
+type Principal struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	Roles  []string `json:"roles"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// statuses of a reservation, only active ones hold their car
+const (
+	ReservationActive    = "active"
+	ReservationCancelled = "cancelled"
+	ReservationExpired   = "expired"
+	ReservationConverted = "converted"
+)
+
+// Reservation holds a car for a customer until it expires, the deposit is in the smallest currency unit
+type Reservation struct {
+	ID         uuid.UUID `json:"id"`
+	CarID      uuid.UUID `json:"carId"`
+	CustomerID uuid.UUID `json:"customerId"`
+	Deposit    int64     `json:"deposit"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// Hold is the reservation holding a car as it is shown with the car, the customer and the deposit are left out for
+// those who may not read customers
+type Hold struct {
+	Status     string     `json:"status"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	CustomerID *uuid.UUID `json:"customerId,omitempty"`
+	Deposit    *int64     `json:"deposit,omitempty"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// statuses of a test drive, only scheduled ones take up their car and salesperson
+const (
+	TestDriveScheduled = "scheduled"
+	TestDriveCompleted = "completed"
+	TestDriveCancelled = "cancelled"
+	TestDriveNoShow    = "no_show"
+)
+
+// TestDrive books a car and a salesperson for a customer from StartsAt to EndsAt, the salesperson is the name of their
+// api key or token
+type TestDrive struct {
+	ID          uuid.UUID `json:"id"`
+	CarID       uuid.UUID `json:"carId"`
+	CustomerID  uuid.UUID `json:"customerId"`
+	Salesperson string    `json:"salesperson"`
+	StartsAt    time.Time `json:"startsAt"`
+	EndsAt      time.Time `json:"endsAt"`
+	Status      string    `json:"status"`
+	Notes       string    `json:"notes"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/types"
+)
+
+// statuses of a trade-in, rejected and converted ones no longer change
+const (
+	TradeInSubmitted = "submitted"
+	TradeInAppraised = "appraised"
+	TradeInApproved  = "approved"
+	TradeInRejected  = "rejected"
+	TradeInConverted = "converted"
+)
+
+// TradeIn is a vehicle a customer trades in, CarID is the used car it became in the inventory once converted
+type TradeIn struct {
+	ID              uuid.UUID  `json:"id"`
+	CustomerID      uuid.UUID  `json:"customerId"`
+	Brand           string     `json:"brand"`
+	Model           string     `json:"model"`
+	ManufactureYear int        `json:"yearOfManufacture"`
+	FuelType        types.Fuel `json:"fuelType"`
+	Mileage         int        `json:"mileage"`
+	Checklist       Checklist  `json:"checklist"`
+	Photos          []string   `json:"photos"`
+	Appraisal       *Appraisal `json:"appraisal,omitempty"`
+	ApprovedBy      string     `json:"approvedBy,omitempty"`
+	ApprovedAt      *time.Time `json:"approvedAt,omitempty"`
+	CarID           *uuid.UUID `json:"carId,omitempty"`
+	Status          string     `json:"status"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+}
+
+// Checklist records the condition of a trade-in, a check is true when it passed
+type Checklist struct {
+	Bodywork       bool `json:"bodywork"`
+	Interior       bool `json:"interior"`
+	Tyres          bool `json:"tyres"`
+	Brakes         bool `json:"brakes"`
+	Engine         bool `json:"engine"`
+	ServiceHistory bool `json:"serviceHistory"`
+}
+
+// Appraisal is the value offered for a trade-in until it expires, in the smallest currency unit
+type Appraisal struct {
+	Value       int64     `json:"value"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	AppraisedBy string    `json:"appraisedBy"`
+	AppraisedAt time.Time `json:"appraisedAt"`
+}
@@ -0,0 +1,116 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+//go:embed openapi.json
+var source []byte
+
+// methods are the keys of a path item that hold operations
+// nolint:gochecknoglobals // read only lookup table
+var methods = map[string]string{
+	"get": http.MethodGet, "put": http.MethodPut, "post": http.MethodPost, "delete": http.MethodDelete,
+	"patch": http.MethodPatch, "head": http.MethodHead, "options": http.MethodOptions,
+}
+
+// Spec is the OpenAPI document of the server, decoded as far as routing and validating requests needs
+type Spec struct {
+	operations map[string]operation
+	schemas    map[string]*Schema
+}
+
+type operation struct {
+	RequestBody *struct {
+		Required bool `json:"required"`
+		Content  map[string]struct {
+			Schema *Schema `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+}
+
+type document struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// Load decodes the document embedded in the binary
+func Load() (*Spec, error) {
+	var doc document
+
+	if err := json.Unmarshal(source, &doc); err != nil {
+		return nil, err
+	}
+
+	spec := &Spec{operations: make(map[string]operation), schemas: doc.Components.Schemas}
+
+	for path, item := range doc.Paths {
+		for key, raw := range item {
+			method, ok := methods[key]
+			if !ok {
+				continue
+			}
+
+			var op operation
+
+			if err := json.Unmarshal(raw, &op); err != nil {
+				return nil, err
+			}
+
+			spec.operations[method+" "+path] = op
+		}
+	}
+
+	return spec, nil
+}
+
+// Routes lists the operations of the document as "METHOD /path/{param}"
+func (s *Spec) Routes() []string {
+	routes := make([]string, 0, len(s.operations))
+	for route := range s.operations {
+		routes = append(routes, route)
+	}
+
+	sort.Strings(routes)
+
+	return routes
+}
+
+// Schema returns the component schema of the name, nil when it is not defined
+func (s *Spec) Schema(name string) *Schema {
+	return s.schemas[name]
+}
+
+// Handler serves the document
+func (s *Spec) Handler(logger *slog.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if _, err := w.Write(source); err != nil {
+			logger.ErrorContext(r.Context(), "error in writing response", "error", err)
+		}
+	})
+}
+
+// requestSchema returns the schema of the json body of the operation, nil when it takes no json body
+func (s *Spec) requestSchema(route, method string) (schema *Schema, required bool) {
+	op, ok := s.operations[method+" "+route]
+	if !ok || op.RequestBody == nil {
+		return nil, false
+	}
+
+	for mediaType, content := range op.RequestBody.Content {
+		if strings.HasPrefix(mediaType, "application/json") {
+			return content.Schema, op.RequestBody.Required
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,255 @@
+package openapi
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/types"
+)
+
+func loadSpec(t *testing.T) *Spec {
+	spec, err := Load()
+	if err != nil {
+		t.Fatalf("error %s was not expected when loading the document", err)
+	}
+
+	return spec
+}
+
+// registeredRoutes collects the routes registered in main.go as r.Handle(path, ...).Methods(http.MethodX)
+func registeredRoutes(t *testing.T) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), "../main.go", nil, 0)
+	if err != nil {
+		t.Fatalf("error %s was not expected when parsing main.go", err)
+	}
+
+	routes := make([]string, 0)
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		methods, ok := n.(*ast.CallExpr)
+		if !ok || !isCall(methods.Fun, "Methods") {
+			return true
+		}
+
+		handle, ok := methods.Fun.(*ast.SelectorExpr).X.(*ast.CallExpr)
+		if !ok || !(isCall(handle.Fun, "Handle") || isCall(handle.Fun, "HandleFunc")) || len(handle.Args) == 0 {
+			return true
+		}
+
+		lit, ok := handle.Args[0].(*ast.BasicLit)
+		if !ok {
+			return true
+		}
+
+		path, _ := strconv.Unquote(lit.Value)
+
+		for _, arg := range methods.Args {
+			if sel, ok := arg.(*ast.SelectorExpr); ok {
+				routes = append(routes, strings.ToUpper(strings.TrimPrefix(sel.Sel.Name, "Method"))+" "+path)
+			}
+		}
+
+		return true
+	})
+
+	sort.Strings(routes)
+
+	return routes
+}
+
+func isCall(fun ast.Expr, name string) bool {
+	sel, ok := fun.(*ast.SelectorExpr)
+
+	return ok && sel.Sel.Name == name
+}
+
+func TestSpec_Routes(t *testing.T) {
+	spec := loadSpec(t)
+	registered := registeredRoutes(t)
+
+	if len(registered) == 0 {
+		t.Fatalf("\n[TEST] Failed. Desc : routes of main.go\nGot none\nExpected the registered routes")
+	}
+
+	if !reflect.DeepEqual(spec.Routes(), registered) {
+		t.Errorf("\n[TEST] Failed. Desc : document drifted from main.go\nGot %v\nExpected %v", spec.Routes(), registered)
+	}
+}
+
+func TestSpec_Schemas(t *testing.T) {
+	spec := loadSpec(t)
+
+	cases := []struct {
+		schema string
+		model  interface{}
+	}{
+		{"Car", models.Car{}},
+		{"Engine", models.Engine{}},
+		{"APIKey", models.APIKey{}},
+		{"Error", models.Error{}},
+		{"ImportResult", models.ImportResult{}},
+		{"RowError", models.RowError{}},
+		{"BatchRequest", models.BatchRequest{}},
+		{"BatchOperation", models.BatchOperation{}},
+		{"BatchResponse", models.BatchResponse{}},
+		{"BatchItem", models.BatchItem{}},
+		{"Customer", models.Customer{}},
+		{"Address", models.Address{}},
+		{"Consent", models.Consent{}},
+		{"Order", models.Order{}},
+		{"Reservation", models.Reservation{}},
+		{"Hold", models.Hold{}},
+		{"TestDrive", models.TestDrive{}},
+		{"TradeIn", models.TradeIn{}},
+		{"Checklist", models.Checklist{}},
+		{"Appraisal", models.Appraisal{}},
+		{"Webhook", models.Webhook{}},
+		{"Delivery", models.Delivery{}},
+	}
+
+	for i, tc := range cases {
+		schema := spec.Schema(tc.schema)
+		if schema == nil {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot no schema\nExpected one", i, tc.schema)
+
+			continue
+		}
+
+		typ := reflect.TypeOf(tc.model)
+		fields := make(map[string]bool)
+
+		for j := 0; j < typ.NumField(); j++ {
+			field := typ.Field(j)
+
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "-" || name == "" {
+				continue
+			}
+
+			fields[name] = true
+
+			prop, ok := schema.Properties[name]
+			if !ok {
+				t.Errorf("\n[TEST %d] Failed. Desc : %v.%v\nGot no property\nExpected one", i, tc.schema, name)
+
+				continue
+			}
+
+			if expected := schemaType(field.Type); prop.Ref == "" && prop.Type != expected {
+				t.Errorf("\n[TEST %d] Failed. Desc : %v.%v\nGot %v\nExpected %v", i, tc.schema, name, prop.Type, expected)
+			}
+		}
+
+		for name := range schema.Properties {
+			if !fields[name] {
+				t.Errorf("\n[TEST %d] Failed. Desc : %v.%v\nGot a property\nExpected no property the model lacks", i, tc.schema, name)
+			}
+		}
+	}
+
+	fuels := make([]interface{}, 0)
+	for f := types.Fuel(0); f.String() != ""; f++ {
+		fuels = append(fuels, f.String())
+	}
+
+	if enum := spec.Schema("Car").Properties["fuelType"].Enum; !reflect.DeepEqual(enum, fuels) {
+		t.Errorf("\n[TEST] Failed. Desc : fuel types\nGot %v\nExpected %v", enum, fuels)
+	}
+}
+
+// schemaType is the json type a go type is marshalled to
+func schemaType(typ reflect.Type) string {
+	switch typ {
+	case reflect.TypeOf(uuid.UUID{}), reflect.TypeOf(time.Time{}), reflect.TypeOf(types.Fuel(0)):
+		return "string"
+	}
+
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return schemaType(typ.Elem())
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func TestSpec_ValidateRequest(t *testing.T) {
+	spec := loadSpec(t)
+	car := `{"model":"X","yearOfManufacture":2020,"brand":"Tesla","fuelType":"electric","engine":{"displacement":0,"noOfCylinder":0,"range":500}}`
+
+	cases := []struct {
+		desc   string
+		route  string
+		method string
+		body   string
+		err    error
+	}{
+		{"valid car", "/car", http.MethodPost, car, nil},
+		{"read only id is ignored", "/car/{id}", http.MethodPut, `{"id":"x",` + car[1:], nil},
+		{"missing and invalid fields", "/car", http.MethodPost,
+			`{"model":"","yearOfManufacture":1800,"brand":"Tesla","fuelType":"hydrogen","engine":{"displacement":1.5,"range":-1}}`,
+			errors.InvalidParam{Param: []string{"engine.displacement", "engine.noOfCylinder", "engine.range", "fuelType", "model",
+				"yearOfManufacture"}}},
+		{"not an object", "/car", http.MethodPost, `[]`, errors.InvalidParam{Param: []string{"body"}}},
+		{"malformed json", "/car", http.MethodPost, `{"model":`, errors.InvalidParam{Param: []string{"body"}}},
+		{"missing body", "/car", http.MethodPost, ``, errors.MissingParam{Param: "body"}},
+		{"invalid array item and date", "/apikey", http.MethodPost, `{"owner":"crm","scopes":[""],"expiresAt":"tomorrow"}`,
+			errors.InvalidParam{Param: []string{"expiresAt", "scopes[0]"}}},
+		{"operation without body", "/car/{id}", http.MethodDelete, `{"model":1}`, nil},
+		{"unknown route", "/unknown", http.MethodPost, `{}`, nil},
+	}
+
+	for i, tc := range cases {
+		err := spec.ValidateRequest(tc.route, tc.method, []byte(tc.body))
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestUI(t *testing.T) {
+	ui := http.StripPrefix("/docs/", UI(logging.Discard()))
+
+	cases := []struct {
+		desc     string
+		target   string
+		contains string
+	}{
+		{"index", "/docs/", "swagger-ui"},
+		{"initializer points at the document", "/docs/swagger-initializer.js", "/openapi.json"},
+	}
+
+	for i, tc := range cases {
+		w := httptest.NewRecorder()
+
+		ui.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://cars"+tc.target, nil))
+
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), tc.contains) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v containing %q", i, tc.desc, w.Code, http.StatusOK, tc.contains)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package openapi
+
+import (
+	_ "embed"
+	"log/slog"
+	"net/http"
+
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// initializer points the bundled Swagger UI at /openapi.json instead of its demo document
+//
+//go:embed swagger-initializer.js
+var initializer []byte
+
+// UI serves the Swagger UI bundled in the binary, it is mounted with the prefix stripped
+func UI(logger *slog.Logger) http.Handler {
+	files := http.FileServer(http.FS(swaggerFiles.FS))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "swagger-initializer.js" {
+			files.ServeHTTP(w, r)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/javascript")
+
+		if _, err := w.Write(initializer); err != nil {
+			logger.ErrorContext(r.Context(), "error in writing response", "error", err)
+		}
+	})
+}
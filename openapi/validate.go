@@ -0,0 +1,218 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+)
+
+const schemaPrefix = "#/components/schemas/"
+
+// Schema is the subset of the OpenAPI schema object used by the document
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Enum                 []interface{}      `json:"enum"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*Schema `json:"properties"`
+	AdditionalProperties *Schema            `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+	MinLength            *int               `json:"minLength"`
+	MaxLength            *int               `json:"maxLength"`
+	ReadOnly             bool               `json:"readOnly"`
+}
+
+// Validates reports whether requests of the route carry a json body described by the document
+func (s *Spec) Validates(route, method string) bool {
+	schema, _ := s.requestSchema(route, method)
+
+	return schema != nil
+}
+
+// ValidateRequest checks the json body of a request against the schema of its operation.
+// It returns errors.InvalidParam naming every offending field, read only fields are ignored
+// since clients commonly send back what they read.
+func (s *Spec) ValidateRequest(route, method string, body []byte) error {
+	schema, required := s.requestSchema(route, method)
+	if schema == nil {
+		return nil
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if required {
+			return errors.MissingParam{Param: "body"}
+		}
+
+		return nil
+	}
+
+	var value interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&value); err != nil {
+		return errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	params := make(map[string]bool)
+	s.validate(schema, value, "", params)
+
+	if len(params) == 0 {
+		return nil
+	}
+
+	invalid := make([]string, 0, len(params))
+	for param := range params {
+		invalid = append(invalid, param)
+	}
+
+	sort.Strings(invalid)
+
+	return errors.InvalidParam{Param: invalid}
+}
+
+// validate records the path of every value that does not match the schema in params
+func (s *Spec) validate(schema *Schema, value interface{}, path string, params map[string]bool) {
+	if schema.Ref != "" {
+		schema = s.schemas[strings.TrimPrefix(schema.Ref, schemaPrefix)]
+		if schema == nil {
+			return
+		}
+	}
+
+	fail := func() {
+		if path == "" {
+			params["body"] = true
+
+			return
+		}
+
+		params[path] = true
+	}
+
+	if !matchesType(schema, value) || !inEnum(schema.Enum, value) {
+		fail()
+
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range schema.Required {
+			if prop, ok := schema.Properties[name]; ok && prop.ReadOnly {
+				continue
+			}
+
+			if _, ok := v[name]; !ok {
+				params[join(path, name)] = true
+			}
+		}
+
+		for name, field := range v {
+			prop, ok := schema.Properties[name]
+			if !ok {
+				prop = schema.AdditionalProperties
+			}
+
+			if prop == nil || prop.ReadOnly {
+				continue
+			}
+
+			s.validate(prop, field, join(path, name), params)
+		}
+	case []interface{}:
+		if schema.Items == nil {
+			return
+		}
+
+		for i, item := range v {
+			s.validate(schema.Items, item, fmt.Sprintf("%s[%d]", path, i), params)
+		}
+	case json.Number:
+		n, _ := v.Float64()
+		if (schema.Minimum != nil && n < *schema.Minimum) || (schema.Maximum != nil && n > *schema.Maximum) {
+			fail()
+		}
+	case string:
+		if !matchesLength(schema, v) || !matchesFormat(schema.Format, v) {
+			fail()
+		}
+	}
+}
+
+func matchesType(schema *Schema, value interface{}) bool {
+	switch value.(type) {
+	case map[string]interface{}:
+		return schema.Type == "" || schema.Type == "object"
+	case []interface{}:
+		return schema.Type == "" || schema.Type == "array"
+	case string:
+		return schema.Type == "" || schema.Type == "string"
+	case bool:
+		return schema.Type == "" || schema.Type == "boolean"
+	case json.Number:
+		if schema.Type == "integer" {
+			_, err := value.(json.Number).Int64()
+
+			return err == nil
+		}
+
+		return schema.Type == "" || schema.Type == "number"
+	default:
+		return false
+	}
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	if len(enum) == 0 {
+		return true
+	}
+
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesLength(schema *Schema, s string) bool {
+	n := len([]rune(s))
+
+	return (schema.MinLength == nil || n >= *schema.MinLength) && (schema.MaxLength == nil || n <= *schema.MaxLength)
+}
+
+func matchesFormat(format, s string) bool {
+	switch format {
+	case "uuid":
+		_, err := uuid.Parse(s)
+
+		return err == nil
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+
+		return err == nil
+	default:
+		return true
+	}
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
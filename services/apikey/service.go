@@ -0,0 +1,183 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/authz"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+const (
+	secretLength = 32
+	separator    = "."
+)
+
+type service struct {
+	store  stores.APIKey
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+func New(store stores.APIKey, logger *slog.Logger) services.APIKey {
+	return service{store: store, logger: logger, now: time.Now}
+}
+
+// Create issues a new api key, the plain text key is only returned here and never stored
+func (s service) Create(ctx context.Context, key *models.APIKey) (*models.APIKey, error) {
+	if err := s.checkKey(key); err != nil {
+		return nil, err
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	key.ID = uuid.New()
+	key.Hash = hash(secret)
+	key.CreatedAt = s.now().UTC()
+	key.LastUsedAt = nil
+	key.RevokedAt = nil
+
+	if err := s.store.Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	key.Key = key.ID.String() + separator + secret
+
+	s.logger.InfoContext(ctx, "api key issued", "key_id", key.ID, "owner", key.Owner)
+
+	return key, nil
+}
+
+// GetAll lists the api keys without their secrets
+func (s service) GetAll(ctx context.Context) ([]models.APIKey, error) {
+	return s.store.GetAll(ctx)
+}
+
+// Rotate replaces the secret of an active api key, the old secret stops working immediately
+func (s service) Rotate(ctx context.Context, id uuid.UUID) (*models.APIKey, error) {
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.store.UpdateHash(ctx, id, hash(secret)); err != nil {
+		return nil, err
+	}
+
+	key, err := s.store.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	key.Key = id.String() + separator + secret
+
+	s.logger.InfoContext(ctx, "api key rotated", "key_id", id)
+
+	return &key, nil
+}
+
+// Revoke permanently disables the api key
+func (s service) Revoke(ctx context.Context, id uuid.UUID) error {
+	if err := s.store.Revoke(ctx, id, s.now().UTC()); err != nil {
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "api key revoked", "key_id", id)
+
+	return nil
+}
+
+// Authenticate resolves the principal owning the given plain text key
+func (s service) Authenticate(ctx context.Context, key string) (*models.Principal, error) {
+	idPart, secret, ok := strings.Cut(key, separator)
+	if !ok || secret == "" {
+		return nil, errors.Unauthenticated{Reason: "malformed api key"}
+	}
+
+	id, err := uuid.Parse(idPart)
+	if err != nil {
+		return nil, errors.Unauthenticated{Reason: "malformed api key"}
+	}
+
+	stored, err := s.store.GetByID(ctx, id)
+
+	switch err.(type) {
+	case nil:
+	case errors.EntityNotFound:
+		return nil, errors.Unauthenticated{Reason: "unknown api key"}
+	default:
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hash(secret)), []byte(stored.Hash)) != 1 {
+		return nil, errors.Unauthenticated{Reason: "unknown api key"}
+	}
+
+	now := s.now().UTC()
+
+	switch {
+	case stored.RevokedAt != nil:
+		return nil, errors.Unauthenticated{Reason: "api key is revoked"}
+	case stored.ExpiresAt != nil && !now.Before(*stored.ExpiresAt):
+		return nil, errors.Unauthenticated{Reason: "api key is expired"}
+	}
+
+	// the last use is informational, failing to record it does not fail the request
+	if err := s.store.UpdateLastUsed(ctx, id, now); err != nil {
+		s.logger.ErrorContext(ctx, "error in recording last use of api key", "key_id", id, "error", err)
+	}
+
+	// the scopes of an api key name the roles granted to its owner
+	return &models.Principal{ID: stored.ID.String(), Name: stored.Owner, Scopes: stored.Scopes, Roles: stored.Scopes}, nil
+}
+
+// checkKey validates the owner, scopes and expiry of a new key, the scopes of a key name the roles it grants
+func (s service) checkKey(key *models.APIKey) error {
+	switch {
+	case strings.TrimSpace(key.Owner) == "":
+		return errors.InvalidParam{Param: []string{"owner"}}
+	case key.ExpiresAt != nil && !key.ExpiresAt.After(s.now()):
+		return errors.InvalidParam{Param: []string{"expiresAt"}}
+	}
+
+	for _, scope := range key.Scopes {
+		if !authz.IsRole(scope) {
+			return errors.InvalidParam{Param: []string{"scopes"}}
+		}
+	}
+
+	return nil
+}
+
+// newSecret generates a random url safe secret
+func newSecret() (string, error) {
+	b := make([]byte, secretLength)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hash returns the hex encoded sha256 of the secret
+func hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,190 @@
+package apikey
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var now = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func initializeTest(t *testing.T) (service, *stores.MockAPIKey) {
+	ctrl := gomock.NewController(t)
+
+	mockStore := stores.NewMockAPIKey(ctrl)
+
+	return service{store: mockStore, logger: logging.Discard(), now: func() time.Time { return now }}, mockStore
+}
+
+func TestService_Create(t *testing.T) {
+	s, mockStore := initializeTest(t)
+
+	mockStore.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	key, err := s.Create(context.Background(), &models.APIKey{Owner: "crm", Scopes: []string{"admin"}})
+	if err != nil {
+		t.Fatalf("\n[TEST] Failed \nDesc create successful\nGot %v\n Expected %v", err, nil)
+	}
+
+	id, secret, _ := strings.Cut(key.Key, separator)
+
+	if id != key.ID.String() || hash(secret) != key.Hash || !key.CreatedAt.Equal(now) {
+		t.Errorf("\n[TEST] Failed \nDesc create successful\nGot %v\n Expected key bound to its id and hash", key)
+	}
+}
+
+func TestService_CreateErrors(t *testing.T) {
+	past := now.Add(-time.Hour)
+
+	cases := []struct {
+		desc     string
+		key      models.APIKey
+		storeErr error
+		err      error
+	}{
+		{"missing owner", models.APIKey{}, nil, errors.InvalidParam{Param: []string{"owner"}}},
+		{"expired", models.APIKey{Owner: "crm", ExpiresAt: &past}, nil, errors.InvalidParam{Param: []string{"expiresAt"}}},
+		{"invalid scope", models.APIKey{Owner: "crm", Scopes: []string{"a,b"}}, nil, errors.InvalidParam{Param: []string{"scopes"}}},
+		{"unknown role", models.APIKey{Owner: "crm", Scopes: []string{"admin", "amdin"}}, nil, errors.InvalidParam{Param: []string{"scopes"}}},
+		{"db error", models.APIKey{Owner: "crm"}, errors.DB{}, errors.DB{}},
+	}
+
+	for i, tc := range cases {
+		s, mockStore := initializeTest(t)
+
+		if tc.storeErr != nil {
+			mockStore.EXPECT().Create(gomock.Any(), gomock.Any()).Return(tc.storeErr)
+		}
+
+		key, err := s.Create(context.Background(), &tc.key)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed \nDesc %v\nGot %v\n Expected %v", i, tc.desc, err, tc.err)
+		}
+
+		if key != nil {
+			t.Errorf("\n[TEST %d] Failed \nDesc %v\nGot %v\n Expected %v", i, tc.desc, key, nil)
+		}
+	}
+}
+
+func TestService_Rotate(t *testing.T) {
+	id := uuid.New()
+
+	s, mockStore := initializeTest(t)
+
+	mockStore.EXPECT().UpdateHash(gomock.Any(), id, gomock.Any()).Return(nil)
+	mockStore.EXPECT().GetByID(gomock.Any(), id).Return(models.APIKey{ID: id, Owner: "crm"}, nil)
+	mockStore.EXPECT().UpdateHash(gomock.Any(), id, gomock.Any()).Return(errors.EntityNotFound{})
+
+	key, err := s.Rotate(context.Background(), id)
+	if err != nil || !strings.HasPrefix(key.Key, id.String()+separator) {
+		t.Errorf("\n[TEST] Failed \nDesc rotate successful\nGot %v, %v\n Expected new key", key, err)
+	}
+
+	if _, err := s.Rotate(context.Background(), id); !reflect.DeepEqual(err, errors.EntityNotFound{}) {
+		t.Errorf("\n[TEST] Failed \nDesc rotate missing key\nGot %v\n Expected %v", err, errors.EntityNotFound{})
+	}
+}
+
+func TestService_Revoke(t *testing.T) {
+	id := uuid.New()
+
+	s, mockStore := initializeTest(t)
+
+	mockStore.EXPECT().Revoke(gomock.Any(), id, now).Return(nil)
+
+	if err := s.Revoke(context.Background(), id); err != nil {
+		t.Errorf("\n[TEST] Failed \nDesc revoke successful\nGot %v\n Expected %v", err, nil)
+	}
+}
+
+func TestService_GetAll(t *testing.T) {
+	s, mockStore := initializeTest(t)
+
+	keys := []models.APIKey{{Owner: "crm"}}
+
+	mockStore.EXPECT().GetAll(gomock.Any()).Return(keys, nil)
+
+	output, err := s.GetAll(context.Background())
+	if err != nil || !reflect.DeepEqual(output, keys) {
+		t.Errorf("\n[TEST] Failed \nDesc get all\nGot %v, %v\n Expected %v", output, err, keys)
+	}
+}
+
+func TestService_Authenticate(t *testing.T) {
+	id := uuid.New()
+	past := now.Add(-time.Hour)
+	secret := "secret"
+	key := id.String() + separator + secret
+	active := models.APIKey{ID: id, Owner: "crm", Scopes: []string{"admin"}, Hash: hash(secret)}
+	revoked := models.APIKey{ID: id, Hash: hash(secret), RevokedAt: &past}
+	expired := models.APIKey{ID: id, Hash: hash(secret), ExpiresAt: &past}
+
+	cases := []struct {
+		desc      string
+		key       string
+		stored    *models.APIKey
+		storeErr  error
+		principal *models.Principal
+		err       error
+	}{
+		{"success", key, &active, nil, &models.Principal{ID: id.String(), Name: "crm", Scopes: []string{"admin"},
+			Roles: []string{"admin"}}, nil},
+		{"malformed key", "aryan-zs", nil, nil, nil, errors.Unauthenticated{Reason: "malformed api key"}},
+		{"malformed id", "id.secret", nil, nil, nil, errors.Unauthenticated{Reason: "malformed api key"}},
+		{"unknown key", key, &models.APIKey{}, errors.EntityNotFound{}, nil, errors.Unauthenticated{Reason: "unknown api key"}},
+		{"db error", key, &models.APIKey{}, errors.DB{}, nil, errors.DB{}},
+		{"wrong secret", id.String() + ".other", &active, nil, nil, errors.Unauthenticated{Reason: "unknown api key"}},
+		{"revoked", key, &revoked, nil, nil, errors.Unauthenticated{Reason: "api key is revoked"}},
+		{"expired", key, &expired, nil, nil, errors.Unauthenticated{Reason: "api key is expired"}},
+	}
+
+	for i, tc := range cases {
+		s, mockStore := initializeTest(t)
+
+		if tc.stored != nil {
+			mockStore.EXPECT().GetByID(gomock.Any(), id).Return(*tc.stored, tc.storeErr)
+		}
+
+		if tc.principal != nil {
+			mockStore.EXPECT().UpdateLastUsed(gomock.Any(), id, now).Return(nil)
+		}
+
+		principal, err := s.Authenticate(context.Background(), tc.key)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed \nDesc %v\nGot %v\n Expected %v", i, tc.desc, err, tc.err)
+		}
+
+		if !reflect.DeepEqual(principal, tc.principal) {
+			t.Errorf("\n[TEST %d] Failed \nDesc %v\nGot %v\n Expected %v", i, tc.desc, principal, tc.principal)
+		}
+	}
+}
+
+func TestService_AuthenticateLastUsedError(t *testing.T) {
+	s, mockStore := initializeTest(t)
+
+	id := uuid.New()
+	secret := "secret"
+
+	mockStore.EXPECT().GetByID(gomock.Any(), id).Return(models.APIKey{ID: id, Owner: "crm", Hash: hash(secret)}, nil)
+	mockStore.EXPECT().UpdateLastUsed(gomock.Any(), id, now).Return(errors.DB{})
+
+	principal, err := s.Authenticate(context.Background(), id.String()+separator+secret)
+	if err != nil || principal == nil || principal.Name != "crm" {
+		t.Errorf("\n[TEST] Failed \nDesc last use not recorded\nGot %v, %v\n Expected the principal of crm", principal, err)
+	}
+}
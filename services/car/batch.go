@@ -0,0 +1,95 @@
+package car
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// maxBatchOperations bounds the number of operations of a batch
+const maxBatchOperations = 1000
+
+// Batch applies the operations in order through Create, Update and Delete, so each is validated and authorized on its own.
+// By default they run in one transaction: when an operation fails the others are rolled back or skipped and get errors.Aborted.
+// Best effort batches apply every operation on its own and report each outcome.
+func (s service) Batch(ctx context.Context, ops []models.BatchOperation, opts models.BatchOptions) ([]models.BatchResult, error) {
+	if len(ops) == 0 || len(ops) > maxBatchOperations {
+		return nil, errors.InvalidParam{Param: []string{"operations"}}
+	}
+
+	results := make([]models.BatchResult, len(ops))
+
+	if opts.BestEffort {
+		for i := range ops {
+			results[i] = s.apply(ctx, ops[i])
+		}
+
+		s.logger.InfoContext(ctx, "batch applied", "operations", len(ops), "best_effort", true)
+
+		return results, nil
+	}
+
+	failed := -1
+
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		for i := range ops {
+			results[i] = s.apply(ctx, ops[i])
+
+			if results[i].Err != nil {
+				failed = i
+
+				return results[i].Err
+			}
+		}
+
+		return nil
+	})
+
+	switch {
+	case err == nil:
+		s.logger.InfoContext(ctx, "batch applied", "operations", len(ops), "best_effort", false)
+	case failed < 0:
+		// the transaction itself failed, none of the operations was applied
+		for i := range ops {
+			results[i] = models.BatchResult{Op: ops[i].Op, Err: err}
+		}
+	default:
+		for i := range ops {
+			if i != failed {
+				results[i] = models.BatchResult{Op: ops[i].Op, Err: errors.Aborted{Index: failed}}
+			}
+		}
+
+		s.logger.InfoContext(ctx, "batch rolled back", "operations", len(ops), "failed", failed)
+	}
+
+	return results, nil
+}
+
+// apply runs a single operation of a batch
+func (s service) apply(ctx context.Context, op models.BatchOperation) models.BatchResult {
+	result := models.BatchResult{Op: op.Op}
+
+	switch {
+	case op.Op != models.BatchCreate && op.Op != models.BatchUpdate && op.Op != models.BatchDelete:
+		result.Err = errors.InvalidParam{Param: []string{"op"}}
+	case op.Op != models.BatchDelete && op.Car == nil:
+		result.Err = errors.MissingParam{Param: "car"}
+	case op.Op != models.BatchCreate && op.ID == uuid.Nil:
+		result.Err = errors.MissingParam{Param: "id"}
+	case op.Op == models.BatchCreate:
+		result.Car, result.Err = s.Create(ctx, op.Car)
+	case op.Op == models.BatchUpdate:
+		op.Car.ID = op.ID
+		op.Car.Engine.ID = op.ID
+
+		result.Car, result.Err = s.Update(ctx, op.Car)
+	default:
+		result.Err = s.Delete(ctx, op.ID)
+	}
+
+	return result
+}
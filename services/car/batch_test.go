@@ -0,0 +1,113 @@
+package car
+
+import (
+	"context"
+	goError "errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+// batchErrors returns the error of every result
+func batchErrors(results []models.BatchResult) []error {
+	errs := make([]error, len(results))
+	for i := range results {
+		errs[i] = results[i].Err
+	}
+
+	return errs
+}
+
+func TestService_Batch(t *testing.T) {
+	id := uuid.New()
+	notFound := errors.EntityNotFound{Entity: "car", ID: id.String()}
+
+	ops := func() []models.BatchOperation {
+		valid := models.Car{Model: "X", ManufactureYear: 2020, Brand: "BMW", Engine: models.Engine{Displacement: 100, NCylinder: 2}}
+
+		return []models.BatchOperation{
+			{Op: models.BatchCreate, Car: &valid},
+			{Op: models.BatchDelete, ID: id},
+			{Op: models.BatchUpdate, Car: &valid},
+			{Op: "upsert"},
+		}
+	}
+
+	cases := []struct {
+		desc string
+		opts models.BatchOptions
+		errs []error
+	}{
+		{"best effort", models.BatchOptions{BestEffort: true},
+			[]error{nil, notFound, errors.MissingParam{Param: "id"}, errors.InvalidParam{Param: []string{"op"}}}},
+		{"atomic", models.BatchOptions{}, []error{errors.Aborted{Index: 1}, notFound, errors.Aborted{Index: 1}, errors.Aborted{Index: 1}}},
+	}
+
+	for i, tc := range cases {
+		s, mockCar, mockEngine := initializeImportTest(t)
+
+		mockEngine.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+		mockCar.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+		mockCar.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(models.Car{}, nil)
+		mockEngine.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(models.Engine{}, nil)
+		mockCar.EXPECT().Delete(gomock.Any(), id).Return(notFound)
+
+		results, err := s.Batch(ctx, ops(), tc.opts)
+		if err != nil {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, nil)
+
+			continue
+		}
+
+		if errs := batchErrors(results); !reflect.DeepEqual(errs, tc.errs) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, errs, tc.errs)
+		}
+
+		if (results[0].Car != nil) != tc.opts.BestEffort {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected the created car only when it was kept", i, tc.desc, results[0].Car)
+		}
+	}
+}
+
+func TestService_BatchTransactionError(t *testing.T) {
+	s, _, _ := initializeImportTest(t)
+
+	dbErr := errors.DB{Err: goError.New("connection lost")}
+
+	mockTx := stores.NewMockTransactor(gomock.NewController(t))
+	mockTx.EXPECT().InTx(gomock.Any(), gomock.Any()).Return(dbErr)
+
+	s.tx = mockTx
+
+	results, err := s.Batch(ctx, []models.BatchOperation{{Op: models.BatchDelete}}, models.BatchOptions{})
+
+	if err != nil || !reflect.DeepEqual(batchErrors(results), []error{dbErr}) {
+		t.Errorf("\n[TEST] Failed. Desc : begin fails\nGot %v %v\nExpected %v", batchErrors(results), err, dbErr)
+	}
+}
+
+func TestService_BatchInvalid(t *testing.T) {
+	s, _, _ := initializeTest(t)
+
+	cases := []struct {
+		desc string
+		ops  []models.BatchOperation
+	}{
+		{"no operations", nil},
+		{"too many operations", make([]models.BatchOperation, maxBatchOperations+1)},
+	}
+
+	for i, tc := range cases {
+		_, err := s.Batch(context.Background(), tc.ops, models.BatchOptions{})
+
+		if !reflect.DeepEqual(err, errors.InvalidParam{Param: []string{"operations"}}) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, errors.InvalidParam{Param: []string{"operations"}})
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package car
+
+import (
+	goError "errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+func TestService_UpdateEmitsEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockCar := stores.NewMockCar(ctrl)
+	mockEngine := stores.NewMockEngine(ctrl)
+	mockEvents := services.NewMockEmitter(ctrl)
+	s := New(mockEngine, mockCar, newTransactor(ctrl), mockEvents, logging.Discard())
+
+	updated := car
+	updated.ID = uuid.New()
+
+	mockEngine.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+	mockCar.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+	mockEvents.EXPECT().Emit(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, event *models.Event) error {
+		if event.Type != models.CarUpdated || event.Car.ID != updated.ID {
+			t.Errorf("\n[TEST] Failed. Desc : update event\nGot %v\nExpected %v of car %v", event.Type, models.CarUpdated, updated.ID)
+		}
+
+		return nil
+	})
+
+	if _, err := s.Update(ctx, &updated); err != nil {
+		t.Errorf("\n[TEST] Failed. Desc : update event\nGot %v\nExpected nil", err)
+	}
+}
+
+func TestService_DeleteEmitError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockCar := stores.NewMockCar(ctrl)
+	mockEngine := stores.NewMockEngine(ctrl)
+	mockEvents := services.NewMockEmitter(ctrl)
+	s := New(mockEngine, mockCar, newTransactor(ctrl), mockEvents, logging.Discard())
+
+	id := uuid.New()
+	outboxErr := goError.New("outbox unavailable")
+
+	mockCar.EXPECT().Delete(gomock.Any(), id).Return(nil)
+	mockEngine.EXPECT().Delete(gomock.Any(), id).Return(nil)
+	mockEvents.EXPECT().Emit(gomock.Any(), &models.Event{Type: models.CarDeleted, Car: &models.Car{ID: id}}).Return(errors.DB{Err: outboxErr})
+
+	// the deletion is rolled back along with the event that could not be written
+	if err := s.Delete(ctx, id); !reflect.DeepEqual(err, errors.DB{Err: outboxErr}) {
+		t.Errorf("\n[TEST] Failed. Desc : emit error\nGot %v\nExpected %v", err, errors.DB{Err: outboxErr})
+	}
+}
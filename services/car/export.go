@@ -0,0 +1,57 @@
+package car
+
+import (
+	"context"
+
+	"github.com/amehrotra/car-dealership/authz"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// Export calls fn with every car matching the filter along with its engine, in the order of their ids.
+// Cars are read from the store as fn consumes them, an error of fn stops the export and is returned.
+func (s service) Export(ctx context.Context, filter filters.Car, fn func(car *models.Car) error) error {
+	if err := authz.Check(ctx, authz.ReadCars); err != nil {
+		return err
+	}
+
+	if filter.Brand != "" && checkBrand(filter.Brand) != nil {
+		return errors.InvalidParam{Param: []string{"brand"}}
+	}
+
+	it, err := s.car.Iterate(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := it.Close(); err != nil {
+			s.logger.ErrorContext(ctx, "error in closing rows", "error", err)
+		}
+	}()
+
+	var count int
+
+	for it.Next() {
+		var car models.Car
+
+		if err := it.Scan(&car); err != nil {
+			return err
+		}
+
+		if err := fn(&car); err != nil {
+			return err
+		}
+
+		count++
+	}
+
+	if err := it.Err(); err != nil {
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "cars exported", "count", count, "brand", filter.Brand)
+
+	return nil
+}
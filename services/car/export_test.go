@@ -0,0 +1,87 @@
+package car
+
+import (
+	"context"
+	goError "errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/amehrotra/car-dealership/authz"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+func TestService_Export(t *testing.T) {
+	stop := goError.New("stop")
+
+	cases := []struct {
+		desc    string
+		filter  filters.Car
+		rows    int
+		iterErr error
+		fnErr   error
+		cars    int
+		err     error
+	}{
+		{"success case", filters.Car{Brand: "bmw"}, 2, nil, nil, 2, nil},
+		{"store error", filters.Car{}, 0, errors.DB{}, nil, 0, errors.DB{}},
+		{"callback error", filters.Car{}, 2, nil, stop, 1, stop},
+	}
+
+	for i, tc := range cases {
+		ctrl := gomock.NewController(t)
+		s, mockCar, _ := initializeTest(t)
+		mockIt := stores.NewMockCarIterator(ctrl)
+
+		if tc.iterErr != nil {
+			mockCar.EXPECT().Iterate(gomock.Any(), tc.filter).Return(nil, tc.iterErr)
+		} else {
+			mockCar.EXPECT().Iterate(gomock.Any(), tc.filter).Return(mockIt, nil)
+			mockIt.EXPECT().Next().Return(true).MaxTimes(tc.rows)
+			mockIt.EXPECT().Next().Return(false).MaxTimes(1)
+			mockIt.EXPECT().Scan(gomock.Any()).Return(nil).MaxTimes(tc.rows)
+			mockIt.EXPECT().Err().Return(nil).MaxTimes(1)
+			mockIt.EXPECT().Close().Return(nil)
+		}
+
+		var cars int
+
+		err := s.Export(ctx, tc.filter, func(car *models.Car) error {
+			cars++
+
+			return tc.fnErr
+		})
+
+		if !reflect.DeepEqual(err, tc.err) || cars != tc.cars {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v %v\nExpected %v %v", i, tc.desc, cars, err, tc.cars, tc.err)
+		}
+	}
+}
+
+func TestService_ExportInvalid(t *testing.T) {
+	s, _, _ := initializeTest(t)
+
+	viewer := authz.WithPrincipal(context.Background(), &models.Principal{Roles: []string{string(authz.Viewer)}})
+	none := authz.WithPrincipal(context.Background(), &models.Principal{})
+
+	cases := []struct {
+		desc string
+		ctx  context.Context
+		err  error
+	}{
+		{"invalid brand", viewer, errors.InvalidParam{Param: []string{"brand"}}},
+		{"no permission", none, errors.Forbidden{Permission: string(authz.ReadCars)}},
+	}
+
+	for i, tc := range cases {
+		err := s.Export(tc.ctx, filters.Car{Brand: "fiat"}, func(*models.Car) error { return nil })
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package car
+
+import (
+	"context"
+	goError "errors"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/authz"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// importBatchSize is the number of cars inserted per transaction in best effort imports
+const importBatchSize = 100
+
+// Import validates every row with the checks of Create and inserts the valid ones.
+// By default the rows are inserted in one transaction and only when all of them are valid,
+// best effort imports insert the valid rows in batches and retry a failed batch row by row to single out the failures.
+func (s service) Import(ctx context.Context, rows []models.ImportRow, opts models.ImportOptions) (*models.ImportResult, error) {
+	if err := authz.Check(ctx, authz.CreateCars); err != nil {
+		return nil, err
+	}
+
+	result := &models.ImportResult{Total: len(rows), DryRun: opts.DryRun}
+	valid := make([]*models.ImportRow, 0, len(rows))
+
+	for i := range rows {
+		row := &rows[i]
+
+		err := row.Err
+		if err == nil {
+			err = checkImport(&row.Car)
+		}
+
+		if err != nil {
+			s.addError(ctx, result, row.Row, err)
+
+			continue
+		}
+
+		valid = append(valid, row)
+	}
+
+	switch {
+	case result.Failed > 0 && !opts.BestEffort:
+		// nothing is imported, nor would be on a dry run
+	case opts.DryRun:
+		result.Imported = len(valid)
+	case opts.BestEffort:
+		for start := 0; start < len(valid); start += importBatchSize {
+			end := start + importBatchSize
+			if end > len(valid) {
+				end = len(valid)
+			}
+
+			s.importBatch(ctx, valid[start:end], result)
+		}
+	default:
+		err := s.tx.InTx(ctx, func(ctx context.Context) error { return s.insertRows(ctx, valid, result) })
+
+		// a failing database is not the fault of the rows, it fails the import rather than a row
+		var dbErr errors.DB
+
+		switch {
+		case goError.As(err, &dbErr):
+			return nil, err
+		case err != nil:
+			result.IDs = nil
+
+			return result, nil
+		}
+
+		result.Imported = len(valid)
+	}
+
+	s.logger.InfoContext(ctx, "cars imported", "total", result.Total, "imported", result.Imported, "failed", result.Failed,
+		"dry_run", opts.DryRun, "best_effort", opts.BestEffort)
+
+	return result, nil
+}
+
+// importBatch inserts the rows in a transaction, a failed batch is retried row by row
+func (s service) importBatch(ctx context.Context, rows []*models.ImportRow, result *models.ImportResult) {
+	ids := len(result.IDs)
+
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		return s.insertRows(ctx, rows, &models.ImportResult{})
+	})
+	if err == nil {
+		for _, row := range rows {
+			result.IDs = append(result.IDs, row.Car.ID)
+		}
+
+		result.Imported += len(rows)
+
+		return
+	}
+
+	result.IDs = result.IDs[:ids]
+
+	for _, row := range rows {
+		row := row
+
+		err := s.tx.InTx(ctx, func(ctx context.Context) error {
+			return s.insertRows(ctx, []*models.ImportRow{row}, &models.ImportResult{})
+		})
+		if err != nil {
+			s.addError(ctx, result, row.Row, err)
+
+			continue
+		}
+
+		result.IDs = append(result.IDs, row.Car.ID)
+		result.Imported++
+	}
+}
+
+// insertRows inserts the engine and car of every row, the first failure is recorded against its row and returned
+func (s service) insertRows(ctx context.Context, rows []*models.ImportRow, result *models.ImportResult) error {
+	for _, row := range rows {
+		id := uuid.New()
+		row.Car.ID = id
+		row.Car.Engine.ID = id
+
+		err := s.engine.Create(ctx, &row.Car.Engine)
+		if err == nil {
+			err = s.car.Create(ctx, &row.Car)
+		}
+
+		if err == nil {
+			err = s.events.Emit(ctx, &models.Event{Type: models.CarCreated, Car: &row.Car})
+		}
+
+		if err != nil {
+			s.addError(ctx, result, row.Row, err)
+
+			return err
+		}
+
+		result.IDs = append(result.IDs, id)
+	}
+
+	return nil
+}
+
+func checkImport(car *models.Car) error {
+	if err := checkCar(car); err != nil {
+		return err
+	}
+
+	return checkEngine(car.Engine)
+}
+
+// addError records the failure of a row, rows that could not be read or are invalid are reported as invalid parameters
+// and store errors are only detailed in the logs
+func (s service) addError(ctx context.Context, result *models.ImportResult, row int, err error) {
+	code, message := "invalid_parameter", err.Error()
+
+	switch err.(type) {
+	case errors.DB:
+		s.logger.ErrorContext(ctx, "error in importing car", "row", row, "error", err)
+
+		code, message = "internal_error", "internal server error"
+	case errors.EntityAlreadyExists:
+		code = "already_exists"
+	}
+
+	result.Errors = append(result.Errors, models.RowError{Row: row, Code: code, Message: message})
+	result.Failed++
+}
@@ -0,0 +1,151 @@
+package car
+
+import (
+	"context"
+	goError "errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/amehrotra/car-dealership/authz"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+	"github.com/amehrotra/car-dealership/stores"
+	"github.com/amehrotra/car-dealership/types"
+)
+
+// initializeImportTest returns a service whose transactions run their function directly
+func initializeImportTest(t *testing.T) (service, *stores.MockCar, *stores.MockEngine) {
+	ctrl := gomock.NewController(t)
+
+	mockCar := stores.NewMockCar(ctrl)
+	mockEngine := stores.NewMockEngine(ctrl)
+	mockTx := newTransactor(ctrl)
+
+	mockEvents := services.NewMockEmitter(ctrl)
+	mockEvents.EXPECT().Emit(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	return service{engine: mockEngine, car: mockCar, tx: mockTx, events: mockEvents, logger: logging.Discard()}, mockCar, mockEngine
+}
+
+// newTransactor returns a transactor running the functions of its transactions directly
+func newTransactor(ctrl *gomock.Controller) *stores.MockTransactor {
+	mockTx := stores.NewMockTransactor(ctrl)
+
+	mockTx.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).AnyTimes()
+
+	return mockTx
+}
+
+func importRows() []models.ImportRow {
+	valid := models.Car{Model: "X", ManufactureYear: 2020, Brand: "BMW", FuelType: types.Petrol, Engine: models.Engine{Displacement: 100, NCylinder: 2}}
+
+	return []models.ImportRow{
+		{Row: 1, Car: valid},
+		{Row: 2, Car: models.Car{Model: "Y", ManufactureYear: 2020, Brand: "Fiat", Engine: valid.Engine}},
+		{Row: 3, Err: errors.InvalidParam{Param: []string{"price"}}},
+		{Row: 4, Car: valid},
+	}
+}
+
+func TestService_Import(t *testing.T) {
+	invalid := []models.RowError{
+		{Row: 2, Code: "invalid_parameter", Message: "parameter brand is invalid"},
+		{Row: 3, Code: "invalid_parameter", Message: "parameter price is invalid"},
+	}
+
+	cases := []struct {
+		desc     string
+		rows     []models.ImportRow
+		opts     models.ImportOptions
+		inserts  int
+		storeErr error
+		result   models.ImportResult
+	}{
+		{"atomic with invalid rows", importRows(), models.ImportOptions{}, 0, nil,
+			models.ImportResult{Total: 4, Failed: 2, Errors: invalid}},
+		{"dry run", importRows(), models.ImportOptions{DryRun: true, BestEffort: true}, 0, nil,
+			models.ImportResult{Total: 4, Imported: 2, Failed: 2, DryRun: true, Errors: invalid}},
+		{"best effort", importRows(), models.ImportOptions{BestEffort: true}, 2, nil,
+			models.ImportResult{Total: 4, Imported: 2, Failed: 2, Errors: invalid}},
+		{"atomic", importRows()[:1], models.ImportOptions{}, 1, nil, models.ImportResult{Total: 1, Imported: 1}},
+		{"atomic duplicate", importRows()[:1], models.ImportOptions{}, 1, errors.EntityAlreadyExists{Entity: "car"},
+			models.ImportResult{Total: 1, Failed: 1, Errors: []models.RowError{{Row: 1, Code: "already_exists", Message: "entity  car already exists"}}}},
+	}
+
+	for i, tc := range cases {
+		s, mockCar, mockEngine := initializeImportTest(t)
+
+		mockEngine.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(tc.inserts)
+		mockCar.EXPECT().Create(gomock.Any(), gomock.Any()).Return(tc.storeErr).Times(tc.inserts)
+
+		result, err := s.Import(ctx, tc.rows, tc.opts)
+		if err != nil {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, nil)
+
+			continue
+		}
+
+		if len(result.IDs) != result.Imported && !tc.opts.DryRun {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v ids\nExpected %v", i, tc.desc, len(result.IDs), result.Imported)
+		}
+
+		result.IDs = nil
+
+		if !reflect.DeepEqual(*result, tc.result) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %+v\nExpected %+v", i, tc.desc, *result, tc.result)
+		}
+	}
+}
+
+func TestService_ImportDBError(t *testing.T) {
+	s, mockCar, mockEngine := initializeImportTest(t)
+
+	dbErr := errors.DB{Err: goError.New("down")}
+
+	mockEngine.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	mockCar.EXPECT().Create(gomock.Any(), gomock.Any()).Return(dbErr)
+
+	result, err := s.Import(ctx, importRows()[:1], models.ImportOptions{})
+	if !reflect.DeepEqual(err, dbErr) || result != nil {
+		t.Errorf("\n[TEST] Failed. Desc : atomic import with the database down\nGot %v, %v\nExpected %v", result, err, dbErr)
+	}
+}
+
+func TestService_ImportBestEffortRetry(t *testing.T) {
+	s, mockCar, mockEngine := initializeImportTest(t)
+
+	rows := importRows()
+	rows = []models.ImportRow{rows[0], rows[3]}
+
+	// the batch fails on its second row, the retry inserts the first row again and fails the second
+	mockEngine.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(4)
+	gomock.InOrder(
+		mockCar.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil),
+		mockCar.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.EntityAlreadyExists{Entity: "car"}),
+		mockCar.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil),
+		mockCar.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.EntityAlreadyExists{Entity: "car"}),
+	)
+
+	result, err := s.Import(ctx, rows, models.ImportOptions{BestEffort: true})
+
+	if err != nil || result.Imported != 1 || len(result.IDs) != 1 || result.Failed != 1 || result.Errors[0].Code != "already_exists" {
+		t.Errorf("\n[TEST] Failed. Desc : retry row by row\nGot %+v %v\nExpected the second row to fail", result, err)
+	}
+}
+
+func TestService_ImportForbidden(t *testing.T) {
+	s, _, _ := initializeImportTest(t)
+
+	viewer := authz.WithPrincipal(context.Background(), &models.Principal{Roles: []string{string(authz.Viewer)}})
+
+	_, err := s.Import(viewer, importRows(), models.ImportOptions{})
+	if _, ok := err.(errors.Forbidden); !ok {
+		t.Errorf("\n[TEST] Failed. Desc : viewer imports\nGot %v\nExpected %v", err, errors.Forbidden{})
+	}
+}
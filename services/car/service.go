@@ -1,10 +1,13 @@
 package car
 
 import (
+	"context"
+	"log/slog"
 	"strings"
 
 	"github.com/google/uuid"
 
+	"github.com/amehrotra/car-dealership/authz"
 	"github.com/amehrotra/car-dealership/errors"
 	"github.com/amehrotra/car-dealership/filters"
 	"github.com/amehrotra/car-dealership/models"
@@ -15,14 +18,21 @@ import (
 type service struct {
 	engine stores.Engine
 	car    stores.Car
+	tx     stores.Transactor
+	events services.Emitter
+	logger *slog.Logger
 }
 
-func New(engine stores.Engine, car stores.Car) services.Car {
-	return service{engine: engine, car: car}
+func New(engine stores.Engine, car stores.Car, tx stores.Transactor, events services.Emitter, logger *slog.Logger) services.Car {
+	return service{engine: engine, car: car, tx: tx, events: events, logger: logger}
 }
 
 // Create validates car information and sends data to store
-func (s service) Create(car *models.Car) (*models.Car, error) {
+func (s service) Create(ctx context.Context, car *models.Car) (*models.Car, error) {
+	if err := authz.Check(ctx, authz.CreateCars); err != nil {
+		return nil, err
+	}
+
 	if err := checkCar(car); err != nil {
 		return nil, err
 	}
@@ -35,26 +45,40 @@ func (s service) Create(car *models.Car) (*models.Car, error) {
 	car.ID = id
 	car.Engine.ID = id
 
-	err := s.engine.Create(&car.Engine)
-	if err != nil {
-		return nil, err
-	}
+	// the car is written along with its event so that neither is kept without the other
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := s.engine.Create(ctx, &car.Engine); err != nil {
+			return err
+		}
 
-	err = s.car.Create(car)
-	if err != nil {
-		return nil, err
-	}
+		if err := s.car.Create(ctx, car); err != nil {
+			return err
+		}
+
+		created, err := s.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
 
-	car, err = s.GetByID(id)
+		car = created
+
+		return s.events.Emit(ctx, &models.Event{Type: models.CarCreated, Car: car})
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	s.logger.InfoContext(ctx, "car created", "car_id", id, "brand", car.Brand)
+
 	return car, nil
 }
 
 // GetAll based on filter extracts data from store about cars
-func (s service) GetAll(filter filters.Car) ([]models.Car, error) {
+func (s service) GetAll(ctx context.Context, filter filters.Car) ([]models.Car, error) {
+	if err := authz.Check(ctx, authz.ReadCars); err != nil {
+		return nil, err
+	}
+
 	// validate brand from filter
 	switch {
 	case filter.Brand == "":
@@ -65,14 +89,14 @@ func (s service) GetAll(filter filters.Car) ([]models.Car, error) {
 		break
 	}
 
-	cars, err := s.car.GetAll(filter)
+	cars, err := s.car.GetAll(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
 	if filter.Engine {
 		for i, car := range cars {
-			engine, err := s.engine.GetByID(car.ID)
+			engine, err := s.engine.GetByID(ctx, car.ID)
 			if err != nil {
 				return nil, err
 			}
@@ -85,13 +109,17 @@ func (s service) GetAll(filter filters.Car) ([]models.Car, error) {
 }
 
 // GetByID based on ID provided extracts data from store about car
-func (s service) GetByID(id uuid.UUID) (*models.Car, error) {
-	car, err := s.car.GetByID(id)
+func (s service) GetByID(ctx context.Context, id uuid.UUID) (*models.Car, error) {
+	if err := authz.Check(ctx, authz.ReadCars); err != nil {
+		return nil, err
+	}
+
+	car, err := s.car.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	engine, err := s.engine.GetByID(id)
+	engine, err := s.engine.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -101,43 +129,92 @@ func (s service) GetByID(id uuid.UUID) (*models.Car, error) {
 	return &car, nil
 }
 
-// Update updates the engine followed by car
-func (s service) Update(car *models.Car) (*models.Car, error) {
-	err := s.engine.Update(&car.Engine)
-	if err != nil {
+// Update updates the engine followed by car, changing the price needs its own permission
+func (s service) Update(ctx context.Context, car *models.Car) (*models.Car, error) {
+	if err := authz.Check(ctx, authz.UpdateCars); err != nil {
 		return nil, err
 	}
 
-	err = checkCar(car)
-	if err != nil {
+	if err := s.checkPriceChange(ctx, car); err != nil {
 		return nil, err
 	}
 
-	err = s.car.Update(car)
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := s.engine.Update(ctx, &car.Engine); err != nil {
+			return err
+		}
+
+		if err := checkCar(car); err != nil {
+			return err
+		}
+
+		if err := s.car.Update(ctx, car); err != nil {
+			return err
+		}
+
+		return s.events.Emit(ctx, &models.Event{Type: models.CarUpdated, Car: car})
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	s.logger.InfoContext(ctx, "car updated", "car_id", car.ID, "price", car.Price)
+
 	return car, nil
 }
 
 // Delete deletes the car from store
-func (s service) Delete(id uuid.UUID) error {
-	err := s.car.Delete(id)
+func (s service) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := authz.Check(ctx, authz.DeleteCars); err != nil {
+		return err
+	}
+
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := s.car.Delete(ctx, id); err != nil {
+			return err
+		}
+
+		if err := s.engine.Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return s.events.Emit(ctx, &models.Event{Type: models.CarDeleted, Car: &models.Car{ID: id}})
+	})
 	if err != nil {
 		return err
 	}
 
-	err = s.engine.Delete(id)
+	s.logger.InfoContext(ctx, "car deleted", "car_id", id)
+
+	return nil
+}
+
+// checkPriceChange rejects price changes by principals who may not change prices
+func (s service) checkPriceChange(ctx context.Context, car *models.Car) error {
+	if authz.Check(ctx, authz.ChangePrices) == nil {
+		return nil
+	}
+
+	stored, err := s.car.GetByID(ctx, car.ID)
 	if err != nil {
 		return err
 	}
 
+	if stored.Price != car.Price {
+		s.logger.WarnContext(ctx, "price change denied", "car_id", car.ID)
+
+		return errors.Forbidden{Permission: string(authz.ChangePrices)}
+	}
+
 	return nil
 }
 
-// checkCar validates the all parameters of the car
+// checkCar validates the all parameters of the car, cars are new unless their condition says otherwise
 func checkCar(car *models.Car) error {
+	if car.Condition == "" {
+		car.Condition = models.CarNew
+	}
+
 	switch {
 	case car.Model == "":
 		return errors.InvalidParam{Param: []string{"model"}}
@@ -147,6 +224,12 @@ func checkCar(car *models.Car) error {
 		return errors.InvalidParam{Param: []string{"brand"}}
 	case car.FuelType < 0 || car.FuelType > 3:
 		return errors.InvalidParam{Param: []string{"fuelType"}}
+	case car.Price < 0:
+		return errors.InvalidParam{Param: []string{"price"}}
+	case car.Condition != models.CarNew && car.Condition != models.CarUsed:
+		return errors.InvalidParam{Param: []string{"condition"}}
+	case car.Mileage < 0:
+		return errors.InvalidParam{Param: []string{"mileage"}}
 	default:
 		return nil
 	}
@@ -1,14 +1,17 @@
 package car
 
 import (
+	"context"
 	"reflect"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 
+	"github.com/amehrotra/car-dealership/authz"
 	"github.com/amehrotra/car-dealership/errors"
 	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/logging"
 	"github.com/amehrotra/car-dealership/models"
 	"github.com/amehrotra/car-dealership/services"
 	"github.com/amehrotra/car-dealership/stores"
@@ -21,11 +24,17 @@ func initializeTest(t *testing.T) (services.Car, *stores.MockCar, *stores.MockEn
 	mockCar := stores.NewMockCar(ctrl)
 	mockEngine := stores.NewMockEngine(ctrl)
 
-	service := New(mockEngine, mockCar)
+	mockEvents := services.NewMockEmitter(ctrl)
+	mockEvents.EXPECT().Emit(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	service := New(mockEngine, mockCar, newTransactor(ctrl), mockEvents, logging.Discard())
 
 	return service, mockCar, mockEngine
 }
 
+// nolint:gochecknoglobals // principal allowed to perform every operation
+var ctx = authz.WithPrincipal(context.Background(), &models.Principal{Roles: []string{string(authz.Admin)}})
+
 //nolint
 var engine = models.Engine{
 	Displacement: 100,
@@ -51,12 +60,12 @@ func TestService_Create(t *testing.T) {
 
 	s, mockCar, mockEngine := initializeTest(t)
 
-	mockEngine.EXPECT().Create(gomock.Any()).Return(nil)
-	mockCar.EXPECT().Create(gomock.Any()).Return(nil)
-	mockCar.EXPECT().GetByID(gomock.Any()).Return(car, nil)
-	mockEngine.EXPECT().GetByID(gomock.Any()).Return(engine, nil)
+	mockEngine.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	mockCar.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	mockCar.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(car, nil)
+	mockEngine.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(engine, nil)
 
-	resp, err := s.Create(&car)
+	resp, err := s.Create(ctx, &car)
 
 	if err != nil {
 		t.Errorf("\n[TEST] Failed \nDesc create successful\nGot %v\n Expected %v", err, nil)
@@ -70,7 +79,7 @@ func TestService_Create(t *testing.T) {
 func TestService_CreateInvalidCar(t *testing.T) {
 	s, _, _ := initializeTest(t)
 
-	resp, err := s.Create(&models.Car{})
+	resp, err := s.Create(ctx, &models.Car{})
 
 	if !reflect.DeepEqual(err, errors.InvalidParam{Param: []string{"model"}}) {
 		t.Errorf("\n[TEST] Failed \nDesc invalid car model\nGot %v\n Expected %v", err, errors.InvalidParam{})
@@ -93,7 +102,7 @@ func TestService_CreateInvalidEngine(t *testing.T) {
 
 	s, _, _ := initializeTest(t)
 
-	resp, err := s.Create(&car)
+	resp, err := s.Create(ctx, &car)
 
 	if !reflect.DeepEqual(err, errors.InvalidParam{Param: []string{"noOfCylinder"}}) {
 		t.Errorf("\n[TEST] Failed \nDesc invalid engine parameter\nGot %v\n Expected %v", err,
@@ -108,9 +117,9 @@ func TestService_CreateInvalidEngine(t *testing.T) {
 func TestService_CreateEngineDBError(t *testing.T) {
 	s, _, mockEngine := initializeTest(t)
 
-	mockEngine.EXPECT().Create(gomock.Any()).Return(errors.DB{})
+	mockEngine.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.DB{})
 
-	resp, err := s.Create(&car)
+	resp, err := s.Create(ctx, &car)
 
 	if !reflect.DeepEqual(err, errors.DB{}) {
 		t.Errorf("\n[TEST] Failed \nDesc db error when creating engine\nGot %v\n Expected %v", err, errors.DB{})
@@ -124,11 +133,11 @@ func TestService_CreateEngineDBError(t *testing.T) {
 func TestService_CreateVerificationError(t *testing.T) {
 	s, mockCar, mockEngine := initializeTest(t)
 
-	mockEngine.EXPECT().Create(gomock.Any()).Return(nil)
-	mockCar.EXPECT().Create(gomock.Any()).Return(nil)
-	mockCar.EXPECT().GetByID(gomock.Any()).Return(car, errors.DB{})
+	mockEngine.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	mockCar.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	mockCar.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(car, errors.DB{})
 
-	resp, err := s.Create(&car)
+	resp, err := s.Create(ctx, &car)
 
 	if !reflect.DeepEqual(err, errors.DB{}) {
 		t.Errorf("\n[TEST] Failed \nDesc create successful\nGot %v\n Expected %v", err, errors.DB{})
@@ -142,10 +151,10 @@ func TestService_CreateVerificationError(t *testing.T) {
 func TestService_CreateCarDBError(t *testing.T) {
 	s, mockCar, mockEngine := initializeTest(t)
 
-	mockEngine.EXPECT().Create(gomock.Any()).Return(nil)
-	mockCar.EXPECT().Create(gomock.Any()).Return(errors.DB{})
+	mockEngine.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	mockCar.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.DB{})
 
-	resp, err := s.Create(&car)
+	resp, err := s.Create(ctx, &car)
 
 	if !reflect.DeepEqual(err, errors.DB{}) {
 		t.Errorf("\n[TEST] Failed \nDesc create successful\nGot %v\n Expected %v", err, errors.DB{})
@@ -169,10 +178,10 @@ func TestService_GetAllWithEngine(t *testing.T) {
 
 	s, mockCar, mockEngine := initializeTest(t)
 
-	mockCar.EXPECT().GetAll(gomock.Any()).Return(cars, nil)
-	mockEngine.EXPECT().GetByID(gomock.Any()).Return(engine, nil)
+	mockCar.EXPECT().GetAll(gomock.Any(), gomock.Any()).Return(cars, nil)
+	mockEngine.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(engine, nil)
 
-	resp, err := s.GetAll(filters.Car{Brand: "BMW", Engine: true})
+	resp, err := s.GetAll(ctx, filters.Car{Brand: "BMW", Engine: true})
 
 	if err != nil {
 		t.Errorf("\n[TEST] Failed \nDesc received all cars\nGot %v\n Expected %v", err, nil)
@@ -196,10 +205,10 @@ func TestService_GetAllWithEngineDBError(t *testing.T) {
 
 	s, mockCar, mockEngine := initializeTest(t)
 
-	mockCar.EXPECT().GetAll(gomock.Any()).Return(cars, nil)
-	mockEngine.EXPECT().GetByID(gomock.Any()).Return(engine, errors.DB{})
+	mockCar.EXPECT().GetAll(gomock.Any(), gomock.Any()).Return(cars, nil)
+	mockEngine.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(engine, errors.DB{})
 
-	resp, err := s.GetAll(filters.Car{Brand: "BMW", Engine: true})
+	resp, err := s.GetAll(ctx, filters.Car{Brand: "BMW", Engine: true})
 
 	if !reflect.DeepEqual(err, errors.DB{}) {
 		t.Errorf("\n[TEST] Failed \nDesc received all cars\nGot %v\n Expected %v", err, nil)
@@ -234,9 +243,9 @@ func TestService_GetAllWithoutEngine(t *testing.T) {
 	for i, tc := range cases {
 		s, mockCar, _ := initializeTest(t)
 
-		mockCar.EXPECT().GetAll(gomock.Any()).Return(cars, nil)
+		mockCar.EXPECT().GetAll(gomock.Any(), gomock.Any()).Return(cars, nil)
 
-		resp, err := s.GetAll(tc.filter)
+		resp, err := s.GetAll(ctx, tc.filter)
 
 		if err != nil {
 			t.Errorf("\n[TEST %d] Failed \nDesc %v\nGot %v\n Expected %v", i, tc.desc, err, tc.err)
@@ -251,9 +260,9 @@ func TestService_GetAllWithoutEngine(t *testing.T) {
 func TestService_GetAllWithoutEngineDBError(t *testing.T) {
 	s, mockCar, _ := initializeTest(t)
 
-	mockCar.EXPECT().GetAll(gomock.Any()).Return(nil, errors.DB{})
+	mockCar.EXPECT().GetAll(gomock.Any(), gomock.Any()).Return(nil, errors.DB{})
 
-	resp, err := s.GetAll(filters.Car{Brand: "BMW"})
+	resp, err := s.GetAll(ctx, filters.Car{Brand: "BMW"})
 
 	if !reflect.DeepEqual(err, errors.DB{}) {
 		t.Errorf("\n[TEST] Failed \nDesc error in getting cars\nGot %v\n Expected %v", err, errors.DB{})
@@ -267,7 +276,7 @@ func TestService_GetAllWithoutEngineDBError(t *testing.T) {
 func TestService_GetAllInvalidBrand(t *testing.T) {
 	s, _, _ := initializeTest(t)
 
-	resp, err := s.GetAll(filters.Car{Brand: "Aryan"})
+	resp, err := s.GetAll(ctx, filters.Car{Brand: "Aryan"})
 
 	if !reflect.DeepEqual(err, errors.InvalidParam{}) {
 		t.Errorf("\n[TEST] Failed \nDesc received all cars\nGot %v\n Expected %v", err, errors.InvalidParam{})
@@ -289,12 +298,12 @@ func TestService_CarGetByID(t *testing.T) {
 
 	s, mockCar, mockEngine := initializeTest(t)
 
-	mockCar.EXPECT().GetByID(id).Return(car, nil)
-	mockEngine.EXPECT().GetByID(id).Return(engine, nil)
+	mockCar.EXPECT().GetByID(gomock.Any(), id).Return(car, nil)
+	mockEngine.EXPECT().GetByID(gomock.Any(), id).Return(engine, nil)
 
 	car.Engine = engine
 
-	resp, err := s.GetByID(id)
+	resp, err := s.GetByID(ctx, id)
 
 	if err != nil {
 		t.Errorf("\n[TEST] Failed \nDesc received car\nGot %v\n Expected %v", err, nil)
@@ -316,11 +325,11 @@ func TestService_CarGetByIDInvalidCar(t *testing.T) {
 
 	s, mockCar, _ := initializeTest(t)
 
-	mockCar.EXPECT().GetByID(id).Return(car, errors.EntityNotFound{})
+	mockCar.EXPECT().GetByID(gomock.Any(), id).Return(car, errors.EntityNotFound{})
 
 	car.Engine = engine
 
-	resp, err := s.GetByID(id)
+	resp, err := s.GetByID(ctx, id)
 
 	if !reflect.DeepEqual(err, errors.EntityNotFound{}) {
 		t.Errorf("\n[TEST] Failed \nDesc received car\nGot %v\n Expected %v", err, nil)
@@ -342,12 +351,12 @@ func TestService_CarGetByIDInvalidEngine(t *testing.T) {
 
 	s, mockCar, mockEngine := initializeTest(t)
 
-	mockCar.EXPECT().GetByID(id).Return(car, nil)
-	mockEngine.EXPECT().GetByID(id).Return(engine, errors.EntityNotFound{})
+	mockCar.EXPECT().GetByID(gomock.Any(), id).Return(car, nil)
+	mockEngine.EXPECT().GetByID(gomock.Any(), id).Return(engine, errors.EntityNotFound{})
 
 	car.Engine = engine
 
-	resp, err := s.GetByID(id)
+	resp, err := s.GetByID(ctx, id)
 
 	if !reflect.DeepEqual(err, errors.EntityNotFound{}) {
 		t.Errorf("\n[TEST] Failed \nDesc received car\nGot %v\n Expected %v", err, nil)
@@ -361,10 +370,10 @@ func TestService_CarGetByIDInvalidEngine(t *testing.T) {
 func TestService_Update(t *testing.T) {
 	s, mockCar, mockEngine := initializeTest(t)
 
-	mockEngine.EXPECT().Update(&engine).Return(nil)
-	mockCar.EXPECT().Update(&car).Return(nil)
+	mockEngine.EXPECT().Update(gomock.Any(), &engine).Return(nil)
+	mockCar.EXPECT().Update(gomock.Any(), &car).Return(nil)
 
-	resp, err := s.Update(&car)
+	resp, err := s.Update(ctx, &car)
 
 	if err != nil {
 		t.Errorf("\n[TEST] Failed \nDesc update successful\nGot %v\n Expected %v", err, nil)
@@ -378,9 +387,9 @@ func TestService_Update(t *testing.T) {
 func TestService_UpdateInvalidEngine(t *testing.T) {
 	s, _, mockEngine := initializeTest(t)
 
-	mockEngine.EXPECT().Update(&engine).Return(errors.EntityNotFound{})
+	mockEngine.EXPECT().Update(gomock.Any(), &engine).Return(errors.EntityNotFound{})
 
-	resp, err := s.Update(&car)
+	resp, err := s.Update(ctx, &car)
 
 	if !reflect.DeepEqual(err, errors.EntityNotFound{}) {
 		t.Errorf("\n[TEST] Failed \nDesc update successful\nGot %v\n Expected %v", err, errors.EntityNotFound{})
@@ -401,9 +410,9 @@ func TestService_UpdateInvalidParam(t *testing.T) {
 
 	s, _, mockEngine := initializeTest(t)
 
-	mockEngine.EXPECT().Update(&engine).Return(nil)
+	mockEngine.EXPECT().Update(gomock.Any(), &engine).Return(nil)
 
-	resp, err := s.Update(&car)
+	resp, err := s.Update(ctx, &car)
 
 	if !reflect.DeepEqual(err, errors.InvalidParam{Param: []string{"brand"}}) {
 		t.Errorf("\n[TEST] Failed \nDesc invalid param\nGot %v\n Expected %v", err, errors.InvalidParam{Param: []string{"brand"}})
@@ -417,10 +426,10 @@ func TestService_UpdateInvalidParam(t *testing.T) {
 func TestService_UpdateInvalidCar(t *testing.T) {
 	s, mockCar, mockEngine := initializeTest(t)
 
-	mockEngine.EXPECT().Update(&engine).Return(nil)
-	mockCar.EXPECT().Update(&car).Return(errors.EntityNotFound{})
+	mockEngine.EXPECT().Update(gomock.Any(), &engine).Return(nil)
+	mockCar.EXPECT().Update(gomock.Any(), &car).Return(errors.EntityNotFound{})
 
-	resp, err := s.Update(&car)
+	resp, err := s.Update(ctx, &car)
 
 	if !reflect.DeepEqual(err, errors.EntityNotFound{}) {
 		t.Errorf("\n[TEST] Failed \nDesc update successful\nGot %v\n Expected %v", err, errors.EntityNotFound{})
@@ -439,10 +448,10 @@ func TestService_Delete(t *testing.T) {
 
 	s, mockCar, mockEngine := initializeTest(t)
 
-	mockCar.EXPECT().Delete(id).Return(nil)
-	mockEngine.EXPECT().Delete(id).Return(nil)
+	mockCar.EXPECT().Delete(gomock.Any(), id).Return(nil)
+	mockEngine.EXPECT().Delete(gomock.Any(), id).Return(nil)
 
-	err = s.Delete(id)
+	err = s.Delete(ctx, id)
 
 	if err != nil {
 		t.Errorf("\n[TEST] Failed \nDesc delete success\nGot %v\n Expected nil", err)
@@ -457,9 +466,9 @@ func TestService_DeleteInvalidCar(t *testing.T) {
 
 	s, mockCar, _ := initializeTest(t)
 
-	mockCar.EXPECT().Delete(id).Return(errors.EntityNotFound{})
+	mockCar.EXPECT().Delete(gomock.Any(), id).Return(errors.EntityNotFound{})
 
-	err = s.Delete(id)
+	err = s.Delete(ctx, id)
 
 	if !reflect.DeepEqual(err, errors.EntityNotFound{}) {
 		t.Errorf("\n[TEST] Failed \nDesc update successful\nGot %v\n Expected %v", err, errors.EntityNotFound{})
@@ -474,10 +483,10 @@ func TestService_DeleteInvalidEngine(t *testing.T) {
 
 	s, mockCar, mockEngine := initializeTest(t)
 
-	mockCar.EXPECT().Delete(id).Return(nil)
-	mockEngine.EXPECT().Delete(id).Return(errors.EntityNotFound{})
+	mockCar.EXPECT().Delete(gomock.Any(), id).Return(nil)
+	mockEngine.EXPECT().Delete(gomock.Any(), id).Return(errors.EntityNotFound{})
 
-	err = s.Delete(id)
+	err = s.Delete(ctx, id)
 
 	if !reflect.DeepEqual(err, errors.EntityNotFound{}) {
 		t.Errorf("\n[TEST] Failed \nDesc update successful\nGot %v\n Expected %v", err, errors.EntityNotFound{})
@@ -515,6 +524,34 @@ func Test_checkCar(t *testing.T) {
 	}
 }
 
+func Test_checkCarCondition(t *testing.T) {
+	car := models.Car{Model: "X", ManufactureYear: 2015, Brand: "bmw", FuelType: types.Diesel}
+
+	cases := []struct {
+		desc      string
+		condition string
+		mileage   int
+		output    string
+		err       error
+	}{
+		{"defaults to new", "", 0, models.CarNew, nil},
+		{"used", models.CarUsed, 120000, models.CarUsed, nil},
+		{"unknown condition", "mint", 0, "mint", errors.InvalidParam{Param: []string{"condition"}}},
+		{"negative mileage", models.CarUsed, -1, models.CarUsed, errors.InvalidParam{Param: []string{"mileage"}}},
+	}
+
+	for i, tc := range cases {
+		input := car
+		input.Condition, input.Mileage = tc.condition, tc.mileage
+
+		err := checkCar(&input)
+
+		if !reflect.DeepEqual(err, tc.err) || input.Condition != tc.output {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v, %v", i, tc.desc, input.Condition, err, tc.output, tc.err)
+		}
+	}
+}
+
 func Test_checkEngine(t *testing.T) {
 	invalidEngine1 := models.Engine{Displacement: 10, NCylinder: 10, Range: 10}
 	invalidEngine2 := models.Engine{Displacement: 0, NCylinder: 0, Range: 0}
@@ -545,3 +582,66 @@ func Test_checkEngine(t *testing.T) {
 		}
 	}
 }
+
+func TestService_Forbidden(t *testing.T) {
+	id := uuid.New()
+	viewer := authz.WithPrincipal(context.Background(), &models.Principal{Roles: []string{string(authz.Viewer)}})
+	salesperson := authz.WithPrincipal(context.Background(), &models.Principal{Roles: []string{string(authz.Salesperson)}})
+	repriced := models.Car{ID: id, Model: "X", ManufactureYear: 2020, Brand: "BMW", Price: 100}
+
+	cases := []struct {
+		desc string
+		call func(s services.Car) error
+		err  error
+	}{
+		{"anonymous read", func(s services.Car) error {
+			_, err := s.GetByID(context.Background(), id)
+			return err
+		}, errors.Forbidden{Permission: string(authz.ReadCars)}},
+		{"viewer create", func(s services.Car) error {
+			_, err := s.Create(viewer, &repriced)
+			return err
+		}, errors.Forbidden{Permission: string(authz.CreateCars)}},
+		{"viewer list", func(s services.Car) error {
+			_, err := s.GetAll(viewer, filters.Car{Brand: "Aryan"})
+			return err
+		}, errors.InvalidParam{}},
+		{"salesperson delete", func(s services.Car) error {
+			return s.Delete(salesperson, id)
+		}, errors.Forbidden{Permission: string(authz.DeleteCars)}},
+		{"salesperson reprice", func(s services.Car) error {
+			_, err := s.Update(salesperson, &repriced)
+			return err
+		}, errors.Forbidden{Permission: string(authz.ChangePrices)}},
+	}
+
+	for i, tc := range cases {
+		s, mockCar, _ := initializeTest(t)
+
+		mockCar.EXPECT().GetByID(gomock.Any(), id).Return(models.Car{ID: id, Price: 50}, nil).AnyTimes()
+
+		err := tc.call(s)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed \nDesc %v\nGot %v\n Expected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestService_UpdateSamePrice(t *testing.T) {
+	id := uuid.New()
+	salesperson := authz.WithPrincipal(context.Background(), &models.Principal{Roles: []string{string(authz.Salesperson)}})
+	updated := models.Car{ID: id, Model: "X", ManufactureYear: 2020, Brand: "BMW", Price: 50}
+
+	s, mockCar, mockEngine := initializeTest(t)
+
+	mockCar.EXPECT().GetByID(gomock.Any(), id).Return(models.Car{ID: id, Price: 50}, nil)
+	mockEngine.EXPECT().Update(gomock.Any(), &updated.Engine).Return(nil)
+	mockCar.EXPECT().Update(gomock.Any(), &updated).Return(nil)
+
+	resp, err := s.Update(salesperson, &updated)
+
+	if err != nil || !reflect.DeepEqual(resp, &updated) {
+		t.Errorf("\n[TEST] Failed \nDesc update without price change\nGot %v, %v\n Expected %v", resp, err, &updated)
+	}
+}
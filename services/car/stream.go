@@ -0,0 +1,97 @@
+package car
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/amehrotra/car-dealership/authz"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/events"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+// streamBuffer is the number of messages a subscriber may fall behind before it is disconnected
+const streamBuffer = 64
+
+type stream struct {
+	hub *events.Hub
+}
+
+// NewStream returns a Stream of the messages the outbox relay publishes to hub
+func NewStream(hub *events.Hub) services.Stream {
+	return stream{hub: hub}
+}
+
+// Subscribe streams the events about cars of the brand of the filter, deletions only carry the id of the car and are
+// streamed whatever the brand. Given the id of the last event received, the events published since are sent first,
+// or a StreamReset message when they are no longer kept.
+func (s stream) Subscribe(ctx context.Context, filter filters.Car, lastEventID string) (<-chan models.Message, error) {
+	if err := authz.Check(ctx, authz.ReadCars); err != nil {
+		return nil, err
+	}
+
+	if filter.Brand != "" && checkBrand(filter.Brand) != nil {
+		return nil, errors.InvalidParam{Param: []string{"brand"}}
+	}
+
+	sub, missed, resumed := s.hub.Subscribe(lastEventID, streamBuffer)
+	out := make(chan models.Message)
+
+	go func() {
+		defer close(out)
+		defer sub.Close()
+
+		send := func(msg models.Message) bool {
+			if !matches(msg, filter.Brand) {
+				return true
+			}
+
+			select {
+			case out <- msg:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		if !resumed && !send(models.Message{Type: models.StreamReset}) {
+			return
+		}
+
+		for _, msg := range missed {
+			if !send(msg) {
+				return
+			}
+		}
+
+		for {
+			select {
+			case msg, ok := <-sub.C:
+				if !ok || !send(msg) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// matches reports whether the message is about a car of the brand
+func matches(msg models.Message, brand string) bool {
+	if brand == "" || msg.Type == models.StreamReset || msg.Type == models.CarDeleted {
+		return true
+	}
+
+	var event models.Event
+	if err := json.Unmarshal(msg.Payload, &event); err != nil || event.Car == nil {
+		return false
+	}
+
+	return strings.EqualFold(event.Car.Brand, brand)
+}
@@ -0,0 +1,95 @@
+package car
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/authz"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/events"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+)
+
+func message(t *testing.T, eventType, brand string) models.Message {
+	payload, err := json.Marshal(models.Event{Type: eventType, Car: &models.Car{ID: uuid.New(), Brand: brand}})
+	if err != nil {
+		t.Fatalf("error %s was not expected when marshaling the event", err)
+	}
+
+	return models.Message{ID: uuid.New(), Type: eventType, Payload: payload}
+}
+
+func TestStream_Subscribe(t *testing.T) {
+	hub := events.NewHub(10)
+	s := NewStream(hub)
+
+	kept := message(t, models.CarCreated, "BMW")
+	_ = hub.Publish(ctx, kept)
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	messages, err := s.Subscribe(streamCtx, filters.Car{Brand: "tesla"}, uuid.NewString())
+	if err != nil {
+		t.Fatalf("\n[TEST] Failed. Desc : subscribe\nGot %v\nExpected nil", err)
+	}
+
+	tesla := message(t, models.CarUpdated, "Tesla")
+	deleted := models.Message{ID: uuid.New(), Type: models.CarDeleted, Payload: []byte(`{"car":{"id":"1"}}`)}
+
+	_ = hub.Publish(ctx, message(t, models.CarUpdated, "BMW"))
+	_ = hub.Publish(ctx, tesla)
+	_ = hub.Publish(ctx, deleted)
+
+	// the last event id is unknown so a reset comes first, the bmw is filtered out and the deletion is kept
+	expected := []string{models.StreamReset, tesla.ID.String(), deleted.ID.String()}
+	got := make([]string, 0)
+
+	for range expected {
+		msg := <-messages
+		if msg.Type == models.StreamReset {
+			got = append(got, msg.Type)
+
+			continue
+		}
+
+		got = append(got, msg.ID.String())
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("\n[TEST] Failed. Desc : filtered stream\nGot %v\nExpected %v", got, expected)
+	}
+
+	cancel()
+
+	for range messages {
+	}
+}
+
+func TestStream_SubscribeErrors(t *testing.T) {
+	s := NewStream(events.NewHub(10))
+	viewer := authz.WithPrincipal(context.Background(), &models.Principal{})
+
+	cases := []struct {
+		desc   string
+		ctx    context.Context
+		filter filters.Car
+		err    error
+	}{
+		{"unknown brand", ctx, filters.Car{Brand: "fiat"}, errors.InvalidParam{Param: []string{"brand"}}},
+		{"missing permission", viewer, filters.Car{}, errors.Forbidden{Permission: string(authz.ReadCars)}},
+	}
+
+	for i, tc := range cases {
+		_, err := s.Subscribe(tc.ctx, tc.filter, "")
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package customer
+
+import (
+	"strings"
+
+	"github.com/amehrotra/car-dealership/models"
+)
+
+// visibleDigits is how many trailing characters of the phone and licence numbers stay readable
+const visibleDigits = 4
+
+// mask hides the personal details of the customer that a listing does not need, leaving enough to tell customers apart.
+// The street address is removed, the city and country are kept.
+func mask(customer *models.Customer) {
+	customer.Email = maskEmail(customer.Email)
+	customer.Phone = maskTail(customer.Phone)
+	customer.LicenceNumber = maskTail(customer.LicenceNumber)
+	customer.Address = models.Address{City: customer.Address.City, Country: customer.Address.Country}
+}
+
+// maskEmail keeps the first letter of the local part and the domain, j***@example.com
+func maskEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" {
+		return maskTail(email)
+	}
+
+	return local[:1] + "***@" + domain
+}
+
+// maskTail replaces all but the last visibleDigits characters with *
+func maskTail(value string) string {
+	if len(value) <= visibleDigits {
+		return strings.Repeat("*", len(value))
+	}
+
+	return strings.Repeat("*", len(value)-visibleDigits) + value[len(value)-visibleDigits:]
+}
@@ -0,0 +1,190 @@
+package customer
+
+import (
+	"context"
+	"log/slog"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/authz"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+const (
+	minPhoneDigits = 7
+	maxPhoneDigits = 15
+)
+
+type service struct {
+	store  stores.Customer
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+func New(store stores.Customer, logger *slog.Logger) services.Customer {
+	return service{store: store, logger: logger, now: time.Now}
+}
+
+// Create records a new customer with their email and phone normalized so that searches find them
+func (s service) Create(ctx context.Context, customer *models.Customer) (*models.Customer, error) {
+	if err := authz.Check(ctx, authz.ManageCustomers); err != nil {
+		return nil, err
+	}
+
+	if err := checkCustomer(customer); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+
+	customer.ID = uuid.New()
+	customer.CreatedAt = now
+	customer.UpdatedAt = now
+
+	if err := s.store.Create(ctx, customer); err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "customer created", "customer_id", customer.ID)
+
+	return customer, nil
+}
+
+// GetAll lists the customers matching the email and phone of the filter with their personal details masked
+func (s service) GetAll(ctx context.Context, filter filters.Customer) ([]models.Customer, error) {
+	if err := authz.Check(ctx, authz.ReadCustomers); err != nil {
+		return nil, err
+	}
+
+	filter.Email = normalizeEmail(filter.Email)
+	filter.Phone = normalizePhone(filter.Phone)
+
+	customers, err := s.store.GetAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range customers {
+		mask(&customers[i])
+	}
+
+	return customers, nil
+}
+
+// GetByID fetches the customer with all their details
+func (s service) GetByID(ctx context.Context, id uuid.UUID) (*models.Customer, error) {
+	if err := authz.Check(ctx, authz.ReadCustomers); err != nil {
+		return nil, err
+	}
+
+	customer, err := s.store.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &customer, nil
+}
+
+// Update replaces the details of the customer, keeping the time they were created
+func (s service) Update(ctx context.Context, customer *models.Customer) (*models.Customer, error) {
+	if err := authz.Check(ctx, authz.ManageCustomers); err != nil {
+		return nil, err
+	}
+
+	if err := checkCustomer(customer); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.store.GetByID(ctx, customer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	customer.CreatedAt = stored.CreatedAt
+	customer.UpdatedAt = s.now().UTC().Truncate(time.Second)
+
+	if err := s.store.Update(ctx, customer); err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "customer updated", "customer_id", customer.ID)
+
+	return customer, nil
+}
+
+// Delete removes the customer
+func (s service) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := authz.Check(ctx, authz.DeleteCustomers); err != nil {
+		return err
+	}
+
+	if err := s.store.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "customer deleted", "customer_id", id)
+
+	return nil
+}
+
+// checkCustomer normalizes the contact details of the customer and validates they can be reached by email or phone
+func checkCustomer(customer *models.Customer) error {
+	var params []string
+
+	customer.Name = strings.TrimSpace(customer.Name)
+	customer.Email = normalizeEmail(customer.Email)
+	customer.Phone = normalizePhone(customer.Phone)
+	customer.LicenceNumber = strings.ToUpper(strings.TrimSpace(customer.LicenceNumber))
+
+	if customer.Name == "" {
+		params = append(params, "name")
+	}
+
+	if customer.Email == "" && customer.Phone == "" {
+		params = append(params, "email", "phone")
+	}
+
+	if customer.Email != "" {
+		if addr, err := mail.ParseAddress(customer.Email); err != nil || addr.Address != customer.Email {
+			params = append(params, "email")
+		}
+	}
+
+	if digits := strings.TrimPrefix(customer.Phone, "+"); customer.Phone != "" &&
+		(len(digits) < minPhoneDigits || len(digits) > maxPhoneDigits) {
+		params = append(params, "phone")
+	}
+
+	if len(params) > 0 {
+		return errors.InvalidParam{Param: params}
+	}
+
+	return nil
+}
+
+// normalizeEmail trims and lower-cases the email
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// normalizePhone keeps the digits of the phone number and its leading +, dropping spaces, dashes and brackets
+func normalizePhone(phone string) string {
+	phone = strings.TrimSpace(phone)
+
+	var b strings.Builder
+
+	for i, r := range phone {
+		if (r >= '0' && r <= '9') || (r == '+' && i == 0) {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,226 @@
+package customer
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/authz"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	// principal allowed to perform every operation
+	ctx = authz.WithPrincipal(context.Background(), &models.Principal{Roles: []string{string(authz.Admin)}})
+	now = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	id  = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+)
+
+func initializeTest(t *testing.T) (service, *stores.MockCustomer) {
+	ctrl := gomock.NewController(t)
+
+	mockStore := stores.NewMockCustomer(ctrl)
+
+	return service{store: mockStore, logger: logging.Discard(), now: func() time.Time { return now }}, mockStore
+}
+
+func customer() models.Customer {
+	return models.Customer{ID: id, Name: "Jane Doe", Email: "jane@example.com", Phone: "+14155550123",
+		Address:       models.Address{Line1: "1 Main St", City: "Springfield", PostalCode: "12345", Country: "US"},
+		LicenceNumber: "D1234567", CreatedAt: now, UpdatedAt: now}
+}
+
+func TestService_Create(t *testing.T) {
+	s, mockStore := initializeTest(t)
+
+	mockStore.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	input := models.Customer{Name: " Jane Doe ", Email: " Jane@Example.com", Phone: "+1 (415) 555-0123", LicenceNumber: "d1234567"}
+
+	output, err := s.Create(ctx, &input)
+	if err != nil {
+		t.Fatalf("\n[TEST] Failed. Desc : create\nGot %v\nExpected nil", err)
+	}
+
+	if output.ID == uuid.Nil || output.Name != "Jane Doe" || output.Email != "jane@example.com" || output.Phone != "+14155550123" ||
+		output.LicenceNumber != "D1234567" || !output.CreatedAt.Equal(now) || !output.UpdatedAt.Equal(now) {
+		t.Errorf("\n[TEST] Failed. Desc : create\nGot %v\nExpected a normalized customer", output)
+	}
+}
+
+func TestService_CreateErrors(t *testing.T) {
+	cases := []struct {
+		desc     string
+		customer models.Customer
+		err      error
+	}{
+		{"missing name", models.Customer{Email: "jane@example.com"}, errors.InvalidParam{Param: []string{"name"}}},
+		{"no contact details", models.Customer{Name: "Jane"}, errors.InvalidParam{Param: []string{"email", "phone"}}},
+		{"invalid email", models.Customer{Name: "Jane", Email: "jane"}, errors.InvalidParam{Param: []string{"email"}}},
+		{"named email", models.Customer{Name: "Jane", Email: "Jane <jane@example.com>"}, errors.InvalidParam{Param: []string{"email"}}},
+		{"short phone", models.Customer{Name: "Jane", Phone: "555-01"}, errors.InvalidParam{Param: []string{"phone"}}},
+	}
+
+	for i, tc := range cases {
+		s, _ := initializeTest(t)
+
+		_, err := s.Create(ctx, &tc.customer)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestService_GetAll(t *testing.T) {
+	s, mockStore := initializeTest(t)
+
+	mockStore.EXPECT().GetAll(gomock.Any(), filters.Customer{Email: "jane@example.com", Phone: "+14155550123"}).
+		Return([]models.Customer{customer()}, nil)
+
+	output, err := s.GetAll(ctx, filters.Customer{Email: "JANE@example.com ", Phone: "+1 415-555-0123"})
+
+	masked := customer()
+	masked.Email = "j***@example.com"
+	masked.Phone = "********0123"
+	masked.LicenceNumber = "****4567"
+	masked.Address = models.Address{City: "Springfield", Country: "US"}
+
+	if err != nil || !reflect.DeepEqual(output, []models.Customer{masked}) {
+		t.Errorf("\n[TEST] Failed. Desc : masked list\nGot %v, %v\nExpected %v", output, err, masked)
+	}
+}
+
+func TestService_GetByID(t *testing.T) {
+	s, mockStore := initializeTest(t)
+
+	mockStore.EXPECT().GetByID(gomock.Any(), id).Return(customer(), nil)
+	mockStore.EXPECT().GetByID(gomock.Any(), id).Return(models.Customer{}, errors.EntityNotFound{Entity: "customer", ID: id.String()})
+
+	expected := customer()
+
+	cases := []struct {
+		desc   string
+		output *models.Customer
+		err    error
+	}{
+		{"unmasked customer", &expected, nil},
+		{"not found", nil, errors.EntityNotFound{Entity: "customer", ID: id.String()}},
+	}
+
+	for i, tc := range cases {
+		output, err := s.GetByID(ctx, id)
+
+		if !reflect.DeepEqual(err, tc.err) || !reflect.DeepEqual(output, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v, %v", i, tc.desc, output, err, tc.output, tc.err)
+		}
+	}
+}
+
+func TestService_Update(t *testing.T) {
+	s, mockStore := initializeTest(t)
+
+	created := now.Add(-time.Hour)
+	stored := customer()
+	stored.CreatedAt = created
+
+	mockStore.EXPECT().GetByID(gomock.Any(), id).Return(stored, nil)
+	mockStore.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+	mockStore.EXPECT().GetByID(gomock.Any(), id).Return(models.Customer{}, errors.EntityNotFound{Entity: "customer", ID: id.String()})
+
+	input := customer()
+	input.CreatedAt = time.Time{}
+
+	output, err := s.Update(ctx, &input)
+	if err != nil || !output.CreatedAt.Equal(created) || !output.UpdatedAt.Equal(now) {
+		t.Errorf("\n[TEST] Failed. Desc : update\nGot %v, %v\nExpected the creation time kept", output, err)
+	}
+
+	missing := customer()
+
+	_, err = s.Update(ctx, &missing)
+	if !reflect.DeepEqual(err, errors.EntityNotFound{Entity: "customer", ID: id.String()}) {
+		t.Errorf("\n[TEST] Failed. Desc : update missing customer\nGot %v\nExpected not found", err)
+	}
+}
+
+func TestService_Delete(t *testing.T) {
+	s, mockStore := initializeTest(t)
+
+	mockStore.EXPECT().Delete(gomock.Any(), id).Return(nil)
+
+	if err := s.Delete(ctx, id); err != nil {
+		t.Errorf("\n[TEST] Failed. Desc : delete\nGot %v\nExpected nil", err)
+	}
+}
+
+func TestService_Forbidden(t *testing.T) {
+	viewer := authz.WithPrincipal(context.Background(), &models.Principal{Roles: []string{string(authz.Viewer)}})
+	salesperson := authz.WithPrincipal(context.Background(), &models.Principal{Roles: []string{string(authz.Salesperson)}})
+
+	cases := []struct {
+		desc string
+		call func(s service) error
+		err  error
+	}{
+		{"anonymous read", func(s service) error {
+			_, err := s.GetByID(context.Background(), id)
+			return err
+		}, errors.Forbidden{Permission: string(authz.ReadCustomers)}},
+		{"viewer list", func(s service) error {
+			_, err := s.GetAll(viewer, filters.Customer{})
+			return err
+		}, errors.Forbidden{Permission: string(authz.ReadCustomers)}},
+		{"viewer create", func(s service) error {
+			input := customer()
+			_, err := s.Create(viewer, &input)
+			return err
+		}, errors.Forbidden{Permission: string(authz.ManageCustomers)}},
+		{"viewer update", func(s service) error {
+			input := customer()
+			_, err := s.Update(viewer, &input)
+			return err
+		}, errors.Forbidden{Permission: string(authz.ManageCustomers)}},
+		{"salesperson delete", func(s service) error {
+			return s.Delete(salesperson, id)
+		}, errors.Forbidden{Permission: string(authz.DeleteCustomers)}},
+	}
+
+	for i, tc := range cases {
+		s, _ := initializeTest(t)
+
+		if err := tc.call(s); !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestMask(t *testing.T) {
+	cases := []struct {
+		desc   string
+		mask   func(string) string
+		value  string
+		masked string
+	}{
+		{"email", maskEmail, "jane@example.com", "j***@example.com"},
+		{"email without local part", maskEmail, "@example.com", "********.com"},
+		{"short value", maskTail, "123", "***"},
+		{"phone", maskTail, "+14155550123", "********0123"},
+		{"empty", maskTail, "", ""},
+	}
+
+	for i, tc := range cases {
+		if masked := tc.mask(tc.value); masked != tc.masked {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, masked, tc.masked)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 
 	"github.com/amehrotra/car-dealership/filters"
@@ -8,9 +10,88 @@ import (
 )
 
 type Car interface {
-	Create(car *models.Car) (*models.Car, error)
-	GetAll(filter filters.Car) ([]models.Car, error)
-	GetByID(id uuid.UUID) (*models.Car, error)
-	Update(car *models.Car) (*models.Car, error)
-	Delete(id uuid.UUID) error
+	Create(ctx context.Context, car *models.Car) (*models.Car, error)
+	GetAll(ctx context.Context, filter filters.Car) ([]models.Car, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Car, error)
+	Update(ctx context.Context, car *models.Car) (*models.Car, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Import(ctx context.Context, rows []models.ImportRow, opts models.ImportOptions) (*models.ImportResult, error)
+	Export(ctx context.Context, filter filters.Car, fn func(car *models.Car) error) error
+	Batch(ctx context.Context, ops []models.BatchOperation, opts models.BatchOptions) ([]models.BatchResult, error)
+}
+
+// Stream pushes the changes to the inventory as they are published, the channel is closed once ctx is done
+// or the subscriber falls behind
+type Stream interface {
+	Subscribe(ctx context.Context, filter filters.Car, lastEventID string) (<-chan models.Message, error)
+}
+
+type APIKey interface {
+	Create(ctx context.Context, key *models.APIKey) (*models.APIKey, error)
+	GetAll(ctx context.Context) ([]models.APIKey, error)
+	Rotate(ctx context.Context, id uuid.UUID) (*models.APIKey, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+	Authenticate(ctx context.Context, key string) (*models.Principal, error)
+}
+
+// Emitter reports changes to the inventory
+type Emitter interface {
+	Emit(ctx context.Context, event *models.Event) error
+}
+
+// Customer masks the personal details of the customers it lists
+type Customer interface {
+	Create(ctx context.Context, customer *models.Customer) (*models.Customer, error)
+	GetAll(ctx context.Context, filter filters.Customer) ([]models.Customer, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Customer, error)
+	Update(ctx context.Context, customer *models.Customer) (*models.Customer, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// Order sells a car to a customer, a car is only sold by one order at a time
+type Order interface {
+	Create(ctx context.Context, order *models.Order) (*models.Order, error)
+	GetAll(ctx context.Context, filter filters.Order) ([]models.Order, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Order, error)
+	Update(ctx context.Context, order *models.Order) (*models.Order, error)
+}
+
+// Reservation holds cars for customers, a held car can neither be reserved again nor sold to someone else
+type Reservation interface {
+	Create(ctx context.Context, reservation *models.Reservation) (*models.Reservation, error)
+	GetActive(ctx context.Context, carID uuid.UUID) (*models.Reservation, error)
+	Cancel(ctx context.Context, carID uuid.UUID) error
+}
+
+// TestDrive books cars and salespeople for test drives, neither is booked twice for the same time
+type TestDrive interface {
+	Create(ctx context.Context, drive *models.TestDrive) (*models.TestDrive, error)
+	GetAll(ctx context.Context, filter filters.TestDrive) ([]models.TestDrive, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.TestDrive, error)
+	Update(ctx context.Context, drive *models.TestDrive) (*models.TestDrive, error)
+	Calendar(ctx context.Context, salesperson string) ([]models.TestDrive, error)
+}
+
+// TradeIn records the vehicles customers trade in along with their appraisal and its approval by a manager, approved
+// trade-ins become used cars of the inventory
+type TradeIn interface {
+	Create(ctx context.Context, tradeIn *models.TradeIn) (*models.TradeIn, error)
+	GetAll(ctx context.Context, filter filters.TradeIn) ([]models.TradeIn, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.TradeIn, error)
+	Update(ctx context.Context, tradeIn *models.TradeIn) (*models.TradeIn, error)
+	Appraise(ctx context.Context, id uuid.UUID, appraisal *models.Appraisal) (*models.TradeIn, error)
+	Review(ctx context.Context, id uuid.UUID, approve bool) (*models.TradeIn, error)
+	Convert(ctx context.Context, id uuid.UUID, car *models.Car) (*models.Car, error)
+}
+
+// Webhook implements events.Publisher, publishing a message queues its deliveries
+type Webhook interface {
+	Publish(ctx context.Context, msg models.Message) error
+	Create(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error)
+	GetAll(ctx context.Context) ([]models.Webhook, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Webhook, error)
+	Update(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetDeliveries(ctx context.Context, webhookID uuid.UUID, status string) ([]models.Delivery, error)
+	Redeliver(ctx context.Context, webhookID, deliveryID uuid.UUID) (*models.Delivery, error)
 }
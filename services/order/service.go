@@ -0,0 +1,313 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	goError "errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/authz"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+type service struct {
+	orders       stores.Order
+	cars         stores.Car
+	customers    stores.Customer
+	reservations stores.Reservation
+	tx           stores.Transactor
+	logger       *slog.Logger
+	now          func() time.Time
+}
+
+func New(orders stores.Order, cars stores.Car, customers stores.Customer, reservations stores.Reservation, tx stores.Transactor,
+	logger *slog.Logger) services.Order {
+	return service{orders: orders, cars: cars, customers: customers, reservations: reservations, tx: tx, logger: logger,
+		now: time.Now}
+}
+
+// nolint:gochecknoglobals // read only map of the statuses an order may move to from each status
+var transitions = map[string][]string{
+	models.OrderDraft:     {models.OrderConfirmed, models.OrderCancelled},
+	models.OrderConfirmed: {models.OrderPaid, models.OrderCancelled},
+	models.OrderPaid:      {models.OrderDelivered, models.OrderCancelled},
+}
+
+// Create drafts the sale of the car to the customer, the price defaults to the price of the car.
+// The car is locked for the transaction so that concurrent orders of the same car are placed one after the other
+// and only the first one succeeds. A reservation of the car by the customer is converted into the order, its deposit
+// becoming the deposit of the order unless the order has one.
+func (s service) Create(ctx context.Context, order *models.Order) (*models.Order, error) {
+	if err := authz.Check(ctx, authz.ManageOrders); err != nil {
+		return nil, err
+	}
+
+	if order.Status != "" && order.Status != models.OrderDraft {
+		return nil, errors.InvalidParam{Param: []string{"status"}}
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+
+	order.ID = uuid.New()
+	order.Status = models.OrderDraft
+	order.CreatedAt = now
+	order.UpdatedAt = now
+
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		car, err := s.cars.GetByID(ctx, order.CarID)
+		if err != nil {
+			return carError(err, order.CarID)
+		}
+
+		if err := s.cars.Lock(ctx, order.CarID); err != nil {
+			return err
+		}
+
+		if _, err := s.customers.GetByID(ctx, order.CustomerID); err != nil {
+			return err
+		}
+
+		held, err := s.checkAvailable(ctx, order, now)
+		if err != nil {
+			return err
+		}
+
+		if order.Price == 0 {
+			order.Price = car.Price
+		}
+
+		if held != nil && order.Deposit == 0 {
+			order.Deposit = held.Deposit
+		}
+
+		if err := checkAmounts(order); err != nil {
+			return err
+		}
+
+		if err := s.orders.Create(ctx, order); err != nil {
+			return err
+		}
+
+		if held == nil {
+			return nil
+		}
+
+		held.Status = models.ReservationConverted
+
+		return s.reservations.Update(ctx, held)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "order created", "order_id", order.ID, "car_id", order.CarID, "customer_id", order.CustomerID)
+
+	return order, nil
+}
+
+// GetAll lists the orders of the car, customer and status of the filter
+func (s service) GetAll(ctx context.Context, filter filters.Order) ([]models.Order, error) {
+	if err := authz.Check(ctx, authz.ReadOrders); err != nil {
+		return nil, err
+	}
+
+	switch filter.Status {
+	case "", models.OrderDraft, models.OrderConfirmed, models.OrderPaid, models.OrderDelivered, models.OrderCancelled:
+	default:
+		return nil, errors.InvalidParam{Param: []string{"status"}}
+	}
+
+	return s.orders.GetAll(ctx, filter)
+}
+
+// GetByID fetches the order
+func (s service) GetByID(ctx context.Context, id uuid.UUID) (*models.Order, error) {
+	if err := authz.Check(ctx, authz.ReadOrders); err != nil {
+		return nil, err
+	}
+
+	order, err := s.orders.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// Update moves the order along its statuses, keeping its status and amounts when they are left out. The amounts can
+// only change while it is a draft.
+// The order is locked for the transaction so that concurrent updates are applied one after the other, each checked
+// against the status the previous one left. Cancelling an order frees its car for another order, the car and the
+// customer of an order never change.
+func (s service) Update(ctx context.Context, order *models.Order) (*models.Order, error) {
+	if err := authz.Check(ctx, authz.ManageOrders); err != nil {
+		return nil, err
+	}
+
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		stored, err := s.orders.Lock(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+
+		if final(stored.Status) {
+			return errors.Conflict{Entity: "order", ID: order.ID.String(), Reason: "is " + stored.Status}
+		}
+
+		keep(order, &stored)
+
+		if order.Status != stored.Status && !allowed(stored.Status, order.Status) {
+			return errors.InvalidParam{Param: []string{"status"}}
+		}
+
+		if stored.Status != models.OrderDraft && amountsChanged(order, &stored) {
+			return errors.Conflict{Entity: "order", ID: order.ID.String(), Reason: "is " + stored.Status + ", its amounts are final"}
+		}
+
+		order.UpdatedAt = s.now().UTC().Truncate(time.Second)
+
+		if err := checkAmounts(order); err != nil {
+			return err
+		}
+
+		return s.orders.Update(ctx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "order updated", "order_id", order.ID, "status", order.Status)
+
+	return order, nil
+}
+
+// checkAvailable returns errors.Conflict when an order of the car that is not cancelled exists or another customer
+// holds it, and the reservation of the customer of the order when they hold it. It is called with the car locked so
+// that no other order or reservation is placed in between.
+func (s service) checkAvailable(ctx context.Context, order *models.Order, now time.Time) (*models.Reservation, error) {
+	carID := order.CarID
+
+	orders, err := s.orders.GetAll(ctx, filters.Order{CarID: carID})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range orders {
+		if orders[i].Status != models.OrderCancelled {
+			return nil, errors.Conflict{Entity: "car", ID: carID.String(), Reason: "is already on order " + orders[i].ID.String()}
+		}
+	}
+
+	held, err := s.reservations.GetActive(ctx, carID, now)
+
+	var notFound errors.EntityNotFound
+
+	switch {
+	case goError.As(err, &notFound):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	case held.CustomerID != order.CustomerID:
+		return nil, errors.Conflict{Entity: "car", ID: carID.String(), Reason: "is reserved until " + held.ExpiresAt.Format(time.RFC3339)}
+	}
+
+	return &held, nil
+}
+
+// checkAmounts validates the amounts of the order and computes its total, the price less the trade-in credit plus
+// the taxes and fees
+func checkAmounts(order *models.Order) error {
+	var params []string
+
+	if order.Price <= 0 {
+		params = append(params, "price")
+	}
+
+	amounts := []struct {
+		param string
+		value int64
+	}{{"taxes", order.Taxes}, {"fees", order.Fees}, {"tradeInCredit", order.TradeInCredit}, {"deposit", order.Deposit}}
+
+	for _, amount := range amounts {
+		if amount.value < 0 {
+			params = append(params, amount.param)
+		}
+	}
+
+	if len(params) > 0 {
+		return errors.InvalidParam{Param: params}
+	}
+
+	order.Total = order.Price + order.Taxes + order.Fees - order.TradeInCredit
+
+	switch {
+	case order.Total < 0:
+		return errors.InvalidParam{Param: []string{"tradeInCredit"}}
+	case order.Deposit > order.Total:
+		return errors.InvalidParam{Param: []string{"deposit"}}
+	}
+
+	return nil
+}
+
+// keep fills the fields of the update that are left out or never change from the stored order
+func keep(order, stored *models.Order) {
+	order.CarID = stored.CarID
+	order.CustomerID = stored.CustomerID
+	order.CreatedAt = stored.CreatedAt
+
+	if order.Status == "" {
+		order.Status = stored.Status
+	}
+
+	amounts := []struct {
+		value  *int64
+		stored int64
+	}{{&order.Price, stored.Price}, {&order.Taxes, stored.Taxes}, {&order.Fees, stored.Fees},
+		{&order.TradeInCredit, stored.TradeInCredit}, {&order.Deposit, stored.Deposit}}
+
+	for _, amount := range amounts {
+		if *amount.value == 0 {
+			*amount.value = amount.stored
+		}
+	}
+}
+
+// amountsChanged reports whether the update changes any of the amounts of the stored order
+func amountsChanged(order, stored *models.Order) bool {
+	return order.Price != stored.Price || order.Taxes != stored.Taxes || order.Fees != stored.Fees ||
+		order.TradeInCredit != stored.TradeInCredit || order.Deposit != stored.Deposit
+}
+
+// allowed reports whether an order may move from one status to the other
+func allowed(from, to string) bool {
+	for _, status := range transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// final reports whether the status is one orders no longer leave
+func final(status string) bool {
+	return status == models.OrderDelivered || status == models.OrderCancelled
+}
+
+// carError reports a car the store did not find as errors.EntityNotFound
+func carError(err error, id uuid.UUID) error {
+	var dbErr errors.DB
+	if goError.As(err, &dbErr) && goError.Is(dbErr.Err, sql.ErrNoRows) {
+		return errors.EntityNotFound{Entity: "car", ID: id.String()}
+	}
+
+	return err
+}
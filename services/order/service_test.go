@@ -0,0 +1,322 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/authz"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	// principal allowed to perform every operation
+	ctx        = authz.WithPrincipal(context.Background(), &models.Principal{Roles: []string{string(authz.Admin)}})
+	now        = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	id         = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+	carID      = uuid.MustParse("3b7e4a1c-2b0e-4d55-a3a4-6f1f1f0c9a10")
+	customerID = uuid.MustParse("c4d0f7a2-5e1b-4f0e-9d57-0a2b8e6d3c21")
+	notHeld    = errors.EntityNotFound{Entity: "reservation", ID: carID.String()}
+)
+
+type mocks struct {
+	orders       *stores.MockOrder
+	cars         *stores.MockCar
+	customers    *stores.MockCustomer
+	reservations *stores.MockReservation
+}
+
+func initializeTest(t *testing.T) (service, mocks) {
+	ctrl := gomock.NewController(t)
+
+	m := mocks{orders: stores.NewMockOrder(ctrl), cars: stores.NewMockCar(ctrl), customers: stores.NewMockCustomer(ctrl),
+		reservations: stores.NewMockReservation(ctrl)}
+
+	tx := stores.NewMockTransactor(ctrl)
+	tx.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).AnyTimes()
+
+	return service{orders: m.orders, cars: m.cars, customers: m.customers, reservations: m.reservations, tx: tx,
+		logger: logging.Discard(), now: func() time.Time { return now }}, m
+}
+
+func TestService_Create(t *testing.T) {
+	s, m := initializeTest(t)
+
+	m.cars.EXPECT().GetByID(gomock.Any(), carID).Return(models.Car{ID: carID, Price: 3000000}, nil)
+	m.cars.EXPECT().Lock(gomock.Any(), carID).Return(nil)
+	m.customers.EXPECT().GetByID(gomock.Any(), customerID).Return(models.Customer{ID: customerID}, nil)
+	m.orders.EXPECT().GetAll(gomock.Any(), filters.Order{CarID: carID}).
+		Return([]models.Order{{ID: uuid.New(), CarID: carID, Status: models.OrderCancelled}}, nil)
+	m.reservations.EXPECT().GetActive(gomock.Any(), carID, now).Return(models.Reservation{}, notHeld)
+	m.orders.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	order, err := s.Create(ctx, &models.Order{CarID: carID, CustomerID: customerID, Taxes: 300000, TradeInCredit: 500000,
+		Deposit: 100000})
+	if err != nil {
+		t.Fatalf("\n[TEST] Failed. Desc : create\nGot %v\nExpected nil", err)
+	}
+
+	if order.ID == uuid.Nil || order.Status != models.OrderDraft || order.Price != 3000000 || order.Total != 2800000 ||
+		!order.CreatedAt.Equal(now) {
+		t.Errorf("\n[TEST] Failed. Desc : create\nGot %v\nExpected a draft at the price of the car", order)
+	}
+}
+
+func TestService_CreateReserved(t *testing.T) {
+	s, m := initializeTest(t)
+
+	held := models.Reservation{ID: id, CarID: carID, CustomerID: customerID, Deposit: 50000, Status: models.ReservationActive}
+	converted := held
+	converted.Status = models.ReservationConverted
+
+	m.cars.EXPECT().GetByID(gomock.Any(), carID).Return(models.Car{ID: carID, Price: 3000000}, nil)
+	m.cars.EXPECT().Lock(gomock.Any(), carID).Return(nil)
+	m.customers.EXPECT().GetByID(gomock.Any(), customerID).Return(models.Customer{ID: customerID}, nil)
+	m.orders.EXPECT().GetAll(gomock.Any(), filters.Order{CarID: carID}).Return([]models.Order{}, nil)
+	m.reservations.EXPECT().GetActive(gomock.Any(), carID, now).Return(held, nil)
+	m.orders.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	m.reservations.EXPECT().Update(gomock.Any(), &converted).Return(nil)
+
+	order, err := s.Create(ctx, &models.Order{CarID: carID, CustomerID: customerID})
+	if err != nil || order.Deposit != 50000 {
+		t.Errorf("\n[TEST] Failed. Desc : order of the reserving customer\nGot %v, %v\nExpected the deposit of the reservation", order, err)
+	}
+}
+
+func TestService_CreateErrors(t *testing.T) {
+	otherID := uuid.New()
+	expiresAt := now.Add(time.Hour)
+
+	cases := []struct {
+		desc  string
+		order models.Order
+		mock  func(m mocks)
+		err   error
+	}{
+		{"not a draft", models.Order{CarID: carID, CustomerID: customerID, Status: models.OrderPaid}, func(m mocks) {},
+			errors.InvalidParam{Param: []string{"status"}}},
+		{"missing car", models.Order{CarID: carID, CustomerID: customerID}, func(m mocks) {
+			m.cars.EXPECT().GetByID(gomock.Any(), carID).Return(models.Car{}, errors.DB{Err: sql.ErrNoRows})
+		}, errors.EntityNotFound{Entity: "car", ID: carID.String()}},
+		{"missing customer", models.Order{CarID: carID, CustomerID: customerID}, func(m mocks) {
+			m.cars.EXPECT().GetByID(gomock.Any(), carID).Return(models.Car{ID: carID, Price: 100}, nil)
+			m.cars.EXPECT().Lock(gomock.Any(), carID).Return(nil)
+			m.customers.EXPECT().GetByID(gomock.Any(), customerID).
+				Return(models.Customer{}, errors.EntityNotFound{Entity: "customer", ID: customerID.String()})
+		}, errors.EntityNotFound{Entity: "customer", ID: customerID.String()}},
+		{"car on another order", models.Order{CarID: carID, CustomerID: customerID}, func(m mocks) {
+			m.cars.EXPECT().GetByID(gomock.Any(), carID).Return(models.Car{ID: carID, Price: 100}, nil)
+			m.cars.EXPECT().Lock(gomock.Any(), carID).Return(nil)
+			m.customers.EXPECT().GetByID(gomock.Any(), customerID).Return(models.Customer{ID: customerID}, nil)
+			m.orders.EXPECT().GetAll(gomock.Any(), filters.Order{CarID: carID}).
+				Return([]models.Order{{ID: otherID, CarID: carID, Status: models.OrderConfirmed}}, nil)
+		}, errors.Conflict{Entity: "car", ID: carID.String(), Reason: "is already on order " + otherID.String()}},
+		{"unpriced car", models.Order{CarID: carID, CustomerID: customerID, Fees: -1}, func(m mocks) {
+			m.cars.EXPECT().GetByID(gomock.Any(), carID).Return(models.Car{ID: carID}, nil)
+			m.cars.EXPECT().Lock(gomock.Any(), carID).Return(nil)
+			m.customers.EXPECT().GetByID(gomock.Any(), customerID).Return(models.Customer{ID: customerID}, nil)
+			m.orders.EXPECT().GetAll(gomock.Any(), filters.Order{CarID: carID}).Return([]models.Order{}, nil)
+			m.reservations.EXPECT().GetActive(gomock.Any(), carID, now).Return(models.Reservation{}, notHeld)
+		}, errors.InvalidParam{Param: []string{"price", "fees"}}},
+		{"reserved by another customer", models.Order{CarID: carID, CustomerID: customerID}, func(m mocks) {
+			m.cars.EXPECT().GetByID(gomock.Any(), carID).Return(models.Car{ID: carID, Price: 100}, nil)
+			m.cars.EXPECT().Lock(gomock.Any(), carID).Return(nil)
+			m.customers.EXPECT().GetByID(gomock.Any(), customerID).Return(models.Customer{ID: customerID}, nil)
+			m.orders.EXPECT().GetAll(gomock.Any(), filters.Order{CarID: carID}).Return([]models.Order{}, nil)
+			m.reservations.EXPECT().GetActive(gomock.Any(), carID, now).
+				Return(models.Reservation{CarID: carID, CustomerID: otherID, ExpiresAt: expiresAt}, nil)
+		}, errors.Conflict{Entity: "car", ID: carID.String(), Reason: "is reserved until 2022-01-01T01:00:00Z"}},
+	}
+
+	for i, tc := range cases {
+		s, m := initializeTest(t)
+		tc.mock(m)
+
+		_, err := s.Create(ctx, &tc.order)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestCheckAmounts(t *testing.T) {
+	cases := []struct {
+		desc  string
+		order models.Order
+		total int64
+		err   error
+	}{
+		{"total", models.Order{Price: 1000, Taxes: 100, Fees: 10, TradeInCredit: 500, Deposit: 610}, 610, nil},
+		{"credit above the price", models.Order{Price: 1000, TradeInCredit: 1001}, -1, errors.InvalidParam{Param: []string{"tradeInCredit"}}},
+		{"deposit above the total", models.Order{Price: 1000, Deposit: 1001}, 1000, errors.InvalidParam{Param: []string{"deposit"}}},
+		{"negative amounts", models.Order{Price: 1000, Taxes: -1, Deposit: -1}, 0, errors.InvalidParam{Param: []string{"taxes", "deposit"}}},
+	}
+
+	for i, tc := range cases {
+		err := checkAmounts(&tc.order)
+
+		if !reflect.DeepEqual(err, tc.err) || tc.order.Total != tc.total {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v, %v", i, tc.desc, tc.order.Total, err, tc.total, tc.err)
+		}
+	}
+}
+
+func TestService_Update(t *testing.T) {
+	draft := models.Order{ID: id, CarID: carID, CustomerID: customerID, Price: 1000, Total: 1000, Status: models.OrderDraft,
+		CreatedAt: now.Add(-time.Hour)}
+	confirmed := draft
+	confirmed.Status = models.OrderConfirmed
+	delivered := draft
+	delivered.Status = models.OrderDelivered
+
+	cases := []struct {
+		desc   string
+		stored models.Order
+		input  models.Order
+		update bool
+		err    error
+	}{
+		{"confirm", draft, models.Order{ID: id, Price: 900, Status: models.OrderConfirmed}, true, nil},
+		{"keep the status", draft, models.Order{ID: id, Price: 900}, true, nil},
+		{"cancel", confirmed, models.Order{ID: id, Price: 1000, Status: models.OrderCancelled}, true, nil},
+		{"status only", confirmed, models.Order{ID: id, Status: models.OrderPaid}, true, nil},
+		{"skip a status", draft, models.Order{ID: id, Price: 1000, Status: models.OrderDelivered}, false,
+			errors.InvalidParam{Param: []string{"status"}}},
+		{"unknown status", draft, models.Order{ID: id, Price: 1000, Status: "sold"}, false, errors.InvalidParam{Param: []string{"status"}}},
+		{"change confirmed amounts", confirmed, models.Order{ID: id, Price: 900, Status: models.OrderPaid}, false,
+			errors.Conflict{Entity: "order", ID: id.String(), Reason: "is confirmed, its amounts are final"}},
+		{"delivered", delivered, models.Order{ID: id, Price: 1000, Status: models.OrderCancelled}, false,
+			errors.Conflict{Entity: "order", ID: id.String(), Reason: "is delivered"}},
+	}
+
+	for i, tc := range cases {
+		s, m := initializeTest(t)
+
+		m.orders.EXPECT().Lock(gomock.Any(), id).Return(tc.stored, nil)
+
+		if tc.update {
+			m.orders.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+		}
+
+		order, err := s.Update(ctx, &tc.input)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+
+			continue
+		}
+
+		if err == nil && (order.CarID != carID || order.CustomerID != customerID || !order.CreatedAt.Equal(tc.stored.CreatedAt) ||
+			order.Total != order.Price || !order.UpdatedAt.Equal(now)) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected the car, customer and creation time kept", i, tc.desc, order)
+		}
+	}
+}
+
+func TestService_UpdateLockError(t *testing.T) {
+	s, m := initializeTest(t)
+
+	notFound := errors.EntityNotFound{Entity: "order", ID: id.String()}
+
+	m.orders.EXPECT().Lock(gomock.Any(), id).Return(models.Order{}, notFound)
+
+	order, err := s.Update(ctx, &models.Order{ID: id, Status: models.OrderConfirmed})
+
+	if order != nil || !reflect.DeepEqual(err, notFound) {
+		t.Errorf("\n[TEST] Failed. Desc : lock error\nGot %v, %v\nExpected %v", order, err, notFound)
+	}
+}
+
+func TestKeep(t *testing.T) {
+	stored := models.Order{ID: id, CarID: carID, CustomerID: customerID, Price: 1000, Taxes: 80, Fees: 20, TradeInCredit: 300,
+		Deposit: 100, Status: models.OrderConfirmed, CreatedAt: now}
+
+	cases := []struct {
+		desc   string
+		input  models.Order
+		output models.Order
+	}{
+		{"left out", models.Order{ID: id}, stored},
+		{"given", models.Order{ID: id, Price: 900, Taxes: 70, Fees: 10, TradeInCredit: 200, Deposit: 50, Status: models.OrderPaid},
+			models.Order{ID: id, CarID: carID, CustomerID: customerID, Price: 900, Taxes: 70, Fees: 10, TradeInCredit: 200, Deposit: 50,
+				Status: models.OrderPaid, CreatedAt: now}},
+		{"car and customer", models.Order{ID: id, CarID: uuid.New(), CustomerID: uuid.New()}, stored},
+	}
+
+	for i, tc := range cases {
+		keep(&tc.input, &stored)
+
+		if !reflect.DeepEqual(tc.input, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, tc.input, tc.output)
+		}
+	}
+}
+
+func TestService_Forbidden(t *testing.T) {
+	viewer := authz.WithPrincipal(context.Background(), &models.Principal{Roles: []string{string(authz.Viewer)}})
+	manager := authz.WithPrincipal(context.Background(), &models.Principal{Roles: []string{string(authz.InventoryManager)}})
+
+	cases := []struct {
+		desc string
+		call func(s service) error
+		err  error
+	}{
+		{"anonymous read", func(s service) error {
+			_, err := s.GetByID(context.Background(), id)
+			return err
+		}, errors.Forbidden{Permission: string(authz.ReadOrders)}},
+		{"viewer list", func(s service) error {
+			_, err := s.GetAll(viewer, filters.Order{})
+			return err
+		}, errors.Forbidden{Permission: string(authz.ReadOrders)}},
+		{"inventory manager create", func(s service) error {
+			_, err := s.Create(manager, &models.Order{CarID: carID, CustomerID: customerID})
+			return err
+		}, errors.Forbidden{Permission: string(authz.ManageOrders)}},
+		{"viewer update", func(s service) error {
+			_, err := s.Update(viewer, &models.Order{ID: id, Status: models.OrderConfirmed})
+			return err
+		}, errors.Forbidden{Permission: string(authz.ManageOrders)}},
+	}
+
+	for i, tc := range cases {
+		s, _ := initializeTest(t)
+
+		if err := tc.call(s); !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestService_Get(t *testing.T) {
+	s, m := initializeTest(t)
+
+	m.orders.EXPECT().GetAll(gomock.Any(), filters.Order{CustomerID: customerID, Status: models.OrderPaid}).Return([]models.Order{}, nil)
+	m.orders.EXPECT().GetByID(gomock.Any(), id).Return(models.Order{ID: id}, nil)
+
+	if _, err := s.GetAll(ctx, filters.Order{CustomerID: customerID, Status: models.OrderPaid}); err != nil {
+		t.Errorf("\n[TEST] Failed. Desc : paid orders\nGot %v\nExpected nil", err)
+	}
+
+	if _, err := s.GetAll(ctx, filters.Order{Status: "sold"}); !reflect.DeepEqual(err,
+		errors.InvalidParam{Param: []string{"status"}}) {
+		t.Errorf("\n[TEST] Failed. Desc : unknown status\nGot %v\nExpected invalid status", err)
+	}
+
+	if order, err := s.GetByID(ctx, id); err != nil || order.ID != id {
+		t.Errorf("\n[TEST] Failed. Desc : get by id\nGot %v, %v\nExpected the order", order, err)
+	}
+}
@@ -0,0 +1,101 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/amehrotra/car-dealership/events"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+// retention is how long the broadcast messages are kept, long after every instance has read them
+const retention = time.Hour
+
+// Broadcast hands the messages the relay publishes to a publisher of every instance rather than of the one relaying them.
+// Published by the relay, a message is written to the broadcast table in the transaction of the relay, and every instance
+// tails the table into its own publisher. Relays take turns on the outbox, so the messages are written and read in order.
+type Broadcast struct {
+	store     stores.Broadcast
+	publisher events.Publisher
+	logger    *slog.Logger
+	now       func() time.Time
+
+	seq     int64
+	started bool
+	pruned  time.Time
+}
+
+// NewBroadcast returns a Broadcast tailing the messages into the publisher of this instance
+func NewBroadcast(store stores.Broadcast, publisher events.Publisher, logger *slog.Logger) *Broadcast {
+	return &Broadcast{store: store, publisher: publisher, logger: logger, now: time.Now}
+}
+
+// Publish writes the message to the broadcast table, in the transaction of ctx
+func (b *Broadcast) Publish(ctx context.Context, msg models.Message) error {
+	return b.store.Create(ctx, &msg)
+}
+
+// Run tails the broadcast table every interval until ctx is done
+func (b *Broadcast) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := b.Tail(ctx); err != nil {
+			b.logger.ErrorContext(ctx, "error in tailing the broadcast", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tail hands the messages written since the last call to the publisher and returns how many there were.
+// The first call starts from the latest message, the messages written before the instance started are not replayed.
+func (b *Broadcast) Tail(ctx context.Context) (int, error) {
+	if !b.started {
+		seq, err := b.store.Last(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		b.seq, b.started = seq, true
+	}
+
+	count := 0
+
+	for {
+		messages, seq, err := b.store.Since(ctx, b.seq, batchSize)
+		if err != nil {
+			return count, err
+		}
+
+		for _, msg := range messages {
+			if err := b.publisher.Publish(ctx, msg); err != nil {
+				b.logger.WarnContext(ctx, "error in publishing broadcast message", "message_id", msg.ID, "error", err)
+			}
+		}
+
+		b.seq = seq
+		count += len(messages)
+
+		if len(messages) < batchSize {
+			break
+		}
+	}
+
+	if now := b.now(); now.Sub(b.pruned) >= time.Minute {
+		if err := b.store.DeleteBefore(ctx, now.UTC().Add(-retention)); err != nil {
+			return count, err
+		}
+
+		b.pruned = now
+	}
+
+	return count, nil
+}
@@ -0,0 +1,93 @@
+package outbox
+
+import (
+	"context"
+	goError "errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+func TestBroadcast_Tail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockBroadcast := stores.NewMockBroadcast(ctrl)
+
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	first, second := uuid.New(), uuid.New()
+	full := make([]models.Message, batchSize)
+
+	for i := range full {
+		full[i] = models.Message{ID: uuid.New()}
+	}
+
+	p := &publisher{}
+	b := NewBroadcast(mockBroadcast, p, logging.Discard())
+	b.now = func() time.Time { return now }
+
+	// the messages written before the instance started are skipped, a full batch is followed by the next one
+	gomock.InOrder(
+		mockBroadcast.EXPECT().Last(gomock.Any()).Return(int64(4), nil),
+		mockBroadcast.EXPECT().Since(gomock.Any(), int64(4), batchSize).Return(full, int64(104), nil),
+		mockBroadcast.EXPECT().Since(gomock.Any(), int64(104), batchSize).Return([]models.Message{{ID: first}}, int64(105), nil),
+		mockBroadcast.EXPECT().DeleteBefore(gomock.Any(), now.Add(-retention)).Return(nil),
+		mockBroadcast.EXPECT().Since(gomock.Any(), int64(105), batchSize).Return([]models.Message{{ID: second}}, int64(106), nil),
+	)
+
+	cases := []struct {
+		desc  string
+		count int
+		last  uuid.UUID
+	}{
+		{"from the latest message", batchSize + 1, first},
+		{"since the last call", 1, second},
+	}
+
+	for i, tc := range cases {
+		n, err := b.Tail(context.Background())
+
+		if err != nil || n != tc.count || p.published[len(p.published)-1] != tc.last {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v", i, tc.desc, n, err, tc.count)
+		}
+	}
+}
+
+func TestBroadcast_TailError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockBroadcast := stores.NewMockBroadcast(ctrl)
+
+	dbErr := goError.New("connection refused")
+
+	gomock.InOrder(
+		mockBroadcast.EXPECT().Last(gomock.Any()).Return(int64(0), dbErr),
+		mockBroadcast.EXPECT().Last(gomock.Any()).Return(int64(4), nil),
+		mockBroadcast.EXPECT().Since(gomock.Any(), int64(4), batchSize).Return(nil, int64(4), dbErr),
+	)
+
+	b := NewBroadcast(mockBroadcast, &publisher{}, logging.Discard())
+
+	for i := 0; i < 2; i++ {
+		if n, err := b.Tail(context.Background()); n != 0 || !reflect.DeepEqual(err, dbErr) {
+			t.Errorf("\n[TEST %d] Failed. Desc : store error\nGot %v, %v\nExpected %v", i, n, err, dbErr)
+		}
+	}
+}
+
+func TestBroadcast_Publish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockBroadcast := stores.NewMockBroadcast(ctrl)
+
+	msg := models.Message{ID: uuid.New(), Key: "car", Type: models.CarCreated}
+
+	mockBroadcast.EXPECT().Create(gomock.Any(), &msg).Return(nil)
+
+	if err := NewBroadcast(mockBroadcast, &publisher{}, logging.Discard()).Publish(context.Background(), msg); err != nil {
+		t.Errorf("\n[TEST] Failed. Desc : publish\nGot %v\nExpected nil", err)
+	}
+}
@@ -0,0 +1,83 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/events"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+const batchSize = 100
+
+// Relay publishes the messages of the outbox, at least once and in order per key
+type Relay struct {
+	store     stores.Outbox
+	tx        stores.Transactor
+	publisher events.Publisher
+	logger    *slog.Logger
+}
+
+func NewRelay(store stores.Outbox, tx stores.Transactor, publisher events.Publisher, logger *slog.Logger) *Relay {
+	return &Relay{store: store, tx: tx, publisher: publisher, logger: logger}
+}
+
+// Run relays the outbox every interval until ctx is done
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.Relay(ctx); err != nil {
+			r.logger.ErrorContext(ctx, "error in relaying the outbox", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Relay publishes the pending messages and returns how many were published. The messages stay locked while they
+// are published so that relays of other instances wait instead of overtaking them. Once a message of a key fails
+// the later ones of that key are held back until the next run, a message published before a crash is published again.
+func (r *Relay) Relay(ctx context.Context) (int, error) {
+	published := make([]uuid.UUID, 0)
+
+	err := r.tx.InTx(ctx, func(ctx context.Context) error {
+		pending, err := r.store.Pending(ctx, batchSize)
+		if err != nil {
+			return err
+		}
+
+		failed := make(map[string]bool)
+
+		for _, msg := range pending {
+			if failed[msg.Key] {
+				continue
+			}
+
+			if err := r.publisher.Publish(ctx, msg); err != nil {
+				r.logger.WarnContext(ctx, "error in publishing message", "message_id", msg.ID, "key", msg.Key, "error", err)
+
+				failed[msg.Key] = true
+
+				continue
+			}
+
+			published = append(published, msg.ID)
+		}
+
+		return r.store.Delete(ctx, published)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(published), nil
+}
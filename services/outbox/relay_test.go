@@ -0,0 +1,121 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	goError "errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+// publisher records the messages it accepts and fails those of the given ids
+type publisher struct {
+	fail      map[uuid.UUID]bool
+	published []uuid.UUID
+}
+
+func (p *publisher) Publish(_ context.Context, msg models.Message) error {
+	if p.fail[msg.ID] {
+		return goError.New("broker unavailable")
+	}
+
+	p.published = append(p.published, msg.ID)
+
+	return nil
+}
+
+func TestService_Emit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockOutbox := stores.NewMockOutbox(ctrl)
+
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := service{store: mockOutbox, now: func() time.Time { return now }}
+	car := &models.Car{ID: uuid.New(), Model: "X"}
+
+	var written models.Message
+
+	mockOutbox.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, msg *models.Message) error {
+		written = *msg
+
+		return nil
+	})
+
+	event := models.Event{Type: models.CarUpdated, Car: car}
+
+	if err := s.Emit(context.Background(), &event); err != nil {
+		t.Fatalf("\n[TEST] Failed. Desc : emit\nGot %v\nExpected nil", err)
+	}
+
+	var payload models.Event
+	if err := json.Unmarshal(written.Payload, &payload); err != nil || !reflect.DeepEqual(payload.Car, car) {
+		t.Errorf("\n[TEST] Failed. Desc : payload\nGot %v, %v\nExpected the event", payload, err)
+	}
+
+	if written.ID != event.ID || written.Key != car.ID.String() || written.Type != models.CarUpdated || !written.OccurredAt.Equal(now) {
+		t.Errorf("\n[TEST] Failed. Desc : emit\nGot %v\nExpected message keyed by the car", written)
+	}
+}
+
+func TestRelay_Relay(t *testing.T) {
+	first, second, other := uuid.New(), uuid.New(), uuid.New()
+
+	pending := []models.Message{
+		{ID: first, Key: "car-1"},
+		{ID: other, Key: "car-2"},
+		{ID: second, Key: "car-1"},
+	}
+
+	cases := []struct {
+		desc      string
+		fail      map[uuid.UUID]bool
+		published []uuid.UUID
+	}{
+		{"every message", nil, []uuid.UUID{first, other, second}},
+		{"later messages of a failed key are held back", map[uuid.UUID]bool{first: true}, []uuid.UUID{other}},
+	}
+
+	for i, tc := range cases {
+		ctrl := gomock.NewController(t)
+		mockOutbox := stores.NewMockOutbox(ctrl)
+		mockTx := stores.NewMockTransactor(ctrl)
+
+		mockTx.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockOutbox.EXPECT().Pending(gomock.Any(), batchSize).Return(pending, nil)
+		mockOutbox.EXPECT().Delete(gomock.Any(), tc.published).Return(nil)
+
+		p := &publisher{fail: tc.fail}
+		r := NewRelay(mockOutbox, mockTx, p, logging.Discard())
+
+		n, err := r.Relay(context.Background())
+
+		if err != nil || n != len(tc.published) || !reflect.DeepEqual(p.published, tc.published) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v", i, tc.desc, p.published, err, tc.published)
+		}
+	}
+}
+
+func TestRelay_RelayError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	mockTx := stores.NewMockTransactor(ctrl)
+
+	txErr := goError.New("lock wait timeout")
+
+	mockTx.EXPECT().InTx(gomock.Any(), gomock.Any()).Return(txErr)
+
+	r := NewRelay(mockOutbox, mockTx, &publisher{}, logging.Discard())
+
+	if n, err := r.Relay(context.Background()); n != 0 || !goError.Is(err, txErr) {
+		t.Errorf("\n[TEST] Failed. Desc : transaction error\nGot %v, %v\nExpected %v", n, err, txErr)
+	}
+}
@@ -0,0 +1,47 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+type service struct {
+	store stores.Outbox
+	now   func() time.Time
+}
+
+// New returns an Emitter writing the events to the outbox, they are published by a Relay once the change is committed
+func New(store stores.Outbox) services.Emitter {
+	return service{store: store, now: time.Now}
+}
+
+// Emit writes the event in the transaction of ctx, the messages of a car are keyed by its id
+func (s service) Emit(ctx context.Context, event *models.Event) error {
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = s.now().UTC()
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	msg := models.Message{ID: event.ID, Type: event.Type, Payload: payload, OccurredAt: event.OccurredAt}
+
+	if event.Car != nil {
+		msg.Key = event.Car.ID.String()
+	}
+
+	return s.store.Create(ctx, &msg)
+}
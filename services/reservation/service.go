@@ -0,0 +1,183 @@
+package reservation
+
+import (
+	"context"
+	goError "errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/authz"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+const (
+	// defaultHold is how long a car is held when the reservation has no expiry
+	defaultHold = 72 * time.Hour
+	// maxHold is the longest a car can be held for
+	maxHold = 14 * 24 * time.Hour
+)
+
+type service struct {
+	reservations stores.Reservation
+	cars         stores.Car
+	customers    stores.Customer
+	orders       stores.Order
+	tx           stores.Transactor
+	logger       *slog.Logger
+	now          func() time.Time
+}
+
+func New(reservations stores.Reservation, cars stores.Car, customers stores.Customer, orders stores.Order, tx stores.Transactor,
+	logger *slog.Logger) services.Reservation {
+	return service{reservations: reservations, cars: cars, customers: customers, orders: orders, tx: tx, logger: logger,
+		now: time.Now}
+}
+
+// Create holds the car for the customer until the reservation expires, three days from now when it has no expiry.
+// The car is locked for the transaction like when it is sold, so that it is neither reserved twice nor reserved
+// while an order of it is placed.
+func (s service) Create(ctx context.Context, reservation *models.Reservation) (*models.Reservation, error) {
+	if err := authz.Check(ctx, authz.ReserveCars); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+
+	if reservation.ExpiresAt.IsZero() {
+		reservation.ExpiresAt = now.Add(defaultHold)
+	}
+
+	reservation.ExpiresAt = reservation.ExpiresAt.UTC().Truncate(time.Second)
+
+	if err := checkReservation(reservation, now); err != nil {
+		return nil, err
+	}
+
+	reservation.ID = uuid.New()
+	reservation.Status = models.ReservationActive
+	reservation.CreatedAt = now
+
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := s.cars.Lock(ctx, reservation.CarID); err != nil {
+			return err
+		}
+
+		if _, err := s.customers.GetByID(ctx, reservation.CustomerID); err != nil {
+			return err
+		}
+
+		if err := s.checkAvailable(ctx, reservation.CarID, now); err != nil {
+			return err
+		}
+
+		return s.reservations.Create(ctx, reservation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "car reserved", "reservation_id", reservation.ID, "car_id", reservation.CarID,
+		"expires_at", reservation.ExpiresAt)
+
+	return reservation, nil
+}
+
+// GetActive fetches the reservation holding the car, nil when it is not held. Whoever reads cars sees whether they
+// are held.
+func (s service) GetActive(ctx context.Context, carID uuid.UUID) (*models.Reservation, error) {
+	if err := authz.Check(ctx, authz.ReadCars); err != nil {
+		return nil, err
+	}
+
+	reservation, err := s.reservations.GetActive(ctx, carID, s.now().UTC())
+
+	var notFound errors.EntityNotFound
+
+	switch {
+	case goError.As(err, &notFound):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+// Cancel releases the car from the reservation holding it
+func (s service) Cancel(ctx context.Context, carID uuid.UUID) error {
+	if err := authz.Check(ctx, authz.ReserveCars); err != nil {
+		return err
+	}
+
+	reservation, err := s.reservations.GetActive(ctx, carID, s.now().UTC())
+	if err != nil {
+		return err
+	}
+
+	reservation.Status = models.ReservationCancelled
+
+	if err := s.reservations.Update(ctx, &reservation); err != nil {
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "reservation cancelled", "reservation_id", reservation.ID, "car_id", carID)
+
+	return nil
+}
+
+// checkAvailable returns errors.Conflict when the car is held by another reservation or on an order that is not
+// cancelled, it is called with the car locked
+func (s service) checkAvailable(ctx context.Context, carID uuid.UUID, now time.Time) error {
+	held, err := s.reservations.GetActive(ctx, carID, now)
+
+	var notFound errors.EntityNotFound
+
+	switch {
+	case err == nil:
+		return errors.Conflict{Entity: "car", ID: carID.String(), Reason: "is reserved until " + held.ExpiresAt.Format(time.RFC3339)}
+	case !goError.As(err, &notFound):
+		return err
+	}
+
+	orders, err := s.orders.GetAll(ctx, filters.Order{CarID: carID})
+	if err != nil {
+		return err
+	}
+
+	for i := range orders {
+		if orders[i].Status != models.OrderCancelled {
+			return errors.Conflict{Entity: "car", ID: carID.String(), Reason: "is already on order " + orders[i].ID.String()}
+		}
+	}
+
+	return nil
+}
+
+// checkReservation validates the deposit and that the expiry is in the future and within maxHold
+func checkReservation(reservation *models.Reservation, now time.Time) error {
+	var params []string
+
+	if reservation.CustomerID == uuid.Nil {
+		params = append(params, "customerId")
+	}
+
+	if reservation.Deposit < 0 {
+		params = append(params, "deposit")
+	}
+
+	if !reservation.ExpiresAt.After(now) || reservation.ExpiresAt.After(now.Add(maxHold)) {
+		params = append(params, "expiresAt")
+	}
+
+	if len(params) > 0 {
+		return errors.InvalidParam{Param: params}
+	}
+
+	return nil
+}
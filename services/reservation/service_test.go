@@ -0,0 +1,200 @@
+package reservation
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/authz"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	// principal allowed to perform every operation
+	ctx        = authz.WithPrincipal(context.Background(), &models.Principal{Roles: []string{string(authz.Admin)}})
+	now        = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	id         = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+	carID      = uuid.MustParse("3b7e4a1c-2b0e-4d55-a3a4-6f1f1f0c9a10")
+	customerID = uuid.MustParse("c4d0f7a2-5e1b-4f0e-9d57-0a2b8e6d3c21")
+	notHeld    = errors.EntityNotFound{Entity: "reservation", ID: carID.String()}
+)
+
+type mocks struct {
+	reservations *stores.MockReservation
+	cars         *stores.MockCar
+	customers    *stores.MockCustomer
+	orders       *stores.MockOrder
+}
+
+func initializeTest(t *testing.T) (service, mocks) {
+	ctrl := gomock.NewController(t)
+
+	m := mocks{reservations: stores.NewMockReservation(ctrl), cars: stores.NewMockCar(ctrl), customers: stores.NewMockCustomer(ctrl),
+		orders: stores.NewMockOrder(ctrl)}
+
+	tx := stores.NewMockTransactor(ctrl)
+	tx.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).AnyTimes()
+
+	return service{reservations: m.reservations, cars: m.cars, customers: m.customers, orders: m.orders, tx: tx,
+		logger: logging.Discard(), now: func() time.Time { return now }}, m
+}
+
+func TestService_Create(t *testing.T) {
+	s, m := initializeTest(t)
+
+	m.cars.EXPECT().Lock(gomock.Any(), carID).Return(nil)
+	m.customers.EXPECT().GetByID(gomock.Any(), customerID).Return(models.Customer{ID: customerID}, nil)
+	m.reservations.EXPECT().GetActive(gomock.Any(), carID, now).Return(models.Reservation{}, notHeld)
+	m.orders.EXPECT().GetAll(gomock.Any(), filters.Order{CarID: carID}).
+		Return([]models.Order{{ID: uuid.New(), CarID: carID, Status: models.OrderCancelled}}, nil)
+	m.reservations.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	reservation, err := s.Create(ctx, &models.Reservation{CarID: carID, CustomerID: customerID, Deposit: 50000})
+	if err != nil {
+		t.Fatalf("\n[TEST] Failed. Desc : create\nGot %v\nExpected nil", err)
+	}
+
+	if reservation.ID == uuid.Nil || reservation.Status != models.ReservationActive || !reservation.ExpiresAt.Equal(now.Add(defaultHold)) ||
+		!reservation.CreatedAt.Equal(now) {
+		t.Errorf("\n[TEST] Failed. Desc : create\nGot %v\nExpected an active reservation of three days", reservation)
+	}
+}
+
+func TestService_CreateErrors(t *testing.T) {
+	orderID := uuid.New()
+	expiresAt := now.Add(time.Hour)
+
+	cases := []struct {
+		desc        string
+		reservation models.Reservation
+		mock        func(m mocks)
+		err         error
+	}{
+		{"invalid fields", models.Reservation{CarID: carID, Deposit: -1, ExpiresAt: now.Add(-time.Hour)}, func(m mocks) {},
+			errors.InvalidParam{Param: []string{"customerId", "deposit", "expiresAt"}}},
+		{"expiry too far", models.Reservation{CarID: carID, CustomerID: customerID, ExpiresAt: now.Add(maxHold + time.Second)},
+			func(m mocks) {}, errors.InvalidParam{Param: []string{"expiresAt"}}},
+		{"missing car", models.Reservation{CarID: carID, CustomerID: customerID}, func(m mocks) {
+			m.cars.EXPECT().Lock(gomock.Any(), carID).Return(errors.EntityNotFound{Entity: "car", ID: carID.String()})
+		}, errors.EntityNotFound{Entity: "car", ID: carID.String()}},
+		{"missing customer", models.Reservation{CarID: carID, CustomerID: customerID}, func(m mocks) {
+			m.cars.EXPECT().Lock(gomock.Any(), carID).Return(nil)
+			m.customers.EXPECT().GetByID(gomock.Any(), customerID).
+				Return(models.Customer{}, errors.EntityNotFound{Entity: "customer", ID: customerID.String()})
+		}, errors.EntityNotFound{Entity: "customer", ID: customerID.String()}},
+		{"already reserved", models.Reservation{CarID: carID, CustomerID: customerID}, func(m mocks) {
+			m.cars.EXPECT().Lock(gomock.Any(), carID).Return(nil)
+			m.customers.EXPECT().GetByID(gomock.Any(), customerID).Return(models.Customer{ID: customerID}, nil)
+			m.reservations.EXPECT().GetActive(gomock.Any(), carID, now).Return(models.Reservation{ID: id, ExpiresAt: expiresAt}, nil)
+		}, errors.Conflict{Entity: "car", ID: carID.String(), Reason: "is reserved until 2022-01-01T01:00:00Z"}},
+		{"on an order", models.Reservation{CarID: carID, CustomerID: customerID}, func(m mocks) {
+			m.cars.EXPECT().Lock(gomock.Any(), carID).Return(nil)
+			m.customers.EXPECT().GetByID(gomock.Any(), customerID).Return(models.Customer{ID: customerID}, nil)
+			m.reservations.EXPECT().GetActive(gomock.Any(), carID, now).Return(models.Reservation{}, notHeld)
+			m.orders.EXPECT().GetAll(gomock.Any(), filters.Order{CarID: carID}).
+				Return([]models.Order{{ID: orderID, CarID: carID, Status: models.OrderPaid}}, nil)
+		}, errors.Conflict{Entity: "car", ID: carID.String(), Reason: "is already on order " + orderID.String()}},
+	}
+
+	for i, tc := range cases {
+		s, m := initializeTest(t)
+		tc.mock(m)
+
+		_, err := s.Create(ctx, &tc.reservation)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestService_GetActive(t *testing.T) {
+	held := models.Reservation{ID: id, CarID: carID, Status: models.ReservationActive}
+
+	cases := []struct {
+		desc   string
+		held   models.Reservation
+		err    error
+		output *models.Reservation
+	}{
+		{"held", held, nil, &held},
+		{"not held", models.Reservation{}, notHeld, nil},
+	}
+
+	for i, tc := range cases {
+		s, m := initializeTest(t)
+
+		m.reservations.EXPECT().GetActive(gomock.Any(), carID, now).Return(tc.held, tc.err)
+
+		output, err := s.GetActive(ctx, carID)
+
+		if err != nil || !reflect.DeepEqual(output, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v", i, tc.desc, output, err, tc.output)
+		}
+	}
+}
+
+func TestService_Cancel(t *testing.T) {
+	s, m := initializeTest(t)
+
+	held := models.Reservation{ID: id, CarID: carID, Status: models.ReservationActive}
+	cancelled := held
+	cancelled.Status = models.ReservationCancelled
+
+	m.reservations.EXPECT().GetActive(gomock.Any(), carID, now).Return(held, nil)
+	m.reservations.EXPECT().Update(gomock.Any(), &cancelled).Return(nil)
+
+	if err := s.Cancel(ctx, carID); err != nil {
+		t.Errorf("\n[TEST] Failed. Desc : cancel\nGot %v\nExpected nil", err)
+	}
+
+	s, m = initializeTest(t)
+
+	m.reservations.EXPECT().GetActive(gomock.Any(), carID, now).Return(models.Reservation{}, notHeld)
+
+	if err := s.Cancel(ctx, carID); !reflect.DeepEqual(err, notHeld) {
+		t.Errorf("\n[TEST] Failed. Desc : cancel not held\nGot %v\nExpected %v", err, notHeld)
+	}
+}
+
+func TestService_Forbidden(t *testing.T) {
+	viewer := authz.WithPrincipal(context.Background(), &models.Principal{Roles: []string{string(authz.Viewer)}})
+	manager := authz.WithPrincipal(context.Background(), &models.Principal{Roles: []string{string(authz.InventoryManager)}})
+
+	cases := []struct {
+		desc string
+		call func(s service) error
+		err  error
+	}{
+		{"anonymous read", func(s service) error {
+			_, err := s.GetActive(context.Background(), carID)
+			return err
+		}, errors.Forbidden{Permission: string(authz.ReadCars)}},
+		{"viewer reserve", func(s service) error {
+			_, err := s.Create(viewer, &models.Reservation{CarID: carID, CustomerID: customerID})
+			return err
+		}, errors.Forbidden{Permission: string(authz.ReserveCars)}},
+		{"inventory manager cancel", func(s service) error {
+			return s.Cancel(manager, carID)
+		}, errors.Forbidden{Permission: string(authz.ReserveCars)}},
+	}
+
+	for i, tc := range cases {
+		s, _ := initializeTest(t)
+
+		if err := tc.call(s); !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
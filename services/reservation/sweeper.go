@@ -0,0 +1,53 @@
+package reservation
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+// Sweeper marks the reservations past their expiry as expired. They stop holding their car at their expiry whether
+// they are swept or not, sweeping keeps their status accurate.
+type Sweeper struct {
+	store  stores.Reservation
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+func NewSweeper(store stores.Reservation, logger *slog.Logger) *Sweeper {
+	return &Sweeper{store: store, logger: logger, now: time.Now}
+}
+
+// Run sweeps the reservations every interval until ctx is done
+func (s *Sweeper) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Sweep(ctx); err != nil {
+			s.logger.ErrorContext(ctx, "error in sweeping reservations", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep expires the active reservations past their expiry and returns how many there were
+func (s *Sweeper) Sweep(ctx context.Context) (int64, error) {
+	expired, err := s.store.Expire(ctx, s.now().UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	if expired > 0 {
+		s.logger.InfoContext(ctx, "reservations expired", "count", expired)
+	}
+
+	return expired, nil
+}
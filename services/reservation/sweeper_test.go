@@ -0,0 +1,43 @@
+package reservation
+
+import (
+	"context"
+	goError "errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+func TestSweeper_Sweep(t *testing.T) {
+	queryErr := errors.DB{Err: goError.New("query error")}
+
+	cases := []struct {
+		desc    string
+		expired int64
+		err     error
+	}{
+		{"expired", 2, nil},
+		{"none expired", 0, nil},
+		{"store error", 0, queryErr},
+	}
+
+	for i, tc := range cases {
+		ctrl := gomock.NewController(t)
+		store := stores.NewMockReservation(ctrl)
+		s := &Sweeper{store: store, logger: logging.Discard(), now: func() time.Time { return now }}
+
+		store.EXPECT().Expire(gomock.Any(), now).Return(tc.expired, tc.err)
+
+		expired, err := s.Sweep(context.Background())
+
+		if !reflect.DeepEqual(err, tc.err) || expired != tc.expired {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v, %v", i, tc.desc, expired, err, tc.expired, tc.err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package testdrive
+
+import "time"
+
+// Hours are the business hours test drives are booked within
+type Hours struct {
+	// Location is the time zone of the dealership
+	Location *time.Location
+	// Open and Close are the times of day the dealership opens and closes
+	Open, Close time.Duration
+	// Closed are the days of the week the dealership is closed on
+	Closed []time.Weekday
+}
+
+// DefaultHours opens the dealership from 9:00 to 18:00 every day but Sunday
+func DefaultHours(loc *time.Location) Hours {
+	return Hours{Location: loc, Open: 9 * time.Hour, Close: 18 * time.Hour, Closed: []time.Weekday{time.Sunday}}
+}
+
+// within reports whether start to end falls between opening and closing of a single day the dealership is open on.
+// Times of day are read off the wall clock so that days on which the clocks change keep their hours.
+func (h Hours) within(start, end time.Time) bool {
+	start, end = start.In(h.Location), end.In(h.Location)
+
+	for _, day := range h.Closed {
+		if start.Weekday() == day {
+			return false
+		}
+	}
+
+	y, m, d := start.Date()
+	endY, endM, endD := end.Date()
+
+	if y != endY || m != endM || d != endD {
+		return false
+	}
+
+	return clock(start) >= h.Open && clock(end) <= h.Close
+}
+
+// clock is the time of day of t
+func clock(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
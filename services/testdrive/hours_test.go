@@ -0,0 +1,37 @@
+package testdrive
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHours_Within(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+
+	hours := DefaultHours(berlin)
+	// the clocks went forward on 27 March 2022 and back on 30 October 2022, both Sundays, so the Mondays after are checked
+	cases := []struct {
+		desc   string
+		start  time.Time
+		end    time.Time
+		within bool
+	}{
+		{"opening hours", time.Date(2022, 1, 3, 9, 0, 0, 0, berlin), time.Date(2022, 1, 3, 9, 30, 0, 0, berlin), true},
+		{"until closing", time.Date(2022, 1, 3, 17, 30, 0, 0, berlin), time.Date(2022, 1, 3, 18, 0, 0, 0, berlin), true},
+		{"before opening", time.Date(2022, 1, 3, 8, 45, 0, 0, berlin), time.Date(2022, 1, 3, 9, 15, 0, 0, berlin), false},
+		{"past closing", time.Date(2022, 1, 3, 17, 45, 0, 0, berlin), time.Date(2022, 1, 3, 18, 15, 0, 0, berlin), false},
+		{"closed day", time.Date(2022, 1, 2, 10, 0, 0, 0, berlin), time.Date(2022, 1, 2, 10, 30, 0, 0, berlin), false},
+		{"summer time", time.Date(2022, 3, 28, 7, 0, 0, 0, time.UTC), time.Date(2022, 3, 28, 7, 30, 0, 0, time.UTC), true},
+		{"utc before opening in winter", time.Date(2022, 10, 31, 7, 30, 0, 0, time.UTC), time.Date(2022, 10, 31, 8, 0, 0, 0, time.UTC),
+			false},
+	}
+
+	for i, tc := range cases {
+		if within := hours.within(tc.start, tc.end); within != tc.within {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, within, tc.within)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package testdrive
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/authz"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+const (
+	// minDrive and maxDrive bound how long a test drive lasts
+	minDrive = 15 * time.Minute
+	maxDrive = 2 * time.Hour
+	// calendarHistory is how far back the calendars of the salespeople go
+	calendarHistory = 30 * 24 * time.Hour
+	// maxSalesperson and maxNotes are the longest salesperson and notes stored
+	maxSalesperson = 100
+	maxNotes       = 500
+)
+
+type service struct {
+	drives    stores.TestDrive
+	cars      stores.Car
+	customers stores.Customer
+	tx        stores.Transactor
+	hours     Hours
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+func New(drives stores.TestDrive, cars stores.Car, customers stores.Customer, tx stores.Transactor, hours Hours,
+	logger *slog.Logger) services.TestDrive {
+	return service{drives: drives, cars: cars, customers: customers, tx: tx, hours: hours, logger: logger, now: time.Now}
+}
+
+// Create books the car and the salesperson for the customer, the salesperson defaults to the caller.
+// The car is locked for the transaction and the overlapping test drives are read for update, so that neither the car
+// nor the salesperson is booked twice for the same time.
+func (s service) Create(ctx context.Context, drive *models.TestDrive) (*models.TestDrive, error) {
+	if err := authz.Check(ctx, authz.ManageTestDrives); err != nil {
+		return nil, err
+	}
+
+	if drive.Status != "" && drive.Status != models.TestDriveScheduled {
+		return nil, errors.InvalidParam{Param: []string{"status"}}
+	}
+
+	if principal, ok := authz.PrincipalFromContext(ctx); ok && drive.Salesperson == "" {
+		drive.Salesperson = principal.Name
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+
+	drive.StartsAt = drive.StartsAt.UTC().Truncate(time.Second)
+	drive.EndsAt = drive.EndsAt.UTC().Truncate(time.Second)
+
+	if err := s.checkTestDrive(drive, now); err != nil {
+		return nil, err
+	}
+
+	drive.ID = uuid.New()
+	drive.Status = models.TestDriveScheduled
+	drive.CreatedAt = now
+	drive.UpdatedAt = now
+
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := s.cars.Lock(ctx, drive.CarID); err != nil {
+			return err
+		}
+
+		if _, err := s.customers.GetByID(ctx, drive.CustomerID); err != nil {
+			return err
+		}
+
+		if err := s.checkFree(ctx, drive); err != nil {
+			return err
+		}
+
+		return s.drives.Create(ctx, drive)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "test drive booked", "test_drive_id", drive.ID, "car_id", drive.CarID, "salesperson", drive.Salesperson,
+		"starts_at", drive.StartsAt)
+
+	return drive, nil
+}
+
+// GetAll lists the test drives of the filter
+func (s service) GetAll(ctx context.Context, filter filters.TestDrive) ([]models.TestDrive, error) {
+	if err := authz.Check(ctx, authz.ReadTestDrives); err != nil {
+		return nil, err
+	}
+
+	if !valid(filter.Status) && filter.Status != "" {
+		return nil, errors.InvalidParam{Param: []string{"status"}}
+	}
+
+	return s.drives.GetAll(ctx, filter)
+}
+
+// GetByID fetches the test drive
+func (s service) GetByID(ctx context.Context, id uuid.UUID) (*models.TestDrive, error) {
+	if err := authz.Check(ctx, authz.ReadTestDrives); err != nil {
+		return nil, err
+	}
+
+	drive, err := s.drives.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &drive, nil
+}
+
+// Update reschedules a scheduled test drive or moves it to completed, cancelled or no_show, keeping its slot, status
+// and notes when they are left out. A test drive is only completed or missed once it has started, and the car, the
+// customer and the salesperson of a test drive never change.
+func (s service) Update(ctx context.Context, drive *models.TestDrive) (*models.TestDrive, error) {
+	if err := authz.Check(ctx, authz.ManageTestDrives); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.drives.GetByID(ctx, drive.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if stored.Status != models.TestDriveScheduled {
+		return nil, errors.Conflict{Entity: "test drive", ID: drive.ID.String(), Reason: "is " + stored.Status}
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+
+	keep(drive, &stored)
+
+	switch {
+	case !valid(drive.Status):
+		return nil, errors.InvalidParam{Param: []string{"status"}}
+	case (drive.Status == models.TestDriveCompleted || drive.Status == models.TestDriveNoShow) && now.Before(stored.StartsAt):
+		return nil, errors.Conflict{Entity: "test drive", ID: drive.ID.String(), Reason: "has not started"}
+	}
+
+	rescheduled := !drive.StartsAt.Equal(stored.StartsAt) || !drive.EndsAt.Equal(stored.EndsAt)
+	drive.UpdatedAt = now
+
+	if !rescheduled {
+		return s.update(ctx, drive)
+	}
+
+	if drive.Status != models.TestDriveScheduled {
+		return nil, errors.InvalidParam{Param: []string{"status"}}
+	}
+
+	if err := s.checkTestDrive(drive, now); err != nil {
+		return nil, err
+	}
+
+	err = s.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := s.cars.Lock(ctx, drive.CarID); err != nil {
+			return err
+		}
+
+		if err := s.checkFree(ctx, drive); err != nil {
+			return err
+		}
+
+		return s.drives.Update(ctx, drive)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "test drive rescheduled", "test_drive_id", drive.ID, "starts_at", drive.StartsAt)
+
+	return drive, nil
+}
+
+// Calendar lists the test drives of the salesperson from calendarHistory ago on, cancelled ones included so that
+// calendars subscribed to them drop them
+func (s service) Calendar(ctx context.Context, salesperson string) ([]models.TestDrive, error) {
+	if err := authz.Check(ctx, authz.ReadTestDrives); err != nil {
+		return nil, err
+	}
+
+	if salesperson == "" {
+		return nil, errors.MissingParam{Param: "salesperson"}
+	}
+
+	return s.drives.GetAll(ctx, filters.TestDrive{Salesperson: salesperson, From: s.now().UTC().Add(-calendarHistory)})
+}
+
+// update stores the test drive without moving it
+func (s service) update(ctx context.Context, drive *models.TestDrive) (*models.TestDrive, error) {
+	if err := s.drives.Update(ctx, drive); err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "test drive updated", "test_drive_id", drive.ID, "status", drive.Status)
+
+	return drive, nil
+}
+
+// checkFree returns errors.Conflict when another scheduled test drive of the car or the salesperson overlaps the test
+// drive, it is called in the transaction storing it
+func (s service) checkFree(ctx context.Context, drive *models.TestDrive) error {
+	overlapping, err := s.drives.GetOverlapping(ctx, drive.CarID, drive.Salesperson, drive.StartsAt, drive.EndsAt)
+	if err != nil {
+		return err
+	}
+
+	for i := range overlapping {
+		other := &overlapping[i]
+		if other.ID == drive.ID {
+			continue
+		}
+
+		reason := "is booked from " + other.StartsAt.Format(time.RFC3339) + " to " + other.EndsAt.Format(time.RFC3339) +
+			" for test drive " + other.ID.String()
+
+		if other.CarID == drive.CarID {
+			return errors.Conflict{Entity: "car", ID: drive.CarID.String(), Reason: reason}
+		}
+
+		return errors.Conflict{Entity: "salesperson", ID: drive.Salesperson, Reason: reason}
+	}
+
+	return nil
+}
+
+// checkTestDrive validates the fields of the test drive and that its slot is ahead, lasts between minDrive and
+// maxDrive and falls within the business hours
+func (s service) checkTestDrive(drive *models.TestDrive, now time.Time) error {
+	var params []string
+
+	if drive.CarID == uuid.Nil {
+		params = append(params, "carId")
+	}
+
+	if drive.CustomerID == uuid.Nil {
+		params = append(params, "customerId")
+	}
+
+	if drive.Salesperson == "" || len(drive.Salesperson) > maxSalesperson {
+		params = append(params, "salesperson")
+	}
+
+	if len(drive.Notes) > maxNotes {
+		params = append(params, "notes")
+	}
+
+	length := drive.EndsAt.Sub(drive.StartsAt)
+
+	switch {
+	case !drive.StartsAt.After(now):
+		params = append(params, "startsAt")
+	case length < minDrive || length > maxDrive || !s.hours.within(drive.StartsAt, drive.EndsAt):
+		params = append(params, "startsAt", "endsAt")
+	}
+
+	if len(params) > 0 {
+		return errors.InvalidParam{Param: params}
+	}
+
+	return nil
+}
+
+// keep fills the fields of the update that are left out or never change from the stored test drive
+func keep(drive, stored *models.TestDrive) {
+	drive.CarID = stored.CarID
+	drive.CustomerID = stored.CustomerID
+	drive.Salesperson = stored.Salesperson
+	drive.CreatedAt = stored.CreatedAt
+
+	if drive.Status == "" {
+		drive.Status = stored.Status
+	}
+
+	if drive.StartsAt.IsZero() {
+		drive.StartsAt = stored.StartsAt
+	}
+
+	if drive.EndsAt.IsZero() {
+		drive.EndsAt = stored.EndsAt
+	}
+
+	if drive.Notes == "" {
+		drive.Notes = stored.Notes
+	}
+
+	drive.StartsAt = drive.StartsAt.UTC().Truncate(time.Second)
+	drive.EndsAt = drive.EndsAt.UTC().Truncate(time.Second)
+}
+
+// valid reports whether the status is one of a test drive
+func valid(status string) bool {
+	switch status {
+	case models.TestDriveScheduled, models.TestDriveCompleted, models.TestDriveCancelled, models.TestDriveNoShow:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,248 @@
+package testdrive
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/authz"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	// principal allowed to perform every operation
+	ctx = authz.WithPrincipal(context.Background(), &models.Principal{Roles: []string{string(authz.Admin)}})
+	// a Saturday
+	now        = time.Date(2022, 1, 1, 8, 0, 0, 0, time.UTC)
+	id         = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+	carID      = uuid.MustParse("3b7e4a1c-2b0e-4d55-a3a4-6f1f1f0c9a10")
+	customerID = uuid.MustParse("c4d0f7a2-5e1b-4f0e-9d57-0a2b8e6d3c21")
+	startsAt   = time.Date(2022, 1, 3, 10, 0, 0, 0, time.UTC)
+	endsAt     = startsAt.Add(30 * time.Minute)
+)
+
+type mocks struct {
+	drives    *stores.MockTestDrive
+	cars      *stores.MockCar
+	customers *stores.MockCustomer
+}
+
+func initializeTest(t *testing.T) (service, mocks) {
+	ctrl := gomock.NewController(t)
+
+	m := mocks{drives: stores.NewMockTestDrive(ctrl), cars: stores.NewMockCar(ctrl), customers: stores.NewMockCustomer(ctrl)}
+
+	tx := stores.NewMockTransactor(ctrl)
+	tx.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).AnyTimes()
+
+	return service{drives: m.drives, cars: m.cars, customers: m.customers, tx: tx, hours: DefaultHours(time.UTC),
+		logger: logging.Discard(), now: func() time.Time { return now }}, m
+}
+
+func TestService_Create(t *testing.T) {
+	s, m := initializeTest(t)
+	ctx := authz.WithPrincipal(context.Background(), &models.Principal{Name: "jane", Roles: []string{string(authz.Salesperson)}})
+
+	m.cars.EXPECT().Lock(gomock.Any(), carID).Return(nil)
+	m.customers.EXPECT().GetByID(gomock.Any(), customerID).Return(models.Customer{ID: customerID}, nil)
+	m.drives.EXPECT().GetOverlapping(gomock.Any(), carID, "jane", startsAt, endsAt).Return([]models.TestDrive{}, nil)
+	m.drives.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	drive, err := s.Create(ctx, &models.TestDrive{CarID: carID, CustomerID: customerID, StartsAt: startsAt, EndsAt: endsAt})
+	if err != nil {
+		t.Fatalf("\n[TEST] Failed. Desc : create\nGot %v\nExpected nil", err)
+	}
+
+	if drive.ID == uuid.Nil || drive.Status != models.TestDriveScheduled || drive.Salesperson != "jane" || !drive.CreatedAt.Equal(now) {
+		t.Errorf("\n[TEST] Failed. Desc : create\nGot %v\nExpected a test drive scheduled with the caller", drive)
+	}
+}
+
+func TestService_CreateErrors(t *testing.T) {
+	otherID := uuid.New()
+	other := models.TestDrive{ID: otherID, CarID: uuid.New(), Salesperson: "jane", StartsAt: startsAt.Add(-15 * time.Minute),
+		EndsAt: startsAt.Add(15 * time.Minute), Status: models.TestDriveScheduled}
+	booked := "is booked from 2022-01-03T09:45:00Z to 2022-01-03T10:15:00Z for test drive " + otherID.String()
+	drive := models.TestDrive{CarID: carID, CustomerID: customerID, Salesperson: "jane", StartsAt: startsAt, EndsAt: endsAt}
+
+	slot := func(start, end time.Time) models.TestDrive {
+		d := drive
+		d.StartsAt, d.EndsAt = start, end
+
+		return d
+	}
+
+	cases := []struct {
+		desc  string
+		drive models.TestDrive
+		mock  func(m mocks)
+		err   error
+	}{
+		{"missing fields", models.TestDrive{StartsAt: startsAt, EndsAt: endsAt}, func(m mocks) {},
+			errors.InvalidParam{Param: []string{"carId", "customerId", "salesperson"}}},
+		{"in the past", slot(now.Add(-time.Hour), now), func(m mocks) {}, errors.InvalidParam{Param: []string{"startsAt"}}},
+		{"too short", slot(startsAt, startsAt.Add(10*time.Minute)), func(m mocks) {},
+			errors.InvalidParam{Param: []string{"startsAt", "endsAt"}}},
+		{"too long", slot(startsAt, startsAt.Add(3*time.Hour)), func(m mocks) {},
+			errors.InvalidParam{Param: []string{"startsAt", "endsAt"}}},
+		{"after closing", slot(startsAt.Add(7*time.Hour+45*time.Minute), startsAt.Add(8*time.Hour+15*time.Minute)), func(m mocks) {},
+			errors.InvalidParam{Param: []string{"startsAt", "endsAt"}}},
+		{"on a sunday", slot(startsAt.Add(-24*time.Hour), endsAt.Add(-24*time.Hour)), func(m mocks) {},
+			errors.InvalidParam{Param: []string{"startsAt", "endsAt"}}},
+		{"missing car", drive, func(m mocks) {
+			m.cars.EXPECT().Lock(gomock.Any(), carID).Return(errors.EntityNotFound{Entity: "car", ID: carID.String()})
+		}, errors.EntityNotFound{Entity: "car", ID: carID.String()}},
+		{"car booked", drive, func(m mocks) {
+			m.cars.EXPECT().Lock(gomock.Any(), carID).Return(nil)
+			m.customers.EXPECT().GetByID(gomock.Any(), customerID).Return(models.Customer{ID: customerID}, nil)
+
+			car := other
+			car.CarID, car.Salesperson = carID, "john"
+
+			m.drives.EXPECT().GetOverlapping(gomock.Any(), carID, "jane", startsAt, endsAt).Return([]models.TestDrive{car}, nil)
+		}, errors.Conflict{Entity: "car", ID: carID.String(), Reason: booked}},
+		{"salesperson booked", drive, func(m mocks) {
+			m.cars.EXPECT().Lock(gomock.Any(), carID).Return(nil)
+			m.customers.EXPECT().GetByID(gomock.Any(), customerID).Return(models.Customer{ID: customerID}, nil)
+			m.drives.EXPECT().GetOverlapping(gomock.Any(), carID, "jane", startsAt, endsAt).Return([]models.TestDrive{other}, nil)
+		}, errors.Conflict{Entity: "salesperson", ID: "jane", Reason: booked}},
+	}
+
+	for i, tc := range cases {
+		s, m := initializeTest(t)
+		tc.mock(m)
+
+		_, err := s.Create(ctx, &tc.drive)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestService_Update(t *testing.T) {
+	stored := models.TestDrive{ID: id, CarID: carID, CustomerID: customerID, Salesperson: "jane", StartsAt: startsAt, EndsAt: endsAt,
+		Status: models.TestDriveScheduled, Notes: "motorway", CreatedAt: now, UpdatedAt: now}
+	started := stored
+	started.StartsAt, started.EndsAt = now.Add(-time.Hour), now.Add(-30*time.Minute)
+	cancelled := stored
+	cancelled.Status = models.TestDriveCancelled
+	moved := stored
+	moved.StartsAt, moved.EndsAt = startsAt.Add(time.Hour), endsAt.Add(time.Hour)
+
+	cases := []struct {
+		desc   string
+		update models.TestDrive
+		mock   func(m mocks)
+		err    error
+	}{
+		{"cancel", models.TestDrive{ID: id, Status: models.TestDriveCancelled}, func(m mocks) {
+			m.drives.EXPECT().GetByID(gomock.Any(), id).Return(stored, nil)
+			m.drives.EXPECT().Update(gomock.Any(), &cancelled).Return(nil)
+		}, nil},
+		{"no show", models.TestDrive{ID: id, Status: models.TestDriveNoShow}, func(m mocks) {
+			m.drives.EXPECT().GetByID(gomock.Any(), id).Return(started, nil)
+			m.drives.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+		}, nil},
+		{"no show before the start", models.TestDrive{ID: id, Status: models.TestDriveNoShow}, func(m mocks) {
+			m.drives.EXPECT().GetByID(gomock.Any(), id).Return(stored, nil)
+		}, errors.Conflict{Entity: "test drive", ID: id.String(), Reason: "has not started"}},
+		{"already cancelled", models.TestDrive{ID: id, Status: models.TestDriveCompleted}, func(m mocks) {
+			m.drives.EXPECT().GetByID(gomock.Any(), id).Return(cancelled, nil)
+		}, errors.Conflict{Entity: "test drive", ID: id.String(), Reason: "is cancelled"}},
+		{"unknown status", models.TestDrive{ID: id, Status: "lost"}, func(m mocks) {
+			m.drives.EXPECT().GetByID(gomock.Any(), id).Return(stored, nil)
+		}, errors.InvalidParam{Param: []string{"status"}}},
+		{"reschedule", models.TestDrive{ID: id, StartsAt: moved.StartsAt, EndsAt: moved.EndsAt}, func(m mocks) {
+			m.drives.EXPECT().GetByID(gomock.Any(), id).Return(stored, nil)
+			m.cars.EXPECT().Lock(gomock.Any(), carID).Return(nil)
+			m.drives.EXPECT().GetOverlapping(gomock.Any(), carID, "jane", moved.StartsAt, moved.EndsAt).Return([]models.TestDrive{stored}, nil)
+			m.drives.EXPECT().Update(gomock.Any(), &moved).Return(nil)
+		}, nil},
+		{"reschedule and cancel", models.TestDrive{ID: id, StartsAt: moved.StartsAt, EndsAt: moved.EndsAt,
+			Status: models.TestDriveCancelled}, func(m mocks) {
+			m.drives.EXPECT().GetByID(gomock.Any(), id).Return(stored, nil)
+		}, errors.InvalidParam{Param: []string{"status"}}},
+	}
+
+	for i, tc := range cases {
+		s, m := initializeTest(t)
+		tc.mock(m)
+
+		_, err := s.Update(ctx, &tc.update)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestService_GetAllCalendar(t *testing.T) {
+	s, m := initializeTest(t)
+
+	if _, err := s.GetAll(ctx, filters.TestDrive{Status: "lost"}); !reflect.DeepEqual(err, errors.InvalidParam{Param: []string{"status"}}) {
+		t.Errorf("\n[TEST] Failed. Desc : unknown status\nGot %v\nExpected invalid status", err)
+	}
+
+	m.drives.EXPECT().GetAll(gomock.Any(), filters.TestDrive{Salesperson: "jane", From: now.Add(-calendarHistory)}).
+		Return([]models.TestDrive{}, nil)
+
+	if _, err := s.Calendar(ctx, "jane"); err != nil {
+		t.Errorf("\n[TEST] Failed. Desc : calendar\nGot %v\nExpected nil", err)
+	}
+
+	if _, err := s.Calendar(ctx, ""); !reflect.DeepEqual(err, errors.MissingParam{Param: "salesperson"}) {
+		t.Errorf("\n[TEST] Failed. Desc : calendar without salesperson\nGot %v\nExpected missing salesperson", err)
+	}
+}
+
+func TestService_Forbidden(t *testing.T) {
+	viewer := authz.WithPrincipal(context.Background(), &models.Principal{Roles: []string{string(authz.Viewer)}})
+	manager := authz.WithPrincipal(context.Background(), &models.Principal{Roles: []string{string(authz.InventoryManager)}})
+
+	cases := []struct {
+		desc string
+		call func(s service) error
+		err  error
+	}{
+		{"anonymous read", func(s service) error {
+			_, err := s.GetByID(context.Background(), id)
+			return err
+		}, errors.Forbidden{Permission: string(authz.ReadTestDrives)}},
+		{"viewer list", func(s service) error {
+			_, err := s.GetAll(viewer, filters.TestDrive{})
+			return err
+		}, errors.Forbidden{Permission: string(authz.ReadTestDrives)}},
+		{"viewer calendar", func(s service) error {
+			_, err := s.Calendar(viewer, "jane")
+			return err
+		}, errors.Forbidden{Permission: string(authz.ReadTestDrives)}},
+		{"inventory manager book", func(s service) error {
+			_, err := s.Create(manager, &models.TestDrive{CarID: carID, CustomerID: customerID})
+			return err
+		}, errors.Forbidden{Permission: string(authz.ManageTestDrives)}},
+		{"viewer update", func(s service) error {
+			_, err := s.Update(viewer, &models.TestDrive{ID: id, Status: models.TestDriveCancelled})
+			return err
+		}, errors.Forbidden{Permission: string(authz.ManageTestDrives)}},
+	}
+
+	for i, tc := range cases {
+		s, _ := initializeTest(t)
+
+		if err := tc.call(s); !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+package tradein
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/authz"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+	"github.com/amehrotra/car-dealership/stores"
+	"github.com/amehrotra/car-dealership/types"
+)
+
+const (
+	// defaultValidity is how long an appraisal is offered for when it has no expiry
+	defaultValidity = 7 * 24 * time.Hour
+	// maxValidity is the longest an appraisal can be offered for
+	maxValidity = 30 * 24 * time.Hour
+	// maxPhotos is the most photos a trade-in has
+	maxPhotos = 20
+	// firstYear is the earliest year of manufacture, like the cars of the inventory
+	firstYear = 1866
+)
+
+type service struct {
+	tradeIns  stores.TradeIn
+	customers stores.Customer
+	cars      services.Car
+	tx        stores.Transactor
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+func New(tradeIns stores.TradeIn, customers stores.Customer, cars services.Car, tx stores.Transactor,
+	logger *slog.Logger) services.TradeIn {
+	return service{tradeIns: tradeIns, customers: customers, cars: cars, tx: tx, logger: logger, now: time.Now}
+}
+
+// Create records the vehicle a customer trades in, it awaits its appraisal
+func (s service) Create(ctx context.Context, tradeIn *models.TradeIn) (*models.TradeIn, error) {
+	if err := authz.Check(ctx, authz.ManageTradeIns); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+
+	if tradeIn.CustomerID == uuid.Nil {
+		return nil, errors.InvalidParam{Param: []string{"customerId"}}
+	}
+
+	if err := checkVehicle(tradeIn, now); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.customers.GetByID(ctx, tradeIn.CustomerID); err != nil {
+		return nil, err
+	}
+
+	tradeIn.ID = uuid.New()
+	tradeIn.Status = models.TradeInSubmitted
+	tradeIn.Appraisal, tradeIn.ApprovedBy, tradeIn.ApprovedAt, tradeIn.CarID = nil, "", nil, nil
+	tradeIn.CreatedAt = now
+	tradeIn.UpdatedAt = now
+
+	if err := s.tradeIns.Create(ctx, tradeIn); err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "trade-in submitted", "trade_in_id", tradeIn.ID, "customer_id", tradeIn.CustomerID)
+
+	return tradeIn, nil
+}
+
+// GetAll lists the trade-ins of the customer and status of the filter
+func (s service) GetAll(ctx context.Context, filter filters.TradeIn) ([]models.TradeIn, error) {
+	if err := authz.Check(ctx, authz.ReadTradeIns); err != nil {
+		return nil, err
+	}
+
+	switch filter.Status {
+	case "", models.TradeInSubmitted, models.TradeInAppraised, models.TradeInApproved, models.TradeInRejected, models.TradeInConverted:
+	default:
+		return nil, errors.InvalidParam{Param: []string{"status"}}
+	}
+
+	return s.tradeIns.GetAll(ctx, filter)
+}
+
+// GetByID fetches the trade-in
+func (s service) GetByID(ctx context.Context, id uuid.UUID) (*models.TradeIn, error) {
+	if err := authz.Check(ctx, authz.ReadTradeIns); err != nil {
+		return nil, err
+	}
+
+	tradeIn, err := s.tradeIns.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tradeIn, nil
+}
+
+// Update replaces the details of the vehicle of a trade-in that is not approved yet. The appraisal was of the vehicle
+// as it was described, so the trade-in awaits a new one.
+func (s service) Update(ctx context.Context, tradeIn *models.TradeIn) (*models.TradeIn, error) {
+	if err := authz.Check(ctx, authz.ManageTradeIns); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.tradeIns.GetByID(ctx, tradeIn.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if stored.Status != models.TradeInSubmitted && stored.Status != models.TradeInAppraised {
+		return nil, errors.Conflict{Entity: "trade-in", ID: tradeIn.ID.String(), Reason: "is " + stored.Status}
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+
+	if err := checkVehicle(tradeIn, now); err != nil {
+		return nil, err
+	}
+
+	tradeIn.CustomerID = stored.CustomerID
+	tradeIn.Status = models.TradeInSubmitted
+	tradeIn.Appraisal, tradeIn.ApprovedBy, tradeIn.ApprovedAt, tradeIn.CarID = nil, "", nil, nil
+	tradeIn.CreatedAt = stored.CreatedAt
+	tradeIn.UpdatedAt = now
+
+	if err := s.tradeIns.Update(ctx, tradeIn); err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "trade-in updated", "trade_in_id", tradeIn.ID)
+
+	return tradeIn, nil
+}
+
+// Appraise offers a value for the trade-in until the appraisal expires, a week from now when it has no expiry.
+// Trade-ins are appraised again as long as they are not approved, or once their approved appraisal expired, and a new
+// appraisal needs a new approval. The trade-in is locked so that an approval and a new appraisal happen one after the other.
+func (s service) Appraise(ctx context.Context, id uuid.UUID, appraisal *models.Appraisal) (*models.TradeIn, error) {
+	if err := authz.Check(ctx, authz.ManageTradeIns); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+
+	if appraisal.ExpiresAt.IsZero() {
+		appraisal.ExpiresAt = now.Add(defaultValidity)
+	}
+
+	appraisal.ExpiresAt = appraisal.ExpiresAt.UTC().Truncate(time.Second)
+
+	var params []string
+
+	if appraisal.Value <= 0 {
+		params = append(params, "value")
+	}
+
+	if !appraisal.ExpiresAt.After(now) || appraisal.ExpiresAt.After(now.Add(maxValidity)) {
+		params = append(params, "expiresAt")
+	}
+
+	if len(params) > 0 {
+		return nil, errors.InvalidParam{Param: params}
+	}
+
+	appraisal.AppraisedBy = caller(ctx)
+	appraisal.AppraisedAt = now
+
+	var tradeIn models.TradeIn
+
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		var err error
+
+		if tradeIn, err = s.tradeIns.Lock(ctx, id); err != nil {
+			return err
+		}
+
+		switch {
+		case tradeIn.Status == models.TradeInApproved && !expired(&tradeIn, now):
+			return errors.Conflict{Entity: "trade-in", ID: id.String(), Reason: "is approved until " +
+				tradeIn.Appraisal.ExpiresAt.Format(time.RFC3339)}
+		case tradeIn.Status != models.TradeInSubmitted && tradeIn.Status != models.TradeInAppraised && tradeIn.Status != models.TradeInApproved:
+			return errors.Conflict{Entity: "trade-in", ID: id.String(), Reason: "is " + tradeIn.Status}
+		}
+
+		tradeIn.Appraisal = appraisal
+		tradeIn.ApprovedBy, tradeIn.ApprovedAt = "", nil
+		tradeIn.Status = models.TradeInAppraised
+		tradeIn.UpdatedAt = now
+
+		return s.tradeIns.Update(ctx, &tradeIn)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "trade-in appraised", "trade_in_id", id, "value", appraisal.Value, "expires_at", appraisal.ExpiresAt)
+
+	return &tradeIn, nil
+}
+
+// Review approves the appraisal of the trade-in on behalf of the caller, or rejects the trade-in. Only appraisals that
+// have not expired are approved, by someone other than their appraiser, while trade-ins are rejected at any point before
+// they are converted. The trade-in is locked so that the appraisal approved is the one read.
+func (s service) Review(ctx context.Context, id uuid.UUID, approve bool) (*models.TradeIn, error) {
+	if err := authz.Check(ctx, authz.ApproveTradeIns); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+	reviewer := caller(ctx)
+
+	var tradeIn models.TradeIn
+
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		var err error
+
+		if tradeIn, err = s.tradeIns.Lock(ctx, id); err != nil {
+			return err
+		}
+
+		switch {
+		case tradeIn.Status == models.TradeInRejected || tradeIn.Status == models.TradeInConverted:
+			return errors.Conflict{Entity: "trade-in", ID: id.String(), Reason: "is " + tradeIn.Status}
+		case !approve:
+			tradeIn.Status = models.TradeInRejected
+		case tradeIn.Status != models.TradeInAppraised:
+			return errors.Conflict{Entity: "trade-in", ID: id.String(), Reason: "is " + tradeIn.Status}
+		case expired(&tradeIn, now):
+			return errors.Conflict{Entity: "trade-in", ID: id.String(), Reason: "has an appraisal that expired at " +
+				tradeIn.Appraisal.ExpiresAt.Format(time.RFC3339)}
+		case reviewer == tradeIn.Appraisal.AppraisedBy:
+			return errors.Conflict{Entity: "trade-in", ID: id.String(), Reason: "is appraised by " + reviewer +
+				", who cannot approve it"}
+		default:
+			approvedAt := now
+			tradeIn.Status = models.TradeInApproved
+			tradeIn.ApprovedBy, tradeIn.ApprovedAt = reviewer, &approvedAt
+		}
+
+		tradeIn.UpdatedAt = now
+
+		return s.tradeIns.Update(ctx, &tradeIn)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "trade-in reviewed", "trade_in_id", id, "status", tradeIn.Status)
+
+	return &tradeIn, nil
+}
+
+// Convert adds the vehicle of an approved trade-in to the inventory as a used car while its appraisal holds. The car
+// takes the brand, model, year, fuel type and mileage of the vehicle and the price and engine given, and is created
+// through the car service in the transaction marking the trade-in converted so that a trade-in becomes a single car.
+func (s service) Convert(ctx context.Context, id uuid.UUID, car *models.Car) (*models.Car, error) {
+	if err := authz.Check(ctx, authz.CreateCars); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		tradeIn, err := s.tradeIns.Lock(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case tradeIn.Status != models.TradeInApproved:
+			return errors.Conflict{Entity: "trade-in", ID: id.String(), Reason: "is " + tradeIn.Status}
+		case expired(&tradeIn, now):
+			return errors.Conflict{Entity: "trade-in", ID: id.String(), Reason: "has an appraisal that expired at " +
+				tradeIn.Appraisal.ExpiresAt.Format(time.RFC3339)}
+		}
+
+		car.Brand, car.Model, car.ManufactureYear, car.FuelType = tradeIn.Brand, tradeIn.Model, tradeIn.ManufactureYear, tradeIn.FuelType
+		car.Condition, car.Mileage = models.CarUsed, tradeIn.Mileage
+
+		created, err := s.cars.Create(ctx, car)
+		if err != nil {
+			return err
+		}
+
+		car = created
+
+		tradeIn.CarID = &created.ID
+		tradeIn.Status = models.TradeInConverted
+		tradeIn.UpdatedAt = now
+
+		return s.tradeIns.Update(ctx, &tradeIn)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "trade-in converted", "trade_in_id", id, "car_id", car.ID)
+
+	return car, nil
+}
+
+// checkVehicle validates the details of the vehicle of the trade-in, the photos are links to the pictures
+func checkVehicle(tradeIn *models.TradeIn, now time.Time) error {
+	var params []string
+
+	if tradeIn.Brand == "" {
+		params = append(params, "brand")
+	}
+
+	if tradeIn.Model == "" {
+		params = append(params, "model")
+	}
+
+	if tradeIn.ManufactureYear < firstYear || tradeIn.ManufactureYear > now.Year() {
+		params = append(params, "yearOfManufacture")
+	}
+
+	if tradeIn.FuelType < types.Diesel || tradeIn.FuelType > types.Electric {
+		params = append(params, "fuelType")
+	}
+
+	if tradeIn.Mileage < 0 {
+		params = append(params, "mileage")
+	}
+
+	if len(tradeIn.Photos) > maxPhotos {
+		params = append(params, "photos")
+	} else {
+		for _, photo := range tradeIn.Photos {
+			if u, err := url.Parse(photo); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				params = append(params, "photos")
+
+				break
+			}
+		}
+	}
+
+	if tradeIn.Photos == nil {
+		tradeIn.Photos = make([]string, 0)
+	}
+
+	if len(params) > 0 {
+		return errors.InvalidParam{Param: params}
+	}
+
+	return nil
+}
+
+// expired reports whether the appraisal of the trade-in is missing or past its expiry
+func expired(tradeIn *models.TradeIn, now time.Time) bool {
+	return tradeIn.Appraisal == nil || !now.Before(tradeIn.Appraisal.ExpiresAt)
+}
+
+// caller is the name of the principal of ctx, empty without one
+func caller(ctx context.Context) string {
+	if principal, ok := authz.PrincipalFromContext(ctx); ok {
+		return principal.Name
+	}
+
+	return ""
+}
@@ -0,0 +1,299 @@
+package tradein
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/authz"
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+	"github.com/amehrotra/car-dealership/stores"
+	"github.com/amehrotra/car-dealership/types"
+)
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	// principal allowed to perform every operation
+	ctx        = authz.WithPrincipal(context.Background(), &models.Principal{Roles: []string{string(authz.Admin)}})
+	now        = time.Date(2022, 1, 10, 0, 0, 0, 0, time.UTC)
+	id         = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+	carID      = uuid.MustParse("3b7e4a1c-2b0e-4d55-a3a4-6f1f1f0c9a10")
+	customerID = uuid.MustParse("c4d0f7a2-5e1b-4f0e-9d57-0a2b8e6d3c21")
+	vehicle    = models.TradeIn{CustomerID: customerID, Brand: "BMW", Model: "320d", ManufactureYear: 2015, FuelType: types.Diesel,
+		Mileage: 120000, Checklist: models.Checklist{Bodywork: true}, Photos: []string{"https://photos.example.com/1.jpg"}}
+)
+
+type mocks struct {
+	tradeIns  *stores.MockTradeIn
+	customers *stores.MockCustomer
+	cars      *services.MockCar
+}
+
+func initializeTest(t *testing.T) (service, mocks) {
+	ctrl := gomock.NewController(t)
+
+	m := mocks{tradeIns: stores.NewMockTradeIn(ctrl), customers: stores.NewMockCustomer(ctrl), cars: services.NewMockCar(ctrl)}
+
+	tx := stores.NewMockTransactor(ctrl)
+	tx.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).AnyTimes()
+
+	return service{tradeIns: m.tradeIns, customers: m.customers, cars: m.cars, tx: tx, logger: logging.Discard(),
+		now: func() time.Time { return now }}, m
+}
+
+// stored is the vehicle as a stored trade-in in the status, appraised until the expiry unless it is zero
+func stored(status string, expiresAt time.Time) models.TradeIn {
+	t := vehicle
+	t.ID, t.Status, t.CreatedAt, t.UpdatedAt = id, status, now.Add(-time.Hour), now.Add(-time.Hour)
+
+	if !expiresAt.IsZero() {
+		t.Appraisal = &models.Appraisal{Value: 900000, ExpiresAt: expiresAt, AppraisedBy: "jane", AppraisedAt: now.Add(-time.Hour)}
+	}
+
+	return t
+}
+
+func TestService_Create(t *testing.T) {
+	s, m := initializeTest(t)
+
+	m.customers.EXPECT().GetByID(gomock.Any(), customerID).Return(models.Customer{ID: customerID}, nil)
+	m.tradeIns.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	tradeIn := vehicle
+
+	output, err := s.Create(ctx, &tradeIn)
+	if err != nil || output.ID == uuid.Nil || output.Status != models.TradeInSubmitted || !output.CreatedAt.Equal(now) {
+		t.Errorf("\n[TEST] Failed. Desc : create\nGot %v, %v\nExpected a submitted trade-in", output, err)
+	}
+
+	invalid := models.TradeIn{CustomerID: customerID, ManufactureYear: 2023, FuelType: 3, Mileage: -1,
+		Photos: []string{"file:///photo.jpg"}}
+	params := errors.InvalidParam{Param: []string{"brand", "model", "yearOfManufacture", "fuelType", "mileage", "photos"}}
+
+	if _, err := s.Create(ctx, &invalid); !reflect.DeepEqual(err, params) {
+		t.Errorf("\n[TEST] Failed. Desc : invalid vehicle\nGot %v\nExpected %v", err, params)
+	}
+}
+
+func TestService_Update(t *testing.T) {
+	s, m := initializeTest(t)
+
+	update := vehicle
+	update.ID, update.Mileage = id, 125000
+
+	expected := stored(models.TradeInSubmitted, time.Time{})
+	expected.Mileage, expected.UpdatedAt = 125000, now
+
+	m.tradeIns.EXPECT().GetByID(gomock.Any(), id).Return(stored(models.TradeInAppraised, now.Add(time.Hour)), nil)
+	m.tradeIns.EXPECT().Update(gomock.Any(), &expected).Return(nil)
+
+	if _, err := s.Update(ctx, &update); err != nil {
+		t.Errorf("\n[TEST] Failed. Desc : update voids the appraisal\nGot %v\nExpected nil", err)
+	}
+
+	s, m = initializeTest(t)
+
+	m.tradeIns.EXPECT().GetByID(gomock.Any(), id).Return(stored(models.TradeInApproved, now.Add(time.Hour)), nil)
+
+	conflict := errors.Conflict{Entity: "trade-in", ID: id.String(), Reason: "is approved"}
+	if _, err := s.Update(ctx, &update); !reflect.DeepEqual(err, conflict) {
+		t.Errorf("\n[TEST] Failed. Desc : update approved\nGot %v\nExpected %v", err, conflict)
+	}
+}
+
+func TestService_Appraise(t *testing.T) {
+	ctx := authz.WithPrincipal(context.Background(), &models.Principal{Name: "jane", Roles: []string{string(authz.Salesperson)}})
+
+	cases := []struct {
+		desc      string
+		stored    models.TradeIn
+		appraisal models.Appraisal
+		expiresAt time.Time
+		err       error
+	}{
+		{"default expiry", stored(models.TradeInSubmitted, time.Time{}), models.Appraisal{Value: 900000}, now.Add(defaultValidity), nil},
+		{"again after the approval expired", stored(models.TradeInApproved, now), models.Appraisal{Value: 800000,
+			ExpiresAt: now.Add(48 * time.Hour)}, now.Add(48 * time.Hour), nil},
+		{"approved", stored(models.TradeInApproved, now.Add(time.Hour)), models.Appraisal{Value: 800000}, time.Time{},
+			errors.Conflict{Entity: "trade-in", ID: id.String(), Reason: "is approved until 2022-01-10T01:00:00Z"}},
+		{"rejected", stored(models.TradeInRejected, time.Time{}), models.Appraisal{Value: 800000}, time.Time{},
+			errors.Conflict{Entity: "trade-in", ID: id.String(), Reason: "is rejected"}},
+		{"invalid appraisal", stored(models.TradeInSubmitted, time.Time{}), models.Appraisal{ExpiresAt: now.Add(maxValidity + time.Hour)},
+			time.Time{}, errors.InvalidParam{Param: []string{"value", "expiresAt"}}},
+	}
+
+	for i, tc := range cases {
+		s, m := initializeTest(t)
+
+		if _, invalid := tc.err.(errors.InvalidParam); !invalid {
+			m.tradeIns.EXPECT().Lock(gomock.Any(), id).Return(tc.stored, nil)
+		}
+
+		if tc.err == nil {
+			m.tradeIns.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+		}
+
+		output, err := s.Appraise(ctx, id, &tc.appraisal)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+
+		if err == nil && (output.Status != models.TradeInAppraised || output.ApprovedAt != nil || output.Appraisal.AppraisedBy != "jane" ||
+			!output.Appraisal.ExpiresAt.Equal(tc.expiresAt)) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected an appraisal by jane until %v", i, tc.desc, output, tc.expiresAt)
+		}
+	}
+}
+
+func TestService_Review(t *testing.T) {
+	ctx := authz.WithPrincipal(context.Background(), &models.Principal{Name: "john", Roles: []string{string(authz.InventoryManager)}})
+
+	selfAppraised := stored(models.TradeInAppraised, now.Add(time.Hour))
+	selfAppraised.Appraisal.AppraisedBy = "john"
+
+	cases := []struct {
+		desc    string
+		stored  models.TradeIn
+		approve bool
+		status  string
+		err     error
+	}{
+		{"approve", stored(models.TradeInAppraised, now.Add(time.Hour)), true, models.TradeInApproved, nil},
+		{"reject", stored(models.TradeInSubmitted, time.Time{}), false, models.TradeInRejected, nil},
+		{"approve expired appraisal", stored(models.TradeInAppraised, now), true, "",
+			errors.Conflict{Entity: "trade-in", ID: id.String(), Reason: "has an appraisal that expired at 2022-01-10T00:00:00Z"}},
+		{"approve without appraisal", stored(models.TradeInSubmitted, time.Time{}), true, "",
+			errors.Conflict{Entity: "trade-in", ID: id.String(), Reason: "is submitted"}},
+		{"reject converted", stored(models.TradeInConverted, now.Add(time.Hour)), false, "",
+			errors.Conflict{Entity: "trade-in", ID: id.String(), Reason: "is converted"}},
+		{"approve own appraisal", selfAppraised, true, "",
+			errors.Conflict{Entity: "trade-in", ID: id.String(), Reason: "is appraised by john, who cannot approve it"}},
+		{"reject own appraisal", selfAppraised, false, models.TradeInRejected, nil},
+	}
+
+	for i, tc := range cases {
+		s, m := initializeTest(t)
+
+		m.tradeIns.EXPECT().Lock(gomock.Any(), id).Return(tc.stored, nil)
+
+		if tc.err == nil {
+			m.tradeIns.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+		}
+
+		output, err := s.Review(ctx, id, tc.approve)
+
+		if !reflect.DeepEqual(err, tc.err) || (err == nil && output.Status != tc.status) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v, %v", i, tc.desc, output, err, tc.status, tc.err)
+		}
+
+		if tc.approve && err == nil && (output.ApprovedBy != "john" || output.ApprovedAt == nil) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected the approval of john", i, tc.desc, output)
+		}
+	}
+}
+
+func TestService_Convert(t *testing.T) {
+	s, m := initializeTest(t)
+
+	engine := models.Engine{Displacement: 2000, NCylinder: 4}
+	car := models.Car{Brand: "BMW", Model: "320d", ManufactureYear: 2015, FuelType: types.Diesel, Price: 1500000,
+		Condition: models.CarUsed, Mileage: 120000, Engine: engine}
+	created := car
+	created.ID = carID
+
+	converted := stored(models.TradeInApproved, now.Add(time.Hour))
+	converted.CarID, converted.Status, converted.UpdatedAt = &carID, models.TradeInConverted, now
+
+	m.tradeIns.EXPECT().Lock(gomock.Any(), id).Return(stored(models.TradeInApproved, now.Add(time.Hour)), nil)
+	m.cars.EXPECT().Create(gomock.Any(), &car).Return(&created, nil)
+	m.tradeIns.EXPECT().Update(gomock.Any(), &converted).Return(nil)
+
+	output, err := s.Convert(ctx, id, &models.Car{Brand: "Audi", Price: 1500000, Engine: engine})
+	if err != nil || !reflect.DeepEqual(output, &created) {
+		t.Errorf("\n[TEST] Failed. Desc : convert\nGot %v, %v\nExpected %v", output, err, created)
+	}
+
+	cases := []struct {
+		desc   string
+		stored models.TradeIn
+		err    error
+	}{
+		{"not approved", stored(models.TradeInAppraised, now.Add(time.Hour)),
+			errors.Conflict{Entity: "trade-in", ID: id.String(), Reason: "is appraised"}},
+		{"expired appraisal", stored(models.TradeInApproved, now.Add(-time.Hour)),
+			errors.Conflict{Entity: "trade-in", ID: id.String(), Reason: "has an appraisal that expired at 2022-01-09T23:00:00Z"}},
+	}
+
+	for i, tc := range cases {
+		s, m := initializeTest(t)
+
+		m.tradeIns.EXPECT().Lock(gomock.Any(), id).Return(tc.stored, nil)
+
+		_, err := s.Convert(ctx, id, &models.Car{Price: 1500000, Engine: engine})
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestService_Forbidden(t *testing.T) {
+	viewer := authz.WithPrincipal(context.Background(), &models.Principal{Roles: []string{string(authz.Viewer)}})
+	salesperson := authz.WithPrincipal(context.Background(), &models.Principal{Roles: []string{string(authz.Salesperson)}})
+
+	cases := []struct {
+		desc string
+		call func(s service) error
+		err  error
+	}{
+		{"anonymous read", func(s service) error {
+			_, err := s.GetByID(context.Background(), id)
+			return err
+		}, errors.Forbidden{Permission: string(authz.ReadTradeIns)}},
+		{"viewer list", func(s service) error {
+			_, err := s.GetAll(viewer, filters.TradeIn{})
+			return err
+		}, errors.Forbidden{Permission: string(authz.ReadTradeIns)}},
+		{"viewer create", func(s service) error {
+			tradeIn := vehicle
+			_, err := s.Create(viewer, &tradeIn)
+			return err
+		}, errors.Forbidden{Permission: string(authz.ManageTradeIns)}},
+		{"viewer update", func(s service) error {
+			tradeIn := vehicle
+			_, err := s.Update(viewer, &tradeIn)
+			return err
+		}, errors.Forbidden{Permission: string(authz.ManageTradeIns)}},
+		{"viewer appraise", func(s service) error {
+			_, err := s.Appraise(viewer, id, &models.Appraisal{Value: 900000})
+			return err
+		}, errors.Forbidden{Permission: string(authz.ManageTradeIns)}},
+		{"salesperson approve", func(s service) error {
+			_, err := s.Review(salesperson, id, true)
+			return err
+		}, errors.Forbidden{Permission: string(authz.ApproveTradeIns)}},
+		{"salesperson convert", func(s service) error {
+			_, err := s.Convert(salesperson, id, &models.Car{Price: 1500000})
+			return err
+		}, errors.Forbidden{Permission: string(authz.CreateCars)}},
+	}
+
+	for i, tc := range cases {
+		s, _ := initializeTest(t)
+
+		if err := tc.call(s); !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package webhook
+
+import (
+	goError "errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// errNotPublic refuses connections to addresses of the internal network
+var errNotPublic = goError.New("webhook address is not public")
+
+// nolint:gochecknoglobals // read only shared address space of carrier-grade NAT, not covered by netip.Addr.IsPrivate
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// NewClient returns the client the deliveries are sent with. It only connects to public addresses, whatever the host of
+// a webhook resolves to at the time, and does not follow redirects, so that webhooks cannot reach internal services.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: dialPublic}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// dialPublic refuses to connect to the resolved address unless it is public
+func dialPublic(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil || !public(ip) {
+		return errNotPublic
+	}
+
+	return nil
+}
+
+// public reports whether the address is reachable on the internet rather than loopback, private, link-local or shared
+func public(ip netip.Addr) bool {
+	ip = ip.Unmap()
+
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !sharedAddressSpace.Contains(ip)
+}
@@ -0,0 +1,52 @@
+package webhook
+
+import (
+	goError "errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestPublic(t *testing.T) {
+	cases := []struct {
+		desc   string
+		ip     string
+		public bool
+	}{
+		{"public", "93.184.216.34", true},
+		{"public ipv6", "2606:2800:220:1:248:1893:25c8:1946", true},
+		{"loopback", "127.0.0.1", false},
+		{"ipv6 loopback", "::1", false},
+		{"cloud metadata", "169.254.169.254", false},
+		{"private", "10.1.2.3", false},
+		{"private ipv6", "fd00::1", false},
+		{"shared address space", "100.64.0.1", false},
+		{"unspecified", "0.0.0.0", false},
+		{"mapped loopback", "::ffff:127.0.0.1", false},
+	}
+
+	for i, tc := range cases {
+		if public := public(netip.MustParseAddr(tc.ip)); public != tc.public {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, public, tc.public)
+		}
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(time.Second)
+
+	if _, err := client.Get(server.URL); !goError.Is(err, errNotPublic) {
+		t.Errorf("\n[TEST] Failed. Desc : loopback webhook\nGot %v\nExpected %v", err, errNotPublic)
+	}
+
+	if err := client.CheckRedirect(nil, nil); !goError.Is(err, http.ErrUseLastResponse) {
+		t.Errorf("\n[TEST] Failed. Desc : redirect\nGot %v\nExpected %v", err, http.ErrUseLastResponse)
+	}
+}
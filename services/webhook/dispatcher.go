@@ -0,0 +1,209 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+const (
+	batchSize    = 50
+	maxAttempts  = 10
+	baseBackoff  = 30 * time.Second
+	maxBackoff   = 6 * time.Hour
+	sendTimeout  = 10 * time.Second
+	maxErrorSize = 255
+
+	// lease outlasts sending a whole batch one delivery after another, each cut off at sendTimeout, so that other
+	// instances do not take over deliveries that are still waiting their turn
+	lease = batchSize*sendTimeout + time.Minute
+)
+
+// Dispatcher sends the queued deliveries to their webhooks, retrying failures with exponential backoff
+// until they run out of attempts and are dead-lettered
+type Dispatcher struct {
+	webhooks   stores.Webhook
+	deliveries stores.Delivery
+	tx         stores.Transactor
+	client     *http.Client
+	logger     *slog.Logger
+	now        func() time.Time
+}
+
+func NewDispatcher(webhooks stores.Webhook, deliveries stores.Delivery, tx stores.Transactor, client *http.Client,
+	logger *slog.Logger) *Dispatcher {
+	return &Dispatcher{webhooks: webhooks, deliveries: deliveries, tx: tx, client: client, logger: logger, now: time.Now}
+}
+
+// Run delivers the due deliveries every interval until ctx is done
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.Deliver(ctx); err != nil {
+			d.logger.ErrorContext(ctx, "error in delivering webhooks", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Deliver sends the deliveries due now and returns how many of them were delivered.
+// The due deliveries are leased first so that other instances skip them while they are being sent.
+func (d *Dispatcher) Deliver(ctx context.Context) (int, error) {
+	var due []models.Delivery
+
+	err := d.tx.InTx(ctx, func(ctx context.Context) error {
+		now := d.now().UTC()
+
+		var err error
+
+		due, err = d.deliveries.Due(ctx, now, batchSize)
+		if err != nil {
+			return err
+		}
+
+		for i := range due {
+			due[i].NextAttemptAt = now.Add(lease)
+
+			if err := d.deliveries.Update(ctx, &due[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+
+	for i := range due {
+		if err := d.attempt(ctx, &due[i]); err != nil {
+			return delivered, err
+		}
+
+		if due[i].Status == models.DeliveryDelivered {
+			delivered++
+		}
+	}
+
+	return delivered, nil
+}
+
+// attempt sends the delivery once and records the outcome
+func (d *Dispatcher) attempt(ctx context.Context, delivery *models.Delivery) error {
+	webhook, err := d.webhooks.GetByID(ctx, delivery.WebhookID)
+
+	switch err.(type) {
+	case nil:
+	case errors.EntityNotFound:
+		// the webhook was deleted along with its deliveries while this one was being sent
+		return nil
+	default:
+		return err
+	}
+
+	now := d.now().UTC()
+
+	if !webhook.Active {
+		delivery.Status = models.DeliveryDead
+		delivery.LastError = "webhook is inactive"
+
+		d.logger.WarnContext(ctx, "delivery dead-lettered", "webhook_id", webhook.ID, "delivery_id", delivery.ID, "error", delivery.LastError)
+
+		return d.deliveries.Update(ctx, delivery)
+	}
+
+	delivery.Attempts++
+
+	err = d.send(ctx, &webhook, delivery)
+
+	switch {
+	case err == nil:
+		delivery.Status = models.DeliveryDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= maxAttempts:
+		delivery.Status = models.DeliveryDead
+		delivery.LastError = truncate(err.Error())
+
+		d.logger.WarnContext(ctx, "delivery dead-lettered", "webhook_id", webhook.ID, "delivery_id", delivery.ID, "error", err)
+	default:
+		delivery.NextAttemptAt = now.Add(backoff(delivery.Attempts))
+		delivery.LastError = truncate(err.Error())
+
+		d.logger.InfoContext(ctx, "delivery failed", "webhook_id", webhook.ID, "delivery_id", delivery.ID,
+			"attempts", delivery.Attempts, "error", err)
+	}
+
+	return d.deliveries.Update(ctx, delivery)
+}
+
+// send posts the signed payload within sendTimeout, any status other than 2xx is a failure
+func (d *Dispatcher) send(ctx context.Context, webhook *models.Webhook, delivery *models.Delivery) error {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Webhook-Id", delivery.EventID.String())
+	req.Header.Set("Webhook-Event", delivery.EventType)
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, d.now(), delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("receiver answered %s", resp.Status)
+	}
+
+	return nil
+}
+
+// backoff returns the wait before the next attempt, doubling from baseBackoff up to maxBackoff
+func backoff(attempts int) time.Duration {
+	wait := baseBackoff
+
+	for i := 1; i < attempts && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+
+	if wait > maxBackoff {
+		return maxBackoff
+	}
+
+	return wait
+}
+
+func truncate(s string) string {
+	if len(s) > maxErrorSize {
+		return s[:maxErrorSize]
+	}
+
+	return s
+}
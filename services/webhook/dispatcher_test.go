@@ -0,0 +1,180 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+// initializeDispatcherTest returns a dispatcher whose transactions run their function directly, along with the
+// deliveries it records
+func initializeDispatcherTest(t *testing.T, webhook models.Webhook, due models.Delivery) (*Dispatcher, *[]models.Delivery) {
+	ctrl := gomock.NewController(t)
+
+	mockWebhook := stores.NewMockWebhook(ctrl)
+	mockDelivery := stores.NewMockDelivery(ctrl)
+	mockTx := stores.NewMockTransactor(ctrl)
+
+	mockTx.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+
+	recorded := make([]models.Delivery, 0)
+
+	mockDelivery.EXPECT().Due(gomock.Any(), now, batchSize).Return([]models.Delivery{due}, nil)
+	mockDelivery.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, delivery *models.Delivery) error {
+		recorded = append(recorded, *delivery)
+
+		return nil
+	}).Times(2)
+	mockWebhook.EXPECT().GetByID(gomock.Any(), webhook.ID).Return(webhook, nil)
+
+	d := NewDispatcher(mockWebhook, mockDelivery, mockTx, http.DefaultClient, logging.Discard())
+	d.now = func() time.Time { return now }
+
+	return d, &recorded
+}
+
+func dueDelivery(webhookID uuid.UUID, attempts int) models.Delivery {
+	return models.Delivery{ID: uuid.New(), WebhookID: webhookID, EventID: uuid.New(), EventType: models.CarUpdated,
+		Payload: []byte(`{"type":"car.updated"}`), Status: models.DeliveryPending, Attempts: attempts, NextAttemptAt: now}
+}
+
+func TestDispatcher_DeliverSigned(t *testing.T) {
+	received := make(chan *http.Request, 1)
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		if err := Verify("secret", r.Header.Get(SignatureHeader), body, 5*time.Minute, now); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		received <- r
+	}))
+	defer receiver.Close()
+
+	webhook := models.Webhook{ID: uuid.New(), URL: receiver.URL, Active: true, Secret: "secret"}
+	due := dueDelivery(webhook.ID, 0)
+	d, recorded := initializeDispatcherTest(t, webhook, due)
+
+	delivered, err := d.Deliver(context.Background())
+	if err != nil || delivered != 1 {
+		t.Fatalf("\n[TEST] Failed. Desc : signed delivery\nGot %v, %v\nExpected 1 delivered", delivered, err)
+	}
+
+	r := <-received
+	if r.Header.Get("Webhook-Id") != due.EventID.String() || r.Header.Get("Webhook-Event") != models.CarUpdated {
+		t.Errorf("\n[TEST] Failed. Desc : delivery headers\nGot %v\nExpected event id and type", r.Header)
+	}
+
+	leased, outcome := (*recorded)[0], (*recorded)[1]
+
+	if !leased.NextAttemptAt.Equal(now.Add(lease)) {
+		t.Errorf("\n[TEST] Failed. Desc : lease\nGot %v\nExpected %v", leased.NextAttemptAt, now.Add(lease))
+	}
+
+	if outcome.Status != models.DeliveryDelivered || outcome.Attempts != 1 || outcome.DeliveredAt == nil {
+		t.Errorf("\n[TEST] Failed. Desc : signed delivery\nGot %v\nExpected delivered after one attempt", outcome)
+	}
+}
+
+func TestDispatcher_DeliverFailures(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	cases := []struct {
+		desc     string
+		active   bool
+		attempts int
+		output   models.Delivery
+	}{
+		{"first failure", true, 0, models.Delivery{Status: models.DeliveryPending, Attempts: 1, NextAttemptAt: now.Add(30 * time.Second),
+			LastError: "receiver answered 503 Service Unavailable"}},
+		{"fourth failure", true, 3, models.Delivery{Status: models.DeliveryPending, Attempts: 4, NextAttemptAt: now.Add(4 * time.Minute),
+			LastError: "receiver answered 503 Service Unavailable"}},
+		{"out of attempts", true, maxAttempts - 1, models.Delivery{Status: models.DeliveryDead, Attempts: maxAttempts,
+			NextAttemptAt: now.Add(lease), LastError: "receiver answered 503 Service Unavailable"}},
+		{"inactive webhook", false, 0, models.Delivery{Status: models.DeliveryDead, NextAttemptAt: now.Add(lease),
+			LastError: "webhook is inactive"}},
+	}
+
+	for i, tc := range cases {
+		webhook := models.Webhook{ID: uuid.New(), URL: receiver.URL, Active: tc.active, Secret: "secret"}
+		d, recorded := initializeDispatcherTest(t, webhook, dueDelivery(webhook.ID, tc.attempts))
+
+		delivered, err := d.Deliver(context.Background())
+		if err != nil || delivered != 0 {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected nothing delivered", i, tc.desc, delivered, err)
+		}
+
+		outcome := (*recorded)[1]
+
+		if outcome.Status != tc.output.Status || outcome.Attempts != tc.output.Attempts || !outcome.NextAttemptAt.Equal(tc.output.NextAttemptAt) ||
+			outcome.LastError != tc.output.LastError {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, outcome, tc.output)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	cases := []struct {
+		attempts int
+		wait     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{5, 8 * time.Minute},
+		{9, 128 * time.Minute},
+		{11, 6 * time.Hour},
+		{50, 6 * time.Hour},
+	}
+
+	for i, tc := range cases {
+		if wait := backoff(tc.attempts); wait != tc.wait {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v attempts\nGot %v\nExpected %v", i, tc.attempts, wait, tc.wait)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"type":"car.created"}`)
+	header := Sign("secret", now, body)
+
+	cases := []struct {
+		desc   string
+		secret string
+		header string
+		body   []byte
+		now    time.Time
+		valid  bool
+	}{
+		{"valid", "secret", header, body, now.Add(time.Minute), true},
+		{"other secret", "other", header, body, now, false},
+		{"tampered body", "secret", header, []byte(`{"type":"car.deleted"}`), now, false},
+		{"replayed", "secret", header, body, now.Add(time.Hour), false},
+		{"malformed", "secret", "v1=abc", body, now, false},
+	}
+
+	for i, tc := range cases {
+		err := Verify(tc.secret, tc.header, tc.body, 5*time.Minute, tc.now)
+
+		if (err == nil) != tc.valid {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected valid %v", i, tc.desc, err, tc.valid)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"log/slog"
+	"net/netip"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+const secretLength = 32
+
+type service struct {
+	webhooks   stores.Webhook
+	deliveries stores.Delivery
+	logger     *slog.Logger
+	now        func() time.Time
+}
+
+func New(webhooks stores.Webhook, deliveries stores.Delivery, logger *slog.Logger) services.Webhook {
+	return service{webhooks: webhooks, deliveries: deliveries, logger: logger, now: time.Now}
+}
+
+// Create subscribes a new webhook, the secret its payloads are signed with is only returned here
+func (s service) Create(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error) {
+	if err := checkWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.ID = uuid.New()
+	webhook.Active = true
+	webhook.Secret = secret
+	webhook.CreatedAt = s.now().UTC()
+
+	if err := s.webhooks.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "webhook created", "webhook_id", webhook.ID, "url", webhook.URL)
+
+	return webhook, nil
+}
+
+// GetAll lists the webhooks without their secrets
+func (s service) GetAll(ctx context.Context) ([]models.Webhook, error) {
+	webhooks, err := s.webhooks.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return webhooks, nil
+}
+
+// GetByID fetches the webhook without its secret
+func (s service) GetByID(ctx context.Context, id uuid.UUID) (*models.Webhook, error) {
+	webhook, err := s.webhooks.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.Secret = ""
+
+	return &webhook, nil
+}
+
+// Update changes the url, event types and state of the webhook, inactive webhooks dead-letter their deliveries
+func (s service) Update(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error) {
+	if err := checkWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.webhooks.GetByID(ctx, webhook.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.webhooks.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "webhook updated", "webhook_id", webhook.ID, "active", webhook.Active)
+
+	webhook.Secret = ""
+	webhook.CreatedAt = stored.CreatedAt
+
+	return webhook, nil
+}
+
+// Delete removes the webhook along with its deliveries
+func (s service) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.webhooks.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "webhook deleted", "webhook_id", id)
+
+	return nil
+}
+
+// GetDeliveries lists the deliveries of the webhook, of any status when status is empty
+func (s service) GetDeliveries(ctx context.Context, webhookID uuid.UUID, status string) ([]models.Delivery, error) {
+	switch status {
+	case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryDead:
+	default:
+		return nil, errors.InvalidParam{Param: []string{"status"}}
+	}
+
+	if _, err := s.webhooks.GetByID(ctx, webhookID); err != nil {
+		return nil, err
+	}
+
+	return s.deliveries.GetAll(ctx, webhookID, status)
+}
+
+// Redeliver queues the delivery again with a fresh set of attempts, typically once it is dead
+func (s service) Redeliver(ctx context.Context, webhookID, deliveryID uuid.UUID) (*models.Delivery, error) {
+	delivery, err := s.deliveries.GetByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if delivery.WebhookID != webhookID {
+		return nil, errors.EntityNotFound{Entity: "delivery", ID: deliveryID.String()}
+	}
+
+	delivery.Status = models.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = s.now().UTC()
+	delivery.LastError = ""
+	delivery.DeliveredAt = nil
+
+	if err := s.deliveries.Update(ctx, &delivery); err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "delivery queued again", "webhook_id", webhookID, "delivery_id", deliveryID)
+
+	return &delivery, nil
+}
+
+// Publish queues a delivery of the message for every active webhook subscribed to its type.
+// The deliveries join the transaction of ctx, the one of the relay, so that they are queued once per message.
+func (s service) Publish(ctx context.Context, msg models.Message) error {
+	webhooks, err := s.webhooks.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := s.now().UTC()
+
+	for i := range webhooks {
+		if !subscribed(&webhooks[i], msg.Type) {
+			continue
+		}
+
+		delivery := models.Delivery{
+			ID:            uuid.New(),
+			WebhookID:     webhooks[i].ID,
+			EventID:       msg.ID,
+			EventType:     msg.Type,
+			Payload:       msg.Payload,
+			Status:        models.DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+
+		if err := s.deliveries.Create(ctx, &delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// subscribed reports whether the webhook is active and receives events of the type
+func subscribed(webhook *models.Webhook, eventType string) bool {
+	if !webhook.Active {
+		return false
+	}
+
+	if len(webhook.EventTypes) == 0 {
+		return true
+	}
+
+	for _, t := range webhook.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkWebhook validates the url and the event types of the webhook
+func checkWebhook(webhook *models.Webhook) error {
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.InvalidParam{Param: []string{"url"}}
+	}
+
+	// hosts resolving to internal addresses are refused when the deliveries are sent
+	if ip, err := netip.ParseAddr(u.Hostname()); u.Hostname() == "localhost" || (err == nil && !public(ip)) {
+		return errors.InvalidParam{Param: []string{"url"}}
+	}
+
+	known := make(map[string]bool)
+	for _, t := range models.EventTypes() {
+		known[t] = true
+	}
+
+	for _, t := range webhook.EventTypes {
+		if !known[t] {
+			return errors.InvalidParam{Param: []string{"eventTypes"}}
+		}
+	}
+
+	return nil
+}
+
+// newSecret generates a random url safe secret
+func newSecret() (string, error) {
+	b := make([]byte, secretLength)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
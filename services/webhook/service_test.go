@@ -0,0 +1,197 @@
+package webhook
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	now = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	id  = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+)
+
+func initializeTest(t *testing.T) (service, *stores.MockWebhook, *stores.MockDelivery) {
+	ctrl := gomock.NewController(t)
+
+	mockWebhook := stores.NewMockWebhook(ctrl)
+	mockDelivery := stores.NewMockDelivery(ctrl)
+
+	return service{webhooks: mockWebhook, deliveries: mockDelivery, logger: logging.Discard(), now: func() time.Time { return now }},
+		mockWebhook, mockDelivery
+}
+
+func TestService_Create(t *testing.T) {
+	s, mockWebhook, _ := initializeTest(t)
+
+	mockWebhook.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	webhook, err := s.Create(context.Background(), &models.Webhook{URL: "https://crm.example.com/hook"})
+	if err != nil {
+		t.Fatalf("\n[TEST] Failed. Desc : create\nGot %v\nExpected nil", err)
+	}
+
+	if webhook.Secret == "" || !webhook.Active || !webhook.CreatedAt.Equal(now) || webhook.ID == uuid.Nil {
+		t.Errorf("\n[TEST] Failed. Desc : create\nGot %v\nExpected active webhook with a secret", webhook)
+	}
+}
+
+func TestService_CreateErrors(t *testing.T) {
+	cases := []struct {
+		desc    string
+		webhook models.Webhook
+		err     error
+	}{
+		{"missing url", models.Webhook{}, errors.InvalidParam{Param: []string{"url"}}},
+		{"relative url", models.Webhook{URL: "/hook"}, errors.InvalidParam{Param: []string{"url"}}},
+		{"unsupported scheme", models.Webhook{URL: "ftp://crm.example.com"}, errors.InvalidParam{Param: []string{"url"}}},
+		{"loopback", models.Webhook{URL: "http://127.0.0.1:8080/hook"}, errors.InvalidParam{Param: []string{"url"}}},
+		{"localhost", models.Webhook{URL: "http://localhost/hook"}, errors.InvalidParam{Param: []string{"url"}}},
+		{"cloud metadata", models.Webhook{URL: "http://169.254.169.254/latest"}, errors.InvalidParam{Param: []string{"url"}}},
+		{"unknown event type", models.Webhook{URL: "https://crm.example.com", EventTypes: []string{"car.sold"}},
+			errors.InvalidParam{Param: []string{"eventTypes"}}},
+	}
+
+	for i, tc := range cases {
+		s, _, _ := initializeTest(t)
+
+		_, err := s.Create(context.Background(), &tc.webhook)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestService_GetHidesSecret(t *testing.T) {
+	s, mockWebhook, _ := initializeTest(t)
+
+	stored := models.Webhook{ID: id, URL: "https://crm.example.com/hook", Secret: "secret"}
+
+	mockWebhook.EXPECT().GetAll(gomock.Any()).Return([]models.Webhook{stored}, nil)
+	mockWebhook.EXPECT().GetByID(gomock.Any(), id).Return(stored, nil)
+
+	webhooks, err := s.GetAll(context.Background())
+	if err != nil || webhooks[0].Secret != "" {
+		t.Errorf("\n[TEST] Failed. Desc : get all\nGot %v, %v\nExpected webhooks without secret", webhooks, err)
+	}
+
+	webhook, err := s.GetByID(context.Background(), id)
+	if err != nil || webhook.Secret != "" {
+		t.Errorf("\n[TEST] Failed. Desc : get by id\nGot %v, %v\nExpected webhook without secret", webhook, err)
+	}
+}
+
+func TestService_Update(t *testing.T) {
+	notFound := errors.EntityNotFound{Entity: "webhook", ID: id.String()}
+
+	cases := []struct {
+		desc     string
+		getErr   error
+		storeErr error
+		err      error
+	}{
+		{"success", nil, nil, nil},
+		{"missing webhook", notFound, nil, notFound},
+		{"db error", nil, errors.DB{}, errors.DB{}},
+	}
+
+	for i, tc := range cases {
+		s, mockWebhook, _ := initializeTest(t)
+
+		webhook := models.Webhook{ID: id, URL: "https://crm.example.com/hook", Active: false}
+
+		mockWebhook.EXPECT().GetByID(gomock.Any(), id).Return(models.Webhook{ID: id, Secret: "secret", CreatedAt: now}, tc.getErr)
+
+		if tc.getErr == nil {
+			mockWebhook.EXPECT().Update(gomock.Any(), &webhook).Return(tc.storeErr)
+		}
+
+		_, err := s.Update(context.Background(), &webhook)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestService_GetDeliveries(t *testing.T) {
+	s, mockWebhook, mockDelivery := initializeTest(t)
+
+	mockWebhook.EXPECT().GetByID(gomock.Any(), id).Return(models.Webhook{ID: id}, nil)
+	mockDelivery.EXPECT().GetAll(gomock.Any(), id, models.DeliveryDead).Return([]models.Delivery{}, nil)
+
+	if _, err := s.GetDeliveries(context.Background(), id, models.DeliveryDead); err != nil {
+		t.Errorf("\n[TEST] Failed. Desc : dead deliveries\nGot %v\nExpected nil", err)
+	}
+
+	_, err := s.GetDeliveries(context.Background(), id, "lost")
+	if !reflect.DeepEqual(err, errors.InvalidParam{Param: []string{"status"}}) {
+		t.Errorf("\n[TEST] Failed. Desc : unknown status\nGot %v\nExpected %v", err, errors.InvalidParam{Param: []string{"status"}})
+	}
+}
+
+func TestService_Redeliver(t *testing.T) {
+	deliveryID := uuid.New()
+	dead := models.Delivery{ID: deliveryID, WebhookID: id, Status: models.DeliveryDead, Attempts: maxAttempts, LastError: "timeout"}
+	other := models.Delivery{ID: deliveryID, WebhookID: uuid.New(), Status: models.DeliveryDead}
+
+	s, _, mockDelivery := initializeTest(t)
+
+	mockDelivery.EXPECT().GetByID(gomock.Any(), deliveryID).Return(dead, nil)
+	mockDelivery.EXPECT().Update(gomock.Any(), &models.Delivery{ID: deliveryID, WebhookID: id, Status: models.DeliveryPending,
+		NextAttemptAt: now}).Return(nil)
+	mockDelivery.EXPECT().GetByID(gomock.Any(), deliveryID).Return(other, nil)
+
+	delivery, err := s.Redeliver(context.Background(), id, deliveryID)
+	if err != nil || delivery.Status != models.DeliveryPending {
+		t.Errorf("\n[TEST] Failed. Desc : redeliver\nGot %v, %v\nExpected pending delivery", delivery, err)
+	}
+
+	// deliveries of other webhooks are not found under this one
+	_, err = s.Redeliver(context.Background(), id, deliveryID)
+	if !reflect.DeepEqual(err, errors.EntityNotFound{Entity: "delivery", ID: deliveryID.String()}) {
+		t.Errorf("\n[TEST] Failed. Desc : other webhook\nGot %v\nExpected not found", err)
+	}
+}
+
+func TestService_Publish(t *testing.T) {
+	s, mockWebhook, mockDelivery := initializeTest(t)
+
+	subscribed := models.Webhook{ID: uuid.New(), Active: true, EventTypes: []string{models.CarCreated}}
+	every := models.Webhook{ID: uuid.New(), Active: true, EventTypes: []string{}}
+	other := models.Webhook{ID: uuid.New(), Active: true, EventTypes: []string{models.CarDeleted}}
+	inactive := models.Webhook{ID: uuid.New(), EventTypes: []string{}}
+
+	msg := models.Message{ID: uuid.New(), Key: id.String(), Type: models.CarCreated, Payload: []byte(`{"type":"car.created"}`)}
+	queued := make([]uuid.UUID, 0)
+
+	mockWebhook.EXPECT().GetAll(gomock.Any()).Return([]models.Webhook{subscribed, every, other, inactive}, nil)
+	mockDelivery.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, delivery *models.Delivery) error {
+		if delivery.Status != models.DeliveryPending || !delivery.NextAttemptAt.Equal(now) || delivery.EventID != msg.ID {
+			t.Errorf("\n[TEST] Failed. Desc : queued delivery\nGot %v\nExpected pending delivery of the message due now", delivery)
+		}
+
+		queued = append(queued, delivery.WebhookID)
+
+		return nil
+	}).Times(2)
+
+	if err := s.Publish(context.Background(), msg); err != nil {
+		t.Errorf("\n[TEST] Failed. Desc : publish\nGot %v\nExpected nil", err)
+	}
+
+	if !reflect.DeepEqual(queued, []uuid.UUID{subscribed.ID, every.ID}) {
+		t.Errorf("\n[TEST] Failed. Desc : publish\nGot %v\nExpected %v", queued, []uuid.UUID{subscribed.ID, every.ID})
+	}
+}
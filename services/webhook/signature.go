@@ -0,0 +1,62 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	goError "errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader carries the signature of a payload, receivers check it with Verify
+const SignatureHeader = "Webhook-Signature"
+
+var errSignature = goError.New("invalid webhook signature")
+
+// Sign returns the signature header of the body sent at the given time, t=<unix seconds>,v1=<hex hmac-sha256 of "<t>.<body>">
+func Sign(secret string, at time.Time, body []byte) string {
+	t := strconv.FormatInt(at.Unix(), 10)
+
+	return "t=" + t + ",v1=" + mac(secret, t, body)
+}
+
+// Verify checks the signature header of the body, signatures older than tolerance are rejected to stop replays
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var t, v1 string
+
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			v1 = value
+		}
+	}
+
+	sec, err := strconv.ParseInt(t, 10, 64)
+	if err != nil || v1 == "" {
+		return errSignature
+	}
+
+	if now.Sub(time.Unix(sec, 0)) > tolerance {
+		return errSignature
+	}
+
+	if !hmac.Equal([]byte(v1), []byte(mac(secret, t, body))) {
+		return errSignature
+	}
+
+	return nil
+}
+
+func mac(secret, t string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(t + "."))
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,10 @@
+package apikey
+
+const (
+	insertKey      = "INSERT INTO api_keys (id,owner,scopes,key_hash,created_at,expires_at) VALUES (?,?,?,?,?,?)"
+	getKeys        = "SELECT id,owner,scopes,key_hash,created_at,expires_at,last_used_at,revoked_at FROM api_keys;"
+	getKey         = "SELECT id,owner,scopes,key_hash,created_at,expires_at,last_used_at,revoked_at FROM api_keys WHERE id=?;"
+	updateHash     = "UPDATE api_keys SET key_hash=? WHERE id=? AND revoked_at IS NULL"
+	updateLastUsed = "UPDATE api_keys SET last_used_at=? WHERE id=? AND (last_used_at IS NULL OR last_used_at<?)"
+	revokeKey      = "UPDATE api_keys SET revoked_at=? WHERE id=? AND revoked_at IS NULL"
+)
@@ -0,0 +1,172 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	goError "errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+const (
+	entity = "apiKey"
+	// lastUsedInterval is how often the last use of a key is recorded at most
+	lastUsedInterval = time.Minute
+)
+
+type store struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func New(db *sql.DB, logger *slog.Logger) stores.APIKey {
+	return store{db: db, logger: logger}
+}
+
+// Create inserts a new api key in the database, only the hash of the key is persisted
+func (s store) Create(ctx context.Context, key *models.APIKey) error {
+	_, err := s.db.ExecContext(ctx, insertKey, key.ID.String(), key.Owner, strings.Join(key.Scopes, ","), key.Hash, key.CreatedAt,
+		nullTime(key.ExpiresAt))
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
+
+// GetAll fetches all the api keys including revoked ones
+func (s store) GetAll(ctx context.Context) ([]models.APIKey, error) {
+	rows, err := s.db.QueryContext(ctx, getKeys)
+	if err != nil {
+		return nil, errors.DB{Err: err}
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			s.logger.ErrorContext(ctx, "error in closing rows", "error", err)
+		}
+	}()
+
+	keys := make([]models.APIKey, 0)
+
+	for rows.Next() {
+		key, err := scanKey(rows)
+		if err != nil {
+			return nil, errors.DB{Err: err}
+		}
+
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.DB{Err: err}
+	}
+
+	return keys, nil
+}
+
+// GetByID fetches the api key of the given id
+func (s store) GetByID(ctx context.Context, id uuid.UUID) (models.APIKey, error) {
+	key, err := scanKey(s.db.QueryRowContext(ctx, getKey, id.String()))
+
+	switch {
+	case goError.Is(err, sql.ErrNoRows):
+		return models.APIKey{}, errors.EntityNotFound{Entity: entity, ID: id.String()}
+	case err != nil:
+		return models.APIKey{}, errors.DB{Err: err}
+	}
+
+	return key, nil
+}
+
+// UpdateHash replaces the hash of an active api key
+func (s store) UpdateHash(ctx context.Context, id uuid.UUID, hash string) error {
+	return s.exec(ctx, id, updateHash, hash, id.String())
+}
+
+// UpdateLastUsed records the time at which the api key was last used, at most once per lastUsedInterval so that keys
+// used on every request do not write on every request. Nothing being updated is not an error, the key was used recently.
+func (s store) UpdateLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	if _, err := s.db.ExecContext(ctx, updateLastUsed, at, id.String(), at.Add(-lastUsedInterval)); err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
+
+// Revoke marks the api key as revoked, revoked keys can never be used again
+func (s store) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return s.exec(ctx, id, revokeKey, at, id.String())
+}
+
+// exec runs the update query and reports missing keys as not found
+func (s store) exec(ctx context.Context, id uuid.UUID, query string, args ...interface{}) error {
+	res, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	if n == 0 {
+		return errors.EntityNotFound{Entity: entity, ID: id.String()}
+	}
+
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanKey reads a single api key from a row
+func scanKey(row scanner) (models.APIKey, error) {
+	var (
+		key                         models.APIKey
+		scopes                      string
+		expiresAt, lastUsed, revoke sql.NullTime
+	)
+
+	err := row.Scan(&key.ID, &key.Owner, &scopes, &key.Hash, &key.CreatedAt, &expiresAt, &lastUsed, &revoke)
+	if err != nil {
+		return models.APIKey{}, err
+	}
+
+	key.Scopes = make([]string, 0)
+
+	if scopes != "" {
+		key.Scopes = strings.Split(scopes, ",")
+	}
+
+	key.ExpiresAt = timePtr(expiresAt)
+	key.LastUsedAt = timePtr(lastUsed)
+	key.RevokedAt = timePtr(revoke)
+
+	return key, nil
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+
+	return &t.Time
+}
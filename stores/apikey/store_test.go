@@ -0,0 +1,180 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	goError "errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+func initializeTests(t *testing.T) (*sql.DB, sqlmock.Sqlmock, stores.APIKey) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error %s was not expected when opening a stub database connection", err)
+	}
+
+	s := New(db, logging.Discard())
+
+	return db, mock, s
+}
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	id        = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+	createdAt = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns   = []string{"id", "owner", "scopes", "key_hash", "created_at", "expires_at", "last_used_at", "revoked_at"}
+)
+
+func TestStore_Create(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	key := models.APIKey{ID: id, Owner: "crm", Scopes: []string{"admin", "read"}, Hash: "hash", CreatedAt: createdAt}
+	queryErr := goError.New("query error")
+
+	mock.ExpectExec(insertKey).WithArgs(id.String(), "crm", "admin,read", "hash", createdAt, sql.NullTime{}).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(insertKey).WithArgs(id.String(), "crm", "admin,read", "hash", createdAt, sql.NullTime{}).
+		WillReturnError(queryErr)
+
+	cases := []struct {
+		desc string
+		err  error
+	}{
+		{"success", nil},
+		{"query error", errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		err := s.Create(context.Background(), &key)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestStore_GetAll(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	mock.ExpectQuery(getKeys).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(id.String(), "crm", "admin", "hash", createdAt, nil, createdAt, nil))
+	mock.ExpectQuery(getKeys).WillReturnError(queryErr)
+	mock.ExpectQuery(getKeys).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow("invalid", "crm", "admin", "hash", createdAt, nil, nil, nil))
+
+	cases := []struct {
+		desc   string
+		output []models.APIKey
+		err    error
+	}{
+		{"success", []models.APIKey{{ID: id, Owner: "crm", Scopes: []string{"admin"}, Hash: "hash", CreatedAt: createdAt,
+			LastUsedAt: &createdAt}}, nil},
+		{"query error", nil, errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		output, err := s.GetAll(context.Background())
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+
+		if !reflect.DeepEqual(output, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, output, tc.output)
+		}
+	}
+
+	if _, err := s.GetAll(context.Background()); err == nil {
+		t.Errorf("\n[TEST] Failed. Desc : scan error\nGot %v\nExpected error", err)
+	}
+}
+
+func TestStore_GetByID(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	mock.ExpectQuery(getKey).WithArgs(id.String()).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(id.String(), "crm", "", "hash", createdAt, createdAt, nil, createdAt))
+	mock.ExpectQuery(getKey).WithArgs(id.String()).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(getKey).WithArgs(id.String()).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc   string
+		output models.APIKey
+		err    error
+	}{
+		{"success", models.APIKey{ID: id, Owner: "crm", Scopes: []string{}, Hash: "hash", CreatedAt: createdAt,
+			ExpiresAt: &createdAt, RevokedAt: &createdAt}, nil},
+		{"not found", models.APIKey{}, errors.EntityNotFound{Entity: entity, ID: id.String()}},
+		{"query error", models.APIKey{}, errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		output, err := s.GetByID(context.Background(), id)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+
+		if !reflect.DeepEqual(output, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, output, tc.output)
+		}
+	}
+}
+
+func TestStore_Updates(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	queryErr := goError.New("query error")
+	resultErr := goError.New("result error")
+	usedBefore := createdAt.Add(-lastUsedInterval)
+
+	mock.ExpectExec(updateHash).WithArgs("hash", id.String()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(updateLastUsed).WithArgs(createdAt, id.String(), usedBefore).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(updateLastUsed).WithArgs(createdAt, id.String(), usedBefore).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(updateLastUsed).WithArgs(createdAt, id.String(), usedBefore).WillReturnError(queryErr)
+	mock.ExpectExec(revokeKey).WithArgs(createdAt, id.String()).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(revokeKey).WithArgs(createdAt, id.String()).WillReturnError(queryErr)
+	mock.ExpectExec(revokeKey).WithArgs(createdAt, id.String()).WillReturnResult(sqlmock.NewErrorResult(resultErr))
+
+	cases := []struct {
+		desc string
+		call func() error
+		err  error
+	}{
+		{"update hash", func() error { return s.UpdateHash(ctx, id, "hash") }, nil},
+		{"update last used", func() error { return s.UpdateLastUsed(ctx, id, createdAt) }, nil},
+		{"last used recently", func() error { return s.UpdateLastUsed(ctx, id, createdAt) }, nil},
+		{"last used query error", func() error { return s.UpdateLastUsed(ctx, id, createdAt) }, errors.DB{Err: queryErr}},
+		{"revoke missing key", func() error { return s.Revoke(ctx, id, createdAt) }, errors.EntityNotFound{Entity: entity, ID: id.String()}},
+		{"query error", func() error { return s.Revoke(ctx, id, createdAt) }, errors.DB{Err: queryErr}},
+		{"rows affected error", func() error { return s.Revoke(ctx, id, createdAt) }, errors.DB{Err: resultErr}},
+	}
+
+	for i, tc := range cases {
+		err := tc.call()
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package broadcast
+
+const (
+	insertMessage  = "INSERT INTO stream_messages (id,message_key,type,payload,occurred_at) VALUES (?,?,?,?,?)"
+	getSince       = "SELECT seq,id,message_key,type,payload,occurred_at FROM stream_messages WHERE seq>? ORDER BY seq LIMIT ?;"
+	getLast        = "SELECT COALESCE(MAX(seq), 0) FROM stream_messages;"
+	deleteMessages = "DELETE FROM stream_messages WHERE occurred_at<?"
+)
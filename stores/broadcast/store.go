@@ -0,0 +1,89 @@
+package broadcast
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+type store struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func New(db *sql.DB, logger *slog.Logger) stores.Broadcast {
+	return store{db: db, logger: logger}
+}
+
+// Create writes the message, in the transaction of the relay publishing it
+func (s store) Create(ctx context.Context, msg *models.Message) error {
+	_, err := stores.Conn(ctx, s.db).ExecContext(ctx, insertMessage, msg.ID.String(), msg.Key, msg.Type, []byte(msg.Payload), msg.OccurredAt)
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
+
+// Since returns up to limit messages written after the one of seq along with the seq of the last one returned
+func (s store) Since(ctx context.Context, seq int64, limit int) ([]models.Message, int64, error) {
+	rows, err := stores.Conn(ctx, s.db).QueryContext(ctx, getSince, seq, limit)
+	if err != nil {
+		return nil, seq, errors.DB{Err: err}
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			s.logger.ErrorContext(ctx, "error in closing rows", "error", err)
+		}
+	}()
+
+	messages := make([]models.Message, 0)
+
+	for rows.Next() {
+		var (
+			msg     models.Message
+			payload []byte
+			next    int64
+		)
+
+		if err := rows.Scan(&next, &msg.ID, &msg.Key, &msg.Type, &payload, &msg.OccurredAt); err != nil {
+			return nil, seq, errors.DB{Err: err}
+		}
+
+		msg.Payload = payload
+		messages = append(messages, msg)
+		seq = next
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, seq, errors.DB{Err: err}
+	}
+
+	return messages, seq, nil
+}
+
+// Last returns the seq of the latest message, 0 when there is none
+func (s store) Last(ctx context.Context) (int64, error) {
+	var seq int64
+
+	if err := stores.Conn(ctx, s.db).QueryRowContext(ctx, getLast).Scan(&seq); err != nil {
+		return 0, errors.DB{Err: err}
+	}
+
+	return seq, nil
+}
+
+// DeleteBefore removes the messages that occurred before the given time
+func (s store) DeleteBefore(ctx context.Context, before time.Time) error {
+	if _, err := stores.Conn(ctx, s.db).ExecContext(ctx, deleteMessages, before); err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
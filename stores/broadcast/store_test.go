@@ -0,0 +1,154 @@
+package broadcast
+
+import (
+	"context"
+	"database/sql"
+	goError "errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+func initializeTests(t *testing.T) (*sql.DB, sqlmock.Sqlmock, stores.Broadcast) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error %s was not expected when opening a stub database connection", err)
+	}
+
+	s := New(db, logging.Discard())
+
+	return db, mock, s
+}
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	id         = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+	occurredAt = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns    = []string{"seq", "id", "message_key", "type", "payload", "occurred_at"}
+)
+
+func TestStore_Create(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	msg := models.Message{ID: id, Key: "car", Type: models.CarCreated, Payload: []byte(`{}`), OccurredAt: occurredAt}
+	queryErr := goError.New("query error")
+
+	mock.ExpectExec(insertMessage).WithArgs(id.String(), "car", models.CarCreated, []byte(`{}`), occurredAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(insertMessage).WithArgs(id.String(), "car", models.CarCreated, []byte(`{}`), occurredAt).
+		WillReturnError(queryErr)
+
+	cases := []struct {
+		desc string
+		err  error
+	}{
+		{"success", nil},
+		{"query error", errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		err := s.Create(context.Background(), &msg)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestStore_Since(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	mock.ExpectQuery(getSince).WithArgs(int64(4), 10).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(7, id.String(), "car", models.CarCreated, []byte(`{}`), occurredAt))
+	mock.ExpectQuery(getSince).WithArgs(int64(7), 10).WillReturnRows(sqlmock.NewRows(columns))
+	mock.ExpectQuery(getSince).WithArgs(int64(7), 10).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc   string
+		seq    int64
+		output []models.Message
+		next   int64
+		err    error
+	}{
+		{"success", 4, []models.Message{{ID: id, Key: "car", Type: models.CarCreated, Payload: []byte(`{}`), OccurredAt: occurredAt}}, 7, nil},
+		{"nothing new", 7, []models.Message{}, 7, nil},
+		{"query error", 7, nil, 7, errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		output, next, err := s.Since(context.Background(), tc.seq, 10)
+
+		if !reflect.DeepEqual(err, tc.err) || next != tc.next {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v, %v", i, tc.desc, next, err, tc.next, tc.err)
+		}
+
+		if !reflect.DeepEqual(output, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, output, tc.output)
+		}
+	}
+}
+
+func TestStore_Last(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	mock.ExpectQuery(getLast).WillReturnRows(sqlmock.NewRows([]string{"seq"}).AddRow(7))
+	mock.ExpectQuery(getLast).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc string
+		seq  int64
+		err  error
+	}{
+		{"success", 7, nil},
+		{"query error", 0, errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		seq, err := s.Last(context.Background())
+
+		if !reflect.DeepEqual(err, tc.err) || seq != tc.seq {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v, %v", i, tc.desc, seq, err, tc.seq, tc.err)
+		}
+	}
+}
+
+func TestStore_DeleteBefore(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	mock.ExpectExec(deleteMessages).WithArgs(occurredAt).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(deleteMessages).WithArgs(occurredAt).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc string
+		err  error
+	}{
+		{"success", nil},
+		{"query error", errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		err := s.DeleteBefore(context.Background(), occurredAt)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
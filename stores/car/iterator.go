@@ -0,0 +1,69 @@
+package car
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+	"github.com/amehrotra/car-dealership/tracing"
+)
+
+// Iterate queries the cars matching the filter joined with their engines, ordered by id.
+// Rows are read as the caller advances so that large inventories are never held in memory.
+func (s store) Iterate(ctx context.Context, filter filters.Car) (stores.CarIterator, error) {
+	var (
+		rows *sql.Rows
+		err  error
+	)
+
+	if filter.Brand != "" {
+		tracing.Statement(ctx, iterateCarsWithBrand)
+		rows, err = stores.Conn(ctx, s.db).QueryContext(ctx, iterateCarsWithBrand, filter.Brand)
+	} else {
+		tracing.Statement(ctx, iterateCars)
+		rows, err = stores.Conn(ctx, s.db).QueryContext(ctx, iterateCars)
+	}
+
+	if err != nil {
+		return nil, errors.DB{Err: err}
+	}
+
+	return iterator{rows: rows}, nil
+}
+
+type iterator struct {
+	rows *sql.Rows
+}
+
+func (it iterator) Next() bool {
+	return it.rows.Next()
+}
+
+func (it iterator) Scan(car *models.Car) error {
+	err := it.rows.Scan(&car.ID, &car.Model, &car.ManufactureYear, &car.Brand, &car.FuelType, &car.Price, &car.Condition, &car.Mileage,
+		&car.UpdatedAt, &car.Engine.ID, &car.Engine.Displacement, &car.Engine.NCylinder, &car.Engine.Range)
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
+
+func (it iterator) Err() error {
+	if err := it.rows.Err(); err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
+
+func (it iterator) Close() error {
+	if err := it.rows.Close(); err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package car
+
+import (
+	"context"
+	goError "errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/types"
+)
+
+func TestStore_Iterate(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	id := uuid.New()
+	car := models.Car{ID: id, Model: "X", ManufactureYear: 2020, Brand: "BMW", FuelType: types.Petrol, Price: 10,
+		Condition: models.CarUsed, Mileage: 120000, UpdatedAt: modifiedAt, Engine: models.Engine{ID: id, Displacement: 200, NCylinder: 2}}
+
+	columns := []string{"id", "model", "year_of_manufacture", "brand", "fuel_type", "price", "condition", "mileage", "updated_at", "id",
+		"displacement", "no_of_cylinder", "range"}
+	queryError := goError.New("query error")
+	rowError := goError.New("connection lost")
+
+	mock.ExpectQuery(iterateCarsWithBrand).WithArgs("BMW").WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(id.String(), "X", 2020, "BMW", []byte("petrol"), 10, "used", 120000, modifiedAt, id.String(), 200, 2, 0))
+	mock.ExpectQuery(iterateCars).WillReturnError(queryError)
+	mock.ExpectQuery(iterateCars).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(id.String(), "X", 2020, "BMW", []byte("petrol"), 10, "used", 120000, modifiedAt, id.String(), 200, 2, 0).RowError(0, rowError))
+
+	cases := []struct {
+		desc   string
+		filter filters.Car
+		output []models.Car
+		err    error
+	}{
+		{"success case", filters.Car{Brand: "BMW"}, []models.Car{car}, nil},
+		{"query error", filters.Car{}, nil, errors.DB{Err: queryError}},
+		{"row error", filters.Car{}, []models.Car{}, errors.DB{Err: rowError}},
+	}
+
+	for i, tc := range cases {
+		it, err := s.Iterate(context.Background(), tc.filter)
+
+		var cars []models.Car
+
+		if err == nil {
+			cars = make([]models.Car, 0)
+
+			for it.Next() {
+				var c models.Car
+
+				if err := it.Scan(&c); err != nil {
+					t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected nil", i, tc.desc, err)
+				}
+
+				cars = append(cars, c)
+			}
+
+			err = it.Err()
+			_ = it.Close()
+		}
+
+		if !reflect.DeepEqual(err, tc.err) || !reflect.DeepEqual(cars, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v %v\nExpected %v %v", i, tc.desc, cars, err, tc.output, tc.err)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("\n[TEST] Failed. Desc : expectations\nGot %v\nExpected nil", err)
+	}
+}
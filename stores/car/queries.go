@@ -1,10 +1,21 @@
 package car
 
+// carColumns are listed rather than selected with * so that the scans do not depend on the order the columns were added in
+const carColumns = "id,model,year_of_manufacture,brand,fuel_type,engine_id,price,`condition`,mileage,updated_at"
+
 const (
-	insertCar        = "INSERT INTO cars (id,model,year_of_manufacture,brand,fuel_type,engine_id) VALUES (?,?,?,?,?,?)"
-	getCars          = "SELECT * FROM cars;"
-	getCarsWithBrand = "SELECT * FROM cars WHERE brand=?;"
-	getCar           = "SELECT * FROM cars WHERE id = ?;"
-	updateCar        = "UPDATE cars SET model=?,year_of_manufacture=?,brand=?,fuel_type=?,engine_id=? WHERE id=?"
-	deleteCar        = "DELETE FROM cars WHERE id=?;"
+	insertCar        = "INSERT INTO cars (" + carColumns + ") VALUES (?,?,?,?,?,?,?,?,?,?)"
+	getCars          = "SELECT " + carColumns + " FROM cars;"
+	getCarsWithBrand = "SELECT " + carColumns + " FROM cars WHERE brand=?;"
+	getCar           = "SELECT " + carColumns + " FROM cars WHERE id = ?;"
+	updateCar        = "UPDATE cars SET model=?,year_of_manufacture=?,brand=?,fuel_type=?,engine_id=?,price=?,`condition`=?,mileage=?," +
+		"updated_at=? WHERE id=?"
+	deleteCar = "DELETE FROM cars WHERE id=?;"
+	lockCar   = "SELECT id FROM cars WHERE id=? FOR UPDATE;"
+	getStock  = "SELECT brand,fuel_type,COUNT(*) FROM cars GROUP BY brand,fuel_type;"
+
+	selectCarsWithEngines = "SELECT c.id,c.model,c.year_of_manufacture,c.brand,c.fuel_type,c.price,c.`condition`,c.mileage,c.updated_at," +
+		"e.id,e.displacement,e.no_of_cylinder,e.`range` FROM cars c JOIN engines e ON e.id=c.engine_id"
+	iterateCars          = selectCarsWithEngines + " ORDER BY c.id;"
+	iterateCarsWithBrand = selectCarsWithEngines + " WHERE c.brand=? ORDER BY c.id;"
 )
@@ -1,8 +1,11 @@
 package car
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	goError "errors"
+	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -10,19 +13,25 @@ import (
 	"github.com/amehrotra/car-dealership/filters"
 	"github.com/amehrotra/car-dealership/models"
 	"github.com/amehrotra/car-dealership/stores"
+	"github.com/amehrotra/car-dealership/tracing"
 )
 
 type store struct {
-	db *sql.DB
+	db     *sql.DB
+	logger *slog.Logger
 }
 
-func New(db *sql.DB) stores.Car {
-	return store{db: db}
+func New(db *sql.DB, logger *slog.Logger) stores.Car {
+	return store{db: db, logger: logger}
 }
 
-// Create inserts a new car in the database
-func (s store) Create(car *models.Car) error {
-	_, err := s.db.Exec(insertCar, car.ID, car.Model, car.ManufactureYear, car.Brand, car.FuelType, car.ID)
+// Create inserts a new car in the database, stamping it as updated now
+func (s store) Create(ctx context.Context, car *models.Car) error {
+	car.UpdatedAt = updatedAt()
+
+	tracing.Statement(ctx, insertCar)
+	_, err := stores.Conn(ctx, s.db).ExecContext(ctx, insertCar, car.ID, car.Model, car.ManufactureYear, car.Brand, car.FuelType, car.ID,
+		car.Price, car.Condition, car.Mileage, car.UpdatedAt)
 	if err != nil {
 		return errors.DB{Err: err}
 	}
@@ -31,16 +40,18 @@ func (s store) Create(car *models.Car) error {
 }
 
 // GetAll fetches cars based on filter
-func (s store) GetAll(filter filters.Car) ([]models.Car, error) {
+func (s store) GetAll(ctx context.Context, filter filters.Car) ([]models.Car, error) {
 	var (
 		rows *sql.Rows
 		err  error
 	)
 
 	if filter.Brand != "" {
-		rows, err = s.db.Query(getCarsWithBrand, filter.Brand)
+		tracing.Statement(ctx, getCarsWithBrand)
+		rows, err = stores.Conn(ctx, s.db).QueryContext(ctx, getCarsWithBrand, filter.Brand)
 	} else {
-		rows, err = s.db.Query(getCars)
+		tracing.Statement(ctx, getCars)
+		rows, err = stores.Conn(ctx, s.db).QueryContext(ctx, getCars)
 	}
 
 	if err != nil {
@@ -49,14 +60,14 @@ func (s store) GetAll(filter filters.Car) ([]models.Car, error) {
 
 	defer func() {
 		if err := rows.Err(); err != nil {
-			log.Printf("error in accessing all rows: %v", err)
+			s.logger.ErrorContext(ctx, "error in accessing all rows", "error", err)
 		}
 	}()
 
 	defer func() {
 		err = rows.Close()
 		if err != nil {
-			log.Printf("error in closing rows : %v", err)
+			s.logger.ErrorContext(ctx, "error in closing rows", "error", err)
 		}
 
 		return
@@ -67,7 +78,8 @@ func (s store) GetAll(filter filters.Car) ([]models.Car, error) {
 	for rows.Next() {
 		var car models.Car
 
-		if err := rows.Scan(&car.ID, &car.Model, &car.ManufactureYear, &car.Brand, &car.FuelType, &car.Engine.ID); err != nil {
+		if err := rows.Scan(&car.ID, &car.Model, &car.ManufactureYear, &car.Brand, &car.FuelType, &car.Engine.ID,
+			&car.Price, &car.Condition, &car.Mileage, &car.UpdatedAt); err != nil {
 			return nil, errors.DB{Err: err}
 		}
 
@@ -78,11 +90,13 @@ func (s store) GetAll(filter filters.Car) ([]models.Car, error) {
 }
 
 // GetByID fetches the car from database of the given id
-func (s store) GetByID(id uuid.UUID) (models.Car, error) {
+func (s store) GetByID(ctx context.Context, id uuid.UUID) (models.Car, error) {
 	var car models.Car
 
-	err := s.db.QueryRow(getCar, id.String()).
-		Scan(&car.ID, &car.Model, &car.ManufactureYear, &car.Brand, &car.FuelType, &car.Engine.ID)
+	tracing.Statement(ctx, getCar)
+	err := stores.Conn(ctx, s.db).QueryRowContext(ctx, getCar, id.String()).
+		Scan(&car.ID, &car.Model, &car.ManufactureYear, &car.Brand, &car.FuelType, &car.Engine.ID, &car.Price, &car.Condition,
+			&car.Mileage, &car.UpdatedAt)
 	if err != nil {
 		return models.Car{}, errors.DB{Err: err}
 	}
@@ -90,9 +104,13 @@ func (s store) GetByID(id uuid.UUID) (models.Car, error) {
 	return car, nil
 }
 
-// Update modifies car of the given id
-func (s store) Update(car *models.Car) error {
-	_, err := s.db.Exec(updateCar, car.Model, car.ManufactureYear, car.Brand, car.FuelType, car.ID, car.ID)
+// Update modifies car of the given id, stamping it as updated now
+func (s store) Update(ctx context.Context, car *models.Car) error {
+	car.UpdatedAt = updatedAt()
+
+	tracing.Statement(ctx, updateCar)
+	_, err := stores.Conn(ctx, s.db).ExecContext(ctx, updateCar, car.Model, car.ManufactureYear, car.Brand, car.FuelType, car.ID,
+		car.Price, car.Condition, car.Mileage, car.UpdatedAt, car.ID)
 
 	if err != nil {
 		return errors.DB{Err: err}
@@ -102,11 +120,73 @@ func (s store) Update(car *models.Car) error {
 }
 
 // Delete removes car with the given id
-func (s store) Delete(id uuid.UUID) error {
-	_, err := s.db.Exec(deleteCar, id.String())
-	if err != nil {
+func (s store) Delete(ctx context.Context, id uuid.UUID) error {
+	tracing.Statement(ctx, deleteCar)
+	_, err := stores.Conn(ctx, s.db).ExecContext(ctx, deleteCar, id.String())
+
+	switch {
+	case stores.IsReferenced(err):
+		return errors.Conflict{Entity: "car", ID: id.String(), Reason: "is referenced by orders, reservations, test drives or trade-ins"}
+	case err != nil:
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
+
+// Lock locks the car until the transaction of ctx ends so that changes depending on its availability are serialized,
+// it only holds for the statement outside a transaction
+func (s store) Lock(ctx context.Context, id uuid.UUID) error {
+	var locked string
+
+	tracing.Statement(ctx, lockCar)
+	err := stores.Conn(ctx, s.db).QueryRowContext(ctx, lockCar, id.String()).Scan(&locked)
+
+	switch {
+	case goError.Is(err, sql.ErrNoRows):
+		return errors.EntityNotFound{Entity: "car", ID: id.String()}
+	case err != nil:
 		return errors.DB{Err: err}
 	}
 
 	return nil
 }
+
+// updatedAt is the time changes are stamped with, truncated to the second precision of the column and of http dates
+func updatedAt() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// Stock counts the cars by brand and fuel type in the database rather than loading them
+func (s store) Stock(ctx context.Context) ([]models.Stock, error) {
+	tracing.Statement(ctx, getStock)
+
+	rows, err := stores.Conn(ctx, s.db).QueryContext(ctx, getStock)
+	if err != nil {
+		return nil, errors.DB{Err: err}
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			s.logger.ErrorContext(ctx, "error in closing rows", "error", err)
+		}
+	}()
+
+	stock := make([]models.Stock, 0)
+
+	for rows.Next() {
+		var st models.Stock
+
+		if err := rows.Scan(&st.Brand, &st.FuelType, &st.Count); err != nil {
+			return nil, errors.DB{Err: err}
+		}
+
+		stock = append(stock, st)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.DB{Err: err}
+	}
+
+	return stock, nil
+}
@@ -2,31 +2,42 @@ package car
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	goError "errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
 
 	"github.com/amehrotra/car-dealership/errors"
 	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/logging"
 	"github.com/amehrotra/car-dealership/models"
 	"github.com/amehrotra/car-dealership/stores"
 	"github.com/amehrotra/car-dealership/types"
 )
 
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	modifiedAt = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns    = []string{"id", "model", "year_of_manufacture", "brand", "fuel_type", "engine_id", "price", "condition", "mileage",
+		"updated_at"}
+)
+
 func initializeTests(t *testing.T) (*sql.DB, sqlmock.Sqlmock, stores.Car) {
 	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 	if err != nil {
 		t.Fatalf("error %s was not expected when opening a stub database connection", err)
 	}
 
-	s := New(db)
+	s := New(db, logging.Discard())
 
 	return db, mock, s
 }
@@ -55,11 +66,13 @@ func TestStore_Create(t *testing.T) {
 	queryErr := goError.New("query error")
 
 	mock.ExpectExec(insertCar).
-		WithArgs(car.ID, car.Model, car.ManufactureYear, car.Brand, car.FuelType, car.ID).
+		WithArgs(car.ID, car.Model, car.ManufactureYear, car.Brand, car.FuelType, car.ID, car.Price, car.Condition,
+			car.Mileage, sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	mock.ExpectExec(insertCar).
-		WithArgs(car.ID, car.Model, car.ManufactureYear, car.Brand, car.FuelType, car.ID).
+		WithArgs(car.ID, car.Model, car.ManufactureYear, car.Brand, car.FuelType, car.ID, car.Price, car.Condition,
+			car.Mileage, sqlmock.AnyArg()).
 		WillReturnError(queryErr)
 
 	cases := []struct {
@@ -72,7 +85,7 @@ func TestStore_Create(t *testing.T) {
 	}
 
 	for i, tc := range cases {
-		err := s.Create(&car)
+		err := s.Create(context.Background(), &car)
 
 		if err != tc.err {
 			t.Errorf("\n[TEST %v] Failed \nDesc %v\nGot %v\n Expected %v", i, tc.desc, err, tc.err)
@@ -100,17 +113,19 @@ func TestStore_GetAll(t *testing.T) {
 			ManufactureYear: 2020,
 			Brand:           "BMW",
 			FuelType:        types.Petrol,
+			Condition:       models.CarNew,
 			Engine:          models.Engine{ID: id},
+			UpdatedAt:       modifiedAt,
 		},
 	}
 
 	queryError := goError.New("query error")
 
-	row1 := sqlmock.NewRows([]string{"id", "model", "year_of_manufacture", "brand", "fuel_type", "engine_id"}).
-		AddRow(id.String(), "X", 2020, "BMW", []byte("petrol"), id.String())
+	row1 := sqlmock.NewRows(columns).
+		AddRow(id.String(), "X", 2020, "BMW", []byte("petrol"), id.String(), 0, "new", 0, modifiedAt)
 
-	row2 := sqlmock.NewRows([]string{"id", "model", "year_of_manufacture", "brand", "fuel_type", "engine_id", "scan_error"}).
-		AddRow(id.String(), "X", 2020, "BMW", []byte("petrol"), id.String(), "scan_error")
+	row2 := sqlmock.NewRows(append(columns, "scan_error")).
+		AddRow(id.String(), "X", 2020, "BMW", []byte("petrol"), id.String(), 0, "new", 0, modifiedAt, "scan_error")
 
 	mock.ExpectQuery(getCarsWithBrand).WithArgs("BMW").WillReturnRows(row1)
 	mock.ExpectQuery(getCars).WillReturnError(queryError)
@@ -124,11 +139,11 @@ func TestStore_GetAll(t *testing.T) {
 	}{
 		{"success case", filters.Car{Brand: "BMW"}, cars, nil},
 		{"query error", filters.Car{}, nil, errors.DB{Err: queryError}},
-		{"scan error", filters.Car{}, nil, errors.DB{Err: fmt.Errorf("sql: expected %d destination arguments in Scan, not %d", 7, 6)}},
+		{"scan error", filters.Car{}, nil, errors.DB{Err: fmt.Errorf("sql: expected %d destination arguments in Scan, not %d", 11, 10)}},
 	}
 
 	for i, tc := range cases {
-		car, err := s.GetAll(tc.filter)
+		car, err := s.GetAll(context.Background(), tc.filter)
 
 		if !reflect.DeepEqual(err, tc.err) {
 			t.Errorf("\n[TEST %v] Failed \nDesc %v\nGot %v\n Expected %v", i, tc.desc, err, tc.err)
@@ -141,7 +156,7 @@ func TestStore_GetAll(t *testing.T) {
 }
 
 func TestStore_GetAllCloseErr(t *testing.T) {
-	db, mock, s := initializeTests(t)
+	db, mock, _ := initializeTests(t)
 	defer db.Close()
 
 	id, err := uuid.NewRandom()
@@ -152,11 +167,11 @@ func TestStore_GetAllCloseErr(t *testing.T) {
 	closeError := goError.New("close error")
 	rowError := goError.New("row error")
 
-	closeRow := sqlmock.NewRows([]string{"id", "model", "year_of_manufacture", "brand", "fuel_type", "engine_id"}).
-		AddRow(id.String(), "X", 2020, "BMW", []byte("petro"), id.String()).CloseError(errors.DB{Err: closeError})
+	closeRow := sqlmock.NewRows(columns).
+		AddRow(id.String(), "X", 2020, "BMW", []byte("petro"), id.String(), 0, "new", 0, modifiedAt).CloseError(errors.DB{Err: closeError})
 
-	errRow := sqlmock.NewRows([]string{"id", "model", "year_of_manufacture", "brand", "fuel_type", "engine_id"}).
-		AddRow(id.String(), "X", 2020, "BMW", []byte("petro"), id.String()).RowError(0, errors.DB{Err: rowError})
+	errRow := sqlmock.NewRows(columns).
+		AddRow(id.String(), "X", 2020, "BMW", []byte("petro"), id.String(), 0, "new", 0, modifiedAt).RowError(0, errors.DB{Err: rowError})
 
 	mock.ExpectQuery(getCars).WillReturnRows(closeRow)
 	mock.ExpectQuery(getCars).WillReturnRows(errRow)
@@ -173,9 +188,9 @@ func TestStore_GetAllCloseErr(t *testing.T) {
 	for i, tc := range cases {
 		var b bytes.Buffer
 
-		log.SetOutput(&b)
+		s := New(db, logging.New(&b, slog.LevelError))
 
-		car, err := s.GetAll(filters.Car{})
+		car, err := s.GetAll(context.Background(), filters.Car{})
 
 		if !strings.Contains(b.String(), tc.err) {
 			t.Errorf("\n[TEST %d] Failed \nDesc %v\nGot %v\n Expected %v", i, tc.desc, err, tc.err)
@@ -201,13 +216,16 @@ func TestStore_GetByID(t *testing.T) {
 		Model:           "X",
 		ManufactureYear: 2020,
 		Brand:           "BMW",
+		Condition:       models.CarUsed,
+		Mileage:         120000,
 		Engine:          models.Engine{ID: id},
+		UpdatedAt:       modifiedAt,
 	}
 
 	queryErr := goError.New("query error")
 
-	rows := sqlmock.NewRows([]string{"id", "model", "year_of_manufacture", "brand", "fuel_type", "engine_id"}).
-		AddRow(id.String(), "X", 2020, "BMW", []byte("diesel"), id.String())
+	rows := sqlmock.NewRows(columns).
+		AddRow(id.String(), "X", 2020, "BMW", []byte("diesel"), id.String(), 0, "used", 120000, modifiedAt)
 
 	mock.ExpectQuery(getCar).WithArgs(id).WillReturnRows(rows)
 	mock.ExpectQuery(getCar).WithArgs(uuid.Nil).WillReturnError(queryErr)
@@ -223,7 +241,7 @@ func TestStore_GetByID(t *testing.T) {
 	}
 
 	for i, tc := range cases {
-		resp, err := s.GetByID(tc.input)
+		resp, err := s.GetByID(context.Background(), tc.input)
 
 		if resp != tc.output {
 			t.Errorf("\n[TEST %v] Failed \nDesc %v\nGot %v\n Expected %v", i, tc.desc, resp, tc.output)
@@ -254,9 +272,11 @@ func TestStore_Update(t *testing.T) {
 		Engine:          models.Engine{ID: id},
 	}
 
-	mock.ExpectExec(updateCar).WithArgs(car.Model, car.ManufactureYear, car.Brand, car.FuelType, car.ID, car.ID).
+	mock.ExpectExec(updateCar).WithArgs(car.Model, car.ManufactureYear, car.Brand, car.FuelType, car.ID, car.Price, car.Condition,
+		car.Mileage, sqlmock.AnyArg(), car.ID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectExec(updateCar).WithArgs(car.Model, car.ManufactureYear, car.Brand, car.FuelType, car.ID, car.ID).
+	mock.ExpectExec(updateCar).WithArgs(car.Model, car.ManufactureYear, car.Brand, car.FuelType, car.ID, car.Price, car.Condition,
+		car.Mileage, sqlmock.AnyArg(), car.ID).
 		WillReturnError(updateFailed)
 
 	cases := []struct {
@@ -269,7 +289,7 @@ func TestStore_Update(t *testing.T) {
 	}
 
 	for i, tc := range cases {
-		err := s.Update(&tc.input)
+		err := s.Update(context.Background(), &tc.input)
 
 		if err != tc.err {
 			t.Errorf("\n[TEST %v] Failed \nDesc %v\nGot %v\n Expected %v", i, tc.desc, err, tc.err)
@@ -290,6 +310,7 @@ func TestStore_Delete(t *testing.T) {
 
 	mock.ExpectExec(deleteCar).WithArgs(id.String()).WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectExec(deleteCar).WithArgs(id.String()).WillReturnError(deleteErr)
+	mock.ExpectExec(deleteCar).WithArgs(id.String()).WillReturnError(&mysql.MySQLError{Number: 1451})
 
 	cases := []struct {
 		desc string
@@ -298,13 +319,72 @@ func TestStore_Delete(t *testing.T) {
 	}{
 		{"Delete Success", id, nil},
 		{"Delete Failed", id, errors.DB{Err: deleteErr}},
+		{"Delete Referenced", id, errors.Conflict{Entity: "car", ID: id.String(),
+			Reason: "is referenced by orders, reservations, test drives or trade-ins"}},
 	}
 
 	for i, tc := range cases {
-		err := s.Delete(tc.id)
+		err := s.Delete(context.Background(), tc.id)
 
 		if err != tc.err {
 			t.Errorf("\n[TEST %v] Failed \nDesc %v\nGot %v\n Expected %v", i, tc.desc, err, tc.err)
 		}
 	}
 }
+
+func TestStore_Lock(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	id := uuid.New()
+	queryErr := goError.New("lock wait timeout")
+
+	mock.ExpectQuery(lockCar).WithArgs(id.String()).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id.String()))
+	mock.ExpectQuery(lockCar).WithArgs(id.String()).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(lockCar).WithArgs(id.String()).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc string
+		err  error
+	}{
+		{"locked", nil},
+		{"missing car", errors.EntityNotFound{Entity: "car", ID: id.String()}},
+		{"query error", errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		err := s.Lock(context.Background(), id)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestStore_Stock(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	mock.ExpectQuery(getStock).WillReturnRows(sqlmock.NewRows([]string{"brand", "fuel_type", "count"}).
+		AddRow("BMW", []byte("petrol"), 2).AddRow("Tesla", []byte("electric"), 1))
+	mock.ExpectQuery(getStock).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc   string
+		output []models.Stock
+		err    error
+	}{
+		{"success", []models.Stock{{Brand: "BMW", FuelType: types.Petrol, Count: 2}, {Brand: "Tesla", FuelType: types.Electric, Count: 1}}, nil},
+		{"query error", nil, errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		output, err := s.Stock(context.Background())
+
+		if !reflect.DeepEqual(err, tc.err) || !reflect.DeepEqual(output, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v, %v", i, tc.desc, output, err, tc.output, tc.err)
+		}
+	}
+}
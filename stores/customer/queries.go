@@ -0,0 +1,14 @@
+package customer
+
+const (
+	customerColumns = "id,name,email,phone,address_line1,address_line2,city,postal_code,country,licence_number,marketing_consent," +
+		"data_sharing_consent,created_at,updated_at"
+
+	insertCustomer = "INSERT INTO customers (" + customerColumns + ") VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?)"
+	// empty filters match every customer
+	getCustomers   = "SELECT " + customerColumns + " FROM customers WHERE (?='' OR email=?) AND (?='' OR phone=?) ORDER BY name,id;"
+	getCustomer    = "SELECT " + customerColumns + " FROM customers WHERE id=?;"
+	updateCustomer = "UPDATE customers SET name=?,email=?,phone=?,address_line1=?,address_line2=?,city=?,postal_code=?,country=?," +
+		"licence_number=?,marketing_consent=?,data_sharing_consent=?,updated_at=? WHERE id=?"
+	deleteCustomer = "DELETE FROM customers WHERE id=?;"
+)
@@ -0,0 +1,136 @@
+package customer
+
+import (
+	"context"
+	"database/sql"
+	goError "errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+const entity = "customer"
+
+type store struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func New(db *sql.DB, logger *slog.Logger) stores.Customer {
+	return store{db: db, logger: logger}
+}
+
+// Create inserts a new customer
+func (s store) Create(ctx context.Context, customer *models.Customer) error {
+	_, err := stores.Conn(ctx, s.db).ExecContext(ctx, insertCustomer, customer.ID.String(), customer.Name, customer.Email, customer.Phone,
+		customer.Address.Line1, customer.Address.Line2, customer.Address.City, customer.Address.PostalCode, customer.Address.Country,
+		customer.LicenceNumber, customer.Consent.Marketing, customer.Consent.DataSharing, customer.CreatedAt, customer.UpdatedAt)
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
+
+// GetAll fetches the customers of the email and phone of the filter, every customer when they are empty
+func (s store) GetAll(ctx context.Context, filter filters.Customer) ([]models.Customer, error) {
+	rows, err := stores.Conn(ctx, s.db).QueryContext(ctx, getCustomers, filter.Email, filter.Email, filter.Phone, filter.Phone)
+	if err != nil {
+		return nil, errors.DB{Err: err}
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			s.logger.ErrorContext(ctx, "error in closing rows", "error", err)
+		}
+	}()
+
+	customers := make([]models.Customer, 0)
+
+	for rows.Next() {
+		customer, err := scanCustomer(rows)
+		if err != nil {
+			return nil, errors.DB{Err: err}
+		}
+
+		customers = append(customers, customer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.DB{Err: err}
+	}
+
+	return customers, nil
+}
+
+// GetByID fetches the customer of the given id
+func (s store) GetByID(ctx context.Context, id uuid.UUID) (models.Customer, error) {
+	customer, err := scanCustomer(stores.Conn(ctx, s.db).QueryRowContext(ctx, getCustomer, id.String()))
+
+	switch {
+	case goError.Is(err, sql.ErrNoRows):
+		return models.Customer{}, errors.EntityNotFound{Entity: entity, ID: id.String()}
+	case err != nil:
+		return models.Customer{}, errors.DB{Err: err}
+	}
+
+	return customer, nil
+}
+
+// Update replaces the details of the customer, the creation time never changes.
+// Unchanged rows are not reported as affected by MySQL, so callers check the customer exists beforehand.
+func (s store) Update(ctx context.Context, customer *models.Customer) error {
+	_, err := stores.Conn(ctx, s.db).ExecContext(ctx, updateCustomer, customer.Name, customer.Email, customer.Phone,
+		customer.Address.Line1, customer.Address.Line2, customer.Address.City, customer.Address.PostalCode, customer.Address.Country,
+		customer.LicenceNumber, customer.Consent.Marketing, customer.Consent.DataSharing, customer.UpdatedAt, customer.ID.String())
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
+
+// Delete removes the customer
+func (s store) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := stores.Conn(ctx, s.db).ExecContext(ctx, deleteCustomer, id.String())
+
+	switch {
+	case stores.IsReferenced(err):
+		return errors.Conflict{Entity: entity, ID: id.String(), Reason: "has orders, reservations, test drives or trade-ins"}
+	case err != nil:
+		return errors.DB{Err: err}
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	if n == 0 {
+		return errors.EntityNotFound{Entity: entity, ID: id.String()}
+	}
+
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanCustomer reads a single customer from a row
+func scanCustomer(row scanner) (models.Customer, error) {
+	var c models.Customer
+
+	err := row.Scan(&c.ID, &c.Name, &c.Email, &c.Phone, &c.Address.Line1, &c.Address.Line2, &c.Address.City, &c.Address.PostalCode,
+		&c.Address.Country, &c.LicenceNumber, &c.Consent.Marketing, &c.Consent.DataSharing, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	return c, nil
+}
@@ -0,0 +1,171 @@
+package customer
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	goError "errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+func initializeTests(t *testing.T) (*sql.DB, sqlmock.Sqlmock, stores.Customer) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error %s was not expected when opening a stub database connection", err)
+	}
+
+	s := New(db, logging.Discard())
+
+	return db, mock, s
+}
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	id        = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+	createdAt = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	customer  = models.Customer{ID: id, Name: "Jane Doe", Email: "jane@example.com", Phone: "+14155550123",
+		Address:       models.Address{Line1: "1 Main St", City: "Springfield", PostalCode: "12345", Country: "US"},
+		LicenceNumber: "D1234567", Consent: models.Consent{Marketing: true}, CreatedAt: createdAt, UpdatedAt: createdAt}
+	columns = []string{"id", "name", "email", "phone", "address_line1", "address_line2", "city", "postal_code", "country",
+		"licence_number", "marketing_consent", "data_sharing_consent", "created_at", "updated_at"}
+)
+
+// row is the row of the customer of the tests
+func row() []driver.Value {
+	return []driver.Value{id.String(), "Jane Doe", "jane@example.com", "+14155550123", "1 Main St", "", "Springfield", "12345", "US",
+		"D1234567", true, false, createdAt, createdAt}
+}
+
+func TestStore_Create(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	mock.ExpectExec(insertCustomer).WithArgs(row()...).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(insertCustomer).WithArgs(row()...).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc string
+		err  error
+	}{
+		{"success", nil},
+		{"query error", errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		err := s.Create(context.Background(), &customer)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestStore_GetAll(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	mock.ExpectQuery(getCustomers).WithArgs("jane@example.com", "jane@example.com", "", "").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(row()...))
+	mock.ExpectQuery(getCustomers).WithArgs("", "", "+14155550000", "+14155550000").WillReturnRows(sqlmock.NewRows(columns))
+	mock.ExpectQuery(getCustomers).WithArgs("", "", "", "").WillReturnError(queryErr)
+
+	cases := []struct {
+		desc   string
+		filter filters.Customer
+		output []models.Customer
+		err    error
+	}{
+		{"by email", filters.Customer{Email: "jane@example.com"}, []models.Customer{customer}, nil},
+		{"unknown phone", filters.Customer{Phone: "+14155550000"}, []models.Customer{}, nil},
+		{"query error", filters.Customer{}, nil, errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		output, err := s.GetAll(context.Background(), tc.filter)
+
+		if !reflect.DeepEqual(err, tc.err) || !reflect.DeepEqual(output, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v, %v", i, tc.desc, output, err, tc.output, tc.err)
+		}
+	}
+}
+
+func TestStore_GetByID(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	mock.ExpectQuery(getCustomer).WithArgs(id.String()).WillReturnRows(sqlmock.NewRows(columns).AddRow(row()...))
+	mock.ExpectQuery(getCustomer).WithArgs(id.String()).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(getCustomer).WithArgs(id.String()).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc   string
+		output models.Customer
+		err    error
+	}{
+		{"success", customer, nil},
+		{"not found", models.Customer{}, errors.EntityNotFound{Entity: entity, ID: id.String()}},
+		{"query error", models.Customer{}, errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		output, err := s.GetByID(context.Background(), id)
+
+		if !reflect.DeepEqual(err, tc.err) || !reflect.DeepEqual(output, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v, %v", i, tc.desc, output, err, tc.output, tc.err)
+		}
+	}
+}
+
+func TestStore_UpdateDelete(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	queryErr := goError.New("query error")
+	args := append(append([]driver.Value{}, row()[1:12]...), createdAt, id.String())
+
+	mock.ExpectExec(updateCustomer).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(updateCustomer).WithArgs(args...).WillReturnError(queryErr)
+	mock.ExpectExec(deleteCustomer).WithArgs(id.String()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(deleteCustomer).WithArgs(id.String()).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(deleteCustomer).WithArgs(id.String()).WillReturnError(&mysql.MySQLError{Number: 1451})
+
+	cases := []struct {
+		desc string
+		call func() error
+		err  error
+	}{
+		{"update unchanged customer", func() error { return s.Update(ctx, &customer) }, nil},
+		{"update query error", func() error { return s.Update(ctx, &customer) }, errors.DB{Err: queryErr}},
+		{"delete", func() error { return s.Delete(ctx, id) }, nil},
+		{"delete missing customer", func() error { return s.Delete(ctx, id) }, errors.EntityNotFound{Entity: entity, ID: id.String()}},
+		{"delete customer with orders", func() error { return s.Delete(ctx, id) }, errors.Conflict{Entity: entity, ID: id.String(),
+			Reason: "has orders, reservations, test drives or trade-ins"}},
+	}
+
+	for i, tc := range cases {
+		err := tc.call()
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
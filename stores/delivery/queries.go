@@ -0,0 +1,14 @@
+package delivery
+
+const (
+	insertDelivery = "INSERT INTO webhook_deliveries (id,webhook_id,event_id,event_type,payload,status,attempts,next_attempt_at,created_at) " +
+		"VALUES (?,?,?,?,?,?,?,?,?)"
+	selectDeliveries = "SELECT id,webhook_id,event_id,event_type,payload,status,attempts,next_attempt_at,last_error,created_at,delivered_at " +
+		"FROM webhook_deliveries"
+	getDeliveries          = selectDeliveries + " WHERE webhook_id=? ORDER BY created_at;"
+	getDeliveriesWithState = selectDeliveries + " WHERE webhook_id=? AND status=? ORDER BY created_at;"
+	getDelivery            = selectDeliveries + " WHERE id=?;"
+	getDue                 = selectDeliveries + " WHERE status='pending' AND next_attempt_at<=? ORDER BY next_attempt_at LIMIT ? " +
+		"FOR UPDATE SKIP LOCKED;"
+	updateDelivery = "UPDATE webhook_deliveries SET status=?,attempts=?,next_attempt_at=?,last_error=?,delivered_at=? WHERE id=?"
+)
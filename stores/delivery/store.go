@@ -0,0 +1,141 @@
+package delivery
+
+import (
+	"context"
+	"database/sql"
+	goError "errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+const entity = "delivery"
+
+type store struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func New(db *sql.DB, logger *slog.Logger) stores.Delivery {
+	return store{db: db, logger: logger}
+}
+
+// Create queues a delivery, in the transaction of the change it reports when there is one
+func (s store) Create(ctx context.Context, delivery *models.Delivery) error {
+	_, err := stores.Conn(ctx, s.db).ExecContext(ctx, insertDelivery, delivery.ID.String(), delivery.WebhookID.String(),
+		delivery.EventID.String(), delivery.EventType, delivery.Payload, delivery.Status, delivery.Attempts, delivery.NextAttemptAt,
+		delivery.CreatedAt)
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
+
+// GetAll fetches the deliveries of the webhook, of any status when status is empty
+func (s store) GetAll(ctx context.Context, webhookID uuid.UUID, status string) ([]models.Delivery, error) {
+	if status == "" {
+		return s.query(ctx, getDeliveries, webhookID.String())
+	}
+
+	return s.query(ctx, getDeliveriesWithState, webhookID.String(), status)
+}
+
+// GetByID fetches the delivery of the given id
+func (s store) GetByID(ctx context.Context, id uuid.UUID) (models.Delivery, error) {
+	delivery, err := scanDelivery(stores.Conn(ctx, s.db).QueryRowContext(ctx, getDelivery, id.String()))
+
+	switch {
+	case goError.Is(err, sql.ErrNoRows):
+		return models.Delivery{}, errors.EntityNotFound{Entity: entity, ID: id.String()}
+	case err != nil:
+		return models.Delivery{}, errors.DB{Err: err}
+	}
+
+	return delivery, nil
+}
+
+// Due locks the pending deliveries due at now, rows locked by other instances are skipped so that each is sent once
+func (s store) Due(ctx context.Context, now time.Time, limit int) ([]models.Delivery, error) {
+	return s.query(ctx, getDue, now, limit)
+}
+
+// Update records the outcome of an attempt
+func (s store) Update(ctx context.Context, delivery *models.Delivery) error {
+	lastError := sql.NullString{String: delivery.LastError, Valid: delivery.LastError != ""}
+
+	var deliveredAt sql.NullTime
+	if delivery.DeliveredAt != nil {
+		deliveredAt = sql.NullTime{Time: *delivery.DeliveredAt, Valid: true}
+	}
+
+	_, err := stores.Conn(ctx, s.db).ExecContext(ctx, updateDelivery, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, lastError,
+		deliveredAt, delivery.ID.String())
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
+
+func (s store) query(ctx context.Context, query string, args ...interface{}) ([]models.Delivery, error) {
+	rows, err := stores.Conn(ctx, s.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.DB{Err: err}
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			s.logger.ErrorContext(ctx, "error in closing rows", "error", err)
+		}
+	}()
+
+	deliveries := make([]models.Delivery, 0)
+
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, errors.DB{Err: err}
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.DB{Err: err}
+	}
+
+	return deliveries, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanDelivery reads a single delivery from a row
+func scanDelivery(row scanner) (models.Delivery, error) {
+	var (
+		delivery    models.Delivery
+		lastError   sql.NullString
+		deliveredAt sql.NullTime
+	)
+
+	err := row.Scan(&delivery.ID, &delivery.WebhookID, &delivery.EventID, &delivery.EventType, &delivery.Payload, &delivery.Status,
+		&delivery.Attempts, &delivery.NextAttemptAt, &lastError, &delivery.CreatedAt, &deliveredAt)
+	if err != nil {
+		return models.Delivery{}, err
+	}
+
+	delivery.LastError = lastError.String
+
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+
+	return delivery, nil
+}
@@ -0,0 +1,192 @@
+package delivery
+
+import (
+	"context"
+	"database/sql"
+	goError "errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+func initializeTests(t *testing.T) (*sql.DB, sqlmock.Sqlmock, stores.Delivery) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error %s was not expected when opening a stub database connection", err)
+	}
+
+	s := New(db, logging.Discard())
+
+	return db, mock, s
+}
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	id        = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+	webhookID = uuid.MustParse("1b1d2b48-8d69-4a3c-9a3f-2b8e1f2d6c11")
+	eventID   = uuid.MustParse("6a0c5d7e-3f4b-4c1e-8a2d-9e7f6b5c4d3a")
+	createdAt = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns   = []string{"id", "webhook_id", "event_id", "event_type", "payload", "status", "attempts", "next_attempt_at", "last_error",
+		"created_at", "delivered_at"}
+)
+
+func pending() models.Delivery {
+	return models.Delivery{ID: id, WebhookID: webhookID, EventID: eventID, EventType: models.CarCreated, Payload: []byte(`{}`),
+		Status: models.DeliveryPending, NextAttemptAt: createdAt, CreatedAt: createdAt}
+}
+
+func TestStore_Create(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	delivery := pending()
+	queryErr := goError.New("query error")
+
+	mock.ExpectExec(insertDelivery).WithArgs(id.String(), webhookID.String(), eventID.String(), models.CarCreated, []byte(`{}`),
+		models.DeliveryPending, 0, createdAt, createdAt).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(insertDelivery).WithArgs(id.String(), webhookID.String(), eventID.String(), models.CarCreated, []byte(`{}`),
+		models.DeliveryPending, 0, createdAt, createdAt).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc string
+		err  error
+	}{
+		{"success", nil},
+		{"query error", errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		err := s.Create(context.Background(), &delivery)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestStore_GetAll(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	dead := pending()
+	dead.Status = models.DeliveryDead
+	dead.Attempts = 10
+	dead.LastError = "receiver answered 500 Internal Server Error"
+
+	mock.ExpectQuery(getDeliveries).WithArgs(webhookID.String()).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(id.String(), webhookID.String(), eventID.String(), models.CarCreated, []byte(`{}`), models.DeliveryPending, 0, createdAt,
+			nil, createdAt, nil))
+	mock.ExpectQuery(getDeliveriesWithState).WithArgs(webhookID.String(), models.DeliveryDead).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(id.String(), webhookID.String(), eventID.String(), models.CarCreated, []byte(`{}`), models.DeliveryDead, 10, createdAt,
+			dead.LastError, createdAt, nil))
+	mock.ExpectQuery(getDeliveries).WithArgs(webhookID.String()).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc   string
+		status string
+		output []models.Delivery
+		err    error
+	}{
+		{"every status", "", []models.Delivery{pending()}, nil},
+		{"dead deliveries", models.DeliveryDead, []models.Delivery{dead}, nil},
+		{"query error", "", nil, errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		output, err := s.GetAll(context.Background(), webhookID, tc.status)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+
+		if !reflect.DeepEqual(output, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, output, tc.output)
+		}
+	}
+}
+
+func TestStore_GetByID(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	delivered := pending()
+	delivered.Status = models.DeliveryDelivered
+	delivered.Attempts = 1
+	delivered.DeliveredAt = &createdAt
+
+	mock.ExpectQuery(getDelivery).WithArgs(id.String()).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(id.String(), webhookID.String(), eventID.String(), models.CarCreated, []byte(`{}`), models.DeliveryDelivered, 1, createdAt,
+			nil, createdAt, createdAt))
+	mock.ExpectQuery(getDelivery).WithArgs(id.String()).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(getDelivery).WithArgs(id.String()).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc   string
+		output models.Delivery
+		err    error
+	}{
+		{"success", delivered, nil},
+		{"not found", models.Delivery{}, errors.EntityNotFound{Entity: entity, ID: id.String()}},
+		{"query error", models.Delivery{}, errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		output, err := s.GetByID(context.Background(), id)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+
+		if !reflect.DeepEqual(output, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, output, tc.output)
+		}
+	}
+}
+
+func TestStore_DueUpdate(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	queryErr := goError.New("query error")
+
+	delivered := pending()
+	delivered.Status = models.DeliveryDelivered
+	delivered.DeliveredAt = &createdAt
+
+	failed := pending()
+	failed.LastError = "timeout"
+
+	mock.ExpectQuery(getDue).WithArgs(createdAt, 50).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(id.String(), webhookID.String(), eventID.String(), models.CarCreated, []byte(`{}`), models.DeliveryPending, 0, createdAt,
+			nil, createdAt, nil))
+	mock.ExpectExec(updateDelivery).WithArgs(models.DeliveryDelivered, 0, createdAt, sql.NullString{},
+		sql.NullTime{Time: createdAt, Valid: true}, id.String()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(updateDelivery).WithArgs(models.DeliveryPending, 0, createdAt, sql.NullString{String: "timeout", Valid: true},
+		sql.NullTime{}, id.String()).WillReturnError(queryErr)
+
+	due, err := s.Due(ctx, createdAt, 50)
+	if err != nil || !reflect.DeepEqual(due, []models.Delivery{pending()}) {
+		t.Errorf("\n[TEST] Failed. Desc : due deliveries\nGot %v, %v\nExpected %v", due, err, []models.Delivery{pending()})
+	}
+
+	if err := s.Update(ctx, &delivered); err != nil {
+		t.Errorf("\n[TEST] Failed. Desc : update delivered\nGot %v\nExpected nil", err)
+	}
+
+	if err := s.Update(ctx, &failed); !reflect.DeepEqual(err, errors.DB{Err: queryErr}) {
+		t.Errorf("\n[TEST] Failed. Desc : update error\nGot %v\nExpected %v", err, errors.DB{Err: queryErr})
+	}
+}
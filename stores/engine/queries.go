@@ -2,7 +2,7 @@ package engine
 
 const (
 	insertEngine = "INSERT INTO engines (id,displacement,no_of_cylinder,`range`) VALUES (?,?,?,?)"
-	getEngine    = "SELECT * FROM engines WHERE id=?"
+	getEngine    = "SELECT id,displacement,no_of_cylinder,`range` FROM engines WHERE id=?"
 	updateEngine = "UPDATE engines SET displacement=?,no_of_cylinder=?,`range`=? WHERE id=?"
 	deleteEngine = "DELETE FROM engines WHERE id = ?;"
 )
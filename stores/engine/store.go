@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/google/uuid"
@@ -8,6 +9,7 @@ import (
 	"github.com/amehrotra/car-dealership/errors"
 	"github.com/amehrotra/car-dealership/models"
 	"github.com/amehrotra/car-dealership/stores"
+	"github.com/amehrotra/car-dealership/tracing"
 )
 
 type store struct {
@@ -19,8 +21,9 @@ func New(db *sql.DB) stores.Engine {
 }
 
 // Create inserts a new engine in the database
-func (s store) Create(engine *models.Engine) error {
-	_, err := s.db.Exec(insertEngine, engine.ID, engine.Displacement, engine.NCylinder, engine.Range)
+func (s store) Create(ctx context.Context, engine *models.Engine) error {
+	tracing.Statement(ctx, insertEngine)
+	_, err := stores.Conn(ctx, s.db).ExecContext(ctx, insertEngine, engine.ID, engine.Displacement, engine.NCylinder, engine.Range)
 	if err != nil {
 		return errors.DB{Err: err}
 	}
@@ -29,10 +32,11 @@ func (s store) Create(engine *models.Engine) error {
 }
 
 // GetByID fetches the engine from database of the given id
-func (s store) GetByID(id uuid.UUID) (models.Engine, error) {
+func (s store) GetByID(ctx context.Context, id uuid.UUID) (models.Engine, error) {
 	var engine models.Engine
 
-	err := s.db.QueryRow(getEngine, id).
+	tracing.Statement(ctx, getEngine)
+	err := stores.Conn(ctx, s.db).QueryRowContext(ctx, getEngine, id).
 		Scan(&engine.ID, &engine.Displacement, &engine.NCylinder, &engine.Range)
 	if err != nil {
 		return models.Engine{}, errors.DB{Err: err}
@@ -42,8 +46,9 @@ func (s store) GetByID(id uuid.UUID) (models.Engine, error) {
 }
 
 // Update modifies engine of the given id
-func (s store) Update(engine *models.Engine) error {
-	_, err := s.db.Exec(updateEngine, engine.Displacement, engine.NCylinder, engine.Range, engine.ID.String())
+func (s store) Update(ctx context.Context, engine *models.Engine) error {
+	tracing.Statement(ctx, updateEngine)
+	_, err := stores.Conn(ctx, s.db).ExecContext(ctx, updateEngine, engine.Displacement, engine.NCylinder, engine.Range, engine.ID.String())
 	if err != nil {
 		return errors.DB{Err: err}
 	}
@@ -52,8 +57,9 @@ func (s store) Update(engine *models.Engine) error {
 }
 
 // Delete removes engine with the given id
-func (s store) Delete(id uuid.UUID) error {
-	_, err := s.db.Exec(deleteEngine, id.String())
+func (s store) Delete(ctx context.Context, id uuid.UUID) error {
+	tracing.Statement(ctx, deleteEngine)
+	_, err := stores.Conn(ctx, s.db).ExecContext(ctx, deleteEngine, id.String())
 	if err != nil {
 		return errors.DB{Err: err}
 	}
@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"context"
 	"database/sql"
 	goError "errors"
 	"testing"
@@ -56,7 +57,7 @@ func TestStore_Create(t *testing.T) {
 	}
 
 	for i, tc := range cases {
-		err := s.Create(&tc.input)
+		err := s.Create(context.Background(), &tc.input)
 
 		if err != tc.err {
 			t.Errorf("\n[TEST %v] Failed \nDesc %v\nGot %v\n Expected %v", i, tc.desc, err, tc.err)
@@ -98,7 +99,7 @@ func TestStore_GetByID(t *testing.T) {
 	}
 
 	for i, tc := range cases {
-		engine, err := s.GetByID(tc.input)
+		engine, err := s.GetByID(context.Background(), tc.input)
 
 		if engine != tc.output {
 			t.Errorf("\n[TEST %v] Failed \nDesc %v\nGot %v\n Expected %v", i, tc.desc, id, tc.output)
@@ -142,7 +143,7 @@ func TestStore_Update(t *testing.T) {
 	}
 
 	for i, tc := range cases {
-		err := s.Update(&tc.input)
+		err := s.Update(context.Background(), &tc.input)
 
 		if err != tc.err {
 			t.Errorf("\n[TEST %v] Failed \nDesc %v\nGot %v\n Expected %v", i, tc.desc, err, tc.err)
@@ -175,7 +176,7 @@ func TestStore_Delete(t *testing.T) {
 	}
 
 	for i, tc := range cases {
-		err := s.Delete(tc.id)
+		err := s.Delete(context.Background(), tc.id)
 
 		if err != tc.err {
 			t.Errorf("\n[TEST %v] Failed \nDesc %v\nGot %v\n Expected %v", i, tc.desc, err, tc.err)
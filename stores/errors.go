@@ -0,0 +1,17 @@
+package stores
+
+import (
+	goError "errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// errRowIsReferenced is the number of the MySQL error refusing to delete a row that a foreign key references
+const errRowIsReferenced = 1451
+
+// IsReferenced reports whether err refused to delete a row because other rows reference it
+func IsReferenced(err error) bool {
+	var mysqlErr *mysql.MySQLError
+
+	return goError.As(err, &mysqlErr) && mysqlErr.Number == errRowIsReferenced
+}
@@ -1,6 +1,9 @@
 package stores
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/uuid"
 
 	"github.com/amehrotra/car-dealership/filters"
@@ -8,16 +11,114 @@ import (
 )
 
 type Car interface {
-	Create(car *models.Car) error
-	GetAll(filter filters.Car) ([]models.Car, error)
-	GetByID(id uuid.UUID) (models.Car, error)
-	Update(car *models.Car) error
-	Delete(id uuid.UUID) error
+	Create(ctx context.Context, car *models.Car) error
+	GetAll(ctx context.Context, filter filters.Car) ([]models.Car, error)
+	GetByID(ctx context.Context, id uuid.UUID) (models.Car, error)
+	Update(ctx context.Context, car *models.Car) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Iterate(ctx context.Context, filter filters.Car) (CarIterator, error)
+	Lock(ctx context.Context, id uuid.UUID) error
+	// Stock counts the cars by brand and fuel type
+	Stock(ctx context.Context) ([]models.Stock, error)
+}
+
+// CarIterator walks the cars of a query along with their engines one row at a time, it must be closed
+type CarIterator interface {
+	Next() bool
+	Scan(car *models.Car) error
+	Err() error
+	Close() error
 }
 
 type Engine interface {
-	Create(engine *models.Engine) error
-	GetByID(id uuid.UUID) (models.Engine, error)
-	Update(engine *models.Engine) error
-	Delete(id uuid.UUID) error
+	Create(ctx context.Context, engine *models.Engine) error
+	GetByID(ctx context.Context, id uuid.UUID) (models.Engine, error)
+	Update(ctx context.Context, engine *models.Engine) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type APIKey interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	GetAll(ctx context.Context) ([]models.APIKey, error)
+	GetByID(ctx context.Context, id uuid.UUID) (models.APIKey, error)
+	UpdateHash(ctx context.Context, id uuid.UUID, hash string) error
+	UpdateLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error
+	Revoke(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+type Webhook interface {
+	Create(ctx context.Context, webhook *models.Webhook) error
+	GetAll(ctx context.Context) ([]models.Webhook, error)
+	GetByID(ctx context.Context, id uuid.UUID) (models.Webhook, error)
+	Update(ctx context.Context, webhook *models.Webhook) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type Customer interface {
+	Create(ctx context.Context, customer *models.Customer) error
+	GetAll(ctx context.Context, filter filters.Customer) ([]models.Customer, error)
+	GetByID(ctx context.Context, id uuid.UUID) (models.Customer, error)
+	Update(ctx context.Context, customer *models.Customer) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type Order interface {
+	Create(ctx context.Context, order *models.Order) error
+	GetAll(ctx context.Context, filter filters.Order) ([]models.Order, error)
+	GetByID(ctx context.Context, id uuid.UUID) (models.Order, error)
+	Lock(ctx context.Context, id uuid.UUID) (models.Order, error)
+	Update(ctx context.Context, order *models.Order) error
+}
+
+type Reservation interface {
+	Create(ctx context.Context, reservation *models.Reservation) error
+	GetActive(ctx context.Context, carID uuid.UUID, at time.Time) (models.Reservation, error)
+	Update(ctx context.Context, reservation *models.Reservation) error
+	Expire(ctx context.Context, at time.Time) (int64, error)
+}
+
+type TestDrive interface {
+	Create(ctx context.Context, drive *models.TestDrive) error
+	GetAll(ctx context.Context, filter filters.TestDrive) ([]models.TestDrive, error)
+	GetByID(ctx context.Context, id uuid.UUID) (models.TestDrive, error)
+	GetOverlapping(ctx context.Context, carID uuid.UUID, salesperson string, start, end time.Time) ([]models.TestDrive, error)
+	Update(ctx context.Context, drive *models.TestDrive) error
+}
+
+type TradeIn interface {
+	Create(ctx context.Context, tradeIn *models.TradeIn) error
+	GetAll(ctx context.Context, filter filters.TradeIn) ([]models.TradeIn, error)
+	GetByID(ctx context.Context, id uuid.UUID) (models.TradeIn, error)
+	Lock(ctx context.Context, id uuid.UUID) (models.TradeIn, error)
+	Update(ctx context.Context, tradeIn *models.TradeIn) error
+}
+
+type Delivery interface {
+	Create(ctx context.Context, delivery *models.Delivery) error
+	GetAll(ctx context.Context, webhookID uuid.UUID, status string) ([]models.Delivery, error)
+	GetByID(ctx context.Context, id uuid.UUID) (models.Delivery, error)
+	// Due locks up to limit pending deliveries whose next attempt is due, it has to be called in a transaction
+	Due(ctx context.Context, now time.Time, limit int) ([]models.Delivery, error)
+	Update(ctx context.Context, delivery *models.Delivery) error
+}
+
+type Outbox interface {
+	Create(ctx context.Context, msg *models.Message) error
+	// Pending locks up to limit unpublished messages in the order they were written, it has to be called in a transaction
+	Pending(ctx context.Context, limit int) ([]models.Message, error)
+	Delete(ctx context.Context, ids []uuid.UUID) error
+}
+
+// Broadcast keeps the published messages for a while so that every instance streams them, not only the one relaying them
+type Broadcast interface {
+	Create(ctx context.Context, msg *models.Message) error
+	// Since returns up to limit messages written after the one of seq, in order, and the seq of the last one returned
+	Since(ctx context.Context, seq int64, limit int) ([]models.Message, int64, error)
+	Last(ctx context.Context) (int64, error)
+	DeleteBefore(ctx context.Context, before time.Time) error
+}
+
+// Transactor runs the store calls of fn in one transaction
+type Transactor interface {
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
 }
@@ -5,7 +5,9 @@
 package stores
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	filters "github.com/amehrotra/car-dealership/filters"
 	models "github.com/amehrotra/car-dealership/models"
@@ -37,75 +39,198 @@ func (m *MockCar) EXPECT() *MockCarMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockCar) Create(car *models.Car) error {
+func (m *MockCar) Create(ctx context.Context, car *models.Car) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", car)
+	ret := m.ctrl.Call(m, "Create", ctx, car)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockCarMockRecorder) Create(car interface{}) *gomock.Call {
+func (mr *MockCarMockRecorder) Create(ctx, car interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCar)(nil).Create), car)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCar)(nil).Create), ctx, car)
 }
 
 // Delete mocks base method.
-func (m *MockCar) Delete(id uuid.UUID) error {
+func (m *MockCar) Delete(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockCarMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockCarMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCar)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCar)(nil).Delete), ctx, id)
 }
 
 // GetAll mocks base method.
-func (m *MockCar) GetAll(filter filters.Car) ([]models.Car, error) {
+func (m *MockCar) GetAll(ctx context.Context, filter filters.Car) ([]models.Car, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", filter)
+	ret := m.ctrl.Call(m, "GetAll", ctx, filter)
 	ret0, _ := ret[0].([]models.Car)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockCarMockRecorder) GetAll(filter interface{}) *gomock.Call {
+func (mr *MockCarMockRecorder) GetAll(ctx, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockCar)(nil).GetAll), filter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockCar)(nil).GetAll), ctx, filter)
 }
 
 // GetByID mocks base method.
-func (m *MockCar) GetByID(id uuid.UUID) (models.Car, error) {
+func (m *MockCar) GetByID(ctx context.Context, id uuid.UUID) (models.Car, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", id)
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
 	ret0, _ := ret[0].(models.Car)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockCarMockRecorder) GetByID(id interface{}) *gomock.Call {
+func (mr *MockCarMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCar)(nil).GetByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCar)(nil).GetByID), ctx, id)
+}
+
+// Iterate mocks base method.
+func (m *MockCar) Iterate(ctx context.Context, filter filters.Car) (CarIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Iterate", ctx, filter)
+	ret0, _ := ret[0].(CarIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Iterate indicates an expected call of Iterate.
+func (mr *MockCarMockRecorder) Iterate(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Iterate", reflect.TypeOf((*MockCar)(nil).Iterate), ctx, filter)
+}
+
+// Lock mocks base method.
+func (m *MockCar) Lock(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockCarMockRecorder) Lock(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockCar)(nil).Lock), ctx, id)
+}
+
+// Stock mocks base method.
+func (m *MockCar) Stock(ctx context.Context) ([]models.Stock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stock", ctx)
+	ret0, _ := ret[0].([]models.Stock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stock indicates an expected call of Stock.
+func (mr *MockCarMockRecorder) Stock(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stock", reflect.TypeOf((*MockCar)(nil).Stock), ctx)
 }
 
 // Update mocks base method.
-func (m *MockCar) Update(car *models.Car) error {
+func (m *MockCar) Update(ctx context.Context, car *models.Car) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", car)
+	ret := m.ctrl.Call(m, "Update", ctx, car)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockCarMockRecorder) Update(car interface{}) *gomock.Call {
+func (mr *MockCarMockRecorder) Update(ctx, car interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCar)(nil).Update), ctx, car)
+}
+
+// MockCarIterator is a mock of CarIterator interface.
+type MockCarIterator struct {
+	ctrl     *gomock.Controller
+	recorder *MockCarIteratorMockRecorder
+}
+
+// MockCarIteratorMockRecorder is the mock recorder for MockCarIterator.
+type MockCarIteratorMockRecorder struct {
+	mock *MockCarIterator
+}
+
+// NewMockCarIterator creates a new mock instance.
+func NewMockCarIterator(ctrl *gomock.Controller) *MockCarIterator {
+	mock := &MockCarIterator{ctrl: ctrl}
+	mock.recorder = &MockCarIteratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCarIterator) EXPECT() *MockCarIteratorMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockCarIterator) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockCarIteratorMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockCarIterator)(nil).Close))
+}
+
+// Err mocks base method.
+func (m *MockCarIterator) Err() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Err")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Err indicates an expected call of Err.
+func (mr *MockCarIteratorMockRecorder) Err() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Err", reflect.TypeOf((*MockCarIterator)(nil).Err))
+}
+
+// Next mocks base method.
+func (m *MockCarIterator) Next() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Next")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Next indicates an expected call of Next.
+func (mr *MockCarIteratorMockRecorder) Next() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockCarIterator)(nil).Next))
+}
+
+// Scan mocks base method.
+func (m *MockCarIterator) Scan(car *models.Car) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Scan", car)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *MockCarIteratorMockRecorder) Scan(car interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCar)(nil).Update), car)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockCarIterator)(nil).Scan), car)
 }
 
 // MockEngine is a mock of Engine interface.
@@ -132,58 +257,1007 @@ func (m *MockEngine) EXPECT() *MockEngineMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockEngine) Create(engine *models.Engine) error {
+func (m *MockEngine) Create(ctx context.Context, engine *models.Engine) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", engine)
+	ret := m.ctrl.Call(m, "Create", ctx, engine)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockEngineMockRecorder) Create(engine interface{}) *gomock.Call {
+func (mr *MockEngineMockRecorder) Create(ctx, engine interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEngine)(nil).Create), engine)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEngine)(nil).Create), ctx, engine)
 }
 
 // Delete mocks base method.
-func (m *MockEngine) Delete(id uuid.UUID) error {
+func (m *MockEngine) Delete(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockEngineMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockEngineMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockEngine)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockEngine)(nil).Delete), ctx, id)
 }
 
 // GetByID mocks base method.
-func (m *MockEngine) GetByID(id uuid.UUID) (models.Engine, error) {
+func (m *MockEngine) GetByID(ctx context.Context, id uuid.UUID) (models.Engine, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", id)
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
 	ret0, _ := ret[0].(models.Engine)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockEngineMockRecorder) GetByID(id interface{}) *gomock.Call {
+func (mr *MockEngineMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockEngine)(nil).GetByID), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockEngine) Update(ctx context.Context, engine *models.Engine) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, engine)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockEngineMockRecorder) Update(ctx, engine interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockEngine)(nil).Update), ctx, engine)
+}
+
+// MockAPIKey is a mock of APIKey interface.
+type MockAPIKey struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyMockRecorder
+}
+
+// MockAPIKeyMockRecorder is the mock recorder for MockAPIKey.
+type MockAPIKeyMockRecorder struct {
+	mock *MockAPIKey
+}
+
+// NewMockAPIKey creates a new mock instance.
+func NewMockAPIKey(ctrl *gomock.Controller) *MockAPIKey {
+	mock := &MockAPIKey{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKey) EXPECT() *MockAPIKeyMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAPIKey) Create(ctx context.Context, key *models.APIKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAPIKeyMockRecorder) Create(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAPIKey)(nil).Create), ctx, key)
+}
+
+// GetAll mocks base method.
+func (m *MockAPIKey) GetAll(ctx context.Context) ([]models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockAPIKeyMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAPIKey)(nil).GetAll), ctx)
+}
+
+// GetByID mocks base method.
+func (m *MockAPIKey) GetByID(ctx context.Context, id uuid.UUID) (models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockAPIKeyMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAPIKey)(nil).GetByID), ctx, id)
+}
+
+// Revoke mocks base method.
+func (m *MockAPIKey) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockAPIKeyMockRecorder) Revoke(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockAPIKey)(nil).Revoke), ctx, id, at)
+}
+
+// UpdateHash mocks base method.
+func (m *MockAPIKey) UpdateHash(ctx context.Context, id uuid.UUID, hash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHash", ctx, id, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateHash indicates an expected call of UpdateHash.
+func (mr *MockAPIKeyMockRecorder) UpdateHash(ctx, id, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHash", reflect.TypeOf((*MockAPIKey)(nil).UpdateHash), ctx, id, hash)
+}
+
+// UpdateLastUsed mocks base method.
+func (m *MockAPIKey) UpdateLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastUsed", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLastUsed indicates an expected call of UpdateLastUsed.
+func (mr *MockAPIKeyMockRecorder) UpdateLastUsed(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastUsed", reflect.TypeOf((*MockAPIKey)(nil).UpdateLastUsed), ctx, id, at)
+}
+
+// MockWebhook is a mock of Webhook interface.
+type MockWebhook struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookMockRecorder
+}
+
+// MockWebhookMockRecorder is the mock recorder for MockWebhook.
+type MockWebhookMockRecorder struct {
+	mock *MockWebhook
+}
+
+// NewMockWebhook creates a new mock instance.
+func NewMockWebhook(ctrl *gomock.Controller) *MockWebhook {
+	mock := &MockWebhook{ctrl: ctrl}
+	mock.recorder = &MockWebhookMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhook) EXPECT() *MockWebhookMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWebhook) Create(ctx context.Context, webhook *models.Webhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, webhook)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhookMockRecorder) Create(ctx, webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhook)(nil).Create), ctx, webhook)
+}
+
+// Delete mocks base method.
+func (m *MockWebhook) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWebhookMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebhook)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockWebhook) GetAll(ctx context.Context) ([]models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockWebhookMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockWebhook)(nil).GetAll), ctx)
+}
+
+// GetByID mocks base method.
+func (m *MockWebhook) GetByID(ctx context.Context, id uuid.UUID) (models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockWebhookMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockEngine)(nil).GetByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockWebhook)(nil).GetByID), ctx, id)
 }
 
 // Update mocks base method.
-func (m *MockEngine) Update(engine *models.Engine) error {
+func (m *MockWebhook) Update(ctx context.Context, webhook *models.Webhook) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", engine)
+	ret := m.ctrl.Call(m, "Update", ctx, webhook)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockEngineMockRecorder) Update(engine interface{}) *gomock.Call {
+func (mr *MockWebhookMockRecorder) Update(ctx, webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWebhook)(nil).Update), ctx, webhook)
+}
+
+// MockCustomer is a mock of Customer interface.
+type MockCustomer struct {
+	ctrl     *gomock.Controller
+	recorder *MockCustomerMockRecorder
+}
+
+// MockCustomerMockRecorder is the mock recorder for MockCustomer.
+type MockCustomerMockRecorder struct {
+	mock *MockCustomer
+}
+
+// NewMockCustomer creates a new mock instance.
+func NewMockCustomer(ctrl *gomock.Controller) *MockCustomer {
+	mock := &MockCustomer{ctrl: ctrl}
+	mock.recorder = &MockCustomerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCustomer) EXPECT() *MockCustomerMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCustomer) Create(ctx context.Context, customer *models.Customer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, customer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCustomerMockRecorder) Create(ctx, customer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCustomer)(nil).Create), ctx, customer)
+}
+
+// Delete mocks base method.
+func (m *MockCustomer) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCustomerMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCustomer)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockCustomer) GetAll(ctx context.Context, filter filters.Customer) ([]models.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, filter)
+	ret0, _ := ret[0].([]models.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockCustomerMockRecorder) GetAll(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockCustomer)(nil).GetAll), ctx, filter)
+}
+
+// GetByID mocks base method.
+func (m *MockCustomer) GetByID(ctx context.Context, id uuid.UUID) (models.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(models.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockCustomerMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCustomer)(nil).GetByID), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockCustomer) Update(ctx context.Context, customer *models.Customer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, customer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCustomerMockRecorder) Update(ctx, customer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCustomer)(nil).Update), ctx, customer)
+}
+
+// MockOrder is a mock of Order interface.
+type MockOrder struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderMockRecorder
+}
+
+// MockOrderMockRecorder is the mock recorder for MockOrder.
+type MockOrderMockRecorder struct {
+	mock *MockOrder
+}
+
+// NewMockOrder creates a new mock instance.
+func NewMockOrder(ctrl *gomock.Controller) *MockOrder {
+	mock := &MockOrder{ctrl: ctrl}
+	mock.recorder = &MockOrderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrder) EXPECT() *MockOrderMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockOrder) Create(ctx context.Context, order *models.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOrderMockRecorder) Create(ctx, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOrder)(nil).Create), ctx, order)
+}
+
+// GetAll mocks base method.
+func (m *MockOrder) GetAll(ctx context.Context, filter filters.Order) ([]models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, filter)
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockOrderMockRecorder) GetAll(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockOrder)(nil).GetAll), ctx, filter)
+}
+
+// GetByID mocks base method.
+func (m *MockOrder) GetByID(ctx context.Context, id uuid.UUID) (models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockOrderMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockOrder)(nil).GetByID), ctx, id)
+}
+
+// Lock mocks base method.
+func (m *MockOrder) Lock(ctx context.Context, id uuid.UUID) (models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx, id)
+	ret0, _ := ret[0].(models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockOrderMockRecorder) Lock(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockOrder)(nil).Lock), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockOrder) Update(ctx context.Context, order *models.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockOrderMockRecorder) Update(ctx, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockOrder)(nil).Update), ctx, order)
+}
+
+// MockReservation is a mock of Reservation interface.
+type MockReservation struct {
+	ctrl     *gomock.Controller
+	recorder *MockReservationMockRecorder
+}
+
+// MockReservationMockRecorder is the mock recorder for MockReservation.
+type MockReservationMockRecorder struct {
+	mock *MockReservation
+}
+
+// NewMockReservation creates a new mock instance.
+func NewMockReservation(ctrl *gomock.Controller) *MockReservation {
+	mock := &MockReservation{ctrl: ctrl}
+	mock.recorder = &MockReservationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReservation) EXPECT() *MockReservationMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockReservation) Create(ctx context.Context, reservation *models.Reservation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, reservation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockReservationMockRecorder) Create(ctx, reservation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReservation)(nil).Create), ctx, reservation)
+}
+
+// Expire mocks base method.
+func (m *MockReservation) Expire(ctx context.Context, at time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Expire", ctx, at)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Expire indicates an expected call of Expire.
+func (mr *MockReservationMockRecorder) Expire(ctx, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockReservation)(nil).Expire), ctx, at)
+}
+
+// GetActive mocks base method.
+func (m *MockReservation) GetActive(ctx context.Context, carID uuid.UUID, at time.Time) (models.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActive", ctx, carID, at)
+	ret0, _ := ret[0].(models.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActive indicates an expected call of GetActive.
+func (mr *MockReservationMockRecorder) GetActive(ctx, carID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActive", reflect.TypeOf((*MockReservation)(nil).GetActive), ctx, carID, at)
+}
+
+// Update mocks base method.
+func (m *MockReservation) Update(ctx context.Context, reservation *models.Reservation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, reservation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockReservationMockRecorder) Update(ctx, reservation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockReservation)(nil).Update), ctx, reservation)
+}
+
+// MockTestDrive is a mock of TestDrive interface.
+type MockTestDrive struct {
+	ctrl     *gomock.Controller
+	recorder *MockTestDriveMockRecorder
+}
+
+// MockTestDriveMockRecorder is the mock recorder for MockTestDrive.
+type MockTestDriveMockRecorder struct {
+	mock *MockTestDrive
+}
+
+// NewMockTestDrive creates a new mock instance.
+func NewMockTestDrive(ctrl *gomock.Controller) *MockTestDrive {
+	mock := &MockTestDrive{ctrl: ctrl}
+	mock.recorder = &MockTestDriveMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTestDrive) EXPECT() *MockTestDriveMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTestDrive) Create(ctx context.Context, drive *models.TestDrive) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, drive)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTestDriveMockRecorder) Create(ctx, drive interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTestDrive)(nil).Create), ctx, drive)
+}
+
+// GetAll mocks base method.
+func (m *MockTestDrive) GetAll(ctx context.Context, filter filters.TestDrive) ([]models.TestDrive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, filter)
+	ret0, _ := ret[0].([]models.TestDrive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockTestDriveMockRecorder) GetAll(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTestDrive)(nil).GetAll), ctx, filter)
+}
+
+// GetByID mocks base method.
+func (m *MockTestDrive) GetByID(ctx context.Context, id uuid.UUID) (models.TestDrive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(models.TestDrive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockTestDriveMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTestDrive)(nil).GetByID), ctx, id)
+}
+
+// GetOverlapping mocks base method.
+func (m *MockTestDrive) GetOverlapping(ctx context.Context, carID uuid.UUID, salesperson string, start, end time.Time) ([]models.TestDrive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOverlapping", ctx, carID, salesperson, start, end)
+	ret0, _ := ret[0].([]models.TestDrive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOverlapping indicates an expected call of GetOverlapping.
+func (mr *MockTestDriveMockRecorder) GetOverlapping(ctx, carID, salesperson, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOverlapping", reflect.TypeOf((*MockTestDrive)(nil).GetOverlapping), ctx, carID, salesperson, start, end)
+}
+
+// Update mocks base method.
+func (m *MockTestDrive) Update(ctx context.Context, drive *models.TestDrive) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, drive)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTestDriveMockRecorder) Update(ctx, drive interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTestDrive)(nil).Update), ctx, drive)
+}
+
+// MockTradeIn is a mock of TradeIn interface.
+type MockTradeIn struct {
+	ctrl     *gomock.Controller
+	recorder *MockTradeInMockRecorder
+}
+
+// MockTradeInMockRecorder is the mock recorder for MockTradeIn.
+type MockTradeInMockRecorder struct {
+	mock *MockTradeIn
+}
+
+// NewMockTradeIn creates a new mock instance.
+func NewMockTradeIn(ctrl *gomock.Controller) *MockTradeIn {
+	mock := &MockTradeIn{ctrl: ctrl}
+	mock.recorder = &MockTradeInMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTradeIn) EXPECT() *MockTradeInMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTradeIn) Create(ctx context.Context, tradeIn *models.TradeIn) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, tradeIn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTradeInMockRecorder) Create(ctx, tradeIn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTradeIn)(nil).Create), ctx, tradeIn)
+}
+
+// GetAll mocks base method.
+func (m *MockTradeIn) GetAll(ctx context.Context, filter filters.TradeIn) ([]models.TradeIn, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, filter)
+	ret0, _ := ret[0].([]models.TradeIn)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockTradeInMockRecorder) GetAll(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTradeIn)(nil).GetAll), ctx, filter)
+}
+
+// GetByID mocks base method.
+func (m *MockTradeIn) GetByID(ctx context.Context, id uuid.UUID) (models.TradeIn, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(models.TradeIn)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockTradeInMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTradeIn)(nil).GetByID), ctx, id)
+}
+
+// Lock mocks base method.
+func (m *MockTradeIn) Lock(ctx context.Context, id uuid.UUID) (models.TradeIn, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx, id)
+	ret0, _ := ret[0].(models.TradeIn)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockTradeInMockRecorder) Lock(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockTradeIn)(nil).Lock), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockTradeIn) Update(ctx context.Context, tradeIn *models.TradeIn) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, tradeIn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTradeInMockRecorder) Update(ctx, tradeIn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTradeIn)(nil).Update), ctx, tradeIn)
+}
+
+// MockDelivery is a mock of Delivery interface.
+type MockDelivery struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeliveryMockRecorder
+}
+
+// MockDeliveryMockRecorder is the mock recorder for MockDelivery.
+type MockDeliveryMockRecorder struct {
+	mock *MockDelivery
+}
+
+// NewMockDelivery creates a new mock instance.
+func NewMockDelivery(ctrl *gomock.Controller) *MockDelivery {
+	mock := &MockDelivery{ctrl: ctrl}
+	mock.recorder = &MockDeliveryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDelivery) EXPECT() *MockDeliveryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDelivery) Create(ctx context.Context, delivery *models.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDeliveryMockRecorder) Create(ctx, delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDelivery)(nil).Create), ctx, delivery)
+}
+
+// Due mocks base method.
+func (m *MockDelivery) Due(ctx context.Context, now time.Time, limit int) ([]models.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Due", ctx, now, limit)
+	ret0, _ := ret[0].([]models.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Due indicates an expected call of Due.
+func (mr *MockDeliveryMockRecorder) Due(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Due", reflect.TypeOf((*MockDelivery)(nil).Due), ctx, now, limit)
+}
+
+// GetAll mocks base method.
+func (m *MockDelivery) GetAll(ctx context.Context, webhookID uuid.UUID, status string) ([]models.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, webhookID, status)
+	ret0, _ := ret[0].([]models.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockDeliveryMockRecorder) GetAll(ctx, webhookID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockDelivery)(nil).GetAll), ctx, webhookID, status)
+}
+
+// GetByID mocks base method.
+func (m *MockDelivery) GetByID(ctx context.Context, id uuid.UUID) (models.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(models.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockDeliveryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockDelivery)(nil).GetByID), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockDelivery) Update(ctx context.Context, delivery *models.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDeliveryMockRecorder) Update(ctx, delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDelivery)(nil).Update), ctx, delivery)
+}
+
+// MockOutbox is a mock of Outbox interface.
+type MockOutbox struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxMockRecorder
+}
+
+// MockOutboxMockRecorder is the mock recorder for MockOutbox.
+type MockOutboxMockRecorder struct {
+	mock *MockOutbox
+}
+
+// NewMockOutbox creates a new mock instance.
+func NewMockOutbox(ctrl *gomock.Controller) *MockOutbox {
+	mock := &MockOutbox{ctrl: ctrl}
+	mock.recorder = &MockOutboxMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutbox) EXPECT() *MockOutboxMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockOutbox) Create(ctx context.Context, msg *models.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOutboxMockRecorder) Create(ctx, msg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOutbox)(nil).Create), ctx, msg)
+}
+
+// Delete mocks base method.
+func (m *MockOutbox) Delete(ctx context.Context, ids []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockOutboxMockRecorder) Delete(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockOutbox)(nil).Delete), ctx, ids)
+}
+
+// Pending mocks base method.
+func (m *MockOutbox) Pending(ctx context.Context, limit int) ([]models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pending", ctx, limit)
+	ret0, _ := ret[0].([]models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pending indicates an expected call of Pending.
+func (mr *MockOutboxMockRecorder) Pending(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pending", reflect.TypeOf((*MockOutbox)(nil).Pending), ctx, limit)
+}
+
+// MockBroadcast is a mock of Broadcast interface.
+type MockBroadcast struct {
+	ctrl     *gomock.Controller
+	recorder *MockBroadcastMockRecorder
+}
+
+// MockBroadcastMockRecorder is the mock recorder for MockBroadcast.
+type MockBroadcastMockRecorder struct {
+	mock *MockBroadcast
+}
+
+// NewMockBroadcast creates a new mock instance.
+func NewMockBroadcast(ctrl *gomock.Controller) *MockBroadcast {
+	mock := &MockBroadcast{ctrl: ctrl}
+	mock.recorder = &MockBroadcastMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBroadcast) EXPECT() *MockBroadcastMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockBroadcast) Create(ctx context.Context, msg *models.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockBroadcastMockRecorder) Create(ctx, msg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBroadcast)(nil).Create), ctx, msg)
+}
+
+// DeleteBefore mocks base method.
+func (m *MockBroadcast) DeleteBefore(ctx context.Context, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBefore", ctx, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBefore indicates an expected call of DeleteBefore.
+func (mr *MockBroadcastMockRecorder) DeleteBefore(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBefore", reflect.TypeOf((*MockBroadcast)(nil).DeleteBefore), ctx, before)
+}
+
+// Last mocks base method.
+func (m *MockBroadcast) Last(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Last", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Last indicates an expected call of Last.
+func (mr *MockBroadcastMockRecorder) Last(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Last", reflect.TypeOf((*MockBroadcast)(nil).Last), ctx)
+}
+
+// Since mocks base method.
+func (m *MockBroadcast) Since(ctx context.Context, seq int64, limit int) ([]models.Message, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Since", ctx, seq, limit)
+	ret0, _ := ret[0].([]models.Message)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Since indicates an expected call of Since.
+func (mr *MockBroadcastMockRecorder) Since(ctx, seq, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Since", reflect.TypeOf((*MockBroadcast)(nil).Since), ctx, seq, limit)
+}
+
+// MockTransactor is a mock of Transactor interface.
+type MockTransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactorMockRecorder
+}
+
+// MockTransactorMockRecorder is the mock recorder for MockTransactor.
+type MockTransactorMockRecorder struct {
+	mock *MockTransactor
+}
+
+// NewMockTransactor creates a new mock instance.
+func NewMockTransactor(ctrl *gomock.Controller) *MockTransactor {
+	mock := &MockTransactor{ctrl: ctrl}
+	mock.recorder = &MockTransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactor) EXPECT() *MockTransactorMockRecorder {
+	return m.recorder
+}
+
+// InTx mocks base method.
+func (m *MockTransactor) InTx(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InTx indicates an expected call of InTx.
+func (mr *MockTransactorMockRecorder) InTx(ctx, fn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockEngine)(nil).Update), engine)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InTx", reflect.TypeOf((*MockTransactor)(nil).InTx), ctx, fn)
 }
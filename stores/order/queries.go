@@ -0,0 +1,13 @@
+package order
+
+const (
+	orderColumns = "id,car_id,customer_id,price,taxes,fees,trade_in_credit,deposit,total,status,created_at,updated_at"
+
+	insertOrder = "INSERT INTO orders (" + orderColumns + ") VALUES (?,?,?,?,?,?,?,?,?,?,?,?)"
+	// empty filters match every order
+	getOrders = "SELECT " + orderColumns + " FROM orders WHERE (?='' OR car_id=?) AND (?='' OR customer_id=?) AND (?='' OR status=?) " +
+		"ORDER BY created_at,id;"
+	getOrder    = "SELECT " + orderColumns + " FROM orders WHERE id=?;"
+	lockOrder   = "SELECT " + orderColumns + " FROM orders WHERE id=? FOR UPDATE;"
+	updateOrder = "UPDATE orders SET price=?,taxes=?,fees=?,trade_in_credit=?,deposit=?,total=?,status=?,updated_at=? WHERE id=?"
+)
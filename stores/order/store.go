@@ -0,0 +1,132 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	goError "errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+const entity = "order"
+
+type store struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func New(db *sql.DB, logger *slog.Logger) stores.Order {
+	return store{db: db, logger: logger}
+}
+
+// Create inserts a new order
+func (s store) Create(ctx context.Context, order *models.Order) error {
+	_, err := stores.Conn(ctx, s.db).ExecContext(ctx, insertOrder, order.ID.String(), order.CarID.String(), order.CustomerID.String(),
+		order.Price, order.Taxes, order.Fees, order.TradeInCredit, order.Deposit, order.Total, order.Status, order.CreatedAt,
+		order.UpdatedAt)
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
+
+// GetAll fetches the orders of the car, customer and status of the filter, oldest first
+func (s store) GetAll(ctx context.Context, filter filters.Order) ([]models.Order, error) {
+	carID, customerID := idParam(filter.CarID), idParam(filter.CustomerID)
+
+	rows, err := stores.Conn(ctx, s.db).QueryContext(ctx, getOrders, carID, carID, customerID, customerID, filter.Status, filter.Status)
+	if err != nil {
+		return nil, errors.DB{Err: err}
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			s.logger.ErrorContext(ctx, "error in closing rows", "error", err)
+		}
+	}()
+
+	orders := make([]models.Order, 0)
+
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, errors.DB{Err: err}
+		}
+
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.DB{Err: err}
+	}
+
+	return orders, nil
+}
+
+// GetByID fetches the order of the given id
+func (s store) GetByID(ctx context.Context, id uuid.UUID) (models.Order, error) {
+	return s.get(ctx, getOrder, id)
+}
+
+// Lock fetches the order of the given id and locks its row until the end of the transaction of ctx
+func (s store) Lock(ctx context.Context, id uuid.UUID) (models.Order, error) {
+	return s.get(ctx, lockOrder, id)
+}
+
+// Update changes the amounts and the status of the order, the car and the customer of an order never change
+func (s store) Update(ctx context.Context, order *models.Order) error {
+	_, err := stores.Conn(ctx, s.db).ExecContext(ctx, updateOrder, order.Price, order.Taxes, order.Fees, order.TradeInCredit,
+		order.Deposit, order.Total, order.Status, order.UpdatedAt, order.ID.String())
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
+
+// get reads the order of the given id with the query
+func (s store) get(ctx context.Context, query string, id uuid.UUID) (models.Order, error) {
+	order, err := scanOrder(stores.Conn(ctx, s.db).QueryRowContext(ctx, query, id.String()))
+
+	switch {
+	case goError.Is(err, sql.ErrNoRows):
+		return models.Order{}, errors.EntityNotFound{Entity: entity, ID: id.String()}
+	case err != nil:
+		return models.Order{}, errors.DB{Err: err}
+	}
+
+	return order, nil
+}
+
+// idParam is the id as a query parameter, empty for the nil id so that it matches every row
+func idParam(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+
+	return id.String()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanOrder reads a single order from a row
+func scanOrder(row scanner) (models.Order, error) {
+	var o models.Order
+
+	err := row.Scan(&o.ID, &o.CarID, &o.CustomerID, &o.Price, &o.Taxes, &o.Fees, &o.TradeInCredit, &o.Deposit, &o.Total, &o.Status,
+		&o.CreatedAt, &o.UpdatedAt)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	return o, nil
+}
@@ -0,0 +1,170 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	goError "errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+func initializeTests(t *testing.T) (*sql.DB, sqlmock.Sqlmock, stores.Order) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error %s was not expected when opening a stub database connection", err)
+	}
+
+	s := New(db, logging.Discard())
+
+	return db, mock, s
+}
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	id         = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+	carID      = uuid.MustParse("3b7e4a1c-2b0e-4d55-a3a4-6f1f1f0c9a10")
+	customerID = uuid.MustParse("c4d0f7a2-5e1b-4f0e-9d57-0a2b8e6d3c21")
+	createdAt  = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	order      = models.Order{ID: id, CarID: carID, CustomerID: customerID, Price: 3000000, Taxes: 300000, Fees: 5000,
+		TradeInCredit: 500000, Deposit: 100000, Total: 2805000, Status: models.OrderDraft, CreatedAt: createdAt, UpdatedAt: createdAt}
+	columns = []string{"id", "car_id", "customer_id", "price", "taxes", "fees", "trade_in_credit", "deposit", "total", "status",
+		"created_at", "updated_at"}
+)
+
+// row is the row of the order of the tests
+func row() []driver.Value {
+	return []driver.Value{id.String(), carID.String(), customerID.String(), 3000000, 300000, 5000, 500000, 100000, 2805000,
+		models.OrderDraft, createdAt, createdAt}
+}
+
+func TestStore_Create(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	mock.ExpectExec(insertOrder).WithArgs(row()...).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(insertOrder).WithArgs(row()...).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc string
+		err  error
+	}{
+		{"success", nil},
+		{"query error", errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		err := s.Create(context.Background(), &order)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestStore_GetAll(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	mock.ExpectQuery(getOrders).WithArgs(carID.String(), carID.String(), "", "", "", "").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(row()...))
+	mock.ExpectQuery(getOrders).WithArgs("", "", customerID.String(), customerID.String(), models.OrderPaid, models.OrderPaid).
+		WillReturnRows(sqlmock.NewRows(columns))
+	mock.ExpectQuery(getOrders).WithArgs("", "", "", "", "", "").WillReturnError(queryErr)
+
+	cases := []struct {
+		desc   string
+		filter filters.Order
+		output []models.Order
+		err    error
+	}{
+		{"by car", filters.Order{CarID: carID}, []models.Order{order}, nil},
+		{"paid orders of the customer", filters.Order{CustomerID: customerID, Status: models.OrderPaid}, []models.Order{}, nil},
+		{"query error", filters.Order{}, nil, errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		output, err := s.GetAll(context.Background(), tc.filter)
+
+		if !reflect.DeepEqual(err, tc.err) || !reflect.DeepEqual(output, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v, %v", i, tc.desc, output, err, tc.output, tc.err)
+		}
+	}
+}
+
+func TestStore_GetByIDLock(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	mock.ExpectQuery(getOrder).WithArgs(id.String()).WillReturnRows(sqlmock.NewRows(columns).AddRow(row()...))
+	mock.ExpectQuery(lockOrder).WithArgs(id.String()).WillReturnRows(sqlmock.NewRows(columns).AddRow(row()...))
+	mock.ExpectQuery(getOrder).WithArgs(id.String()).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(lockOrder).WithArgs(id.String()).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(getOrder).WithArgs(id.String()).WillReturnError(queryErr)
+	mock.ExpectQuery(lockOrder).WithArgs(id.String()).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc   string
+		get    func(ctx context.Context, id uuid.UUID) (models.Order, error)
+		output models.Order
+		err    error
+	}{
+		{"success", s.GetByID, order, nil},
+		{"locked", s.Lock, order, nil},
+		{"not found", s.GetByID, models.Order{}, errors.EntityNotFound{Entity: entity, ID: id.String()}},
+		{"locked not found", s.Lock, models.Order{}, errors.EntityNotFound{Entity: entity, ID: id.String()}},
+		{"query error", s.GetByID, models.Order{}, errors.DB{Err: queryErr}},
+		{"lock error", s.Lock, models.Order{}, errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		output, err := tc.get(context.Background(), id)
+
+		if !reflect.DeepEqual(err, tc.err) || !reflect.DeepEqual(output, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v, %v", i, tc.desc, output, err, tc.output, tc.err)
+		}
+	}
+}
+
+func TestStore_Update(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+	args := append(append([]driver.Value{}, row()[3:10]...), createdAt, id.String())
+
+	mock.ExpectExec(updateOrder).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(updateOrder).WithArgs(args...).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc string
+		err  error
+	}{
+		{"success", nil},
+		{"query error", errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		err := s.Update(context.Background(), &order)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
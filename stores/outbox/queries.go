@@ -0,0 +1,7 @@
+package outbox
+
+const (
+	insertMessage = "INSERT INTO outbox (id,message_key,type,payload,occurred_at) VALUES (?,?,?,?,?)"
+	getPending    = "SELECT id,message_key,type,payload,occurred_at FROM outbox ORDER BY seq LIMIT ? FOR UPDATE;"
+	deleteMessage = "DELETE FROM outbox WHERE id IN (?"
+)
@@ -0,0 +1,91 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+type store struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func New(db *sql.DB, logger *slog.Logger) stores.Outbox {
+	return store{db: db, logger: logger}
+}
+
+// Create writes the message, in the transaction of the change it reports
+func (s store) Create(ctx context.Context, msg *models.Message) error {
+	_, err := stores.Conn(ctx, s.db).ExecContext(ctx, insertMessage, msg.ID.String(), msg.Key, msg.Type, []byte(msg.Payload), msg.OccurredAt)
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
+
+// Pending locks the oldest messages, other relays wait for the lock rather than skipping rows
+// so that the messages of a key are never published out of order
+func (s store) Pending(ctx context.Context, limit int) ([]models.Message, error) {
+	rows, err := stores.Conn(ctx, s.db).QueryContext(ctx, getPending, limit)
+	if err != nil {
+		return nil, errors.DB{Err: err}
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			s.logger.ErrorContext(ctx, "error in closing rows", "error", err)
+		}
+	}()
+
+	messages := make([]models.Message, 0)
+
+	for rows.Next() {
+		var (
+			msg     models.Message
+			payload []byte
+		)
+
+		if err := rows.Scan(&msg.ID, &msg.Key, &msg.Type, &payload, &msg.OccurredAt); err != nil {
+			return nil, errors.DB{Err: err}
+		}
+
+		msg.Payload = payload
+		messages = append(messages, msg)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.DB{Err: err}
+	}
+
+	return messages, nil
+}
+
+// Delete removes the published messages
+func (s store) Delete(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id.String()
+	}
+
+	query := deleteMessage + strings.Repeat(",?", len(ids)-1) + ")"
+
+	_, err := stores.Conn(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	goError "errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+func initializeTests(t *testing.T) (*sql.DB, sqlmock.Sqlmock, stores.Outbox) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error %s was not expected when opening a stub database connection", err)
+	}
+
+	s := New(db, logging.Discard())
+
+	return db, mock, s
+}
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	id         = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+	otherID    = uuid.MustParse("1b1d2b48-8d69-4a3c-9a3f-2b8e1f2d6c11")
+	occurredAt = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns    = []string{"id", "message_key", "type", "payload", "occurred_at"}
+)
+
+func TestStore_Create(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	msg := models.Message{ID: id, Key: "car", Type: models.CarCreated, Payload: []byte(`{}`), OccurredAt: occurredAt}
+	queryErr := goError.New("query error")
+
+	mock.ExpectExec(insertMessage).WithArgs(id.String(), "car", models.CarCreated, []byte(`{}`), occurredAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(insertMessage).WithArgs(id.String(), "car", models.CarCreated, []byte(`{}`), occurredAt).
+		WillReturnError(queryErr)
+
+	cases := []struct {
+		desc string
+		err  error
+	}{
+		{"success", nil},
+		{"query error", errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		err := s.Create(context.Background(), &msg)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestStore_Pending(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	mock.ExpectQuery(getPending).WithArgs(10).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(id.String(), "car", models.CarCreated, []byte(`{}`), occurredAt))
+	mock.ExpectQuery(getPending).WithArgs(10).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc   string
+		output []models.Message
+		err    error
+	}{
+		{"success", []models.Message{{ID: id, Key: "car", Type: models.CarCreated, Payload: []byte(`{}`), OccurredAt: occurredAt}}, nil},
+		{"query error", nil, errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		output, err := s.Pending(context.Background(), 10)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+
+		if !reflect.DeepEqual(output, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, output, tc.output)
+		}
+	}
+}
+
+func TestStore_Delete(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	mock.ExpectExec(deleteMessage+",?)").WithArgs(id.String(), otherID.String()).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(deleteMessage + ")").WithArgs(id.String()).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc string
+		ids  []uuid.UUID
+		err  error
+	}{
+		{"published messages", []uuid.UUID{id, otherID}, nil},
+		{"query error", []uuid.UUID{id}, errors.DB{Err: queryErr}},
+		{"nothing published", nil, nil},
+	}
+
+	for i, tc := range cases {
+		err := s.Delete(context.Background(), tc.ids)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
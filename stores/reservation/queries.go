@@ -0,0 +1,11 @@
+package reservation
+
+const (
+	reservationColumns = "id,car_id,customer_id,deposit,expires_at,status,created_at"
+
+	insertReservation = "INSERT INTO reservations (" + reservationColumns + ") VALUES (?,?,?,?,?,?,?)"
+	getActive         = "SELECT " + reservationColumns + " FROM reservations WHERE car_id=? AND status='active' AND expires_at>? " +
+		"ORDER BY created_at DESC LIMIT 1;"
+	updateReservation  = "UPDATE reservations SET status=? WHERE id=?"
+	expireReservations = "UPDATE reservations SET status='expired' WHERE status='active' AND expires_at<=?"
+)
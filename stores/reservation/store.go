@@ -0,0 +1,80 @@
+package reservation
+
+import (
+	"context"
+	"database/sql"
+	goError "errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+const entity = "reservation"
+
+type store struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func New(db *sql.DB, logger *slog.Logger) stores.Reservation {
+	return store{db: db, logger: logger}
+}
+
+// Create inserts a new reservation
+func (s store) Create(ctx context.Context, reservation *models.Reservation) error {
+	_, err := stores.Conn(ctx, s.db).ExecContext(ctx, insertReservation, reservation.ID.String(), reservation.CarID.String(),
+		reservation.CustomerID.String(), reservation.Deposit, reservation.ExpiresAt, reservation.Status, reservation.CreatedAt)
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
+
+// GetActive fetches the reservation holding the car at the given time, active reservations past their expiry that are
+// not swept yet no longer hold it
+func (s store) GetActive(ctx context.Context, carID uuid.UUID, at time.Time) (models.Reservation, error) {
+	var r models.Reservation
+
+	err := stores.Conn(ctx, s.db).QueryRowContext(ctx, getActive, carID.String(), at).
+		Scan(&r.ID, &r.CarID, &r.CustomerID, &r.Deposit, &r.ExpiresAt, &r.Status, &r.CreatedAt)
+
+	switch {
+	case goError.Is(err, sql.ErrNoRows):
+		return models.Reservation{}, errors.EntityNotFound{Entity: entity, ID: carID.String()}
+	case err != nil:
+		return models.Reservation{}, errors.DB{Err: err}
+	}
+
+	return r, nil
+}
+
+// Update changes the status of the reservation
+func (s store) Update(ctx context.Context, reservation *models.Reservation) error {
+	_, err := stores.Conn(ctx, s.db).ExecContext(ctx, updateReservation, reservation.Status, reservation.ID.String())
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
+
+// Expire marks the active reservations past their expiry at the given time as expired and returns how many there were
+func (s store) Expire(ctx context.Context, at time.Time) (int64, error) {
+	res, err := stores.Conn(ctx, s.db).ExecContext(ctx, expireReservations, at)
+	if err != nil {
+		return 0, errors.DB{Err: err}
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.DB{Err: err}
+	}
+
+	return n, nil
+}
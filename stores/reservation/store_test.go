@@ -0,0 +1,135 @@
+package reservation
+
+import (
+	"context"
+	"database/sql"
+	goError "errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+func initializeTests(t *testing.T) (*sql.DB, sqlmock.Sqlmock, stores.Reservation) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error %s was not expected when opening a stub database connection", err)
+	}
+
+	s := New(db, logging.Discard())
+
+	return db, mock, s
+}
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	id          = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+	carID       = uuid.MustParse("3b7e4a1c-2b0e-4d55-a3a4-6f1f1f0c9a10")
+	customerID  = uuid.MustParse("c4d0f7a2-5e1b-4f0e-9d57-0a2b8e6d3c21")
+	createdAt   = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt   = createdAt.Add(72 * time.Hour)
+	reservation = models.Reservation{ID: id, CarID: carID, CustomerID: customerID, Deposit: 50000, ExpiresAt: expiresAt,
+		Status: models.ReservationActive, CreatedAt: createdAt}
+	columns = []string{"id", "car_id", "customer_id", "deposit", "expires_at", "status", "created_at"}
+)
+
+func TestStore_Create(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	mock.ExpectExec(insertReservation).WithArgs(id.String(), carID.String(), customerID.String(), 50000, expiresAt, "active", createdAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(insertReservation).WithArgs(id.String(), carID.String(), customerID.String(), 50000, expiresAt, "active", createdAt).
+		WillReturnError(queryErr)
+
+	cases := []struct {
+		desc string
+		err  error
+	}{
+		{"success", nil},
+		{"query error", errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		err := s.Create(context.Background(), &reservation)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestStore_GetActive(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+	at := createdAt.Add(time.Hour)
+
+	mock.ExpectQuery(getActive).WithArgs(carID.String(), at).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(id.String(), carID.String(), customerID.String(), 50000, expiresAt, "active", createdAt))
+	mock.ExpectQuery(getActive).WithArgs(carID.String(), at).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(getActive).WithArgs(carID.String(), at).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc   string
+		output models.Reservation
+		err    error
+	}{
+		{"held", reservation, nil},
+		{"not held", models.Reservation{}, errors.EntityNotFound{Entity: entity, ID: carID.String()}},
+		{"query error", models.Reservation{}, errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		output, err := s.GetActive(context.Background(), carID, at)
+
+		if !reflect.DeepEqual(err, tc.err) || !reflect.DeepEqual(output, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v, %v", i, tc.desc, output, err, tc.output, tc.err)
+		}
+	}
+}
+
+func TestStore_UpdateExpire(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	queryErr := goError.New("query error")
+	cancelled := reservation
+	cancelled.Status = models.ReservationCancelled
+
+	mock.ExpectExec(updateReservation).WithArgs("cancelled", id.String()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(expireReservations).WithArgs(expiresAt).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(expireReservations).WithArgs(expiresAt).WillReturnError(queryErr)
+
+	if err := s.Update(ctx, &cancelled); err != nil {
+		t.Errorf("\n[TEST] Failed. Desc : cancel\nGot %v\nExpected nil", err)
+	}
+
+	cases := []struct {
+		desc    string
+		expired int64
+		err     error
+	}{
+		{"expired", 3, nil},
+		{"query error", 0, errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		expired, err := s.Expire(ctx, expiresAt)
+
+		if !reflect.DeepEqual(err, tc.err) || expired != tc.expired {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v, %v", i, tc.desc, expired, err, tc.expired, tc.err)
+		}
+	}
+}
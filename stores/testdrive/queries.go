@@ -0,0 +1,17 @@
+package testdrive
+
+const (
+	testDriveColumns = "id,car_id,customer_id,salesperson,starts_at,ends_at,status,notes,created_at,updated_at"
+
+	insertTestDrive = "INSERT INTO test_drives (" + testDriveColumns + ") VALUES (?,?,?,?,?,?,?,?,?,?)"
+	// empty filters match every test drive
+	getTestDrives = "SELECT " + testDriveColumns + " FROM test_drives WHERE (?='' OR car_id=?) AND (?='' OR customer_id=?) " +
+		"AND (?='' OR salesperson=?) AND (?='' OR status=?) AND (? IS NULL OR ends_at>?) AND (? IS NULL OR starts_at<?) " +
+		"ORDER BY starts_at,id;"
+	getTestDrive = "SELECT " + testDriveColumns + " FROM test_drives WHERE id=?;"
+	// the overlapping rows are locked, and the gaps between them with the index on salesperson and starts_at, so that two
+	// bookings of a salesperson are not made at once
+	getOverlapping = "SELECT " + testDriveColumns + " FROM test_drives WHERE status='scheduled' AND (car_id=? OR salesperson=?) " +
+		"AND starts_at<? AND ends_at>? ORDER BY starts_at,id FOR UPDATE;"
+	updateTestDrive = "UPDATE test_drives SET starts_at=?,ends_at=?,status=?,notes=?,updated_at=? WHERE id=?"
+)
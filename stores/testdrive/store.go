@@ -0,0 +1,143 @@
+package testdrive
+
+import (
+	"context"
+	"database/sql"
+	goError "errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+const entity = "test drive"
+
+type store struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func New(db *sql.DB, logger *slog.Logger) stores.TestDrive {
+	return store{db: db, logger: logger}
+}
+
+// Create inserts a new test drive
+func (s store) Create(ctx context.Context, drive *models.TestDrive) error {
+	_, err := stores.Conn(ctx, s.db).ExecContext(ctx, insertTestDrive, drive.ID.String(), drive.CarID.String(), drive.CustomerID.String(),
+		drive.Salesperson, drive.StartsAt, drive.EndsAt, drive.Status, drive.Notes, drive.CreatedAt, drive.UpdatedAt)
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
+
+// GetAll fetches the test drives of the filter, earliest first
+func (s store) GetAll(ctx context.Context, filter filters.TestDrive) ([]models.TestDrive, error) {
+	carID, customerID := idParam(filter.CarID), idParam(filter.CustomerID)
+	from, to := timeParam(filter.From), timeParam(filter.To)
+
+	return s.query(ctx, getTestDrives, carID, carID, customerID, customerID, filter.Salesperson, filter.Salesperson, filter.Status,
+		filter.Status, from, from, to, to)
+}
+
+// GetByID fetches the test drive of the given id
+func (s store) GetByID(ctx context.Context, id uuid.UUID) (models.TestDrive, error) {
+	drive, err := scanTestDrive(stores.Conn(ctx, s.db).QueryRowContext(ctx, getTestDrive, id.String()))
+
+	switch {
+	case goError.Is(err, sql.ErrNoRows):
+		return models.TestDrive{}, errors.EntityNotFound{Entity: entity, ID: id.String()}
+	case err != nil:
+		return models.TestDrive{}, errors.DB{Err: err}
+	}
+
+	return drive, nil
+}
+
+// GetOverlapping fetches and locks the scheduled test drives of the car or the salesperson overlapping start to end
+func (s store) GetOverlapping(ctx context.Context, carID uuid.UUID, salesperson string, start, end time.Time) ([]models.TestDrive, error) {
+	return s.query(ctx, getOverlapping, carID.String(), salesperson, end, start)
+}
+
+// Update changes the slot, the status and the notes of the test drive
+func (s store) Update(ctx context.Context, drive *models.TestDrive) error {
+	_, err := stores.Conn(ctx, s.db).ExecContext(ctx, updateTestDrive, drive.StartsAt, drive.EndsAt, drive.Status, drive.Notes,
+		drive.UpdatedAt, drive.ID.String())
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
+
+// query reads the test drives of a query
+func (s store) query(ctx context.Context, query string, args ...interface{}) ([]models.TestDrive, error) {
+	rows, err := stores.Conn(ctx, s.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.DB{Err: err}
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			s.logger.ErrorContext(ctx, "error in closing rows", "error", err)
+		}
+	}()
+
+	drives := make([]models.TestDrive, 0)
+
+	for rows.Next() {
+		drive, err := scanTestDrive(rows)
+		if err != nil {
+			return nil, errors.DB{Err: err}
+		}
+
+		drives = append(drives, drive)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.DB{Err: err}
+	}
+
+	return drives, nil
+}
+
+// idParam is the id as a query parameter, empty for the nil id so that it matches every row
+func idParam(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+
+	return id.String()
+}
+
+// timeParam is the time as a query parameter, NULL for the zero time so that it matches every row
+func timeParam(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+
+	return t
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTestDrive reads a single test drive from a row
+func scanTestDrive(row scanner) (models.TestDrive, error) {
+	var d models.TestDrive
+
+	err := row.Scan(&d.ID, &d.CarID, &d.CustomerID, &d.Salesperson, &d.StartsAt, &d.EndsAt, &d.Status, &d.Notes, &d.CreatedAt,
+		&d.UpdatedAt)
+	if err != nil {
+		return models.TestDrive{}, err
+	}
+
+	return d, nil
+}
@@ -0,0 +1,174 @@
+package testdrive
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	goError "errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+func initializeTests(t *testing.T) (*sql.DB, sqlmock.Sqlmock, stores.TestDrive) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error %s was not expected when opening a stub database connection", err)
+	}
+
+	s := New(db, logging.Discard())
+
+	return db, mock, s
+}
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	id         = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+	carID      = uuid.MustParse("3b7e4a1c-2b0e-4d55-a3a4-6f1f1f0c9a10")
+	customerID = uuid.MustParse("c4d0f7a2-5e1b-4f0e-9d57-0a2b8e6d3c21")
+	createdAt  = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	startsAt   = time.Date(2022, 1, 3, 10, 0, 0, 0, time.UTC)
+	endsAt     = startsAt.Add(30 * time.Minute)
+	drive      = models.TestDrive{ID: id, CarID: carID, CustomerID: customerID, Salesperson: "jane", StartsAt: startsAt, EndsAt: endsAt,
+		Status: models.TestDriveScheduled, Notes: "wants to try the motorway", CreatedAt: createdAt, UpdatedAt: createdAt}
+	columns = []string{"id", "car_id", "customer_id", "salesperson", "starts_at", "ends_at", "status", "notes", "created_at", "updated_at"}
+)
+
+// row is the row of the test drive of the tests
+func row() []driver.Value {
+	return []driver.Value{id.String(), carID.String(), customerID.String(), "jane", startsAt, endsAt, models.TestDriveScheduled,
+		"wants to try the motorway", createdAt, createdAt}
+}
+
+func TestStore_Create(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	mock.ExpectExec(insertTestDrive).WithArgs(row()...).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(insertTestDrive).WithArgs(row()...).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc string
+		err  error
+	}{
+		{"success", nil},
+		{"query error", errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		err := s.Create(context.Background(), &drive)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestStore_GetAll(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+	from := startsAt.Add(-time.Hour)
+
+	mock.ExpectQuery(getTestDrives).WithArgs(carID.String(), carID.String(), "", "", "", "", "", "", nil, nil, nil, nil).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(row()...))
+	mock.ExpectQuery(getTestDrives).WithArgs("", "", "", "", "jane", "jane", models.TestDriveNoShow, models.TestDriveNoShow, from, from,
+		endsAt, endsAt).WillReturnRows(sqlmock.NewRows(columns))
+	mock.ExpectQuery(getTestDrives).WithArgs("", "", "", "", "", "", "", "", nil, nil, nil, nil).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc   string
+		filter filters.TestDrive
+		output []models.TestDrive
+		err    error
+	}{
+		{"by car", filters.TestDrive{CarID: carID}, []models.TestDrive{drive}, nil},
+		{"no shows of the salesperson in a period", filters.TestDrive{Salesperson: "jane", Status: models.TestDriveNoShow, From: from,
+			To: endsAt}, []models.TestDrive{}, nil},
+		{"query error", filters.TestDrive{}, nil, errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		output, err := s.GetAll(context.Background(), tc.filter)
+
+		if !reflect.DeepEqual(err, tc.err) || !reflect.DeepEqual(output, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v, %v", i, tc.desc, output, err, tc.output, tc.err)
+		}
+	}
+}
+
+func TestStore_GetByID(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	mock.ExpectQuery(getTestDrive).WithArgs(id.String()).WillReturnRows(sqlmock.NewRows(columns).AddRow(row()...))
+	mock.ExpectQuery(getTestDrive).WithArgs(id.String()).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(getTestDrive).WithArgs(id.String()).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc   string
+		output models.TestDrive
+		err    error
+	}{
+		{"success", drive, nil},
+		{"not found", models.TestDrive{}, errors.EntityNotFound{Entity: entity, ID: id.String()}},
+		{"query error", models.TestDrive{}, errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		output, err := s.GetByID(context.Background(), id)
+
+		if !reflect.DeepEqual(err, tc.err) || !reflect.DeepEqual(output, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v, %v", i, tc.desc, output, err, tc.output, tc.err)
+		}
+	}
+}
+
+func TestStore_GetOverlappingUpdate(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	queryErr := goError.New("query error")
+	start, end := startsAt.Add(15*time.Minute), endsAt.Add(15*time.Minute)
+
+	mock.ExpectQuery(getOverlapping).WithArgs(carID.String(), "jane", end, start).WillReturnRows(sqlmock.NewRows(columns).AddRow(row()...))
+	mock.ExpectQuery(getOverlapping).WithArgs(carID.String(), "jane", end, start).WillReturnError(queryErr)
+	mock.ExpectExec(updateTestDrive).WithArgs(startsAt, endsAt, models.TestDriveScheduled, "wants to try the motorway", createdAt,
+		id.String()).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	cases := []struct {
+		desc   string
+		output []models.TestDrive
+		err    error
+	}{
+		{"overlapping", []models.TestDrive{drive}, nil},
+		{"query error", nil, errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		output, err := s.GetOverlapping(ctx, carID, "jane", start, end)
+
+		if !reflect.DeepEqual(err, tc.err) || !reflect.DeepEqual(output, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v, %v", i, tc.desc, output, err, tc.output, tc.err)
+		}
+	}
+
+	if err := s.Update(ctx, &drive); err != nil {
+		t.Errorf("\n[TEST] Failed. Desc : update\nGot %v\nExpected nil", err)
+	}
+}
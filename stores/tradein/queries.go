@@ -0,0 +1,17 @@
+package tradein
+
+const (
+	tradeInColumns = "id,customer_id,brand,model,year,fuel_type,mileage,check_bodywork,check_interior,check_tyres,check_brakes," +
+		"check_engine,check_service_history,photos,appraisal_value,appraisal_expires_at,appraised_by,appraised_at,approved_by," +
+		"approved_at,car_id,status,created_at,updated_at"
+
+	insertTradeIn = "INSERT INTO trade_ins (" + tradeInColumns + ") VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)"
+	// empty filters match every trade-in
+	getTradeIns = "SELECT " + tradeInColumns + " FROM trade_ins WHERE (?='' OR customer_id=?) AND (?='' OR status=?) " +
+		"ORDER BY created_at,id;"
+	getTradeIn    = "SELECT " + tradeInColumns + " FROM trade_ins WHERE id=?;"
+	lockTradeIn   = "SELECT " + tradeInColumns + " FROM trade_ins WHERE id=? FOR UPDATE;"
+	updateTradeIn = "UPDATE trade_ins SET brand=?,model=?,year=?,fuel_type=?,mileage=?,check_bodywork=?,check_interior=?,check_tyres=?," +
+		"check_brakes=?,check_engine=?,check_service_history=?,photos=?,appraisal_value=?,appraisal_expires_at=?,appraised_by=?," +
+		"appraised_at=?,approved_by=?,approved_at=?,car_id=?,status=?,updated_at=? WHERE id=?"
+)
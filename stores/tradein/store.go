@@ -0,0 +1,193 @@
+package tradein
+
+import (
+	"context"
+	"database/sql"
+	goError "errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+const entity = "trade-in"
+
+type store struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func New(db *sql.DB, logger *slog.Logger) stores.TradeIn {
+	return store{db: db, logger: logger}
+}
+
+// Create inserts a new trade-in
+func (s store) Create(ctx context.Context, t *models.TradeIn) error {
+	args := append([]interface{}{t.ID.String(), t.CustomerID.String()}, fields(t)...)
+	args = append(args, t.CreatedAt, t.UpdatedAt)
+
+	if _, err := stores.Conn(ctx, s.db).ExecContext(ctx, insertTradeIn, args...); err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
+
+// GetAll fetches the trade-ins of the customer and status of the filter, oldest first
+func (s store) GetAll(ctx context.Context, filter filters.TradeIn) ([]models.TradeIn, error) {
+	customerID := ""
+	if filter.CustomerID != uuid.Nil {
+		customerID = filter.CustomerID.String()
+	}
+
+	rows, err := stores.Conn(ctx, s.db).QueryContext(ctx, getTradeIns, customerID, customerID, filter.Status, filter.Status)
+	if err != nil {
+		return nil, errors.DB{Err: err}
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			s.logger.ErrorContext(ctx, "error in closing rows", "error", err)
+		}
+	}()
+
+	tradeIns := make([]models.TradeIn, 0)
+
+	for rows.Next() {
+		t, err := scanTradeIn(rows)
+		if err != nil {
+			return nil, errors.DB{Err: err}
+		}
+
+		tradeIns = append(tradeIns, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.DB{Err: err}
+	}
+
+	return tradeIns, nil
+}
+
+// GetByID fetches the trade-in of the given id
+func (s store) GetByID(ctx context.Context, id uuid.UUID) (models.TradeIn, error) {
+	return s.get(ctx, getTradeIn, id)
+}
+
+// Lock fetches the trade-in of the given id and locks its row until the end of the transaction of ctx
+func (s store) Lock(ctx context.Context, id uuid.UUID) (models.TradeIn, error) {
+	return s.get(ctx, lockTradeIn, id)
+}
+
+// Update changes every field of the trade-in but its customer
+func (s store) Update(ctx context.Context, t *models.TradeIn) error {
+	args := append(fields(t), t.UpdatedAt, t.ID.String())
+
+	if _, err := stores.Conn(ctx, s.db).ExecContext(ctx, updateTradeIn, args...); err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
+
+// get reads the trade-in of the given id with the query
+func (s store) get(ctx context.Context, query string, id uuid.UUID) (models.TradeIn, error) {
+	t, err := scanTradeIn(stores.Conn(ctx, s.db).QueryRowContext(ctx, query, id.String()))
+
+	switch {
+	case goError.Is(err, sql.ErrNoRows):
+		return models.TradeIn{}, errors.EntityNotFound{Entity: entity, ID: id.String()}
+	case err != nil:
+		return models.TradeIn{}, errors.DB{Err: err}
+	}
+
+	return t, nil
+}
+
+// fields are the values of the columns from brand to status, the photos are stored one per line
+func fields(t *models.TradeIn) []interface{} {
+	var (
+		value                  int64
+		appraisedBy            string
+		expiresAt, appraisedAt sql.NullTime
+		carID                  sql.NullString
+	)
+
+	if a := t.Appraisal; a != nil {
+		value, appraisedBy = a.Value, a.AppraisedBy
+		expiresAt = sql.NullTime{Time: a.ExpiresAt, Valid: true}
+		appraisedAt = sql.NullTime{Time: a.AppraisedAt, Valid: true}
+	}
+
+	if t.CarID != nil {
+		carID = sql.NullString{String: t.CarID.String(), Valid: true}
+	}
+
+	c := t.Checklist
+
+	return []interface{}{t.Brand, t.Model, t.ManufactureYear, t.FuelType, t.Mileage, c.Bodywork, c.Interior, c.Tyres, c.Brakes,
+		c.Engine, c.ServiceHistory, strings.Join(t.Photos, "\n"), value, expiresAt, appraisedBy, appraisedAt, t.ApprovedBy,
+		nullTime(t.ApprovedAt), carID, t.Status}
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTradeIn reads a single trade-in from a row
+func scanTradeIn(row scanner) (models.TradeIn, error) {
+	var (
+		t                                  models.TradeIn
+		c                                  = &t.Checklist
+		photos, appraisedBy                string
+		value                              int64
+		expiresAt, appraisedAt, approvedAt sql.NullTime
+		carID                              sql.NullString
+	)
+
+	err := row.Scan(&t.ID, &t.CustomerID, &t.Brand, &t.Model, &t.ManufactureYear, &t.FuelType, &t.Mileage, &c.Bodywork, &c.Interior,
+		&c.Tyres, &c.Brakes, &c.Engine, &c.ServiceHistory, &photos, &value, &expiresAt, &appraisedBy, &appraisedAt, &t.ApprovedBy,
+		&approvedAt, &carID, &t.Status, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return models.TradeIn{}, err
+	}
+
+	t.Photos = make([]string, 0)
+
+	if photos != "" {
+		t.Photos = strings.Split(photos, "\n")
+	}
+
+	if expiresAt.Valid {
+		t.Appraisal = &models.Appraisal{Value: value, ExpiresAt: expiresAt.Time, AppraisedBy: appraisedBy, AppraisedAt: appraisedAt.Time}
+	}
+
+	if approvedAt.Valid {
+		t.ApprovedAt = &approvedAt.Time
+	}
+
+	if carID.Valid {
+		id, err := uuid.Parse(carID.String)
+		if err != nil {
+			return models.TradeIn{}, err
+		}
+
+		t.CarID = &id
+	}
+
+	return t, nil
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: *t, Valid: true}
+}
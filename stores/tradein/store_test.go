@@ -0,0 +1,182 @@
+package tradein
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	goError "errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+	"github.com/amehrotra/car-dealership/types"
+)
+
+func initializeTests(t *testing.T) (*sql.DB, sqlmock.Sqlmock, stores.TradeIn) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error %s was not expected when opening a stub database connection", err)
+	}
+
+	s := New(db, logging.Discard())
+
+	return db, mock, s
+}
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	id         = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+	customerID = uuid.MustParse("c4d0f7a2-5e1b-4f0e-9d57-0a2b8e6d3c21")
+	createdAt  = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	submitted  = models.TradeIn{ID: id, CustomerID: customerID, Brand: "BMW", Model: "320d", ManufactureYear: 2015, FuelType: types.Diesel,
+		Mileage: 120000, Checklist: models.Checklist{Bodywork: true, Tyres: true},
+		Photos: []string{"https://photos/1.jpg", "https://photos/2.jpg"}, Status: models.TradeInSubmitted, CreatedAt: createdAt,
+		UpdatedAt: createdAt}
+	columns = []string{"id", "customer_id", "brand", "model", "year", "fuel_type", "mileage", "check_bodywork", "check_interior",
+		"check_tyres", "check_brakes", "check_engine", "check_service_history", "photos", "appraisal_value", "appraisal_expires_at",
+		"appraised_by", "appraised_at", "approved_by", "approved_at", "car_id", "status", "created_at", "updated_at"}
+)
+
+// row is the row of the submitted trade-in of the tests
+func row() []driver.Value {
+	return []driver.Value{id.String(), customerID.String(), "BMW", "320d", 2015, []byte("diesel"), 120000, true, false, true, false,
+		false, false, "https://photos/1.jpg\nhttps://photos/2.jpg", 0, nil, "", nil, "", nil, nil, models.TradeInSubmitted, createdAt,
+		createdAt}
+}
+
+func TestStore_Create(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+	args := row()
+	args[5] = "diesel"
+
+	mock.ExpectExec(insertTradeIn).WithArgs(args...).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(insertTradeIn).WithArgs(args...).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc string
+		err  error
+	}{
+		{"success", nil},
+		{"query error", errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		err := s.Create(context.Background(), &submitted)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestStore_GetAll(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	mock.ExpectQuery(getTradeIns).WithArgs(customerID.String(), customerID.String(), "", "").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(row()...))
+	mock.ExpectQuery(getTradeIns).WithArgs("", "", models.TradeInApproved, models.TradeInApproved).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc   string
+		filter filters.TradeIn
+		output []models.TradeIn
+		err    error
+	}{
+		{"by customer", filters.TradeIn{CustomerID: customerID}, []models.TradeIn{submitted}, nil},
+		{"query error", filters.TradeIn{Status: models.TradeInApproved}, nil, errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		output, err := s.GetAll(context.Background(), tc.filter)
+
+		if !reflect.DeepEqual(err, tc.err) || !reflect.DeepEqual(output, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v, %v", i, tc.desc, output, err, tc.output, tc.err)
+		}
+	}
+}
+
+func TestStore_GetByIDLock(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+	carID := uuid.New()
+	expiresAt, approvedAt := createdAt.Add(7*24*time.Hour), createdAt.Add(time.Hour)
+
+	converted := submitted
+	converted.Appraisal = &models.Appraisal{Value: 900000, ExpiresAt: expiresAt, AppraisedBy: "jane", AppraisedAt: createdAt}
+	converted.ApprovedBy, converted.ApprovedAt = "john", &approvedAt
+	converted.CarID = &carID
+	converted.Status = models.TradeInConverted
+
+	convertedRow := row()
+	convertedRow[14], convertedRow[15], convertedRow[16], convertedRow[17] = 900000, expiresAt, "jane", createdAt
+	convertedRow[18], convertedRow[19], convertedRow[20], convertedRow[21] = "john", approvedAt, carID.String(), models.TradeInConverted
+
+	mock.ExpectQuery(getTradeIn).WithArgs(id.String()).WillReturnRows(sqlmock.NewRows(columns).AddRow(row()...))
+	mock.ExpectQuery(lockTradeIn).WithArgs(id.String()).WillReturnRows(sqlmock.NewRows(columns).AddRow(convertedRow...))
+	mock.ExpectQuery(getTradeIn).WithArgs(id.String()).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(lockTradeIn).WithArgs(id.String()).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc   string
+		get    func(ctx context.Context, id uuid.UUID) (models.TradeIn, error)
+		output models.TradeIn
+		err    error
+	}{
+		{"submitted", s.GetByID, submitted, nil},
+		{"converted", s.Lock, converted, nil},
+		{"not found", s.GetByID, models.TradeIn{}, errors.EntityNotFound{Entity: entity, ID: id.String()}},
+		{"query error", s.Lock, models.TradeIn{}, errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		output, err := tc.get(context.Background(), id)
+
+		if !reflect.DeepEqual(err, tc.err) || !reflect.DeepEqual(output, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v, %v\nExpected %v, %v", i, tc.desc, output, err, tc.output, tc.err)
+		}
+	}
+}
+
+func TestStore_Update(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+	args := append(append([]driver.Value{}, row()[2:22]...), createdAt, id.String())
+	args[3] = "diesel"
+
+	mock.ExpectExec(updateTradeIn).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(updateTradeIn).WithArgs(args...).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc string
+		err  error
+	}{
+		{"success", nil},
+		{"query error", errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		err := s.Update(context.Background(), &submitted)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package stores
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/amehrotra/car-dealership/errors"
+)
+
+// Executor runs the statements of a store, it is either the database or the transaction of the context
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txKey struct{}
+
+// txState is the transaction of a context along with the functions to run once it is committed
+type txState struct {
+	tx          *sql.Tx
+	afterCommit []func()
+}
+
+// Conn returns the transaction started by a Transactor for ctx, or db outside of one,
+// so that stores join the transaction of their caller without changing their interface
+func Conn(ctx context.Context, db *sql.DB) Executor {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx
+	}
+
+	return db
+}
+
+// InTransaction reports whether ctx carries a transaction started by a Transactor
+func InTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*txState)
+
+	return ok
+}
+
+// AfterCommit runs fn once the transaction of ctx is committed, right away outside of one.
+// fn is dropped when the transaction is rolled back.
+func AfterCommit(ctx context.Context, fn func()) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, fn)
+
+		return
+	}
+
+	fn()
+}
+
+type transactor struct {
+	db *sql.DB
+}
+
+// NewTransactor returns a Transactor over the transactions of db
+func NewTransactor(db *sql.DB) Transactor {
+	return transactor{db: db}
+}
+
+// InTx runs fn in a transaction which is committed when fn returns nil and rolled back otherwise.
+// Calls nested in fn join the transaction already in ctx.
+func (t transactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if InTransaction(ctx) {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	state := &txState{tx: tx}
+
+	if err := fn(context.WithValue(ctx, txKey{}, state)); err != nil {
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.DB{Err: err}
+	}
+
+	for _, f := range state.afterCommit {
+		f()
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package stores
+
+import (
+	"context"
+	goError "errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/amehrotra/car-dealership/errors"
+)
+
+func TestTransactor_InTx(t *testing.T) {
+	fnErr := goError.New("fn failed")
+	dbErr := goError.New("connection lost")
+
+	cases := []struct {
+		desc   string
+		expect func(mock sqlmock.Sqlmock)
+		fnErr  error
+		err    error
+	}{
+		{"committed", func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectExec("UPDATE cars").WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}, nil, nil},
+		{"rolled back", func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectExec("UPDATE cars").WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectRollback()
+		}, fnErr, fnErr},
+		{"begin error", func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin().WillReturnError(dbErr)
+		}, nil, errors.DB{Err: dbErr}},
+		{"commit error", func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectExec("UPDATE cars").WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit().WillReturnError(dbErr)
+		}, nil, errors.DB{Err: dbErr}},
+	}
+
+	for i, tc := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("error %s was not expected when opening a stub database connection", err)
+		}
+
+		tc.expect(mock)
+
+		tx := NewTransactor(db)
+		committed := false
+
+		err = tx.InTx(context.Background(), func(ctx context.Context) error {
+			// nested calls join the transaction
+			return tx.InTx(ctx, func(ctx context.Context) error {
+				if _, err := Conn(ctx, db).ExecContext(ctx, "UPDATE cars"); err != nil {
+					return err
+				}
+
+				AfterCommit(ctx, func() { committed = true })
+
+				return tc.fnErr
+			})
+		})
+
+		if err != tc.err {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+
+		if committed != (tc.desc == "committed") {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot after commit run %v\nExpected it only on commit", i, tc.desc, committed)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected all expectations to be met", i, tc.desc, err)
+		}
+
+		db.Close()
+	}
+}
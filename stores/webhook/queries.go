@@ -0,0 +1,9 @@
+package webhook
+
+const (
+	insertWebhook = "INSERT INTO webhooks (id,url,event_types,active,secret,created_at) VALUES (?,?,?,?,?,?)"
+	getWebhooks   = "SELECT id,url,event_types,active,secret,created_at FROM webhooks;"
+	getWebhook    = "SELECT id,url,event_types,active,secret,created_at FROM webhooks WHERE id=?;"
+	updateWebhook = "UPDATE webhooks SET url=?,event_types=?,active=? WHERE id=?"
+	deleteWebhook = "DELETE FROM webhooks WHERE id=?;"
+)
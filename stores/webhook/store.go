@@ -0,0 +1,138 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	goError "errors"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+const entity = "webhook"
+
+type store struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func New(db *sql.DB, logger *slog.Logger) stores.Webhook {
+	return store{db: db, logger: logger}
+}
+
+// Create inserts a new webhook along with the secret its payloads are signed with
+func (s store) Create(ctx context.Context, webhook *models.Webhook) error {
+	_, err := stores.Conn(ctx, s.db).ExecContext(ctx, insertWebhook, webhook.ID.String(), webhook.URL,
+		strings.Join(webhook.EventTypes, ","), webhook.Active, webhook.Secret, webhook.CreatedAt)
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
+
+// GetAll fetches all the webhooks
+func (s store) GetAll(ctx context.Context) ([]models.Webhook, error) {
+	rows, err := stores.Conn(ctx, s.db).QueryContext(ctx, getWebhooks)
+	if err != nil {
+		return nil, errors.DB{Err: err}
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			s.logger.ErrorContext(ctx, "error in closing rows", "error", err)
+		}
+	}()
+
+	webhooks := make([]models.Webhook, 0)
+
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, errors.DB{Err: err}
+		}
+
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.DB{Err: err}
+	}
+
+	return webhooks, nil
+}
+
+// GetByID fetches the webhook of the given id
+func (s store) GetByID(ctx context.Context, id uuid.UUID) (models.Webhook, error) {
+	webhook, err := scanWebhook(stores.Conn(ctx, s.db).QueryRowContext(ctx, getWebhook, id.String()))
+
+	switch {
+	case goError.Is(err, sql.ErrNoRows):
+		return models.Webhook{}, errors.EntityNotFound{Entity: entity, ID: id.String()}
+	case err != nil:
+		return models.Webhook{}, errors.DB{Err: err}
+	}
+
+	return webhook, nil
+}
+
+// Update replaces the url, event types and state of the webhook, the secret never changes.
+// Unchanged rows are not reported as affected by MySQL, so callers check the webhook exists beforehand.
+func (s store) Update(ctx context.Context, webhook *models.Webhook) error {
+	_, err := stores.Conn(ctx, s.db).ExecContext(ctx, updateWebhook, webhook.URL, strings.Join(webhook.EventTypes, ","), webhook.Active,
+		webhook.ID.String())
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	return nil
+}
+
+// Delete removes the webhook along with its deliveries
+func (s store) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := stores.Conn(ctx, s.db).ExecContext(ctx, deleteWebhook, id.String())
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.DB{Err: err}
+	}
+
+	if n == 0 {
+		return errors.EntityNotFound{Entity: entity, ID: id.String()}
+	}
+
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanWebhook reads a single webhook from a row
+func scanWebhook(row scanner) (models.Webhook, error) {
+	var (
+		webhook    models.Webhook
+		eventTypes string
+	)
+
+	err := row.Scan(&webhook.ID, &webhook.URL, &eventTypes, &webhook.Active, &webhook.Secret, &webhook.CreatedAt)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	webhook.EventTypes = make([]string, 0)
+
+	if eventTypes != "" {
+		webhook.EventTypes = strings.Split(eventTypes, ",")
+	}
+
+	return webhook, nil
+}
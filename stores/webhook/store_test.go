@@ -0,0 +1,174 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	goError "errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/logging"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+func initializeTests(t *testing.T) (*sql.DB, sqlmock.Sqlmock, stores.Webhook) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error %s was not expected when opening a stub database connection", err)
+	}
+
+	s := New(db, logging.Discard())
+
+	return db, mock, s
+}
+
+// nolint:gochecknoglobals // to remove redundant declaration in test file
+var (
+	id        = uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+	createdAt = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns   = []string{"id", "url", "event_types", "active", "secret", "created_at"}
+)
+
+func TestStore_Create(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	webhook := models.Webhook{ID: id, URL: "https://crm.example.com/hook", EventTypes: []string{models.CarCreated, models.CarDeleted},
+		Active: true, Secret: "secret", CreatedAt: createdAt}
+	queryErr := goError.New("query error")
+
+	mock.ExpectExec(insertWebhook).WithArgs(id.String(), webhook.URL, "car.created,car.deleted", true, "secret", createdAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(insertWebhook).WithArgs(id.String(), webhook.URL, "car.created,car.deleted", true, "secret", createdAt).
+		WillReturnError(queryErr)
+
+	cases := []struct {
+		desc string
+		err  error
+	}{
+		{"success", nil},
+		{"query error", errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		err := s.Create(context.Background(), &webhook)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+func TestStore_GetAll(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	mock.ExpectQuery(getWebhooks).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(id.String(), "https://crm.example.com/hook", "car.updated", true, "secret", createdAt).
+		AddRow(id.String(), "https://erp.example.com/hook", "", false, "secret", createdAt))
+	mock.ExpectQuery(getWebhooks).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc   string
+		output []models.Webhook
+		err    error
+	}{
+		{"success", []models.Webhook{
+			{ID: id, URL: "https://crm.example.com/hook", EventTypes: []string{models.CarUpdated}, Active: true, Secret: "secret", CreatedAt: createdAt},
+			{ID: id, URL: "https://erp.example.com/hook", EventTypes: []string{}, Secret: "secret", CreatedAt: createdAt},
+		}, nil},
+		{"query error", nil, errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		output, err := s.GetAll(context.Background())
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+
+		if !reflect.DeepEqual(output, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, output, tc.output)
+		}
+	}
+}
+
+func TestStore_GetByID(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	queryErr := goError.New("query error")
+
+	mock.ExpectQuery(getWebhook).WithArgs(id.String()).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(id.String(), "https://crm.example.com/hook", "car.created,car.updated", true, "secret", createdAt))
+	mock.ExpectQuery(getWebhook).WithArgs(id.String()).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(getWebhook).WithArgs(id.String()).WillReturnError(queryErr)
+
+	cases := []struct {
+		desc   string
+		output models.Webhook
+		err    error
+	}{
+		{"success", models.Webhook{ID: id, URL: "https://crm.example.com/hook", EventTypes: []string{models.CarCreated, models.CarUpdated},
+			Active: true, Secret: "secret", CreatedAt: createdAt}, nil},
+		{"not found", models.Webhook{}, errors.EntityNotFound{Entity: entity, ID: id.String()}},
+		{"query error", models.Webhook{}, errors.DB{Err: queryErr}},
+	}
+
+	for i, tc := range cases {
+		output, err := s.GetByID(context.Background(), id)
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+
+		if !reflect.DeepEqual(output, tc.output) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, output, tc.output)
+		}
+	}
+}
+
+func TestStore_UpdateDelete(t *testing.T) {
+	db, mock, s := initializeTests(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	webhook := models.Webhook{ID: id, URL: "https://crm.example.com/hook"}
+
+	queryErr := goError.New("query error")
+	resultErr := goError.New("result error")
+
+	mock.ExpectExec(updateWebhook).WithArgs(webhook.URL, "", false, id.String()).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(updateWebhook).WithArgs(webhook.URL, "", false, id.String()).WillReturnError(queryErr)
+	mock.ExpectExec(deleteWebhook).WithArgs(id.String()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(deleteWebhook).WithArgs(id.String()).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(deleteWebhook).WithArgs(id.String()).WillReturnResult(sqlmock.NewErrorResult(resultErr))
+
+	cases := []struct {
+		desc string
+		call func() error
+		err  error
+	}{
+		{"update unchanged webhook", func() error { return s.Update(ctx, &webhook) }, nil},
+		{"update query error", func() error { return s.Update(ctx, &webhook) }, errors.DB{Err: queryErr}},
+		{"delete", func() error { return s.Delete(ctx, id) }, nil},
+		{"delete missing webhook", func() error { return s.Delete(ctx, id) }, errors.EntityNotFound{Entity: entity, ID: id.String()}},
+		{"rows affected error", func() error { return s.Delete(ctx, id) }, errors.DB{Err: resultErr}},
+	}
+
+	for i, tc := range cases {
+		err := tc.call()
+
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+)
+
+type carService struct {
+	next   services.Car
+	tracer trace.Tracer
+}
+
+// CarService creates a span for every call to the car service
+func CarService(next services.Car, tp trace.TracerProvider) services.Car {
+	return carService{next: next, tracer: tp.Tracer(Name)}
+}
+
+func (s carService) Create(ctx context.Context, car *models.Car) (*models.Car, error) {
+	ctx, span := s.tracer.Start(ctx, "services.Car/Create")
+	resp, err := s.next.Create(ctx, car)
+	end(span, err)
+
+	return resp, err
+}
+
+func (s carService) GetAll(ctx context.Context, filter filters.Car) ([]models.Car, error) {
+	ctx, span := s.tracer.Start(ctx, "services.Car/GetAll")
+	resp, err := s.next.GetAll(ctx, filter)
+	end(span, err)
+
+	return resp, err
+}
+
+func (s carService) GetByID(ctx context.Context, id uuid.UUID) (*models.Car, error) {
+	ctx, span := s.tracer.Start(ctx, "services.Car/GetByID")
+	resp, err := s.next.GetByID(ctx, id)
+	end(span, err)
+
+	return resp, err
+}
+
+func (s carService) Update(ctx context.Context, car *models.Car) (*models.Car, error) {
+	ctx, span := s.tracer.Start(ctx, "services.Car/Update")
+	resp, err := s.next.Update(ctx, car)
+	end(span, err)
+
+	return resp, err
+}
+
+func (s carService) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, span := s.tracer.Start(ctx, "services.Car/Delete")
+	err := s.next.Delete(ctx, id)
+	end(span, err)
+
+	return err
+}
+
+func (s carService) Import(ctx context.Context, rows []models.ImportRow, opts models.ImportOptions) (*models.ImportResult, error) {
+	ctx, span := s.tracer.Start(ctx, "services.Car/Import", trace.WithAttributes(attribute.Int("import.rows", len(rows)),
+		attribute.Bool("import.dry_run", opts.DryRun), attribute.Bool("import.best_effort", opts.BestEffort)))
+	resp, err := s.next.Import(ctx, rows, opts)
+	end(span, err)
+
+	return resp, err
+}
+
+func (s carService) Export(ctx context.Context, filter filters.Car, fn func(car *models.Car) error) error {
+	ctx, span := s.tracer.Start(ctx, "services.Car/Export")
+	err := s.next.Export(ctx, filter, fn)
+	end(span, err)
+
+	return err
+}
+
+func (s carService) Batch(ctx context.Context, ops []models.BatchOperation, opts models.BatchOptions) ([]models.BatchResult, error) {
+	ctx, span := s.tracer.Start(ctx, "services.Car/Batch",
+		trace.WithAttributes(attribute.Int("batch.operations", len(ops)), attribute.Bool("batch.best_effort", opts.BestEffort)))
+	resp, err := s.next.Batch(ctx, ops, opts)
+	end(span, err)
+
+	return resp, err
+}
@@ -0,0 +1,175 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+type carStore struct {
+	next   stores.Car
+	tracer trace.Tracer
+}
+
+// CarStore creates a client span for every call to the car store, the store adds the statement it executes
+func CarStore(next stores.Car, tp trace.TracerProvider) stores.Car {
+	return carStore{next: next, tracer: tp.Tracer(Name)}
+}
+
+func (s carStore) start(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, "stores.Car/"+operation, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemMySQL, semconv.DBCollectionName("cars"), semconv.DBOperationName(operation)))
+}
+
+func (s carStore) Create(ctx context.Context, car *models.Car) error {
+	ctx, span := s.start(ctx, "Create")
+	err := s.next.Create(ctx, car)
+	end(span, err)
+
+	return err
+}
+
+func (s carStore) GetAll(ctx context.Context, filter filters.Car) ([]models.Car, error) {
+	ctx, span := s.start(ctx, "GetAll")
+	resp, err := s.next.GetAll(ctx, filter)
+	end(span, err)
+
+	return resp, err
+}
+
+func (s carStore) GetByID(ctx context.Context, id uuid.UUID) (models.Car, error) {
+	ctx, span := s.start(ctx, "GetByID")
+	resp, err := s.next.GetByID(ctx, id)
+	end(span, err)
+
+	return resp, err
+}
+
+func (s carStore) Update(ctx context.Context, car *models.Car) error {
+	ctx, span := s.start(ctx, "Update")
+	err := s.next.Update(ctx, car)
+	end(span, err)
+
+	return err
+}
+
+func (s carStore) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, span := s.start(ctx, "Delete")
+	err := s.next.Delete(ctx, id)
+	end(span, err)
+
+	return err
+}
+
+// Iterate keeps the span open until the iterator is closed so that it covers reading the rows
+func (s carStore) Iterate(ctx context.Context, filter filters.Car) (stores.CarIterator, error) {
+	ctx, span := s.start(ctx, "Iterate")
+
+	it, err := s.next.Iterate(ctx, filter)
+	if err != nil {
+		end(span, err)
+
+		return nil, err
+	}
+
+	return &carIterator{CarIterator: it, span: span}, nil
+}
+
+func (s carStore) Lock(ctx context.Context, id uuid.UUID) error {
+	ctx, span := s.start(ctx, "Lock")
+	err := s.next.Lock(ctx, id)
+	end(span, err)
+
+	return err
+}
+
+func (s carStore) Stock(ctx context.Context) ([]models.Stock, error) {
+	ctx, span := s.start(ctx, "Stock")
+	resp, err := s.next.Stock(ctx)
+	end(span, err)
+
+	return resp, err
+}
+
+// carIterator ends the span of the query on Close and counts the rows read
+type carIterator struct {
+	stores.CarIterator
+	span trace.Span
+	rows int
+}
+
+func (it *carIterator) Next() bool {
+	if !it.CarIterator.Next() {
+		return false
+	}
+
+	it.rows++
+
+	return true
+}
+
+func (it *carIterator) Close() error {
+	err := it.CarIterator.Close()
+	if err == nil {
+		err = it.CarIterator.Err()
+	}
+
+	it.span.SetAttributes(attribute.Int("db.response.returned_rows", it.rows))
+	end(it.span, err)
+
+	return err
+}
+
+type engineStore struct {
+	next   stores.Engine
+	tracer trace.Tracer
+}
+
+// EngineStore creates a client span for every call to the engine store, the store adds the statement it executes
+func EngineStore(next stores.Engine, tp trace.TracerProvider) stores.Engine {
+	return engineStore{next: next, tracer: tp.Tracer(Name)}
+}
+
+func (s engineStore) start(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, "stores.Engine/"+operation, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemMySQL, semconv.DBCollectionName("engines"), semconv.DBOperationName(operation)))
+}
+
+func (s engineStore) Create(ctx context.Context, engine *models.Engine) error {
+	ctx, span := s.start(ctx, "Create")
+	err := s.next.Create(ctx, engine)
+	end(span, err)
+
+	return err
+}
+
+func (s engineStore) GetByID(ctx context.Context, id uuid.UUID) (models.Engine, error) {
+	ctx, span := s.start(ctx, "GetByID")
+	resp, err := s.next.GetByID(ctx, id)
+	end(span, err)
+
+	return resp, err
+}
+
+func (s engineStore) Update(ctx context.Context, engine *models.Engine) error {
+	ctx, span := s.start(ctx, "Update")
+	err := s.next.Update(ctx, engine)
+	end(span, err)
+
+	return err
+}
+
+func (s engineStore) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, span := s.start(ctx, "Delete")
+	err := s.next.Delete(ctx, id)
+	end(span, err)
+
+	return err
+}
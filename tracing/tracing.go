@@ -0,0 +1,78 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	serviceName = "car-dealership"
+
+	// Name is the instrumentation scope of the spans created by the dealership
+	Name = "github.com/amehrotra/car-dealership"
+)
+
+// Exporters supported by NewProvider
+const (
+	None   = "none"
+	Stdout = "stdout"
+	OTLP   = "otlp"
+)
+
+// NewProvider builds a tracer provider batching spans to the exporter.
+// Stdout pretty prints spans to w, OTLP sends them over http and is configured by the standard OTEL_EXPORTER_OTLP_* variables
+// and None, or an empty name, keeps spans in process so that trace ids are still propagated.
+func NewProvider(ctx context.Context, exporter string, w io.Writer) (*sdktrace.TracerProvider, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+
+	switch exporter {
+	case None, "":
+	case Stdout:
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(w), stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, sdktrace.WithBatcher(exp))
+	case OTLP:
+		exp, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, sdktrace.WithBatcher(exp))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", exporter)
+	}
+
+	return sdktrace.NewTracerProvider(opts...), nil
+}
+
+// Statement annotates the span of the store call in ctx with the sql statement it executes
+func Statement(ctx context.Context, query string) {
+	trace.SpanFromContext(ctx).SetAttributes(semconv.DBQueryText(query))
+}
+
+// end records the error of the call on the span and ends it
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,96 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+
+	"github.com/amehrotra/car-dealership/errors"
+	"github.com/amehrotra/car-dealership/filters"
+	"github.com/amehrotra/car-dealership/models"
+	"github.com/amehrotra/car-dealership/services"
+	"github.com/amehrotra/car-dealership/stores"
+)
+
+func TestNewProvider(t *testing.T) {
+	cases := []struct {
+		desc     string
+		exporter string
+		err      bool
+	}{
+		{"no exporter", "", false},
+		{"none", None, false},
+		{"stdout", Stdout, false},
+		{"unknown exporter", "jaeger", true},
+	}
+
+	for i, tc := range cases {
+		tp, err := NewProvider(context.Background(), tc.exporter, &bytes.Buffer{})
+
+		if (err != nil) != tc.err {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected error %v", i, tc.desc, err, tc.err)
+		}
+
+		if tp != nil {
+			_ = tp.Shutdown(context.Background())
+		}
+	}
+}
+
+func TestDecorators(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	mockStore := stores.NewMockCar(ctrl)
+	mockService := services.NewMockCar(ctrl)
+	store := CarStore(mockStore, tp)
+	service := CarService(mockService, tp)
+	id := uuid.New()
+
+	mockService.EXPECT().GetAll(gomock.Any(), filters.Car{}).DoAndReturn(func(ctx context.Context, filter filters.Car) ([]models.Car, error) {
+		return nil, store.Delete(ctx, id)
+	})
+	mockStore.EXPECT().Delete(gomock.Any(), id).DoAndReturn(func(ctx context.Context, id uuid.UUID) error {
+		Statement(ctx, "DELETE FROM cars WHERE id=?")
+
+		return errors.DB{Err: context.Canceled}
+	})
+
+	_, _ = service.GetAll(context.Background(), filters.Car{})
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("\n[TEST] Failed. Desc : spans\nGot %v\nExpected 2", len(spans))
+	}
+
+	storeSpan, serviceSpan := spans[0], spans[1]
+	attributes := attribute.NewSet(storeSpan.Attributes()...)
+	statement, _ := attributes.Value(semconv.DBQueryTextKey)
+
+	cases := []struct {
+		desc     string
+		output   interface{}
+		expected interface{}
+	}{
+		{"store span name", storeSpan.Name(), "stores.Car/Delete"},
+		{"service span name", serviceSpan.Name(), "services.Car/GetAll"},
+		{"store span is child of service span", storeSpan.Parent().SpanID(), serviceSpan.SpanContext().SpanID()},
+		{"statement attribute", statement.AsString(), "DELETE FROM cars WHERE id=?"},
+		{"error status", storeSpan.Status().Code, codes.Error},
+	}
+
+	for i, tc := range cases {
+		if tc.output != tc.expected {
+			t.Errorf("\n[TEST %d] Failed. Desc : %v\nGot %v\nExpected %v", i, tc.desc, tc.output, tc.expected)
+		}
+	}
+}
@@ -22,6 +22,20 @@ const (
 	electric = "electric"
 )
 
+// String returns the name of the fuel type, unknown fuel types have an empty name
+func (f Fuel) String() string {
+	switch f {
+	case Petrol:
+		return petrol
+	case Diesel:
+		return diesel
+	case Electric:
+		return electric
+	default:
+		return ""
+	}
+}
+
 func (f Fuel) MarshalJSON() ([]byte, error) {
 	var s string
 